@@ -0,0 +1,241 @@
+// Package alerts 基于消息推送SDK的运维告警助手
+//
+// 按告警级别将告警路由到预先配置的通道（如 critical→短信+语音，warning→钉钉，info→邮件），
+// 并支持按指纹去重和恢复通知。
+package alerts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// DefaultDedupeWindow 默认去重时间窗口
+const DefaultDedupeWindow = 10 * time.Minute
+
+// Severity 告警级别
+type Severity int
+
+// 告警级别枚举
+const (
+	SeverityInfo     Severity = iota // 通知
+	SeverityWarning                  // 警告
+	SeverityCritical                 // 严重
+)
+
+// String 返回告警级别名称
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// 告警状态（作为模板参数 status 传递）
+const (
+	StatusFiring   = "firing"   // 告警中
+	StatusResolved = "resolved" // 已恢复
+)
+
+// Route 告警投递目标
+type Route struct {
+	ChannelID     int      // 通道ID
+	SignatureName string   // 签名名称
+	Receivers     []string // 接收者列表
+}
+
+// Config 告警配置
+type Config struct {
+	Routes       map[Severity][]Route // 各级别对应的投递目标
+	DedupeWindow time.Duration        // 相同指纹的去重窗口，0表示使用默认值
+}
+
+// Sender 发送告警所需的客户端能力，*mlievpush.Client 满足该接口
+type Sender interface {
+	SendBatch(ctx context.Context, req *mlievpush.SendBatchRequest, opts ...mlievpush.CallOption) (*mlievpush.SendBatchData, error)
+}
+
+var _ Sender = (*mlievpush.Client)(nil)
+
+// Option 单次告警选项
+type Option func(*notifyOptions)
+
+type notifyOptions struct {
+	fingerprint string
+}
+
+// WithFingerprint 指定告警指纹（默认根据标题生成）
+func WithFingerprint(fingerprint string) Option {
+	return func(o *notifyOptions) {
+		o.fingerprint = fingerprint
+	}
+}
+
+// activeAlert 仍处于告警中的记录
+type activeAlert struct {
+	severity Severity
+	sentAt   time.Time
+}
+
+// Notifier 告警通知器，并发安全
+type Notifier struct {
+	mu     sync.Mutex
+	cfg    Config
+	active map[string]*activeAlert
+	now    func() time.Time
+}
+
+// NewNotifier 创建告警通知器
+func NewNotifier(cfg Config) *Notifier {
+	return &Notifier{
+		cfg:    cfg,
+		active: make(map[string]*activeAlert),
+		now:    time.Now,
+	}
+}
+
+// Configure 更新告警配置
+func (n *Notifier) Configure(cfg Config) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.cfg = cfg
+}
+
+// Notify 发送告警
+// 去重窗口内重复的同级别告警会被忽略；级别升级时立即发送
+func (n *Notifier) Notify(ctx context.Context, client Sender, severity Severity, title, body string, opts ...Option) error {
+	fingerprint := buildFingerprint(title, opts)
+
+	n.mu.Lock()
+	routes := n.cfg.Routes[severity]
+	window := n.dedupeWindow()
+	prev, ok := n.active[fingerprint]
+	n.mu.Unlock()
+
+	if len(routes) == 0 {
+		return fmt.Errorf("no route configured for severity %s", severity)
+	}
+	if ok && severity <= prev.severity && n.now().Sub(prev.sentAt) < window {
+		return nil
+	}
+
+	// 至少一个目标发送成功即视为已告警，避免部分失败时重复轰炸其他目标
+	sent, err := n.send(ctx, client, routes, severity, StatusFiring, title, body)
+	if sent > 0 {
+		n.mu.Lock()
+		n.active[fingerprint] = &activeAlert{severity: severity, sentAt: n.now()}
+		n.mu.Unlock()
+	}
+
+	return err
+}
+
+// Resolve 发送恢复通知，投递到告警发出时的级别对应的目标
+// 指纹不处于告警中时不发送
+func (n *Notifier) Resolve(ctx context.Context, client Sender, title, body string, opts ...Option) error {
+	fingerprint := buildFingerprint(title, opts)
+
+	n.mu.Lock()
+	prev, ok := n.active[fingerprint]
+	routes := n.cfg.Routes[severityOf(prev)]
+	n.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	sent, err := n.send(ctx, client, routes, prev.severity, StatusResolved, title, body)
+	if sent > 0 {
+		n.mu.Lock()
+		delete(n.active, fingerprint)
+		n.mu.Unlock()
+	}
+
+	return err
+}
+
+// send 向所有目标发送告警，返回成功的目标数和各目标的错误
+func (n *Notifier) send(ctx context.Context, client Sender, routes []Route, severity Severity, status, title, body string) (int, error) {
+	sent := 0
+	var errs []error
+	for _, route := range routes {
+		req := &mlievpush.SendBatchRequest{
+			ChannelID:     route.ChannelID,
+			SignatureName: route.SignatureName,
+			Receivers:     route.Receivers,
+			TemplateParams: map[string]interface{}{
+				"title":    title,
+				"body":     body,
+				"severity": severity.String(),
+				"status":   status,
+			},
+		}
+		if _, err := client.SendBatch(ctx, req); err != nil {
+			errs = append(errs, fmt.Errorf("send alert to channel %d: %w", route.ChannelID, err))
+			continue
+		}
+		sent++
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// dedupeWindow 获取去重窗口（调用方需持有锁）
+func (n *Notifier) dedupeWindow() time.Duration {
+	if n.cfg.DedupeWindow > 0 {
+		return n.cfg.DedupeWindow
+	}
+	return DefaultDedupeWindow
+}
+
+// severityOf 获取记录的告警级别
+func severityOf(a *activeAlert) Severity {
+	if a == nil {
+		return SeverityInfo
+	}
+	return a.severity
+}
+
+// buildFingerprint 计算告警指纹
+func buildFingerprint(title string, opts []Option) string {
+	o := &notifyOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.fingerprint != "" {
+		return o.fingerprint
+	}
+
+	sum := sha256.Sum256([]byte(title))
+	return hex.EncodeToString(sum[:8])
+}
+
+// defaultNotifier 包级别默认通知器
+var defaultNotifier = NewNotifier(Config{})
+
+// Configure 配置默认通知器
+func Configure(cfg Config) {
+	defaultNotifier.Configure(cfg)
+}
+
+// Notify 使用默认通知器发送告警
+func Notify(ctx context.Context, client Sender, severity Severity, title, body string, opts ...Option) error {
+	return defaultNotifier.Notify(ctx, client, severity, title, body, opts...)
+}
+
+// Resolve 使用默认通知器发送恢复通知
+func Resolve(ctx context.Context, client Sender, title, body string, opts ...Option) error {
+	return defaultNotifier.Resolve(ctx, client, title, body, opts...)
+}
@@ -0,0 +1,138 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// fakeSender 记录发送请求的测试客户端
+type fakeSender struct {
+	requests []*mlievpush.SendBatchRequest
+	err      error
+}
+
+func (f *fakeSender) SendBatch(ctx context.Context, req *mlievpush.SendBatchRequest, opts ...mlievpush.CallOption) (*mlievpush.SendBatchData, error) {
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &mlievpush.SendBatchData{TotalCount: len(req.Receivers)}, nil
+}
+
+func testConfig() Config {
+	return Config{
+		Routes: map[Severity][]Route{
+			SeverityCritical: {
+				{ChannelID: 1, SignatureName: "【运维】", Receivers: []string{"13800138000"}},
+				{ChannelID: 2, SignatureName: "【运维】", Receivers: []string{"13800138000"}},
+			},
+			SeverityWarning: {{ChannelID: 3, Receivers: []string{"ops-group"}}},
+			SeverityInfo:    {{ChannelID: 4, Receivers: []string{"ops@example.com"}}},
+		},
+		DedupeWindow: time.Minute,
+	}
+}
+
+// TestNotifyRouting 测试按级别路由
+func TestNotifyRouting(t *testing.T) {
+	sender := &fakeSender{}
+	n := NewNotifier(testConfig())
+
+	if err := n.Notify(context.Background(), sender, SeverityCritical, "db down", "primary unreachable"); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if len(sender.requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(sender.requests))
+	}
+	if sender.requests[0].ChannelID != 1 || sender.requests[1].ChannelID != 2 {
+		t.Errorf("unexpected channels: %d, %d", sender.requests[0].ChannelID, sender.requests[1].ChannelID)
+	}
+	if sender.requests[0].TemplateParams["severity"] != "critical" {
+		t.Errorf("severity param = %v, want critical", sender.requests[0].TemplateParams["severity"])
+	}
+}
+
+// TestNotifyDedupe 测试去重与级别升级
+func TestNotifyDedupe(t *testing.T) {
+	sender := &fakeSender{}
+	n := NewNotifier(testConfig())
+	now := time.Now()
+	n.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	n.Notify(ctx, sender, SeverityWarning, "disk", "90%")
+	n.Notify(ctx, sender, SeverityWarning, "disk", "91%")
+	if len(sender.requests) != 1 {
+		t.Fatalf("duplicate alert should be suppressed, requests = %d", len(sender.requests))
+	}
+
+	// 级别升级立即发送
+	n.Notify(ctx, sender, SeverityCritical, "disk", "99%")
+	if len(sender.requests) != 3 {
+		t.Fatalf("escalated alert should be sent, requests = %d", len(sender.requests))
+	}
+
+	// 超出窗口后再次发送
+	now = now.Add(2 * time.Minute)
+	n.Notify(ctx, sender, SeverityCritical, "disk", "99%")
+	if len(sender.requests) != 5 {
+		t.Fatalf("alert after window should be sent, requests = %d", len(sender.requests))
+	}
+}
+
+// TestResolve 测试恢复通知
+func TestResolve(t *testing.T) {
+	sender := &fakeSender{}
+	n := NewNotifier(testConfig())
+	ctx := context.Background()
+
+	// 未告警时不发送
+	if err := n.Resolve(ctx, sender, "api latency", "ok"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(sender.requests) != 0 {
+		t.Fatalf("requests = %d, want 0", len(sender.requests))
+	}
+
+	n.Notify(ctx, sender, SeverityWarning, "api latency", "p99 2s", WithFingerprint("latency"))
+	n.Resolve(ctx, sender, "api latency", "p99 200ms", WithFingerprint("latency"))
+	if len(sender.requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(sender.requests))
+	}
+	resolved := sender.requests[1]
+	if resolved.ChannelID != 3 || resolved.TemplateParams["status"] != StatusResolved {
+		t.Errorf("unexpected resolve request: %+v", resolved)
+	}
+
+	// 恢复后再次告警不受去重影响
+	n.Notify(ctx, sender, SeverityWarning, "api latency", "p99 2s", WithFingerprint("latency"))
+	if len(sender.requests) != 3 {
+		t.Errorf("requests = %d, want 3", len(sender.requests))
+	}
+}
+
+// TestNotifyErrors 测试错误处理
+func TestNotifyErrors(t *testing.T) {
+	n := NewNotifier(testConfig())
+	ctx := context.Background()
+
+	if err := n.Notify(ctx, &fakeSender{}, Severity(9), "x", "y"); err == nil {
+		t.Error("expected error for unrouted severity")
+	}
+
+	sendErr := errors.New("gateway down")
+	failing := &fakeSender{err: sendErr}
+	if err := n.Notify(ctx, failing, SeverityInfo, "x", "y"); !errors.Is(err, sendErr) {
+		t.Fatalf("Notify() error = %v, want %v", err, sendErr)
+	}
+
+	// 发送失败不计入去重，下次仍会尝试
+	n.Notify(ctx, failing, SeverityInfo, "x", "y")
+	if len(failing.requests) != 2 {
+		t.Errorf("requests = %d, want 2", len(failing.requests))
+	}
+}
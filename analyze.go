@@ -0,0 +1,124 @@
+package mlievpush
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReceiverKind 推断的接收者类型
+type ReceiverKind string
+
+// 接收者类型
+const (
+	ReceiverKindMobile ReceiverKind = "mobile" // 手机号
+	ReceiverKindEmail  ReceiverKind = "email"  // 邮箱地址
+	ReceiverKindURL    ReceiverKind = "url"    // Webhook 地址
+	ReceiverKindOther  ReceiverKind = "other"  // 其他（用户ID、设备令牌等）
+)
+
+// ReceiverIssue 接收者列表中的问题条目
+type ReceiverIssue struct {
+	Index    int          // 在列表中的位置（从0开始）
+	Receiver string       // 原始接收者
+	Kind     ReceiverKind // 推断的类型
+	Reason   string       // 问题说明
+}
+
+// ReceiverReport 接收者列表分析报告
+type ReceiverReport struct {
+	Total      int                  // 条目总数
+	Unique     int                  // 去重后的有效条目数
+	Kinds      map[ReceiverKind]int // 推断类型 -> 数量（不含无效条目）
+	Countries  map[string]int       // 手机号地区（ISO 3166-1 alpha-2）-> 数量，无国际前缀的号码计入 ""
+	Invalid    []ReceiverIssue      // 格式无效的条目
+	Duplicates []ReceiverIssue      // 重复的条目（首次出现的条目不计入）
+}
+
+// OK 判断列表是否没有无效和重复的条目
+func (r *ReceiverReport) OK() bool {
+	return len(r.Invalid) == 0 && len(r.Duplicates) == 0
+}
+
+// MixedKinds 判断列表是否混合了多种接收者类型（如手机号中混入邮箱）
+func (r *ReceiverReport) MixedKinds() bool {
+	return len(r.Kinds) > 1
+}
+
+// AnalyzeReceivers 分析接收者列表：推断每个条目的类型，找出格式无效和重复的条目，并统计手机号的地区分布
+// 用于在管理后台创建活动前校验上传的名单，不会发送任何请求
+func AnalyzeReceivers(receivers []string) *ReceiverReport {
+	report := &ReceiverReport{
+		Total:     len(receivers),
+		Kinds:     make(map[ReceiverKind]int),
+		Countries: make(map[string]int),
+	}
+
+	seen := make(map[string]int, len(receivers))
+	for i, receiver := range receivers {
+		kind := InferReceiverKind(receiver)
+		key, err := analyzeReceiver(kind, receiver)
+		if err != nil {
+			report.Invalid = append(report.Invalid, ReceiverIssue{Index: i, Receiver: receiver, Kind: kind, Reason: err.Error()})
+			continue
+		}
+		if first, ok := seen[key]; ok {
+			report.Duplicates = append(report.Duplicates, ReceiverIssue{Index: i, Receiver: receiver, Kind: kind, Reason: fmt.Sprintf("duplicate of entry %d", first)})
+			continue
+		}
+		seen[key] = i
+
+		report.Unique++
+		report.Kinds[kind]++
+		if kind == ReceiverKindMobile {
+			_, region, _ := DetectCountry(receiver)
+			report.Countries[region]++
+		}
+	}
+	return report
+}
+
+// InferReceiverKind 根据格式推断接收者类型
+func InferReceiverKind(receiver string) ReceiverKind {
+	s := strings.TrimSpace(receiver)
+	switch {
+	case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+		return ReceiverKindURL
+	case strings.Contains(s, "@"):
+		return ReceiverKindEmail
+	case s != "" && strings.Trim(phoneSeparators.Replace(s), "+0123456789") == "":
+		return ReceiverKindMobile
+	default:
+		return ReceiverKindOther
+	}
+}
+
+// analyzeReceiver 校验接收者并返回用于去重的规范化键
+func analyzeReceiver(kind ReceiverKind, receiver string) (string, error) {
+	s := strings.TrimSpace(receiver)
+	if s == "" {
+		return "", fmt.Errorf("empty receiver")
+	}
+	switch kind {
+	case ReceiverKindMobile:
+		digits := phoneSeparators.Replace(s)
+		if strings.HasPrefix(digits, "00") {
+			digits = "+" + digits[2:]
+		}
+		if !phoneNumberPattern.MatchString(digits) {
+			return "", fmt.Errorf("not a valid phone number")
+		}
+		return digits, nil
+	case ReceiverKindEmail:
+		if err := validateEmail(s); err != nil {
+			return "", err
+		}
+		return strings.ToLower(s), nil
+	case ReceiverKindURL:
+		if err := validateWebhookURL(s); err != nil {
+			return "", err
+		}
+		return s, nil
+	default:
+		return s, nil
+	}
+}
@@ -0,0 +1,42 @@
+package mlievpush
+
+import "testing"
+
+// TestAnalyzeReceivers 测试接收者列表分析
+func TestAnalyzeReceivers(t *testing.T) {
+	report := AnalyzeReceivers([]string{
+		"13800138000",
+		"138-0013-8000", // 重复
+		"+8613900139000",
+		"+14155550123",
+		"alice@example.com",
+		"Alice@Example.com", // 重复（邮箱不区分大小写）
+		"bob@",              // 无效
+		"12",                // 无效
+		"",                  // 无效
+		"user_42",
+	})
+
+	if report.Total != 10 || report.Unique != 5 {
+		t.Errorf("Total = %d, Unique = %d", report.Total, report.Unique)
+	}
+	if len(report.Invalid) != 3 || report.Invalid[0].Index != 6 {
+		t.Errorf("Invalid = %+v", report.Invalid)
+	}
+	if len(report.Duplicates) != 2 || report.Duplicates[0].Index != 1 || report.Duplicates[1].Index != 5 {
+		t.Errorf("Duplicates = %+v", report.Duplicates)
+	}
+	if report.Kinds[ReceiverKindMobile] != 3 || report.Kinds[ReceiverKindEmail] != 1 || report.Kinds[ReceiverKindOther] != 1 {
+		t.Errorf("Kinds = %v", report.Kinds)
+	}
+	if report.Countries["CN"] != 1 || report.Countries["US"] != 1 || report.Countries[""] != 1 {
+		t.Errorf("Countries = %v", report.Countries)
+	}
+	if report.OK() || !report.MixedKinds() {
+		t.Errorf("OK() = %v, MixedKinds() = %v", report.OK(), report.MixedKinds())
+	}
+
+	if got := InferReceiverKind("https://hooks.example.com/x"); got != ReceiverKindURL {
+		t.Errorf("InferReceiverKind(url) = %s", got)
+	}
+}
@@ -0,0 +1,141 @@
+package mlievpush
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxAttachmentSize 默认单个附件大小上限（与网关10MB限制一致）
+const DefaultMaxAttachmentSize = 10 << 20
+
+// Attachment 消息附件
+type Attachment struct {
+	ID          string `json:"id,omitempty"`           // 已上传附件ID（通过 UploadAttachment 获取，设置后无需填写 Content）
+	Filename    string `json:"filename"`               // 文件名（必填）
+	ContentType string `json:"content_type,omitempty"` // MIME类型（为空时根据内容自动识别）
+	Content     []byte `json:"content,omitempty"`      // 文件内容（JSON中为Base64编码）
+}
+
+// AttachmentLimit 附件限制
+type AttachmentLimit struct {
+	MaxSize      int64    `json:"max_size,omitempty"`       // 单个附件大小上限（字节），0表示不限制
+	MaxTotalSize int64    `json:"max_total_size,omitempty"` // 附件总大小上限（字节），0表示不限制
+	MaxCount     int      `json:"max_count,omitempty"`      // 附件数量上限，0表示不限制
+	AllowedTypes []string `json:"allowed_types,omitempty"`  // 允许的MIME类型，支持 "image/*" 形式的通配，为空表示不限制
+}
+
+// DefaultAttachmentLimit 未单独配置通道时使用的附件限制
+var DefaultAttachmentLimit = AttachmentLimit{
+	MaxSize: DefaultMaxAttachmentSize,
+}
+
+// AttachmentError 附件校验错误（本地校验，不会发送请求）
+type AttachmentError struct {
+	Filename string // 附件文件名
+	Reason   string // 错误原因
+}
+
+// Error 实现 error 接口
+func (e *AttachmentError) Error() string {
+	if e.Filename == "" {
+		return fmt.Sprintf("invalid attachments: %s", e.Reason)
+	}
+	return fmt.Sprintf("invalid attachment %q: %s", e.Filename, e.Reason)
+}
+
+// WithAttachmentLimit 设置指定通道的附件限制
+func WithAttachmentLimit(channelID int, limit AttachmentLimit) ClientOption {
+	return func(c *Client) {
+		c.attachmentLimits[channelID] = limit
+	}
+}
+
+// WithDefaultAttachmentLimit 设置未单独配置通道时的附件限制
+func WithDefaultAttachmentLimit(limit AttachmentLimit) ClientOption {
+	return func(c *Client) {
+		c.defaultAttachmentLimit = limit
+	}
+}
+
+// attachmentLimit 获取通道对应的附件限制
+func (c *Client) attachmentLimit(channelID int) AttachmentLimit {
+	if limit, ok := c.attachmentLimits[channelID]; ok {
+		return limit
+	}
+	return c.defaultAttachmentLimit
+}
+
+// Validate 按限制校验附件列表，返回 *AttachmentError
+func (l AttachmentLimit) Validate(attachments []Attachment) error {
+	if l.MaxCount > 0 && len(attachments) > l.MaxCount {
+		return &AttachmentError{
+			Reason: fmt.Sprintf("too many attachments: %d > %d", len(attachments), l.MaxCount),
+		}
+	}
+
+	var total int64
+	for _, att := range attachments {
+		if att.Filename == "" {
+			return &AttachmentError{Reason: "missing filename"}
+		}
+		// 引用已上传的附件，大小和类型已在上传时校验
+		if att.ID != "" && len(att.Content) == 0 {
+			continue
+		}
+
+		size := int64(len(att.Content))
+		if l.MaxSize > 0 && size > l.MaxSize {
+			return &AttachmentError{
+				Filename: att.Filename,
+				Reason:   fmt.Sprintf("size %d bytes exceeds limit of %d bytes", size, l.MaxSize),
+			}
+		}
+		total += size
+
+		if len(l.AllowedTypes) > 0 {
+			contentType := attachmentContentType(att)
+			if !matchContentType(contentType, l.AllowedTypes) {
+				return &AttachmentError{
+					Filename: att.Filename,
+					Reason:   fmt.Sprintf("content type %q is not allowed", contentType),
+				}
+			}
+		}
+	}
+
+	if l.MaxTotalSize > 0 && total > l.MaxTotalSize {
+		return &AttachmentError{
+			Reason: fmt.Sprintf("total size %d bytes exceeds limit of %d bytes", total, l.MaxTotalSize),
+		}
+	}
+
+	return nil
+}
+
+// attachmentContentType 获取附件的MIME类型（去除参数部分）
+func attachmentContentType(att Attachment) string {
+	contentType := att.ContentType
+	if contentType == "" {
+		contentType = http.DetectContentType(att.Content)
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(contentType)
+}
+
+// matchContentType 判断MIME类型是否在允许列表中
+func matchContentType(contentType string, allowed []string) bool {
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == contentType {
+			return true
+		}
+		if strings.HasSuffix(pattern, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
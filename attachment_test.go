@@ -0,0 +1,118 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAttachmentLimitValidate 测试附件限制校验
+func TestAttachmentLimitValidate(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+
+	tests := []struct {
+		name        string
+		limit       AttachmentLimit
+		attachments []Attachment
+		wantErr     bool
+	}{
+		{
+			name:        "无附件",
+			limit:       DefaultAttachmentLimit,
+			attachments: nil,
+			wantErr:     false,
+		},
+		{
+			name:        "大小合法",
+			limit:       AttachmentLimit{MaxSize: 16},
+			attachments: []Attachment{{Filename: "a.png", Content: png}},
+			wantErr:     false,
+		},
+		{
+			name:        "超出单个大小",
+			limit:       AttachmentLimit{MaxSize: 4},
+			attachments: []Attachment{{Filename: "a.png", Content: png}},
+			wantErr:     true,
+		},
+		{
+			name:  "超出总大小",
+			limit: AttachmentLimit{MaxTotalSize: 20},
+			attachments: []Attachment{
+				{Filename: "a.png", Content: png},
+				{Filename: "b.png", Content: png},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "超出数量",
+			limit:       AttachmentLimit{MaxCount: 1},
+			attachments: []Attachment{{Filename: "a.png", Content: png}, {Filename: "b.png", Content: png}},
+			wantErr:     true,
+		},
+		{
+			name:        "通配类型自动识别",
+			limit:       AttachmentLimit{AllowedTypes: []string{"image/*"}},
+			attachments: []Attachment{{Filename: "a.png", Content: png}},
+			wantErr:     false,
+		},
+		{
+			name:        "类型不允许",
+			limit:       AttachmentLimit{AllowedTypes: []string{"application/pdf"}},
+			attachments: []Attachment{{Filename: "a.txt", ContentType: "text/plain; charset=utf-8", Content: []byte("hi")}},
+			wantErr:     true,
+		},
+		{
+			name:        "缺少文件名",
+			limit:       DefaultAttachmentLimit,
+			attachments: []Attachment{{Content: png}},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limit.Validate(tt.attachments)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var attErr *AttachmentError
+			if err != nil && !errors.As(err, &attErr) {
+				t.Errorf("expected AttachmentError, got %T", err)
+			}
+		})
+	}
+}
+
+// TestSendMessageAttachmentTooLarge 测试超限附件不会发起请求
+func TestSendMessageAttachmentTooLarge(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithAttachmentLimit(2, AttachmentLimit{MaxSize: 8}),
+	)
+
+	req := &SendMessageRequest{
+		ChannelID:     2,
+		SignatureName: "【测试签名】",
+		Receiver:      "user@example.com",
+		Attachments:   []Attachment{{Filename: "report.pdf", Content: make([]byte, 9)}},
+	}
+
+	_, err := client.SendMessage(context.Background(), req)
+	var attErr *AttachmentError
+	if !errors.As(err, &attErr) {
+		t.Fatalf("expected AttachmentError, got %v", err)
+	}
+	if attErr.Filename != "report.pdf" {
+		t.Errorf("Filename = %v, want %v", attErr.Filename, "report.pdf")
+	}
+	if called {
+		t.Error("request should not be sent when attachment validation fails")
+	}
+}
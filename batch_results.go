@@ -0,0 +1,261 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 批量发送逐条结果状态
+const (
+	BatchResultAccepted = "accepted" // 已入队，TaskID 可用于查询和回调关联
+	BatchResultRejected = "rejected" // 被网关立即拒绝（格式错误、黑名单等），Code/Reason 说明原因
+)
+
+// BatchResult 批量发送中单个接收者的结果（SendBatchDetailed 返回）
+type BatchResult struct {
+	Receiver string `json:"receiver"`          // 接收者
+	Status   string `json:"status"`            // 结果状态，见 BatchResult* 常量
+	TaskID   string `json:"task_id,omitempty"` // 任务ID（已入队时）
+	Code     int    `json:"code,omitempty"`    // 错误码（被拒绝时）
+	Reason   string `json:"reason,omitempty"`  // 拒绝原因（被拒绝时）
+}
+
+// Accepted 判断接收者是否已入队
+func (r *BatchResult) Accepted() bool {
+	return r.Status == BatchResultAccepted
+}
+
+// ResultFor 按接收者查找逐条结果，网关未返回逐条结果或接收者不在批次中时返回 false
+// 同一接收者在批次中出现多次时返回第一条
+func (d *SendBatchData) ResultFor(receiver string) (*BatchResult, bool) {
+	for i := range d.Results {
+		if d.Results[i].Receiver == receiver {
+			return &d.Results[i], true
+		}
+	}
+	return nil, false
+}
+
+// TaskIDs 返回已入队接收者到任务ID的映射，便于回调到达时关联回业务记录
+func (d *SendBatchData) TaskIDs() map[string]string {
+	ids := make(map[string]string, len(d.Results))
+	for _, r := range d.Results {
+		if r.Accepted() && r.TaskID != "" {
+			if _, ok := ids[r.Receiver]; !ok {
+				ids[r.Receiver] = r.TaskID
+			}
+		}
+	}
+	return ids
+}
+
+// Rejected 返回被网关立即拒绝的逐条结果
+func (d *SendBatchData) Rejected() []BatchResult {
+	var rejected []BatchResult
+	for _, r := range d.Results {
+		if !r.Accepted() {
+			rejected = append(rejected, r)
+		}
+	}
+	return rejected
+}
+
+// SendBatchDetailed 批量发送消息并返回每个接收者的任务ID和立即拒绝原因（SendBatchData.Results），
+// 便于把失败映射回手机号；校验、分片、部分失败错误等行为与 SendBatch 相同
+// 网关不支持逐条结果时返回 *NotSupportedError（开启 WithCapabilityDiscovery 时在发送前判断）
+func (c *Client) SendBatchDetailed(ctx context.Context, req *SendBatchRequest, opts ...CallOption) (*SendBatchData, error) {
+	if err := c.requireFeature(ctx, FeatureBatchResults); err != nil {
+		return nil, err
+	}
+	detailed := make([]CallOption, 0, len(opts)+1)
+	detailed = append(detailed, opts...)
+	detailed = append(detailed, func(o *callOptions) { o.batchDetail = true })
+	return c.SendBatch(ctx, req, detailed...)
+}
+
+// batchPath 批量发送接口路径，请求逐条结果时附加 detail 参数
+func batchPath(ctx context.Context) string {
+	if o := callOptionsFrom(ctx); o != nil && o.batchDetail {
+		return "/api/v1/messages/batch?detail=true"
+	}
+	return "/api/v1/messages/batch"
+}
+
+// fillFailedReceivers 网关只返回逐条结果时，根据被拒绝的条目补全 FailedReceivers
+func fillFailedReceivers(data *SendBatchData) {
+	if len(data.FailedReceivers) > 0 {
+		return
+	}
+	for _, r := range data.Rejected() {
+		data.FailedReceivers = append(data.FailedReceivers, FailedReceiver{Receiver: r.Receiver, Code: r.Code, Reason: r.Reason})
+	}
+}
+
+// BatchStreamError 逐条结果已开始回调后处理中断（响应解析失败、连接中断或回调返回错误），不会自动重试（避免重复发送）
+type BatchStreamError struct {
+	Delivered int   // 中断前已回调的结果数（自动分片时为所有分片的累计数）
+	Err       error // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *BatchStreamError) Error() string {
+	return fmt.Sprintf("batch result stream interrupted after %d results: %v", e.Delivered, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *BatchStreamError) Unwrap() error {
+	return e.Err
+}
+
+// SendBatchStream 批量发送消息，边解析响应边对每个接收者的结果调用 onResult，不在内存中保留逐条结果，
+// 百万级接收者的批次内存占用保持平稳；返回的 SendBatchData 只包含计数和被拒绝的接收者（FailedReceivers），Results 为空
+// onResult 返回错误或发生panic（按 WithPanicRecovery 配置捕获，作为 *PanicError）时停止解析并返回 *BatchStreamError；校验、分片、部分失败错误等行为与 SendBatch 相同
+func (c *Client) SendBatchStream(ctx context.Context, req *SendBatchRequest, onResult func(BatchResult) error, opts ...CallOption) (*SendBatchData, error) {
+	stream := &batchResultStream{onResult: func(r BatchResult) error {
+		return c.safeCallErr(ctx, "onResult", func() error { return onResult(r) })
+	}}
+	ctx = withResponseStreamer(ctx, stream)
+	return c.SendBatchDetailed(ctx, req, opts...)
+}
+
+// batchResultStream 流式解析批量发送响应中的逐条结果
+type batchResultStream struct {
+	onResult  func(BatchResult) error
+	delivered int // 已回调的结果数
+}
+
+// streams 只流式处理成功的响应，错误响应按常规方式解析
+func (s *batchResultStream) streams(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusOK
+}
+
+// consume 解析响应信封，逐条回调 data.results，其余字段保留在 Data 中
+func (s *batchResultStream) consume(resp *http.Response) (*Response, error) {
+	dec := json.NewDecoder(resp.Body)
+	var result Response
+	err := decodeObject(dec, func(key string) error {
+		switch key {
+		case "code":
+			return dec.Decode(&result.Code)
+		case "message":
+			return dec.Decode(&result.Message)
+		case "data":
+			data, err := s.decodeData(dec)
+			result.Data = data
+			return err
+		default:
+			var skip json.RawMessage
+			return dec.Decode(&skip)
+		}
+	})
+	if err != nil {
+		return nil, s.fail(fmt.Errorf("decode batch response: %w", err))
+	}
+	return &result, nil
+}
+
+// decodeData 解析 data 对象：逐条回调 results，被拒绝的条目写入 failed_receivers（网关未返回时）
+func (s *batchResultStream) decodeData(dec *json.Decoder) (json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	var failed []FailedReceiver
+	err := decodeObject(dec, func(key string) error {
+		if key != "results" {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			fields[key] = raw
+			return nil
+		}
+		return decodeArray(dec, func() error {
+			var r BatchResult
+			if err := dec.Decode(&r); err != nil {
+				return err
+			}
+			if !r.Accepted() {
+				failed = append(failed, FailedReceiver{Receiver: r.Receiver, Code: r.Code, Reason: r.Reason})
+			}
+			if err := s.onResult(r); err != nil {
+				return &BatchStreamError{Delivered: s.delivered, Err: err}
+			}
+			s.delivered++
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fields["failed_receivers"]; !ok && len(failed) > 0 {
+		raw, err := json.Marshal(failed)
+		if err != nil {
+			return nil, err
+		}
+		fields["failed_receivers"] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// fail 已回调过结果时包装为 *BatchStreamError
+func (s *batchResultStream) fail(err error) error {
+	var streamErr *BatchStreamError
+	if errors.As(err, &streamErr) {
+		return streamErr
+	}
+	if s.delivered == 0 {
+		return err
+	}
+	return &BatchStreamError{Delivered: s.delivered, Err: err}
+}
+
+// decodeObject 逐个读取 JSON 对象的键，由 field 解析对应的值；null 视为空对象
+func decodeObject(dec *json.Decoder, field func(key string) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected object, got %v", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, got %v", tok)
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// decodeArray 逐个读取 JSON 数组的元素，由 elem 解析；null 视为空数组
+func decodeArray(dec *json.Decoder, elem func() error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected array, got %v", tok)
+	}
+	for dec.More() {
+		if err := elem(); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
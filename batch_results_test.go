@@ -0,0 +1,147 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestSendBatchDetailed 测试批量发送逐条结果
+func TestSendBatchDetailed(t *testing.T) {
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.Verify(r); err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+		query = r.URL.RawQuery
+		var req SendBatchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		results := make([]BatchResult, 0, len(req.Receivers))
+		for i, receiver := range req.Receivers {
+			if receiver == "13800138002" {
+				results = append(results, BatchResult{Receiver: receiver, Status: BatchResultRejected, Code: ErrCodeInvalidReceiver, Reason: "空号"})
+				continue
+			}
+			results = append(results, BatchResult{Receiver: receiver, Status: BatchResultAccepted, TaskID: "t" + string(rune('0'+i))})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{
+			"batch_id": "b1", "total_count": len(results), "success_count": len(results) - 1, "failed_count": 1, "results": results,
+		}})
+	}))
+	defer server.Close()
+
+	req := &SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000", "13800138001", "13800138002"}}
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithPartialFailureError(true))
+
+	data, err := client.SendBatchDetailed(context.Background(), req)
+	if query != "detail=true" {
+		t.Errorf("query = %q", query)
+	}
+	var partialErr *PartialFailureError
+	if !errors.As(err, &partialErr) || len(partialErr.Failed) != 1 || partialErr.Failed[0].Reason != "空号" {
+		t.Fatalf("SendBatchDetailed() error = %v", err)
+	}
+	if ids := data.TaskIDs(); len(ids) != 2 || ids["13800138001"] != "t1" {
+		t.Errorf("TaskIDs() = %v", ids)
+	}
+	if r, ok := data.ResultFor("13800138002"); !ok || r.Accepted() || r.Code != ErrCodeInvalidReceiver {
+		t.Errorf("ResultFor() = %+v, %v", r, ok)
+	}
+	if rejected := data.Rejected(); len(rejected) != 1 {
+		t.Errorf("Rejected() = %+v", rejected)
+	}
+
+	// 自动分片时合并各分片的逐条结果
+	client = NewClient(server.URL, "test_app_id", "test_secret", WithBatchChunkSize(2))
+	data, err = client.SendBatchDetailed(context.Background(), req)
+	if err != nil || len(data.Results) != 3 || len(data.FailedReceivers) != 1 {
+		t.Errorf("chunked SendBatchDetailed() = %+v, %v", data, err)
+	}
+
+	if _, err := client.SendBatch(context.Background(), req); err != nil || query != "" {
+		t.Errorf("SendBatch() query = %q, err = %v", query, err)
+	}
+}
+
+// TestSendBatchStream 测试流式解析逐条结果
+func TestSendBatchStream(t *testing.T) {
+	const total = 20000
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"code":0,"message":"success","data":{"batch_id":"b1","total_count":%d,"success_count":%d,"failed_count":1,"results":[`, total, total-1)
+		for i := 0; i < total; i++ {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			if i == 7 {
+				fmt.Fprintf(w, `{"receiver":"r%d","status":"rejected","code":%d,"reason":"空号"}`, i, ErrCodeInvalidReceiver)
+				continue
+			}
+			fmt.Fprintf(w, `{"receiver":"r%d","status":"accepted","task_id":"t%d"}`, i, i)
+		}
+		w.Write([]byte(`],"created_at":"2025-11-25T10:00:00Z"}}`))
+	}))
+	defer server.Close()
+
+	receivers := make([]string, total)
+	for i := range receivers {
+		receivers[i] = fmt.Sprintf("r%d", i)
+	}
+	req := &SendBatchRequest{ChannelID: 1, Receivers: receivers}
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithRetry(3, Backoff{}))
+
+	var accepted int
+	data, err := client.SendBatchStream(context.Background(), req, func(r BatchResult) error {
+		if r.Accepted() {
+			accepted++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SendBatchStream() error = %v", err)
+	}
+	if accepted != total-1 || data.TotalCount != total || data.BatchID != "b1" || data.CreatedAt == "" || len(data.Results) != 0 {
+		t.Errorf("accepted = %d, data = %+v", accepted, data.FailedReceivers)
+	}
+	if len(data.FailedReceivers) != 1 || data.FailedReceivers[0].Receiver != "r7" {
+		t.Errorf("FailedReceivers = %+v", data.FailedReceivers)
+	}
+
+	// 回调返回错误时停止解析且不重试
+	calls.Store(0)
+	stop := errors.New("stop")
+	_, err = client.SendBatchStream(context.Background(), req, func(r BatchResult) error {
+		if r.Receiver == "r100" {
+			return stop
+		}
+		return nil
+	})
+	var streamErr *BatchStreamError
+	if !errors.As(err, &streamErr) || streamErr.Delivered != 100 || !errors.Is(err, stop) {
+		t.Errorf("expected BatchStreamError, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+
+	// 回调panic被捕获并作为错误返回
+	_, err = client.SendBatchStream(context.Background(), req, func(r BatchResult) error {
+		if r.Receiver == "r3" {
+			panic("boom")
+		}
+		return nil
+	})
+	var panicErr *PanicError
+	if !errors.As(err, &streamErr) || streamErr.Delivered != 3 || !errors.As(err, &panicErr) || panicErr.Callback != "onResult" {
+		t.Errorf("expected BatchStreamError wrapping PanicError, got %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// BatchSender 默认配置
+const (
+	DefaultBatchSenderWorkers       = 4           // 默认 worker 数
+	DefaultBatchSenderMaxBatchSize  = 100         // 单次批量发送的默认最大接收者数
+	DefaultBatchSenderFlushInterval = time.Second // 默认最长等待时间
+	DefaultBatchSenderQueueSize     = 1000        // 默认队列容量
+)
+
+// ErrBatchSenderClosed BatchSender 已关闭，不再接受新消息
+var ErrBatchSenderClosed = errors.New("mlievpush: batch sender closed")
+
+// BatchSenderOptions 异步批量发送配置，零值字段使用默认值
+type BatchSenderOptions struct {
+	Workers       int                   // 并发发送的 worker 数
+	MaxBatchSize  int                   // 单次批量发送的最大接收者数，凑满后立即发送
+	FlushInterval time.Duration         // 未凑满的分组最长等待时间
+	QueueSize     int                   // 队列容量，队列满时 Enqueue 阻塞
+	OnResult      func(BatchSendResult) // 每条消息的发送结果回调（可能在多个 worker 中并发调用），可为nil
+}
+
+// BatchSendResult 异步批量发送中单条消息的结果
+type BatchSendResult struct {
+	Request *SendMessageRequest // 入队的消息
+	TaskID  string              // 任务ID（分组只有一条消息、按单条发送时）
+	BatchID string              // 批次ID（按批量发送时）
+	Err     error               // 发送失败的错误；批量部分失败时为 *PartialFailureError，网关返回明细时只有失败的接收者有错误
+}
+
+// BatchSender 异步批量发送器：消息通过 Enqueue 入队，按通道、签名、模板参数等相同的字段分组，
+// 由 worker 池合并为 SendBatch 调用发送，并通过 OnResult 回调每条消息的结果
+type BatchSender struct {
+	client *Client
+	opts   BatchSenderOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *SendMessageRequest
+	work   chan []*SendMessageRequest
+	done   chan struct{}
+}
+
+// NewBatchSender 创建并启动异步批量发送器，使用完毕后必须调用 Close
+func NewBatchSender(c *Client, opts BatchSenderOptions) *BatchSender {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultBatchSenderWorkers
+	}
+	if opts.MaxBatchSize <= 0 {
+		opts.MaxBatchSize = DefaultBatchSenderMaxBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultBatchSenderFlushInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultBatchSenderQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &BatchSender{
+		client: c,
+		opts:   opts,
+		ctx:    ctx,
+		cancel: cancel,
+		queue:  make(chan *SendMessageRequest, opts.QueueSize),
+		work:   make(chan []*SendMessageRequest, opts.Workers),
+		done:   make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range s.work {
+				s.send(group)
+			}
+		}()
+	}
+	go s.dispatch()
+	go func() {
+		wg.Wait()
+		close(s.done)
+	}()
+	return s
+}
+
+// Enqueue 将消息加入发送队列，队列满时阻塞直到有空位或 ctx 结束
+// 设置了 IdempotencyKey 的消息单独发送，以保留幂等语义
+func (s *BatchSender) Enqueue(ctx context.Context, req *SendMessageRequest) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrBatchSenderClosed
+	}
+	select {
+	case s.queue <- req:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close 停止接受新消息，发送队列中剩余的消息并等待完成
+// ctx 结束时取消正在进行和剩余的请求，这些消息以 context.Canceled 错误回调
+func (s *BatchSender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+		return ctx.Err()
+	}
+}
+
+// dispatch 从队列读取消息并分组，分组凑满或超过等待时间后交给 worker 发送
+func (s *BatchSender) dispatch() {
+	defer close(s.work)
+
+	groups := make(map[string][]*SendMessageRequest)
+	var order []string
+	flush := func() {
+		for _, key := range order {
+			s.work <- groups[key]
+		}
+		groups = make(map[string][]*SendMessageRequest)
+		order = nil
+	}
+
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case req, ok := <-s.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if req.IdempotencyKey != "" {
+				s.work <- []*SendMessageRequest{req}
+				continue
+			}
+			key := batchGroupKey(req)
+			if _, ok := groups[key]; !ok {
+				order = append(order, key)
+			}
+			groups[key] = append(groups[key], req)
+			if len(groups[key]) >= s.opts.MaxBatchSize {
+				s.work <- groups[key]
+				delete(groups, key)
+				for i, k := range order {
+					if k == key {
+						order = append(order[:i], order[i+1:]...)
+						break
+					}
+				}
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send 发送一个分组，只有一条消息时按单条发送
+func (s *BatchSender) send(group []*SendMessageRequest) {
+	if len(group) == 1 {
+		data, err := s.client.SendMessage(s.ctx, group[0])
+		result := BatchSendResult{Request: group[0], Err: err}
+		if data != nil {
+			result.TaskID = data.TaskID
+		}
+		s.report(result)
+		return
+	}
+
+	first := group[0]
+	req := &SendBatchRequest{
+		ChannelID:      first.ChannelID,
+		SignatureName:  first.SignatureName,
+		Receivers:      make([]string, len(group)),
+		CountryCode:    first.CountryCode,
+		Region:         first.Region,
+		TemplateParams: first.TemplateParams,
+		ScheduledAt:    first.ScheduledAt,
+		DeadlineAt:     first.DeadlineAt,
+		Attachments:    first.Attachments,
+		CallbackURL:    first.CallbackURL,
+	}
+	for i, m := range group {
+		req.Receivers[i] = m.Receiver
+	}
+
+	data, err := s.client.SendBatch(s.ctx, req)
+	failed, err := batchFailures(data, err)
+	for _, m := range group {
+		result := BatchSendResult{Request: m, Err: err}
+		if failed != nil {
+			result.Err = failed[m.Receiver]
+		}
+		if data != nil {
+			result.BatchID = data.BatchID
+		}
+		s.report(result)
+	}
+}
+
+// batchFailures 检查批量发送结果，无论是否开启 WithPartialFailureError，部分失败都作为 *PartialFailureError 返回
+// 网关返回了失败明细时另返回每个失败接收者的错误，只有这些接收者回调错误；未返回明细时无法区分，分组内所有消息共享该错误
+func batchFailures(data *SendBatchData, err error) (map[string]error, error) {
+	if err == nil && data != nil && (data.SuccessCount < data.TotalCount || data.FailedCount > 0 || len(data.FailedReceivers) > 0) {
+		err = &PartialFailureError{
+			BatchID:      data.BatchID,
+			TotalCount:   data.TotalCount,
+			SuccessCount: data.SuccessCount,
+			FailedCount:  data.FailedCount,
+			Failed:       data.FailedReceivers,
+		}
+	}
+
+	var partialErr *PartialFailureError
+	if !errors.As(err, &partialErr) || len(partialErr.Failed) == 0 {
+		return nil, err
+	}
+	failed := make(map[string]error, len(partialErr.Failed))
+	for _, f := range partialErr.Failed {
+		receiverErr := *partialErr
+		receiverErr.Failed = []FailedReceiver{f}
+		failed[f.Receiver] = &receiverErr
+	}
+	return failed, err
+}
+
+// report 回调单条消息的结果
+func (s *BatchSender) report(result BatchSendResult) {
+	if s.opts.OnResult != nil {
+		s.client.safeCall(s.ctx, "OnResult", func() { s.opts.OnResult(result) })
+	}
+}
+
+// batchGroupKey 计算消息的分组键：除接收者外的字段都相同的消息可以合并为一次批量发送
+// 设置了幂等键的消息在 dispatch 中单独发送，不会参与合并
+func batchGroupKey(req *SendMessageRequest) string {
+	m := *req
+	m.Receiver = ""
+	data, err := json.Marshal(&m)
+	if err != nil {
+		// 无法序列化的消息（如包含函数类型的参数）不参与合并
+		return "\x00" + req.Receiver
+	}
+	return string(data)
+}
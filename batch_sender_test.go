@@ -0,0 +1,176 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestBatchSender 测试异步批量发送的分组与结果回调
+func TestBatchSender(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+	var singles []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/messages/batch" {
+			var req SendBatchRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			batches = append(batches, req.Receivers)
+			mu.Unlock()
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"batch_id": "b1", "total_count": len(req.Receivers), "success_count": len(req.Receivers)}})
+			return
+		}
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		singles = append(singles, req.Receiver)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t-" + req.Receiver}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	var results []BatchSendResult
+	sender := NewBatchSender(client, BatchSenderOptions{
+		MaxBatchSize:  3,
+		FlushInterval: time.Hour,
+		OnResult: func(r BatchSendResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, r)
+		},
+	})
+
+	ctx := context.Background()
+	params := map[string]interface{}{"code": "1234"}
+	for _, receiver := range []string{"1", "2", "3", "4"} {
+		if err := sender.Enqueue(ctx, &SendMessageRequest{ChannelID: 1, SignatureName: "test", Receiver: receiver, TemplateParams: params}); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+	// 模板参数不同，不能与上面的消息合并
+	sender.Enqueue(ctx, &SendMessageRequest{ChannelID: 1, SignatureName: "test", Receiver: "5", TemplateParams: map[string]interface{}{"code": "9999"}})
+
+	if err := sender.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := sender.Enqueue(ctx, &SendMessageRequest{}); !errors.Is(err, ErrBatchSenderClosed) {
+		t.Errorf("Enqueue() after Close error = %v", err)
+	}
+
+	if len(batches) != 1 || len(batches[0]) != 3 {
+		t.Errorf("batches = %v, want one batch of 3", batches)
+	}
+	if len(singles) != 2 {
+		t.Errorf("singles = %v, want 2 single sends", singles)
+	}
+	if len(results) != 5 {
+		t.Fatalf("results = %d, want 5", len(results))
+	}
+	for _, r := range results {
+		if r.Err != nil || (r.BatchID == "" && r.TaskID != "t-"+r.Request.Receiver) {
+			t.Errorf("result = %+v", r)
+		}
+	}
+}
+
+// TestBatchSenderPartialFailure 测试未开启 WithPartialFailureError 时部分失败的接收者仍回调错误
+func TestBatchSenderPartialFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   map[string]interface{}
+		failed map[string]bool // 期望回调错误的接收者
+		reason string          // 期望接收者 2 的错误携带的失败原因
+	}{
+		{
+			name: "with details",
+			data: map[string]interface{}{"batch_id": "b1", "total_count": 3, "success_count": 2, "failed_count": 1,
+				"failed_receivers": []map[string]interface{}{{"receiver": "2", "code": 40001, "reason": "invalid receiver"}}},
+			failed: map[string]bool{"2": true},
+			reason: "invalid receiver",
+		},
+		{
+			name:   "without details",
+			data:   map[string]interface{}{"batch_id": "b1", "total_count": 3, "success_count": 2, "failed_count": 1},
+			failed: map[string]bool{"1": true, "2": true, "3": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": tt.data})
+			}))
+			defer server.Close()
+
+			var mu sync.Mutex
+			results := make(map[string]error)
+			sender := NewBatchSender(NewClient(server.URL, "test_app_id", "test_secret"), BatchSenderOptions{
+				FlushInterval: time.Hour,
+				OnResult: func(r BatchSendResult) {
+					mu.Lock()
+					defer mu.Unlock()
+					results[r.Request.Receiver] = r.Err
+				},
+			})
+			ctx := context.Background()
+			for _, receiver := range []string{"1", "2", "3"} {
+				sender.Enqueue(ctx, &SendMessageRequest{ChannelID: 1, SignatureName: "test", Receiver: receiver})
+			}
+			sender.Close(ctx)
+
+			if len(results) != 3 {
+				t.Fatalf("results = %v, want 3", results)
+			}
+			for receiver, err := range results {
+				if tt.failed[receiver] != errors.Is(err, ErrPartialFailure) {
+					t.Errorf("receiver %s: err = %v, want failed = %v", receiver, err, tt.failed[receiver])
+				}
+			}
+			var partialErr *PartialFailureError
+			if errors.As(results["2"], &partialErr) && tt.reason != "" && (len(partialErr.Failed) != 1 || partialErr.Failed[0].Reason != tt.reason) {
+				t.Errorf("receiver 2 error = %+v", partialErr)
+			}
+		})
+	}
+}
+
+// TestBatchSenderIdempotencyKey 测试设置了幂等键的消息单独发送并保留幂等键
+func TestBatchSenderIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var batches int
+	keys := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/api/v1/messages/batch" {
+			batches++
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"batch_id": "b1", "total_count": 2, "success_count": 2}})
+			return
+		}
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		keys[req.Receiver] = req.IdempotencyKey
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t-" + req.Receiver}})
+	}))
+	defer server.Close()
+
+	sender := NewBatchSender(NewClient(server.URL, "test_app_id", "test_secret"), BatchSenderOptions{FlushInterval: time.Hour})
+	ctx := context.Background()
+	sender.Enqueue(ctx, &SendMessageRequest{ChannelID: 1, SignatureName: "test", Receiver: "1"})
+	sender.Enqueue(ctx, &SendMessageRequest{ChannelID: 1, SignatureName: "test", Receiver: "2"})
+	sender.Enqueue(ctx, &SendMessageRequest{ChannelID: 1, SignatureName: "test", Receiver: "3", IdempotencyKey: "order-3"})
+	sender.Close(ctx)
+
+	if batches != 1 || len(keys) != 1 || keys["3"] != "order-3" {
+		t.Errorf("batches = %d, single sends = %v, want one batch and receiver 3 sent alone with its key", batches, keys)
+	}
+}
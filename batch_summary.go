@@ -0,0 +1,138 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// batchSummaryConcurrency 批量查询批次汇总时的最大并发数
+const batchSummaryConcurrency = 8
+
+// BatchSummary 批次汇总
+type BatchSummary struct {
+	BatchID         string `json:"batch_id"`         // 批次ID
+	TotalCount      int    `json:"total_count"`      // 总数量
+	PendingCount    int    `json:"pending_count"`    // 待处理数量
+	ProcessingCount int    `json:"processing_count"` // 处理中数量
+	SuccessCount    int    `json:"success_count"`    // 发送成功数量
+	FailedCount     int    `json:"failed_count"`     // 发送失败数量
+	DeliveredCount  int    `json:"delivered_count"`  // 回调确认送达数量
+	CreatedAt       string `json:"created_at"`       // 创建时间
+	UpdatedAt       string `json:"updated_at"`       // 更新时间
+
+	RawData // 原始JSON，见 RawData
+}
+
+// BatchSummaryTotals 多个批次的汇总
+type BatchSummaryTotals struct {
+	BatchCount      int // 批次数量（仅统计查询成功的批次）
+	TotalCount      int // 总数量
+	PendingCount    int // 待处理数量
+	ProcessingCount int // 处理中数量
+	SuccessCount    int // 发送成功数量
+	FailedCount     int // 发送失败数量
+	DeliveredCount  int // 回调确认送达数量
+}
+
+// add 累加单个批次
+func (t *BatchSummaryTotals) add(s *BatchSummary) {
+	t.BatchCount++
+	t.TotalCount += s.TotalCount
+	t.PendingCount += s.PendingCount
+	t.ProcessingCount += s.ProcessingCount
+	t.SuccessCount += s.SuccessCount
+	t.FailedCount += s.FailedCount
+	t.DeliveredCount += s.DeliveredCount
+}
+
+// SuccessRate 发送成功率（成功数 / 已完成数），尚无完成的消息时返回0
+func (t BatchSummaryTotals) SuccessRate() float64 {
+	done := t.SuccessCount + t.FailedCount
+	if done == 0 {
+		return 0
+	}
+	return float64(t.SuccessCount) / float64(done)
+}
+
+// BatchSummaries 多个批次的查询结果
+type BatchSummaries struct {
+	Batches []*BatchSummary    // 各批次汇总，按传入顺序排列（重复的批次ID只保留一个），查询失败的为nil
+	Overall BatchSummaryTotals // 查询成功的批次合计
+	Errors  map[string]error   // 查询失败的批次ID及错误
+}
+
+// GetBatchSummary 查询单个批次的汇总
+func (c *Client) GetBatchSummary(ctx context.Context, batchID string) (*BatchSummary, error) {
+	if err := c.requireFeature(ctx, FeatureStatistics); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/batches/" + batchID + "/summary"
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data BatchSummary
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// GetBatchSummaries 并发查询多个批次的汇总并计算合计，适用于分块发送的营销活动看板
+// 单个批次查询失败不会中断其他批次，失败信息记录在 Errors 中；仅当 ctx 结束时返回错误
+func (c *Client) GetBatchSummaries(ctx context.Context, batchIDs []string) (*BatchSummaries, error) {
+	if err := c.requireFeature(ctx, FeatureStatistics); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(batchIDs))
+	seen := make(map[string]bool, len(batchIDs))
+	for _, id := range batchIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	result := &BatchSummaries{
+		Batches: make([]*BatchSummary, len(ids)),
+		Errors:  make(map[string]error),
+	}
+	errs := make([]error, len(ids))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchSummaryConcurrency)
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			result.Batches[i], errs[i] = c.GetBatchSummary(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	for i, id := range ids {
+		if errs[i] != nil {
+			result.Errors[id] = errs[i]
+			continue
+		}
+		result.Overall.add(result.Batches[i])
+	}
+
+	if err := ctx.Err(); err != nil {
+		return result, fmt.Errorf("get batch summaries: %w", err)
+	}
+	return result, nil
+}
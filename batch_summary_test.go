@@ -0,0 +1,60 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestGetBatchSummaries 测试多批次汇总
+func TestGetBatchSummaries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		batchID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/batches/"), "/summary")
+		if batchID == "missing" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeBatchNotFound, "message": "批量任务不存在"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data": map[string]interface{}{
+				"batch_id":      batchID,
+				"total_count":   100,
+				"pending_count": 10,
+				"success_count": 80,
+				"failed_count":  10,
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	result, err := client.GetBatchSummaries(context.Background(), []string{"b1", "b2", "missing", "b1"})
+	if err != nil {
+		t.Fatalf("GetBatchSummaries() error = %v", err)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3 (duplicates should be skipped)", calls.Load())
+	}
+	if len(result.Batches) != 3 || result.Batches[0].BatchID != "b1" || result.Batches[1].BatchID != "b2" || result.Batches[2] != nil {
+		t.Errorf("unexpected batches: %+v", result.Batches)
+	}
+	if !IsAPIError(result.Errors["missing"]) {
+		t.Errorf("Errors[missing] = %v", result.Errors["missing"])
+	}
+
+	overall := result.Overall
+	if overall.BatchCount != 2 || overall.TotalCount != 200 || overall.SuccessCount != 160 {
+		t.Errorf("unexpected overall: %+v", overall)
+	}
+	if rate := overall.SuccessRate(); rate != 160.0/180.0 {
+		t.Errorf("SuccessRate() = %v", rate)
+	}
+}
@@ -0,0 +1,189 @@
+// Package benchmark SDK热路径的基准测试报告，用于对比不同版本的性能
+//
+// 独立于 mlievpush 包，业务程序引入 SDK 时不会依赖 testing 包；只应在 CI 或性能回归工具中使用。
+//
+//	report, err := benchmark.Run()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Print(report)
+//	if err := report.CheckBudget(nil); err != nil {
+//	    log.Fatal(err)
+//	}
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// Result 单项基准测试结果
+type Result struct {
+	Name        string // 基准名称
+	N           int    // 迭代次数
+	NsPerOp     int64  // 每次操作耗时（纳秒）
+	AllocsPerOp int64  // 每次操作内存分配次数
+	BytesPerOp  int64  // 每次操作分配字节数
+}
+
+// Report 基准测试报告
+type Report struct {
+	GoVersion string   // Go版本
+	Results   []Result // 各项结果
+}
+
+// DefaultAllocBudgets 热路径的内存分配预算（每次操作的最大分配次数），在当前实测值之上保留约三成余量，
+// 避免编译器版本差异导致误报；竞态检测（-race）会额外分配内存，此时不应检查预算
+var DefaultAllocBudgets = map[string]int64{
+	"CanonicalJSON":     56,
+	"GenerateSignature": 36,
+	"DoRequest":         160,
+}
+
+// benchCase 基准测试项，setup 返回单次操作
+type benchCase struct {
+	name  string
+	setup func() func() error
+}
+
+// cases 基准测试列表
+var cases = []benchCase{
+	{"CanonicalJSON", setupCanonicalJSON},
+	{"GenerateSignature", setupGenerateSignature},
+	{"DoRequest", setupDoRequest},
+}
+
+// Run 运行SDK热路径的基准测试并生成报告，每项约耗时1秒，任一操作失败时返回错误
+func Run() (*Report, error) {
+	report := &Report{GoVersion: runtime.Version()}
+	for _, bc := range cases {
+		op := bc.setup()
+		var opErr error
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := op(); err != nil {
+					opErr = err
+					b.FailNow()
+				}
+			}
+		})
+		if opErr != nil {
+			return nil, fmt.Errorf("%s: %w", bc.name, opErr)
+		}
+		report.Results = append(report.Results, Result{
+			Name:        bc.name,
+			N:           r.N,
+			NsPerOp:     r.NsPerOp(),
+			AllocsPerOp: r.AllocsPerOp(),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+		})
+	}
+	return report, nil
+}
+
+// String 以表格形式输出报告
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "go: %s\n", r.GoVersion)
+	for _, res := range r.Results {
+		fmt.Fprintf(&sb, "%-20s %10d %10d ns/op %8d B/op %6d allocs/op\n",
+			res.Name, res.N, res.NsPerOp, res.BytesPerOp, res.AllocsPerOp)
+	}
+	return sb.String()
+}
+
+// CheckBudget 检查各项分配次数是否超出预算，budgets 为 nil 时使用 DefaultAllocBudgets
+func (r *Report) CheckBudget(budgets map[string]int64) error {
+	if budgets == nil {
+		budgets = DefaultAllocBudgets
+	}
+	for _, res := range r.Results {
+		if budget, ok := budgets[res.Name]; ok && res.AllocsPerOp > budget {
+			return fmt.Errorf("%s: %d allocs/op exceeds budget of %d", res.Name, res.AllocsPerOp, budget)
+		}
+	}
+	return nil
+}
+
+// benchParams 基准测试使用的请求参数
+func benchParams() map[string]interface{} {
+	return map[string]interface{}{
+		"channel_id":     1,
+		"signature_name": "【测试签名】",
+		"receiver":       "13800138000",
+		"template_params": map[string]interface{}{
+			"code":        "123456",
+			"expire_time": "5",
+		},
+	}
+}
+
+// setupCanonicalJSON 请求体规范化基准
+func setupCanonicalJSON() func() error {
+	body := []byte(`{"receiver":"13800138000","channel_id":1,"signature_name":"【测试签名】","template_params":{"expire_time":"5","code":"123456"}}`)
+	return func() error {
+		_, err := mlievpush.CanonicalJSON(body)
+		return err
+	}
+}
+
+// setupGenerateSignature 签名生成基准
+func setupGenerateSignature() func() error {
+	params := benchParams()
+	return func() error {
+		mlievpush.GenerateSignature(http.MethodPost, "/api/v1/messages", params, "1700000000", "abc123", "secret123456")
+		return nil
+	}
+}
+
+// setupDoRequest 完整请求流程基准（使用进程内响应，不产生网络开销）
+func setupDoRequest() func() error {
+	client := mlievpush.NewClient("http://bench.invalid", "bench_app", "bench_secret",
+		mlievpush.WithHTTPClient(&http.Client{Transport: sinkTransport{}}),
+	)
+	req := benchRequest()
+	ctx := context.Background()
+	return func() error {
+		_, err := client.SendMessage(ctx, req)
+		return err
+	}
+}
+
+// benchRequest 基准测试使用的发送请求
+func benchRequest() *mlievpush.SendMessageRequest {
+	return &mlievpush.SendMessageRequest{
+		ChannelID:      1,
+		SignatureName:  "【测试签名】",
+		Receiver:       "13800138000",
+		TemplateParams: map[string]interface{}{"code": "123456"},
+	}
+}
+
+// sinkResponse 基准测试返回的固定响应
+var sinkResponse = []byte(`{"code":0,"message":"success","data":{"task_id":"550e8400-e29b-41d4-a716-446655440000","status":"pending","created_at":"2025-11-25T10:00:00Z"}}`)
+
+// sinkTransport 丢弃请求并返回固定响应的 RoundTripper
+type sinkTransport struct{}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (sinkTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(sinkResponse)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// BenchmarkHotPaths 各热路径的基准，go test -bench . -benchmem ./benchmark
+func BenchmarkHotPaths(b *testing.B) {
+	for _, bc := range cases {
+		b.Run(bc.name, func(b *testing.B) {
+			op := bc.setup()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := op(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDoRequestHTTP 经由 httptest 服务器的完整请求基准
+func BenchmarkDoRequestHTTP(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(sinkResponse)
+	}))
+	defer server.Close()
+
+	client := mlievpush.NewClient(server.URL, "bench_app", "bench_secret")
+	req := benchRequest()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.SendMessage(ctx, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestCases 测试各基准操作本身可以成功执行
+func TestCases(t *testing.T) {
+	for _, bc := range cases {
+		if err := bc.setup()(); err != nil {
+			t.Errorf("%s: %v", bc.name, err)
+		}
+	}
+}
+
+// TestReport 测试基准报告的预算检查
+func TestReport(t *testing.T) {
+	report := &Report{
+		Results: []Result{{Name: "CanonicalJSON", AllocsPerOp: 10}},
+	}
+	if err := report.CheckBudget(map[string]int64{"CanonicalJSON": 10}); err != nil {
+		t.Errorf("CheckBudget() error = %v", err)
+	}
+	if err := report.CheckBudget(map[string]int64{"CanonicalJSON": 9}); err == nil {
+		t.Error("expected budget error")
+	}
+
+	// 报告可以序列化用于跨版本对比
+	if _, err := json.Marshal(report); err != nil {
+		t.Errorf("marshal report: %v", err)
+	}
+}
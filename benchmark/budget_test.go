@@ -0,0 +1,19 @@
+//go:build !race
+
+package benchmark
+
+import "testing"
+
+// TestAllocationBudget 测试热路径内存分配不超出预算；竞态检测会额外分配内存，因此只在非 -race 构建中运行
+func TestAllocationBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation budget in short mode")
+	}
+	for _, bc := range cases {
+		op := bc.setup()
+		allocs := testing.AllocsPerRun(100, func() { op() })
+		if budget := DefaultAllocBudgets[bc.name]; int64(allocs) > budget {
+			t.Errorf("%s: %.0f allocs/op exceeds budget of %d", bc.name, allocs, budget)
+		}
+	}
+}
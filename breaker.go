@@ -0,0 +1,219 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 熔断器默认配置
+const (
+	DefaultBreakerFailureThreshold = 5                // 连续失败次数阈值
+	DefaultBreakerCooldown         = 30 * time.Second // 熔断持续时间
+)
+
+// ErrCircuitOpen 熔断器打开（本地熔断器拒绝请求，或网关返回 40007）
+var ErrCircuitOpen = errors.New("mlievpush: circuit open")
+
+// BreakerState 熔断器状态
+type BreakerState string
+
+// 熔断器状态
+const (
+	BreakerClosed   BreakerState = "closed"    // 正常放行
+	BreakerOpen     BreakerState = "open"      // 熔断中，直接拒绝请求
+	BreakerHalfOpen BreakerState = "half_open" // 冷却结束，放行一个探测请求
+)
+
+// BreakerConfig 熔断器配置
+type BreakerConfig struct {
+	FailureThreshold int           // 连续失败多少次后熔断，0 使用 DefaultBreakerFailureThreshold
+	Cooldown         time.Duration // 熔断持续时间，到期后放行一个探测请求，0 使用 DefaultBreakerCooldown
+}
+
+// CircuitOpenError 本地熔断器拒绝请求的错误，不会发送请求
+type CircuitOpenError struct {
+	ChannelID int       // 通道ID，0表示不指定通道的请求（查询、模板管理等）
+	OpenUntil time.Time // 预计恢复探测的时间
+}
+
+// Error 实现 error 接口
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for channel %d until %s", e.ChannelID, e.OpenUntil.Format(time.RFC3339))
+}
+
+// Is 使 errors.Is(err, ErrCircuitOpen) 成立
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// BreakerStatus 单个通道熔断器的状态
+type BreakerStatus struct {
+	ChannelID int          `json:"channel_id"`           // 通道ID，0表示不指定通道的请求
+	State     BreakerState `json:"state"`                // 当前状态
+	Failures  int          `json:"failures"`             // 连续失败次数
+	OpenUntil time.Time    `json:"open_until,omitempty"` // 熔断到期时间（仅 open 状态）
+}
+
+// WithCircuitBreaker 开启按通道的熔断器：同一通道连续失败达到阈值后，在冷却期内直接返回 *CircuitOpenError，
+// 冷却结束后放行一个探测请求，成功则恢复，失败则再次熔断
+// 各通道独立计数，一个服务商故障不会影响同一网关上的其他通道；不指定通道的请求共用通道0的熔断器
+// 只有网关系统错误（4xxxx）、HTTP 5xx 和网络错误计为失败，参数错误、鉴权错误、限流等不影响熔断
+func WithCircuitBreaker(config BreakerConfig) ClientOption {
+	return func(c *Client) {
+		if config.FailureThreshold <= 0 {
+			config.FailureThreshold = DefaultBreakerFailureThreshold
+		}
+		if config.Cooldown <= 0 {
+			config.Cooldown = DefaultBreakerCooldown
+		}
+		c.breakers = &breakerSet{config: config, now: time.Now, channels: make(map[int]*breaker)}
+	}
+}
+
+// BreakerStates 返回各通道熔断器的状态（按通道ID排序），未开启熔断器时返回nil
+func (c *Client) BreakerStates() []BreakerStatus {
+	if c.breakers == nil {
+		return nil
+	}
+	return c.breakers.states()
+}
+
+// breaker 单个通道的熔断器
+type breaker struct {
+	failures  int       // 连续失败次数
+	openUntil time.Time // 熔断到期时间，零值表示未熔断
+	probing   bool      // 是否有探测请求进行中
+}
+
+// breakerSet 按通道ID管理的熔断器
+type breakerSet struct {
+	config BreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	channels map[int]*breaker
+}
+
+// state 计算熔断器状态，调用方需持有锁
+func (s *breakerSet) state(b *breaker, now time.Time) BreakerState {
+	switch {
+	case b.openUntil.IsZero():
+		return BreakerClosed
+	case now.Before(b.openUntil):
+		return BreakerOpen
+	default:
+		return BreakerHalfOpen
+	}
+}
+
+// allow 判断是否放行请求，半开状态只放行一个探测请求；s 为nil时始终放行
+func (s *breakerSet) allow(channelID int) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.channels[channelID]
+	if !ok {
+		return nil
+	}
+	now := s.now()
+	switch s.state(b, now) {
+	case BreakerOpen:
+		return &CircuitOpenError{ChannelID: channelID, OpenUntil: b.openUntil}
+	case BreakerHalfOpen:
+		if b.probing {
+			return &CircuitOpenError{ChannelID: channelID, OpenUntil: now.Add(s.config.Cooldown)}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record 记录请求结果；s 为nil时忽略
+func (s *breakerSet) record(channelID int, err error, statusCode int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.channels[channelID]
+	if !ok {
+		b = &breaker{}
+		s.channels[channelID] = b
+	}
+	probing := b.probing
+	b.probing = false
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// 调用方取消不代表通道故障，探测未完成时保持半开
+		return
+	}
+	if !breakerFailure(err, statusCode) {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+
+	b.failures++
+	if probing || b.failures >= s.config.FailureThreshold {
+		b.openUntil = s.now().Add(s.config.Cooldown)
+	}
+}
+
+// stateOf 返回通道熔断器的当前状态，s 为nil时返回空字符串
+func (s *breakerSet) stateOf(channelID int) BreakerState {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.channels[channelID]
+	if !ok {
+		return BreakerClosed
+	}
+	return s.state(b, s.now())
+}
+
+// states 返回各通道熔断器的状态
+func (s *breakerSet) states() []BreakerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	statuses := make([]BreakerStatus, 0, len(s.channels))
+	for channelID, b := range s.channels {
+		status := BreakerStatus{ChannelID: channelID, State: s.state(b, now), Failures: b.failures}
+		if status.State == BreakerOpen {
+			status.OpenUntil = b.openUntil
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ChannelID < statuses[j].ChannelID })
+	return statuses
+}
+
+// breakerFailure 判断请求结果是否计为通道故障
+func breakerFailure(err error, statusCode int) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code/10000 == 4
+	}
+	if statusCode >= http.StatusInternalServerError {
+		return true
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,99 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCircuitBreakerPerChannel 测试熔断器按通道独立
+func TestCircuitBreakerPerChannel(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}}
+		if req.ChannelID == 3 {
+			resp = map[string]interface{}{"code": ErrCodeProviderError, "message": "服务商错误"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithCircuitBreaker(BreakerConfig{FailureThreshold: 2, Cooldown: time.Minute}))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SendMessage(ctx, &SendMessageRequest{ChannelID: 3, Receiver: "13800138000"}); !IsAPIError(err) {
+			t.Fatalf("expected APIError, got %v", err)
+		}
+	}
+
+	_, err := client.SendMessage(ctx, &SendMessageRequest{ChannelID: 3, Receiver: "13800138000"})
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || openErr.ChannelID != 3 || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected CircuitOpenError, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected open breaker to skip the request, got %d calls", calls)
+	}
+
+	if _, err := client.SendMessage(ctx, &SendMessageRequest{ChannelID: 5, Receiver: "13800138000"}); err != nil {
+		t.Errorf("channel 5 should not be affected, got %v", err)
+	}
+
+	states := client.BreakerStates()
+	if len(states) != 2 || states[0].ChannelID != 3 || states[0].State != BreakerOpen || states[1].State != BreakerClosed {
+		t.Errorf("unexpected states: %+v", states)
+	}
+}
+
+// TestCircuitBreakerHalfOpen 测试冷却后探测
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := &breakerSet{
+		config:   BreakerConfig{FailureThreshold: 1, Cooldown: time.Second},
+		now:      func() time.Time { return now },
+		channels: make(map[int]*breaker),
+	}
+	failure := NewAPIError(ErrCodeNetworkTimeout, "网络超时")
+
+	s.record(1, failure, http.StatusOK)
+	if err := s.allow(1); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open, got %v", err)
+	}
+
+	now = now.Add(2 * time.Second)
+	if err := s.allow(1); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+	if err := s.allow(1); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected concurrent probe to be rejected, got %v", err)
+	}
+	s.record(1, failure, http.StatusOK)
+	if err := s.allow(1); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected failed probe to reopen, got %v", err)
+	}
+
+	now = now.Add(2 * time.Second)
+	if err := s.allow(1); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+	s.record(1, nil, http.StatusOK)
+	if states := s.states(); states[0].State != BreakerClosed || states[0].Failures != 0 {
+		t.Errorf("expected closed after successful probe, got %+v", states)
+	}
+
+	// 业务错误不计为通道故障
+	s.record(1, NewAPIError(ErrCodeRateLimitExceeded, "超出速率限制"), http.StatusOK)
+	if err := s.allow(1); err != nil {
+		t.Errorf("rate limit should not open breaker, got %v", err)
+	}
+}
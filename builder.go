@@ -0,0 +1,246 @@
+package mlievpush
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 消息构建器写入模板参数使用的键，网关模板通过同名变量引用
+const (
+	ParamSubject       = "subject"        // 邮件主题
+	ParamBody          = "body"           // 邮件正文
+	ParamBodyHTML      = "body_html"      // 邮件正文是否为HTML（"1"表示HTML）
+	ParamMarkdownTitle = "markdown_title" // Markdown 消息标题（钉钉、企业微信）
+	ParamMarkdown      = "markdown"       // Markdown 消息内容（钉钉、企业微信）
+)
+
+// MessageBuilder 按消息类型构建 SendMessageRequest，Build 时在本地校验类型相关的字段
+type MessageBuilder struct {
+	messageType string
+	req         SendMessageRequest
+	errs        []error
+}
+
+// NewSMSMessage 创建短信消息构建器
+func NewSMSMessage(channelID int, phone string) *MessageBuilder {
+	return newMessageBuilder(MessageTypeSMS, channelID, phone)
+}
+
+// NewVoiceMessage 创建语音消息构建器
+func NewVoiceMessage(channelID int, phone string) *MessageBuilder {
+	return newMessageBuilder(MessageTypeVoice, channelID, phone)
+}
+
+// NewEmailMessage 创建邮件消息构建器
+func NewEmailMessage(channelID int, address string) *MessageBuilder {
+	return newMessageBuilder(MessageTypeEmail, channelID, address)
+}
+
+// NewWechatWorkMessage 创建企业微信消息构建器
+func NewWechatWorkMessage(channelID int, receiver string) *MessageBuilder {
+	return newMessageBuilder(MessageTypeWechatWork, channelID, receiver)
+}
+
+// NewDingtalkMessage 创建钉钉消息构建器
+func NewDingtalkMessage(channelID int, receiver string) *MessageBuilder {
+	return newMessageBuilder(MessageTypeDingtalk, channelID, receiver)
+}
+
+// NewWebhookMessage 创建 Webhook 消息构建器
+func NewWebhookMessage(channelID int, url string) *MessageBuilder {
+	return newMessageBuilder(MessageTypeWebhook, channelID, url)
+}
+
+// NewPushMessage 创建推送通知构建器
+func NewPushMessage(channelID int, deviceToken string) *MessageBuilder {
+	return newMessageBuilder(MessageTypePush, channelID, deviceToken)
+}
+
+// newMessageBuilder 创建指定类型的消息构建器
+func newMessageBuilder(messageType string, channelID int, receiver string) *MessageBuilder {
+	return &MessageBuilder{
+		messageType: messageType,
+		req: SendMessageRequest{
+			ChannelID: channelID,
+			Receiver:  receiver,
+		},
+	}
+}
+
+// MessageType 返回构建器的消息类型
+func (b *MessageBuilder) MessageType() string {
+	return b.messageType
+}
+
+// Signature 设置签名名称
+func (b *MessageBuilder) Signature(name string) *MessageBuilder {
+	b.req.SignatureName = name
+	return b
+}
+
+// Param 设置模板参数
+func (b *MessageBuilder) Param(key string, value interface{}) *MessageBuilder {
+	if b.req.TemplateParams == nil {
+		b.req.TemplateParams = make(map[string]interface{})
+	}
+	b.req.TemplateParams[key] = value
+	return b
+}
+
+// Params 批量设置模板参数
+func (b *MessageBuilder) Params(params map[string]interface{}) *MessageBuilder {
+	for k, v := range params {
+		b.Param(k, v)
+	}
+	return b
+}
+
+// Region 设置地区代码（ISO 3166-1 alpha-2）
+func (b *MessageBuilder) Region(region string) *MessageBuilder {
+	b.req.Region = region
+	return b
+}
+
+// ScheduleAt 设置定时发送时间
+func (b *MessageBuilder) ScheduleAt(t time.Time) *MessageBuilder {
+	b.req.ScheduledAt = FormatDeadline(t)
+	return b
+}
+
+// Deadline 设置投递截止时间
+func (b *MessageBuilder) Deadline(t time.Time) *MessageBuilder {
+	b.req.DeadlineAt = FormatDeadline(t)
+	return b
+}
+
+// CallbackURL 设置该消息的投递回调地址，覆盖应用注册的回调地址
+func (b *MessageBuilder) CallbackURL(callbackURL string) *MessageBuilder {
+	b.req.CallbackURL = callbackURL
+	return b
+}
+
+// Subject 设置邮件主题（仅邮件）
+func (b *MessageBuilder) Subject(subject string) *MessageBuilder {
+	if b.only("Subject", MessageTypeEmail) {
+		b.Param(ParamSubject, subject)
+	}
+	return b
+}
+
+// Body 设置纯文本邮件正文（仅邮件）
+func (b *MessageBuilder) Body(body string) *MessageBuilder {
+	if b.only("Body", MessageTypeEmail) {
+		b.Param(ParamBody, body)
+		delete(b.req.TemplateParams, ParamBodyHTML)
+	}
+	return b
+}
+
+// HTMLBody 设置 HTML 邮件正文（仅邮件）
+func (b *MessageBuilder) HTMLBody(html string) *MessageBuilder {
+	if b.only("HTMLBody", MessageTypeEmail) {
+		b.Param(ParamBody, html)
+		b.Param(ParamBodyHTML, "1")
+	}
+	return b
+}
+
+// Attach 添加附件（仅邮件）
+func (b *MessageBuilder) Attach(attachments ...Attachment) *MessageBuilder {
+	if b.only("Attach", MessageTypeEmail) {
+		b.req.Attachments = append(b.req.Attachments, attachments...)
+	}
+	return b
+}
+
+// Markdown 设置 Markdown 消息标题和内容（仅钉钉、企业微信）
+func (b *MessageBuilder) Markdown(title, text string) *MessageBuilder {
+	if b.only("Markdown", MessageTypeDingtalk, MessageTypeWechatWork) {
+		b.Param(ParamMarkdownTitle, title)
+		b.Param(ParamMarkdown, text)
+	}
+	return b
+}
+
+// only 检查字段是否适用于当前消息类型，不适用时记录错误
+func (b *MessageBuilder) only(field string, messageTypes ...string) bool {
+	for _, t := range messageTypes {
+		if b.messageType == t {
+			return true
+		}
+	}
+	b.errs = append(b.errs, fmt.Errorf("%s is not supported for %s messages", field, b.messageType))
+	return false
+}
+
+// Build 校验并返回发送请求，所有校验错误通过 errors.Join 合并返回
+// 校验内容：必填字段、接收者格式、类型相关字段（邮件主题和正文、Markdown 内容）及默认内容长度限制
+func (b *MessageBuilder) Build() (*SendMessageRequest, error) {
+	errs := append([]error(nil), b.errs...)
+
+	if b.req.ChannelID <= 0 {
+		errs = append(errs, fmt.Errorf("invalid channel id %d", b.req.ChannelID))
+	}
+	if b.req.SignatureName == "" {
+		errs = append(errs, errors.New("signature name is required"))
+	}
+	if err := ValidateReceiver(b.messageType, b.req.Receiver); err != nil {
+		errs = append(errs, err)
+	}
+
+	params := b.req.TemplateParams
+	switch b.messageType {
+	case MessageTypeEmail:
+		if params[ParamSubject] == nil || params[ParamSubject] == "" {
+			errs = append(errs, errors.New("email subject is required"))
+		}
+		if params[ParamBody] == nil || params[ParamBody] == "" {
+			errs = append(errs, errors.New("email body is required"))
+		}
+		if err := b.checkContent(ParamBody); err != nil {
+			errs = append(errs, err)
+		}
+		if len(b.req.Attachments) > 0 {
+			if err := DefaultAttachmentLimit.Validate(b.req.Attachments); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	case MessageTypeDingtalk, MessageTypeWechatWork:
+		if _, ok := params[ParamMarkdown]; ok {
+			if params[ParamMarkdown] == "" {
+				errs = append(errs, errors.New("markdown text is required"))
+			}
+			if err := b.checkContent(ParamMarkdown); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	req := b.req
+	if params != nil {
+		req.TemplateParams = make(map[string]interface{}, len(params))
+		for k, v := range params {
+			req.TemplateParams[k] = v
+		}
+	}
+	req.Attachments = append([]Attachment(nil), b.req.Attachments...)
+	return &req, nil
+}
+
+// checkContent 按默认内容长度限制校验指定参数
+func (b *MessageBuilder) checkContent(key string) error {
+	content, ok := b.req.TemplateParams[key].(string)
+	if !ok {
+		return nil
+	}
+	limit, ok := DefaultContentLimits[b.messageType]
+	if !ok {
+		return nil
+	}
+	return limit.Check(b.messageType, content)
+}
@@ -0,0 +1,73 @@
+package mlievpush
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestEmailMessageBuilder 测试邮件消息构建
+func TestEmailMessageBuilder(t *testing.T) {
+	req, err := NewEmailMessage(3, "user@example.com").
+		Signature("公司").
+		Subject("账单").
+		HTMLBody("<p>hello</p>").
+		Param("month", "10").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if req.ChannelID != 3 || req.Receiver != "user@example.com" || req.SignatureName != "公司" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if req.TemplateParams[ParamSubject] != "账单" || req.TemplateParams[ParamBody] != "<p>hello</p>" ||
+		req.TemplateParams[ParamBodyHTML] != "1" || req.TemplateParams["month"] != "10" {
+		t.Errorf("unexpected params: %v", req.TemplateParams)
+	}
+}
+
+// TestMessageBuilderValidation 测试构建时的本地校验
+func TestMessageBuilderValidation(t *testing.T) {
+	_, err := NewEmailMessage(1, "not-an-email").Signature("s").Build()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	var recvErr *ReceiverError
+	if !errors.As(err, &recvErr) {
+		t.Errorf("expected *ReceiverError, got %v", err)
+	}
+	for _, want := range []string{"subject is required", "body is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err, want)
+		}
+	}
+
+	_, err = NewSMSMessage(1, "13800138000").Signature("s").Subject("x").Build()
+	if err == nil || !strings.Contains(err.Error(), "Subject is not supported for sms") {
+		t.Errorf("Subject on sms should fail: %v", err)
+	}
+
+	_, err = NewDingtalkMessage(1, "robot").Signature("s").Markdown("t", strings.Repeat("a", 20001)).Build()
+	var lenErr *ContentLengthError
+	if !errors.As(err, &lenErr) {
+		t.Errorf("expected *ContentLengthError, got %v", err)
+	}
+}
+
+// TestDingtalkMessageBuilder 测试钉钉 Markdown 消息构建
+func TestDingtalkMessageBuilder(t *testing.T) {
+	b := NewDingtalkMessage(2, "robot").Signature("s").Markdown("告警", "**CPU** 90%")
+	req, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if b.MessageType() != MessageTypeDingtalk || req.TemplateParams[ParamMarkdown] != "**CPU** 90%" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	// 构建结果与构建器互不影响
+	b.Param("extra", 1)
+	if _, ok := req.TemplateParams["extra"]; ok {
+		t.Error("built request should not share params with builder")
+	}
+}
@@ -0,0 +1,88 @@
+// Package callback 推送服务投递回调的接收与处理
+//
+// 网关使用与请求相同的 HMAC-SHA256 算法对回调签名（X-App-Id、X-Timestamp、X-Nonce、X-Signature 请求头），
+// Handler 负责校验签名、解析事件并分发给用户的处理函数。
+package callback
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// 回调请求头
+const (
+	HeaderAppID     = mlievpush.HeaderAppID
+	HeaderTimestamp = mlievpush.HeaderTimestamp
+	HeaderNonce     = mlievpush.HeaderNonce
+	HeaderSignature = mlievpush.HeaderSignature
+)
+
+// 回调校验错误
+var (
+	ErrInvalidSignature = mlievpush.ErrInvalidSignature // 回调签名校验失败
+	ErrTimestampSkew    = mlievpush.ErrTimestampSkew    // 回调时间戳超出允许的时钟偏差
+)
+
+// CallbackEvent 投递回调事件
+type CallbackEvent struct {
+	Version string `json:"-"`              // 事件结构版本（version 字段，缺省为 EventVersion1）
+	Type    string `json:"type,omitempty"` // 事件类型，缺省为 EventTypeStatus
+	EventID string `json:"event_id"`       // 事件ID（网关重试投递时保持不变）
+	AppID   string `json:"app_id"`         // 应用ID（以 X-App-Id 请求头为准）
+	TaskID  string `json:"task_id"`        // 任务ID
+	Status  string `json:"status"`         // 回调状态，见 mlievpush.CallbackStatus* 常量
+
+	ProviderCode    string `json:"provider_code,omitempty"`    // 服务商原始状态码（如运营商回执码）
+	ProviderMessage string `json:"provider_message,omitempty"` // 服务商原始状态说明
+
+	Sequence    int64  `json:"sequence,omitempty"`     // 同一任务内单调递增的事件序号
+	SentAt      string `json:"sent_at,omitempty"`      // 提交服务商时间（RFC 3339）
+	DeliveredAt string `json:"delivered_at,omitempty"` // 送达时间（RFC 3339），未送达时为空
+	UpdatedAt   string `json:"updated_at,omitempty"`   // 状态更新时间（RFC 3339）
+
+	Nonce string          `json:"-"` // 本次投递的随机数
+	Raw   json.RawMessage `json:"-"` // 原始请求体
+}
+
+// ParseCallback 校验回调请求签名（X-Signature、X-Timestamp、X-Nonce）并解析事件
+// 适用于不使用 Handler、自行编写回调接口的场景；签名错误返回 ErrInvalidSignature，时间戳超出偏差返回 ErrTimestampSkew，
+// 事件版本未注册时返回 *UnknownEventVersion（保留原始请求体）
+func ParseCallback(r *http.Request, appSecret string) (*CallbackEvent, error) {
+	return parseCallback(r, mlievpush.NewVerifier(mlievpush.StaticSecret(appSecret)))
+}
+
+// parseCallback 校验回调签名并解析事件
+func parseCallback(r *http.Request, verifier *mlievpush.Verifier) (*CallbackEvent, error) {
+	verified, err := verifier.Verify(r)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := decodeEvent(verified.Body)
+	if unknown, ok := err.(*UnknownEventVersion); ok {
+		unknown.AppID = verified.AppID
+		unknown.Nonce = verified.Nonce
+		return nil, unknown
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: unmarshal callback event: %w", mlievpush.ErrInvalidRequest, err)
+	}
+	event.AppID = verified.AppID
+	event.Nonce = verified.Nonce
+	event.Raw = verified.Body
+
+	return event, nil
+}
+
+// ProviderReason 将服务商原始状态码归一化为统一的失败原因，见 mlievpush.NormalizeProviderError
+func (e *CallbackEvent) ProviderReason() mlievpush.ProviderReason {
+	return mlievpush.NormalizeProviderError(e.ProviderCode)
+}
+
+// IsTest 判断是否为测试事件（mlievpush.Client.TestCallback 触发）
+func (e *CallbackEvent) IsTest() bool {
+	return e.Type == EventTypeTest
+}
@@ -0,0 +1,46 @@
+package callback
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// TestParseCallback 测试解析回调事件
+func TestParseCallback(t *testing.T) {
+	payload := map[string]interface{}{
+		"event_id":         "evt-1",
+		"task_id":          "task-1",
+		"status":           "delivered",
+		"provider_code":    "DELIVRD",
+		"provider_message": "用户已接收",
+		"sent_at":          "2025-11-25T10:00:00Z",
+		"delivered_at":     "2025-11-25T10:00:03Z",
+	}
+
+	r := newSignedRequest(t, testSecret, payload)
+	event, err := ParseCallback(r, testSecret)
+	if err != nil {
+		t.Fatalf("ParseCallback() error = %v", err)
+	}
+
+	if event.TaskID != "task-1" || event.Status != "delivered" || event.AppID != "test_app_id" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.ProviderCode != "DELIVRD" || event.DeliveredAt != "2025-11-25T10:00:03Z" {
+		t.Errorf("unexpected provider fields: %+v", event)
+	}
+
+	// 请求体可以被后续逻辑再次读取
+	if body, _ := io.ReadAll(r.Body); len(body) == 0 {
+		t.Error("request body should be restored")
+	}
+}
+
+// TestParseCallbackInvalidSignature 测试签名错误
+func TestParseCallbackInvalidSignature(t *testing.T) {
+	r := newSignedRequest(t, "wrong_secret", deliveredPayload())
+	if _, err := ParseCallback(r, testSecret); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseCallback() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
@@ -0,0 +1,122 @@
+// Package callbacktest 提供回调接口的测试辅助工具
+//
+// Recorder 作为 callback.WithTestEventHandler 的处理函数记录收到的测试事件，
+// ExpectDelivery 调用 TestCallback 让网关投递测试事件，并确认回调处理器确实收到了该事件，
+// 可在每次发布后对回调链路（地址、网络、签名密钥）做端到端校验：
+//
+//	rec := callbacktest.NewRecorder()
+//	http.Handle("/callback", callback.NewHandler(secret, onEvent, callback.WithTestEventHandler(rec.Handle)))
+//
+//	if _, err := callbacktest.ExpectDelivery(ctx, client, rec); err != nil {
+//		log.Fatalf("回调链路异常: %v", err)
+//	}
+package callbacktest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+	"github.com/muleiwu/mliev-push-go/callback"
+)
+
+// DefaultPath NewRequest 构造的回调请求路径
+const DefaultPath = "/callback"
+
+// Recorder 记录回调处理器收到的测试事件，并发安全
+type Recorder struct {
+	mu      sync.Mutex
+	events  []*callback.CallbackEvent
+	changed chan struct{} // 收到新事件时关闭并替换
+}
+
+// NewRecorder 创建测试事件记录器
+func NewRecorder() *Recorder {
+	return &Recorder{changed: make(chan struct{})}
+}
+
+// Handle 记录测试事件，用作 callback.WithTestEventHandler 的处理函数
+func (r *Recorder) Handle(ctx context.Context, event *callback.CallbackEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	close(r.changed)
+	r.changed = make(chan struct{})
+	return nil
+}
+
+// Events 返回收到的全部测试事件（按接收顺序）
+func (r *Recorder) Events() []*callback.CallbackEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*callback.CallbackEvent(nil), r.events...)
+}
+
+// Expect 等待事件ID为 eventID 的测试事件，已收到时立即返回；ctx 结束时返回错误
+func (r *Recorder) Expect(ctx context.Context, eventID string) (*callback.CallbackEvent, error) {
+	for {
+		r.mu.Lock()
+		for _, event := range r.events {
+			if event.EventID == eventID {
+				r.mu.Unlock()
+				return event, nil
+			}
+		}
+		changed := r.changed
+		r.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("callbacktest: test event %s not received: %w", eventID, ctx.Err())
+		}
+	}
+}
+
+// ExpectDelivery 调用 TestCallback 让网关投递测试事件，确认投递成功且 rec 收到了该事件
+// 回调处理器部署在其他进程时 rec 收不到事件，此时直接检查 TestCallback 返回的投递结果即可
+func ExpectDelivery(ctx context.Context, client mlievpush.PushClient, rec *Recorder) (*mlievpush.TestCallbackData, error) {
+	data, err := client.TestCallback(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("callbacktest: test callback: %w", err)
+	}
+	if !data.Delivered {
+		return data, fmt.Errorf("callbacktest: test callback to %s not delivered (status %d): %s", data.CallbackURL, data.StatusCode, data.ErrorMessage)
+	}
+	if _, err := rec.Expect(ctx, data.EventID); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// NewRequest 构造与网关一致的已签名回调请求（路径为 DefaultPath），用于在单元测试中直接调用回调处理器
+// 与 httptest.NewRequest 一样，事件无法序列化时 panic
+func NewRequest(appID, appSecret string, event *callback.CallbackEvent) *http.Request {
+	body, err := json.Marshal(event)
+	if err != nil {
+		panic(fmt.Sprintf("callbacktest: marshal event: %v", err))
+	}
+	var params map[string]interface{}
+	if err := json.Unmarshal(body, &params); err != nil {
+		panic(fmt.Sprintf("callbacktest: unmarshal event: %v", err))
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := strconv.FormatInt(time.Now().UnixNano(), 36)
+	signature := mlievpush.GenerateSignature(http.MethodPost, DefaultPath, params, timestamp, nonce, appSecret)
+
+	r := httptest.NewRequest(http.MethodPost, DefaultPath, bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(mlievpush.HeaderAppID, appID)
+	r.Header.Set(mlievpush.HeaderTimestamp, timestamp)
+	r.Header.Set(mlievpush.HeaderNonce, nonce)
+	r.Header.Set(mlievpush.HeaderSignature, signature)
+	return r
+}
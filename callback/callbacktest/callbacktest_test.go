@@ -0,0 +1,91 @@
+package callbacktest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+	"github.com/muleiwu/mliev-push-go/callback"
+	"github.com/muleiwu/mliev-push-go/mlievpushtest"
+)
+
+// TestExpectDelivery 测试网关投递测试事件后确认收到
+func TestExpectDelivery(t *testing.T) {
+	srv := mlievpushtest.NewServer()
+	defer srv.Close()
+
+	var handled int
+	rec := NewRecorder()
+	handler := callback.NewHandler(srv.AppSecret, func(ctx context.Context, event *callback.CallbackEvent) error {
+		handled++
+		return nil
+	}, callback.WithTestEventHandler(rec.Handle))
+
+	// 模拟网关：向回调处理器投递已签名的测试事件
+	srv.Handle(http.MethodPost, "/api/v1/callbacks/test", func(r *mlievpushtest.Request) mlievpushtest.Response {
+		event := &callback.CallbackEvent{Type: callback.EventTypeTest, EventID: "evt-test-1", TaskID: "test-task", Status: mlievpush.CallbackStatusDelivered}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, NewRequest(srv.AppID, srv.AppSecret, event))
+		return mlievpushtest.OK(mlievpush.TestCallbackData{
+			EventID:     event.EventID,
+			CallbackURL: "https://example.com" + DefaultPath,
+			Delivered:   w.Code == http.StatusOK,
+			StatusCode:  w.Code,
+		})
+	})
+
+	data, err := ExpectDelivery(context.Background(), srv.Client(), rec)
+	if err != nil {
+		t.Fatalf("ExpectDelivery() error = %v", err)
+	}
+	if data.EventID != "evt-test-1" || len(rec.Events()) != 1 {
+		t.Errorf("data = %+v, events = %d", data, len(rec.Events()))
+	}
+	if handled != 0 {
+		t.Error("test events should not reach the event handler")
+	}
+}
+
+// TestExpectDeliveryFailed 测试投递失败和未收到事件
+func TestExpectDeliveryFailed(t *testing.T) {
+	srv := mlievpushtest.NewServer()
+	defer srv.Close()
+
+	srv.Enqueue(http.MethodPost, "/api/v1/callbacks/test",
+		mlievpushtest.OK(mlievpush.TestCallbackData{EventID: "evt-1", StatusCode: http.StatusUnauthorized, ErrorMessage: "unauthorized"}),
+		mlievpushtest.OK(mlievpush.TestCallbackData{EventID: "evt-2", Delivered: true, StatusCode: http.StatusOK}),
+	)
+
+	rec := NewRecorder()
+	if _, err := ExpectDelivery(context.Background(), srv.Client(), rec); err == nil {
+		t.Error("expected error for undelivered test callback")
+	}
+
+	// 投递成功但事件落到了其他处理器
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := ExpectDelivery(ctx, srv.Client(), rec); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestRecorderExpect 测试等待稍后到达的事件
+func TestRecorderExpect(t *testing.T) {
+	rec := NewRecorder()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		rec.Handle(context.Background(), &callback.CallbackEvent{EventID: "other"})
+		rec.Handle(context.Background(), &callback.CallbackEvent{EventID: "evt-1"})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	event, err := rec.Expect(ctx, "evt-1")
+	if err != nil || event.EventID != "evt-1" {
+		t.Errorf("Expect() = %+v, %v", event, err)
+	}
+}
@@ -0,0 +1,173 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// defaultFallbackTTL 补发去重记录的默认有效期
+const defaultFallbackTTL = 24 * time.Hour
+
+// DefaultFallbackTimeout 单次补发（查询原任务并发送）的默认超时时间
+const DefaultFallbackTimeout = 3 * time.Second
+
+// fallbackKeyPrefix 补发消息幂等键前缀: fallback:<原始任务ID>:<补发次数>
+const fallbackKeyPrefix = "fallback:"
+
+// FallbackPolicy 失败回调自动补发策略：收到失败/拒收回调后，使用原任务的参数通过备用通道重新发送
+type FallbackPolicy struct {
+	Client      mlievpush.PushClient // 用于查询原任务参数并补发的客户端（必填）
+	Routes      map[int]int          // 失败通道ID -> 备用通道ID，未配置的通道不补发
+	MaxAttempts int                  // 同一条原始消息最多补发次数（沿 Routes 逐级补发），0 使用 1
+	Statuses    []string             // 触发补发的回调状态，为空时使用 failed 和 rejected
+	Store       Store                // 补发去重存储（多实例部署时应使用共享存储），为nil时使用内存存储
+	TTL         time.Duration        // 去重记录有效期，0 使用24小时
+	// Timeout 单次补发（查询原任务并发送，含客户端重试）的超时时间，0 使用 DefaultFallbackTimeout
+	// 补发在回调请求内同步执行，会使回调响应最多延迟该时长，应小于网关的回调超时时间，避免网关在补发期间重试投递
+	Timeout time.Duration
+
+	// OnResend 补发结果回调（可选）；未触发补发（无备用通道、超出次数、重复事件）时不调用
+	OnResend func(ctx context.Context, event *CallbackEvent, data *mlievpush.SendMessageData, err error)
+}
+
+// WithFallback 设置失败回调自动补发策略，补发在处理函数成功后执行
+// 补发失败不会影响回调的确认（网关不会因此重试投递），结果通过 FallbackPolicy.OnResend 获取
+func WithFallback(policy FallbackPolicy) Option {
+	return func(h *Handler) {
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = 1
+		}
+		if len(policy.Statuses) == 0 {
+			policy.Statuses = []string{mlievpush.CallbackStatusFailed, mlievpush.CallbackStatusRejected}
+		}
+		if policy.Store == nil {
+			policy.Store = NewMemoryStore()
+		}
+		if policy.TTL <= 0 {
+			policy.TTL = defaultFallbackTTL
+		}
+		if policy.Timeout <= 0 {
+			policy.Timeout = DefaultFallbackTimeout
+		}
+		h.fallback = &policy
+	}
+}
+
+// FallbackIdempotencyKey 补发消息的幂等键: fallback:<原始任务ID>:<补发次数>
+// 同一原始任务的同一次补发使用相同的键，多个实例重复处理时由网关去重
+func FallbackIdempotencyKey(rootTaskID string, attempt int) string {
+	return fallbackKeyPrefix + rootTaskID + ":" + strconv.Itoa(attempt)
+}
+
+// parseFallbackKey 解析补发幂等键，非补发消息返回 ok=false
+func parseFallbackKey(key string) (rootTaskID string, attempt int, ok bool) {
+	rest, ok := strings.CutPrefix(key, fallbackKeyPrefix)
+	if !ok {
+		return "", 0, false
+	}
+	i := strings.LastIndex(rest, ":")
+	if i <= 0 {
+		return "", 0, false
+	}
+	attempt, err := strconv.Atoi(rest[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return rest[:i], attempt, true
+}
+
+// matches 判断事件状态是否触发补发
+func (p *FallbackPolicy) matches(status string) bool {
+	for _, s := range p.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// resend 按策略补发失败的消息
+// 以处理中状态占用 fallback:<任务ID>，补发成功或不满足补发条件时标记为已完成；失败或超时时释放占用，同一任务的后续失败回调可以重新补发
+// 补发受 Timeout 限制；占用、释放和完成不随回调请求取消，回调连接断开时也能释放占用
+func (p *FallbackPolicy) resend(h *Handler, r *http.Request, event *CallbackEvent) {
+	if !p.matches(event.Status) {
+		return
+	}
+	ctx := context.WithoutCancel(r.Context())
+	key := fallbackKeyPrefix + event.TaskID
+	state, err := p.Store.Claim(ctx, key, h.claimTTL)
+	if err != nil {
+		p.report(h, r, event, nil, fmt.Errorf("claim fallback for task %s: %w", event.TaskID, err))
+		return
+	}
+	if state != ClaimAcquired {
+		// 同一任务的其他失败事件已触发过补发或正在补发
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(r.Context(), p.Timeout)
+	data, err := p.send(sendCtx, event)
+	cancel()
+	if err != nil {
+		if releaseErr := p.Store.Release(ctx, key); releaseErr != nil {
+			err = errors.Join(err, fmt.Errorf("release fallback for task %s: %w", event.TaskID, releaseErr))
+		}
+	} else if completeErr := p.Store.Complete(ctx, key, p.TTL); completeErr != nil {
+		err = fmt.Errorf("complete fallback for task %s: %w", event.TaskID, completeErr)
+	}
+	if data == nil && err == nil {
+		return
+	}
+	p.report(h, r, event, data, err)
+}
+
+// send 查询原任务并通过备用通道补发，不满足补发条件时返回 nil, nil
+func (p *FallbackPolicy) send(ctx context.Context, event *CallbackEvent) (*mlievpush.SendMessageData, error) {
+	task, err := p.Client.QueryTask(ctx, event.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("query task %s: %w", event.TaskID, err)
+	}
+
+	channelID, ok := p.Routes[task.ChannelID]
+	if !ok {
+		return nil, nil
+	}
+	root, attempt := event.TaskID, 1
+	if r, n, ok := parseFallbackKey(task.IdempotencyKey); ok {
+		root, attempt = r, n+1
+	}
+	if attempt > p.MaxAttempts {
+		return nil, nil
+	}
+	if task.SignatureName == "" {
+		return nil, fmt.Errorf("fallback task %s: original request parameters are not available", event.TaskID)
+	}
+
+	return p.Client.SendMessage(ctx, &mlievpush.SendMessageRequest{
+		ChannelID:      channelID,
+		SignatureName:  task.SignatureName,
+		Receiver:       task.Receiver,
+		CountryCode:    task.CountryCode,
+		Region:         task.Region,
+		TemplateParams: task.TemplateParams,
+		IdempotencyKey: FallbackIdempotencyKey(root, attempt),
+		CallbackURL:    task.CallbackURL,
+	})
+}
+
+// report 调用补发结果回调，回调中的panic按处理器配置捕获并上报到错误回调
+func (p *FallbackPolicy) report(h *Handler, r *http.Request, event *CallbackEvent, data *mlievpush.SendMessageData, err error) {
+	if p.OnResend == nil {
+		return
+	}
+	h.safeCall(r, "OnResend", func() {
+		p.OnResend(r.Context(), event, data, err)
+	})
+}
@@ -0,0 +1,176 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// TestFallback 测试失败回调自动补发
+func TestFallback(t *testing.T) {
+	var sent []mlievpush.SendMessageRequest
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data interface{}
+		switch {
+		case r.Method == http.MethodPost:
+			var req mlievpush.SendMessageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			sent = append(sent, req)
+			data = map[string]interface{}{"task_id": "t2", "status": "pending"}
+		case strings.HasSuffix(r.URL.Path, "/t1"):
+			data = map[string]interface{}{"task_id": "t1", "channel_id": 1, "receiver": "13800138000", "signature_name": "test", "template_params": map[string]interface{}{"code": "1234"}}
+		case strings.HasSuffix(r.URL.Path, "/t2"):
+			data = map[string]interface{}{"task_id": "t2", "channel_id": 2, "receiver": "13800138000", "signature_name": "test", "idempotency_key": "fallback:t1:1"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": data})
+	}))
+	defer gateway.Close()
+
+	var resent []string
+	h := NewHandler(testSecret, nil, WithFallback(FallbackPolicy{
+		Client:      mlievpush.NewClient(gateway.URL, "test_app_id", "test_secret"),
+		Routes:      map[int]int{1: 2, 2: 3},
+		MaxAttempts: 1,
+		OnResend: func(ctx context.Context, event *CallbackEvent, data *mlievpush.SendMessageData, err error) {
+			if err != nil {
+				t.Errorf("OnResend error = %v", err)
+				return
+			}
+			resent = append(resent, event.TaskID+"->"+data.TaskID)
+		},
+	}))
+
+	deliver := func(eventID, taskID, status string) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newSignedRequest(t, testSecret, map[string]interface{}{"event_id": eventID, "task_id": taskID, "status": status}))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	deliver("evt-1", "t1", mlievpush.CallbackStatusFailed)
+	deliver("evt-2", "t1", mlievpush.CallbackStatusRejected) // 同一任务，不重复补发
+	deliver("evt-3", "t2", mlievpush.CallbackStatusFailed)   // 已达到最大补发次数
+	deliver("evt-4", "t3", mlievpush.CallbackStatusDelivered)
+
+	if len(sent) != 1 || len(resent) != 1 || resent[0] != "t1->t2" {
+		t.Fatalf("sent = %+v, resent = %v", sent, resent)
+	}
+	if sent[0].ChannelID != 2 || sent[0].IdempotencyKey != "fallback:t1:1" || sent[0].TemplateParams["code"] != "1234" {
+		t.Errorf("fallback request = %+v", sent[0])
+	}
+}
+
+// TestFallbackRetryAfterFailure 测试补发失败后释放占用，同一任务的下一次失败回调可以重新补发
+func TestFallbackRetryAfterFailure(t *testing.T) {
+	sends := 0
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data interface{}
+		if r.Method == http.MethodPost {
+			sends++
+			if sends == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			data = map[string]interface{}{"task_id": "t2", "status": "pending"}
+		} else {
+			data = map[string]interface{}{"task_id": "t1", "channel_id": 1, "receiver": "13800138000", "signature_name": "test"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": data})
+	}))
+	defer gateway.Close()
+
+	var results []error
+	h := NewHandler(testSecret, nil, WithStore(NewMemoryStore(), time.Hour), WithFallback(FallbackPolicy{
+		Client: mlievpush.NewClient(gateway.URL, "test_app_id", "test_secret"),
+		Routes: map[int]int{1: 2},
+		OnResend: func(ctx context.Context, event *CallbackEvent, data *mlievpush.SendMessageData, err error) {
+			results = append(results, err)
+		},
+	}))
+
+	for _, eventID := range []string{"evt-1", "evt-2", "evt-3"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newSignedRequest(t, testSecret, map[string]interface{}{"event_id": eventID, "task_id": "t1", "status": mlievpush.CallbackStatusFailed}))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	// 第一次补发失败，第二次成功，第三次已完成不再补发
+	if sends != 2 || len(results) != 2 || results[0] == nil || results[1] != nil {
+		t.Errorf("sends = %d, results = %v", sends, results)
+	}
+}
+
+// TestFallbackOnResendPanic 测试补发结果回调中的panic上报到错误回调
+func TestFallbackOnResendPanic(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer gateway.Close()
+
+	var reported error
+	h := NewHandler(testSecret, nil, WithErrorHandler(func(r *http.Request, err error) { reported = err }), WithFallback(FallbackPolicy{
+		Client: mlievpush.NewClient(gateway.URL, "test_app_id", "test_secret"),
+		Routes: map[int]int{1: 2},
+		OnResend: func(ctx context.Context, event *CallbackEvent, data *mlievpush.SendMessageData, err error) {
+			panic("boom")
+		},
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newSignedRequest(t, testSecret, map[string]interface{}{"event_id": "evt-1", "task_id": "t1", "status": mlievpush.CallbackStatusFailed}))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var panicErr *mlievpush.PanicError
+	if !errors.As(reported, &panicErr) || panicErr.Callback != "OnResend" {
+		t.Errorf("reported = %v, want *PanicError from OnResend", reported)
+	}
+}
+
+// TestFallbackTimeout 测试补发超时后回调及时响应，并释放占用
+func TestFallbackTimeout(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer gateway.Close()
+
+	var results []error
+	store := NewMemoryStore()
+	h := NewHandler(testSecret, nil, WithFallback(FallbackPolicy{
+		Client:  mlievpush.NewClient(gateway.URL, "test_app_id", "test_secret"),
+		Routes:  map[int]int{1: 2},
+		Store:   store,
+		Timeout: 20 * time.Millisecond,
+		OnResend: func(ctx context.Context, event *CallbackEvent, data *mlievpush.SendMessageData, err error) {
+			results = append(results, err)
+		},
+	}))
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newSignedRequest(t, testSecret, map[string]interface{}{"event_id": "evt-1", "task_id": "t1", "status": mlievpush.CallbackStatusFailed}))
+	if rec.Code != http.StatusOK || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("status = %d after %s", rec.Code, time.Since(start))
+	}
+	if len(results) != 1 || !errors.Is(results[0], context.DeadlineExceeded) {
+		t.Errorf("results = %v, want deadline exceeded", results)
+	}
+	if state, _ := store.Claim(context.Background(), "fallback:t1", time.Minute); state != ClaimAcquired {
+		t.Errorf("fallback claim after timeout = %d, want released", state)
+	}
+}
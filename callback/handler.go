@@ -0,0 +1,445 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// DefaultStoreTTL 已处理事件ID的默认保留时间
+const DefaultStoreTTL = 24 * time.Hour
+
+// DefaultClaimTTL 事件处理中占用的默认时长，处理进程崩溃后占用到期，网关重试时可以重新处理
+const DefaultClaimTTL = 5 * time.Minute
+
+// EventHandler 回调事件处理函数，返回错误时网关会重试投递
+type EventHandler func(ctx context.Context, event *CallbackEvent) error
+
+// SecretLookup 根据应用ID查找应用密钥，应用不存在时应返回 ErrUnknownApp
+type SecretLookup = mlievpush.SecretLookup
+
+// ErrUnknownApp 回调来自未知应用
+var ErrUnknownApp = mlievpush.ErrUnknownApp
+
+// Handler 回调 HTTP 处理器
+type Handler struct {
+	lookup        SecretLookup                     // 应用密钥查找
+	nonces        mlievpush.NonceCache             // 随机数缓存（可选，用于防重放）
+	maxClockSkew  time.Duration                    // 允许的最大时钟偏差
+	verifier      *mlievpush.Verifier              // 签名校验器
+	handle        EventHandler                     // 事件处理函数
+	store         Store                            // 已处理事件存储（可选）
+	storeTTL      time.Duration                    // 事件ID保留时间
+	claimTTL      time.Duration                    // 处理中占用时长
+	onError       func(r *http.Request, err error) // 错误回调
+	recoverPanics bool                             // 是否捕获处理函数中的panic
+	logger        mlievpush.Logger                 // 日志输出（错误回调自身panic时使用）
+	stateStore    StateStore                       // 任务状态存储（可选，用于乱序保护）
+	onOutOfOrder  OutOfOrderFunc                   // 乱序事件回调
+	publish       EventHandler                     // 事件转发（可选）
+	eventStore    EventStore                       // 事件历史存储（可选）
+	fallback      *FallbackPolicy                  // 失败回调自动补发策略（可选）
+
+	onUnknownVersion func(ctx context.Context, event *UnknownEventVersion) error // 未知版本事件处理（可选）
+	watcher          *TaskWatcher                                                // 任务通知器（可选）
+	onTestEvent      EventHandler                                                // 测试事件处理（可选）
+}
+
+// Option 回调处理器配置选项
+type Option func(*Handler)
+
+// WithStore 设置已处理事件存储，重复投递的事件会直接确认而不再处理
+func WithStore(store Store, ttl time.Duration) Option {
+	return func(h *Handler) {
+		h.store = store
+		if ttl > 0 {
+			h.storeTTL = ttl
+		}
+	}
+}
+
+// WithClaimTTL 设置事件处理中占用的时长（默认 DefaultClaimTTL），应大于处理函数的最长执行时间
+func WithClaimTTL(ttl time.Duration) Option {
+	return func(h *Handler) {
+		if ttl > 0 {
+			h.claimTTL = ttl
+		}
+	}
+}
+
+// WithNonceCache 设置随机数缓存，拒绝重放的回调请求
+func WithNonceCache(cache mlievpush.NonceCache) Option {
+	return func(h *Handler) {
+		h.nonces = cache
+	}
+}
+
+// WithMaxClockSkew 设置允许的最大时钟偏差（默认 mlievpush.DefaultMaxClockSkew）
+// 超出时返回 401，错误回调收到的错误满足 errors.Is(err, ErrTimestampSkew)
+func WithMaxClockSkew(skew time.Duration) Option {
+	return func(h *Handler) {
+		h.maxClockSkew = skew
+	}
+}
+
+// WithErrorHandler 设置错误回调（签名失败、解析失败、处理失败、panic）
+func WithErrorHandler(fn func(r *http.Request, err error)) Option {
+	return func(h *Handler) {
+		h.onError = fn
+	}
+}
+
+// WithPanicRecovery 设置是否捕获处理函数中的panic（默认开启）
+func WithPanicRecovery(enabled bool) Option {
+	return func(h *Handler) {
+		h.recoverPanics = enabled
+	}
+}
+
+// WithLogger 设置日志输出，错误回调本身发生panic时无法再上报给错误回调，记录到该日志（默认使用标准库 log）
+func WithLogger(logger mlievpush.Logger) Option {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// WithTestEventHandler 设置测试事件（type 为 "test"）的处理函数，返回错误时响应 500
+// 测试事件校验签名后直接确认，不经过去重、乱序保护和事件处理函数，业务代码不会收到合成的任务
+func WithTestEventHandler(fn EventHandler) Option {
+	return func(h *Handler) {
+		h.onTestEvent = fn
+	}
+}
+
+// NewHandler 创建单应用回调处理器
+func NewHandler(appSecret string, handle EventHandler, opts ...Option) *Handler {
+	lookup := func(ctx context.Context, appID string) (string, error) {
+		return appSecret, nil
+	}
+	return NewMultiTenantHandler(lookup, handle, opts...)
+}
+
+// NewMultiTenantHandler 创建多应用回调处理器
+// 根据 X-App-Id 请求头查找对应的密钥进行校验，多个租户应用可共用同一个回调地址
+func NewMultiTenantHandler(lookup SecretLookup, handle EventHandler, opts ...Option) *Handler {
+	h := &Handler{
+		lookup:        lookup,
+		handle:        handle,
+		storeTTL:      DefaultStoreTTL,
+		claimTTL:      DefaultClaimTTL,
+		recoverPanics: true,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	verifierOpts := []mlievpush.VerifierOption{mlievpush.WithMaxClockSkew(h.maxClockSkew)}
+	if h.nonces != nil {
+		verifierOpts = append(verifierOpts, mlievpush.WithNonceCache(h.nonces))
+	}
+	h.verifier = mlievpush.NewVerifier(h.lookup, verifierOpts...)
+
+	return h
+}
+
+// ServeHTTP 实现 http.Handler 接口
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	event, err := parseCallback(r, h.verifier)
+	var unknown *UnknownEventVersion
+	if errors.As(err, &unknown) && h.onUnknownVersion != nil {
+		err = h.onUnknownVersion(r.Context(), unknown)
+		if err == nil {
+			writeResult(w, http.StatusOK, "success")
+			return
+		}
+	}
+	if err != nil {
+		h.reportError(r, err)
+		status := mlievpush.VerifyErrorStatus(err)
+		writeResult(w, status, http.StatusText(status))
+		return
+	}
+
+	if event.IsTest() {
+		if h.onTestEvent != nil {
+			if err := h.onTestEvent(r.Context(), event); err != nil {
+				h.reportError(r, err)
+				writeResult(w, http.StatusInternalServerError, "process failed")
+				return
+			}
+		}
+		writeResult(w, http.StatusOK, "success")
+		return
+	}
+
+	if err := h.process(r, event); err != nil {
+		h.reportError(r, err)
+		writeResult(w, http.StatusInternalServerError, "process failed")
+		return
+	}
+
+	writeResult(w, http.StatusOK, "success")
+}
+
+// process 去重后依次调用处理函数、保存任务状态、补发与通知、转发事件和写入事件历史
+// 配置 Store 时每个步骤单独占用，成功后才标记为已完成：后面的步骤失败时网关重试只重新执行未完成的步骤，不会再次调用处理函数
+// 乱序事件（分发前检查或保存状态时原子比较发现）另外完成 <ID>:stale 标记，重试时据此恢复分发结果，不会对旧事件补发、通知或转发
+func (h *Handler) process(r *http.Request, event *CallbackEvent) error {
+	ctx := r.Context()
+	id := eventKey(event)
+	handled, decided := true, false
+	if err := h.step(ctx, id, func() (err error) {
+		if handled, err = h.dispatch(r, event); err != nil {
+			return err
+		}
+		decided = true
+		if !handled {
+			return h.markStale(ctx, id)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if !decided && h.store != nil {
+		// 之前的投递已完成分发步骤，按乱序标记恢复分发结果
+		stale, err := h.isStale(ctx, id)
+		if err != nil {
+			return err
+		}
+		handled = !stale
+	}
+	if h.stateStore != nil {
+		if err := h.step(ctx, id+":state", func() error {
+			if !handled {
+				// 乱序事件不更新状态
+				return nil
+			}
+			current, saved, err := h.stateStore.SaveIfNewer(ctx, eventState(event))
+			if err != nil {
+				return fmt.Errorf("save task state %s: %w", event.TaskID, err)
+			}
+			if !saved {
+				// 并发投递的更新事件已先保存，本事件按乱序处理，不再补发、通知和转发
+				handled = false
+				h.reportOutOfOrder(r, event, current)
+				return h.markStale(ctx, id)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	if err := h.step(ctx, id+":notify", func() error {
+		if !handled {
+			return nil
+		}
+		if h.fallback != nil {
+			h.fallback.resend(h, r, event)
+		}
+		h.notify(event)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if h.publish != nil {
+		if err := h.step(ctx, id+":publish", func() error {
+			if !handled {
+				// 乱序事件不转发
+				return nil
+			}
+			return h.call(ctx, h.publish, event)
+		}); err != nil {
+			return err
+		}
+	}
+	if h.eventStore != nil {
+		return h.step(ctx, id+":record", func() error {
+			if err := h.eventStore.Append(ctx, EventRecord{ReceivedAt: time.Now(), Event: event}); err != nil {
+				return fmt.Errorf("record event %s: %w", event.TaskID, err)
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// step 使用处理中占用执行一个处理步骤，未配置 Store 时直接执行
+// 步骤已完成时跳过；其他请求正在处理时返回 ErrEventInProgress；失败时释放占用，让网关重试时能重新处理
+func (h *Handler) step(ctx context.Context, id string, run func() error) error {
+	if h.store == nil {
+		return run()
+	}
+
+	state, err := h.store.Claim(ctx, id, h.claimTTL)
+	if err != nil {
+		return fmt.Errorf("claim event %s: %w", id, err)
+	}
+	switch state {
+	case ClaimDone:
+		// 已处理过的重复投递，直接确认
+		return nil
+	case ClaimInProgress:
+		return fmt.Errorf("event %s: %w", id, ErrEventInProgress)
+	}
+
+	if err := run(); err != nil {
+		if releaseErr := h.store.Release(ctx, id); releaseErr != nil {
+			return errors.Join(err, fmt.Errorf("release event %s: %w", id, releaseErr))
+		}
+		return err
+	}
+	if err := h.store.Complete(ctx, id, h.storeTTL); err != nil {
+		return fmt.Errorf("complete event %s: %w", id, err)
+	}
+	return nil
+}
+
+// markStale 完成事件的乱序标记，未配置 Store 时不需要标记
+func (h *Handler) markStale(ctx context.Context, id string) error {
+	if h.store == nil {
+		return nil
+	}
+	key := id + ":stale"
+	if _, err := h.store.Claim(ctx, key, h.claimTTL); err != nil {
+		return fmt.Errorf("claim event %s: %w", key, err)
+	}
+	if err := h.store.Complete(ctx, key, h.storeTTL); err != nil {
+		return fmt.Errorf("complete event %s: %w", key, err)
+	}
+	return nil
+}
+
+// isStale 判断事件是否已被标记为乱序，Store 没有查询接口，通过占用探测：已完成表示有标记，占用成功则立即释放
+func (h *Handler) isStale(ctx context.Context, id string) (bool, error) {
+	key := id + ":stale"
+	state, err := h.store.Claim(ctx, key, h.claimTTL)
+	if err != nil {
+		return false, fmt.Errorf("claim event %s: %w", key, err)
+	}
+	switch state {
+	case ClaimDone:
+		return true, nil
+	case ClaimInProgress:
+		return false, fmt.Errorf("event %s: %w", key, ErrEventInProgress)
+	}
+	if err := h.store.Release(ctx, key); err != nil {
+		return false, fmt.Errorf("release event %s: %w", key, err)
+	}
+	return false, nil
+}
+
+// dispatch 检查事件顺序后调用处理函数，返回事件是否被处理（乱序事件返回 false）
+func (h *Handler) dispatch(r *http.Request, event *CallbackEvent) (bool, error) {
+	ctx := r.Context()
+	if h.stateStore != nil {
+		current, ok, err := h.stateStore.Load(ctx, event.TaskID)
+		if err != nil {
+			return false, fmt.Errorf("load task state %s: %w", event.TaskID, err)
+		}
+		if ok && !IsNewer(current, eventState(event)) {
+			h.reportOutOfOrder(r, event, current)
+			return false, nil
+		}
+	}
+
+	if h.handle != nil {
+		if err := h.call(ctx, h.handle, event); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// call 调用处理函数，按配置捕获panic
+func (h *Handler) call(ctx context.Context, fn EventHandler, event *CallbackEvent) (err error) {
+	if h.recoverPanics {
+		defer func() {
+			if v := recover(); v != nil {
+				err = fmt.Errorf("panic in callback handler: %v\n%s", v, debug.Stack())
+			}
+		}()
+	}
+	return fn(ctx, event)
+}
+
+// reportOutOfOrder 调用乱序事件回调
+func (h *Handler) reportOutOfOrder(r *http.Request, event *CallbackEvent, current TaskState) {
+	if h.onOutOfOrder == nil {
+		return
+	}
+	h.safeCall(r, "OnOutOfOrder", func() {
+		h.onOutOfOrder(r.Context(), event, current)
+	})
+}
+
+// safeCall 调用不返回错误的用户回调，按配置捕获panic，捕获的panic转换为 *mlievpush.PanicError 上报到错误回调
+func (h *Handler) safeCall(r *http.Request, name string, fn func()) {
+	if !h.recoverPanics {
+		fn()
+		return
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			h.reportError(r, &mlievpush.PanicError{Callback: name, Value: v, Stack: debug.Stack()})
+		}
+	}()
+	fn()
+}
+
+// reportError 调用错误回调，按配置捕获错误回调中的panic并连同调用栈记录到日志
+func (h *Handler) reportError(r *http.Request, err error) {
+	if h.onError == nil {
+		return
+	}
+	if h.recoverPanics {
+		defer func() {
+			if v := recover(); v != nil {
+				h.logPanic(&mlievpush.PanicError{Callback: "OnError", Value: v, Stack: debug.Stack()}, err)
+			}
+		}()
+	}
+	h.onError(r, err)
+}
+
+// logPanic 记录错误回调中的panic
+func (h *Handler) logPanic(panicErr *mlievpush.PanicError, err error) {
+	if h.logger != nil {
+		h.logger.Errorf("mlievpush callback: %v while reporting %q\n%s", panicErr, err, panicErr.Stack)
+		return
+	}
+	log.Printf("mlievpush callback: %v while reporting %q\n%s", panicErr, err, panicErr.Stack)
+}
+
+// eventKey 事件去重键，优先使用事件ID，缺失时使用投递随机数；按应用隔离
+func eventKey(event *CallbackEvent) string {
+	key := "nonce:" + event.Nonce
+	if event.EventID != "" {
+		key = event.EventID
+	}
+	if event.AppID != "" {
+		key = event.AppID + ":" + key
+	}
+	return key
+}
+
+// writeResult 输出与API一致的响应结构
+func writeResult(w http.ResponseWriter, status int, message string) {
+	code := 0
+	if status != http.StatusOK {
+		code = status
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    code,
+		"message": message,
+	})
+}
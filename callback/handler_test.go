@@ -0,0 +1,500 @@
+package callback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+const testSecret = "test_secret"
+
+// newSignedRequest 构造带签名的回调请求
+func newSignedRequest(t *testing.T, secret string, payload map[string]interface{}) *http.Request {
+	t.Helper()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var params map[string]interface{}
+	json.Unmarshal(body, &params)
+
+	path := "/push/callback"
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := "nonce-" + timestamp
+	signature := mlievpush.GenerateSignature(http.MethodPost, path, params, timestamp, nonce, secret)
+
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	r.Header.Set(HeaderAppID, "test_app_id")
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderSignature, signature)
+	return r
+}
+
+func deliveredPayload() map[string]interface{} {
+	return map[string]interface{}{
+		"event_id": "evt-1",
+		"task_id":  "550e8400-e29b-41d4-a716-446655440000",
+		"status":   mlievpush.CallbackStatusDelivered,
+	}
+}
+
+// TestHandler 测试回调校验与分发
+func TestHandler(t *testing.T) {
+	var got *CallbackEvent
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		got = event
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got == nil || got.TaskID != "550e8400-e29b-41d4-a716-446655440000" || got.Status != "delivered" {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+}
+
+// TestHandlerInvalidSignature 测试签名错误
+func TestHandlerInvalidSignature(t *testing.T) {
+	called := false
+	var reported error
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		called = true
+		return nil
+	}, WithErrorHandler(func(r *http.Request, err error) { reported = err }))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, "wrong_secret", deliveredPayload()))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if called {
+		t.Error("handler should not be called")
+	}
+	if !errors.Is(reported, ErrInvalidSignature) {
+		t.Errorf("reported error = %v, want %v", reported, ErrInvalidSignature)
+	}
+}
+
+// TestHandlerStoreDedupe 测试重复投递只处理一次
+func TestHandlerStoreDedupe(t *testing.T) {
+	calls := 0
+	fail := true
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		calls++
+		if fail {
+			return errors.New("db unavailable")
+		}
+		return nil
+	}, WithStore(NewMemoryStore(), time.Hour))
+
+	// 第一次处理失败，返回500让网关重试
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	// 重试成功
+	fail = false
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// 重复投递被确认但不再处理
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+// TestHandlerPanic 测试处理函数panic被捕获
+func TestHandlerPanic(t *testing.T) {
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+// TestMemoryStore 测试内存存储的占用、完成与过期
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if state, _ := s.Claim(ctx, "a", time.Minute); state != ClaimAcquired {
+		t.Fatalf("first claim = %d, want acquired", state)
+	}
+	if state, _ := s.Claim(ctx, "a", time.Minute); state != ClaimInProgress {
+		t.Fatalf("second claim = %d, want in progress", state)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if state, _ := s.Claim(ctx, "a", time.Minute); state != ClaimAcquired {
+		t.Fatalf("claim after expiry = %d, want acquired", state)
+	}
+
+	s.Complete(ctx, "a", time.Hour)
+	now = now.Add(30 * time.Minute)
+	if state, _ := s.Claim(ctx, "a", time.Minute); state != ClaimDone {
+		t.Fatalf("claim after complete = %d, want done", state)
+	}
+
+	s.Release(ctx, "a")
+	if state, _ := s.Claim(ctx, "a", time.Minute); state != ClaimAcquired {
+		t.Fatalf("claim after release = %d, want acquired", state)
+	}
+}
+
+// TestHandlerStoreSteps 测试转发失败时网关重试只重新转发，不再调用处理函数
+func TestHandlerStoreSteps(t *testing.T) {
+	calls, published := 0, 0
+	fail := true
+	pub := PublisherFunc(func(ctx context.Context, msg *Message) error {
+		if fail {
+			return errors.New("broker down")
+		}
+		published++
+		return nil
+	})
+	store := NewMemoryStore()
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		calls++
+		return nil
+	}, WithStore(store, time.Hour), WithPublisher(pub, "push.delivery"))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+
+	fail = false
+	for i := 0; i < 2; i++ {
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", w.Code)
+		}
+	}
+	if calls != 1 || published != 1 {
+		t.Errorf("handler calls = %d, published = %d, want 1 and 1", calls, published)
+	}
+}
+
+// failingStateStore 保存失败的任务状态存储
+type failingStateStore struct {
+	*MemoryStateStore
+	fail bool
+}
+
+// SaveIfNewer 实现 StateStore 接口
+func (s *failingStateStore) SaveIfNewer(ctx context.Context, state TaskState) (TaskState, bool, error) {
+	if s.fail {
+		return TaskState{}, false, errors.New("state store down")
+	}
+	return s.MemoryStateStore.SaveIfNewer(ctx, state)
+}
+
+// TestHandlerStoreStateStep 测试保存任务状态失败时网关重试只重新保存状态，不再调用处理函数
+func TestHandlerStoreStateStep(t *testing.T) {
+	calls := 0
+	states := &failingStateStore{MemoryStateStore: NewMemoryStateStore(time.Hour), fail: true}
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		calls++
+		return nil
+	}, WithStore(NewMemoryStore(), time.Hour), WithOrdering(states, nil))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+
+	states.fail = false
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if _, ok, _ := states.Load(context.Background(), "550e8400-e29b-41d4-a716-446655440000"); calls != 1 || !ok {
+		t.Errorf("handler calls = %d, state saved = %v, want 1 and true", calls, ok)
+	}
+}
+
+// failingEventStore 写入失败的事件历史存储
+type failingEventStore struct {
+	*MemoryEventStore
+	fail bool
+}
+
+// Append 实现 EventStore 接口
+func (s *failingEventStore) Append(ctx context.Context, record EventRecord) error {
+	if s.fail {
+		return errors.New("history store down")
+	}
+	return s.MemoryEventStore.Append(ctx, record)
+}
+
+// TestHandlerStoreStaleRedelivery 测试写入事件历史失败后重试投递：乱序事件仍不通知，正常事件只通知一次
+func TestHandlerStoreStaleRedelivery(t *testing.T) {
+	watcher := NewTaskWatcher()
+	updates, cancel := watcher.Subscribe("task-1")
+	defer cancel()
+	history := &failingEventStore{MemoryEventStore: NewMemoryEventStore()}
+	calls := 0
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		calls++
+		return nil
+	}, WithStore(NewMemoryStore(), time.Hour), WithOrdering(NewMemoryStateStore(time.Hour), nil),
+		WithEventStore(history), WithTaskWatcher(watcher))
+
+	deliver := func(eventID, status string, seq int) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newSignedRequest(t, testSecret, map[string]interface{}{
+			"event_id": eventID,
+			"task_id":  "task-1",
+			"status":   status,
+			"sequence": seq,
+		}))
+		return w.Code
+	}
+	notified := func() int {
+		n := 0
+		for {
+			select {
+			case <-updates:
+				n++
+			default:
+				return n
+			}
+		}
+	}
+
+	if code := deliver("e2", mlievpush.CallbackStatusDelivered, 2); code != http.StatusOK || notified() != 1 {
+		t.Fatalf("in-order event status = %d", code)
+	}
+
+	// 乱序事件写入事件历史失败，重试投递时仍按乱序处理
+	history.fail = true
+	if code := deliver("e1", mlievpush.TaskStatusProcessing, 1); code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", code)
+	}
+	history.fail = false
+	if code := deliver("e1", mlievpush.TaskStatusProcessing, 1); code != http.StatusOK {
+		t.Fatalf("redelivery status = %d, want 200", code)
+	}
+	if n := notified(); n != 0 || calls != 1 {
+		t.Errorf("out-of-order event: notified = %d, handler calls = %d, want 0 and 1", n, calls)
+	}
+
+	// 正常事件写入事件历史失败，重试投递时不再通知
+	history.fail = true
+	deliver("e3", mlievpush.CallbackStatusDelivered, 3)
+	if n := notified(); n != 1 {
+		t.Fatalf("notified = %d, want 1", n)
+	}
+	history.fail = false
+	if code := deliver("e3", mlievpush.CallbackStatusDelivered, 3); code != http.StatusOK {
+		t.Fatalf("redelivery status = %d, want 200", code)
+	}
+	if n := notified(); n != 0 || calls != 2 {
+		t.Errorf("in-order event redelivery: notified = %d, handler calls = %d, want 0 and 2", n, calls)
+	}
+}
+
+// testLogger 记录错误日志
+type testLogger struct {
+	errors []string
+}
+
+func (l *testLogger) Debugf(format string, args ...interface{}) {}
+func (l *testLogger) Infof(format string, args ...interface{})  {}
+func (l *testLogger) Errorf(format string, args ...interface{}) {
+	l.errors = append(l.errors, fmt.Sprintf(format, args...))
+}
+
+// TestHandlerErrorHandlerPanic 测试错误回调中的panic连同调用栈记录到日志
+func TestHandlerErrorHandlerPanic(t *testing.T) {
+	logger := &testLogger{}
+	h := NewHandler(testSecret, nil, WithLogger(logger), WithErrorHandler(func(r *http.Request, err error) {
+		panic("boom")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, "wrong_secret", deliveredPayload()))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+	if len(logger.errors) != 1 || !strings.Contains(logger.errors[0], "panic in OnError: boom") || !strings.Contains(logger.errors[0], "goroutine") {
+		t.Errorf("logged = %q", logger.errors)
+	}
+}
+
+// TestHandlerStoreInProgress 测试同一事件处理中时重复投递返回500，而不是在处理完成前确认
+func TestHandlerStoreInProgress(t *testing.T) {
+	store := NewMemoryStore()
+	var reported error
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		return nil
+	}, WithStore(store, time.Hour), WithErrorHandler(func(r *http.Request, err error) { reported = err }))
+
+	event, err := ParseCallback(newSignedRequest(t, testSecret, deliveredPayload()), testSecret)
+	if err != nil {
+		t.Fatalf("ParseCallback() error = %v", err)
+	}
+	store.Claim(context.Background(), eventKey(event), time.Minute)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusInternalServerError || !errors.Is(reported, ErrEventInProgress) {
+		t.Errorf("status = %d, reported = %v, want 500 and ErrEventInProgress", w.Code, reported)
+	}
+}
+
+// TestMultiTenantHandler 测试多应用按 X-App-Id 查找密钥
+func TestMultiTenantHandler(t *testing.T) {
+	secrets := map[string]string{
+		"tenant_a": "secret_a",
+		"tenant_b": "secret_b",
+	}
+	lookup := func(ctx context.Context, appID string) (string, error) {
+		secret, ok := secrets[appID]
+		if !ok {
+			return "", ErrUnknownApp
+		}
+		return secret, nil
+	}
+
+	var apps []string
+	h := NewMultiTenantHandler(lookup, func(ctx context.Context, event *CallbackEvent) error {
+		apps = append(apps, event.AppID)
+		return nil
+	})
+
+	deliver := func(appID, secret string) int {
+		r := newSignedRequest(t, secret, deliveredPayload())
+		r.Header.Set(HeaderAppID, appID)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := deliver("tenant_a", "secret_a"); code != http.StatusOK {
+		t.Errorf("tenant_a status = %d, want %d", code, http.StatusOK)
+	}
+	if code := deliver("tenant_b", "secret_b"); code != http.StatusOK {
+		t.Errorf("tenant_b status = %d, want %d", code, http.StatusOK)
+	}
+	// 用其他租户的密钥签名
+	if code := deliver("tenant_a", "secret_b"); code != http.StatusUnauthorized {
+		t.Errorf("cross-tenant status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := deliver("tenant_c", "secret_c"); code != http.StatusUnauthorized {
+		t.Errorf("unknown app status = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	if len(apps) != 2 || apps[0] != "tenant_a" || apps[1] != "tenant_b" {
+		t.Errorf("dispatched apps = %v", apps)
+	}
+}
+
+// TestHandlerNonceReplay 测试重放的回调请求被拒绝
+func TestHandlerNonceReplay(t *testing.T) {
+	calls := 0
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		calls++
+		return nil
+	}, WithNonceCache(mlievpush.NewMemoryNonceCache()))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// 相同的随机数再次投递
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("replay status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+// TestHandlerClockSkew 测试时钟偏差与签名错误区分
+func TestHandlerClockSkew(t *testing.T) {
+	var gotErr error
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		return nil
+	}, WithMaxClockSkew(time.Minute), WithErrorHandler(func(r *http.Request, err error) {
+		gotErr = err
+	}))
+
+	r := newSignedRequest(t, testSecret, deliveredPayload())
+	r.Header.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Add(-2*time.Minute).Unix(), 10))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if !errors.Is(gotErr, ErrTimestampSkew) {
+		t.Errorf("error = %v, want %v", gotErr, ErrTimestampSkew)
+	}
+}
+
+// TestTaskWatcher 测试回调通知等待中的任务
+func TestTaskWatcher(t *testing.T) {
+	watcher := NewTaskWatcher()
+	updates, cancel := watcher.Subscribe("550e8400-e29b-41d4-a716-446655440000")
+	defer cancel()
+
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error { return nil }, WithTaskWatcher(watcher))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	select {
+	case <-updates:
+	default:
+		t.Error("expected notification")
+	}
+
+	cancel()
+	watcher.Notify("550e8400-e29b-41d4-a716-446655440000")
+	if len(watcher.subs) != 0 {
+		t.Error("expected subscription to be removed")
+	}
+}
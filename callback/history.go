@@ -0,0 +1,154 @@
+package callback
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EventRecord 事件历史记录
+type EventRecord struct {
+	ReceivedAt time.Time      `json:"received_at"` // 接收时间
+	Event      *CallbackEvent `json:"event"`       // 回调事件
+}
+
+// EventStore 投递事件历史存储
+type EventStore interface {
+	// Append 追加一条已处理的事件
+	Append(ctx context.Context, record EventRecord) error
+	// TaskHistory 按事件序号（相同时按接收时间）返回任务的事件历史，任务不存在时返回空
+	TaskHistory(ctx context.Context, taskID string) ([]EventRecord, error)
+}
+
+// WithEventStore 设置事件历史存储，处理成功的事件（包括被乱序保护忽略的旧事件）都会写入
+// 写入失败时返回 500，网关会重试投递
+func WithEventStore(store EventStore) Option {
+	return func(h *Handler) {
+		h.eventStore = store
+	}
+}
+
+// MemoryEventStore 基于内存的事件历史存储，适用于测试和单实例部署
+type MemoryEventStore struct {
+	mu     sync.RWMutex
+	byTask map[string][]EventRecord
+}
+
+// NewMemoryEventStore 创建内存事件历史存储
+func NewMemoryEventStore() *MemoryEventStore {
+	return &MemoryEventStore{byTask: make(map[string][]EventRecord)}
+}
+
+// Append 实现 EventStore 接口
+func (s *MemoryEventStore) Append(ctx context.Context, record EventRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.byTask[record.Event.TaskID] = append(s.byTask[record.Event.TaskID], record)
+	return nil
+}
+
+// TaskHistory 实现 EventStore 接口
+func (s *MemoryEventStore) TaskHistory(ctx context.Context, taskID string) ([]EventRecord, error) {
+	s.mu.RLock()
+	records := append([]EventRecord(nil), s.byTask[taskID]...)
+	s.mu.RUnlock()
+
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Event.Sequence != b.Event.Sequence {
+			return a.Event.Sequence < b.Event.Sequence
+		}
+		return a.ReceivedAt.Before(b.ReceivedAt)
+	})
+	return records, nil
+}
+
+// FileEventStore 基于本地文件的事件历史存储（JSON Lines，追加写入），适用于无需独立数据库的小型部署
+// 打开时将历史加载到内存建立索引，数据量较大时请使用数据库实现 EventStore
+type FileEventStore struct {
+	mu     sync.Mutex
+	file   *os.File
+	memory *MemoryEventStore
+}
+
+// OpenFileEventStore 打开（不存在时创建）事件历史文件
+func OpenFileEventStore(path string) (*FileEventStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open event store: %w", err)
+	}
+
+	s := &FileEventStore{file: file, memory: NewMemoryEventStore()}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var record EventRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.Event == nil {
+			// 进程崩溃可能留下写了一半的最后一行，跳过
+			continue
+		}
+		s.memory.Append(context.Background(), record)
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("read event store: %w", err)
+	}
+	if err := terminateLastLine(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// terminateLastLine 文件末尾缺少换行（写了一半的记录）时补上，避免后续记录与其拼接
+func terminateLastLine(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("read event store: %w", err)
+	}
+	if last[0] != '\n' {
+		if _, err := file.Write([]byte{'\n'}); err != nil {
+			return fmt.Errorf("repair event store: %w", err)
+		}
+	}
+	return nil
+}
+
+// Append 实现 EventStore 接口
+func (s *FileEventStore) Append(ctx context.Context, record EventRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal event record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write event record: %w", err)
+	}
+	return s.memory.Append(ctx, record)
+}
+
+// TaskHistory 实现 EventStore 接口
+func (s *FileEventStore) TaskHistory(ctx context.Context, taskID string) ([]EventRecord, error) {
+	return s.memory.TaskHistory(ctx, taskID)
+}
+
+// Close 关闭事件历史文件
+func (s *FileEventStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,76 @@
+package callback
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// TestFileEventStore 测试事件历史写入、排序和重新打开
+func TestFileEventStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	store, err := OpenFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	store.Append(ctx, EventRecord{ReceivedAt: now, Event: &CallbackEvent{TaskID: "t1", Status: mlievpush.CallbackStatusDelivered, Sequence: 2}})
+	store.Append(ctx, EventRecord{ReceivedAt: now.Add(time.Second), Event: &CallbackEvent{TaskID: "t1", Status: mlievpush.TaskStatusProcessing, Sequence: 1}})
+	store.Append(ctx, EventRecord{ReceivedAt: now, Event: &CallbackEvent{TaskID: "t2", Status: mlievpush.CallbackStatusFailed}})
+	store.Close()
+
+	// 模拟崩溃留下的半行
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"received_at":"2025-`)
+	f.Close()
+
+	store, err = OpenFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	history, err := store.TaskHistory(ctx, "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Event.Sequence != 1 || history[1].Event.Status != mlievpush.CallbackStatusDelivered {
+		t.Errorf("unexpected history: %+v", history)
+	}
+	// 半行之后追加的记录不受影响
+	store.Append(ctx, EventRecord{ReceivedAt: now, Event: &CallbackEvent{TaskID: "t3"}})
+	store.Close()
+	store, _ = OpenFileEventStore(path)
+	if h, _ := store.TaskHistory(ctx, "t3"); len(h) != 1 {
+		t.Errorf("record after partial line lost: %v", h)
+	}
+
+	if missing, _ := store.TaskHistory(ctx, "unknown"); len(missing) != 0 {
+		t.Errorf("unknown task history = %v", missing)
+	}
+}
+
+// TestWithEventStore 测试回调处理器写入事件历史
+func TestWithEventStore(t *testing.T) {
+	store := NewMemoryEventStore()
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error { return nil }, WithEventStore(store))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+
+	history, _ := store.TaskHistory(context.Background(), "550e8400-e29b-41d4-a716-446655440000")
+	if len(history) != 1 || history[0].Event.EventID != "evt-1" || history[0].ReceivedAt.IsZero() {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
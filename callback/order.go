@@ -0,0 +1,165 @@
+package callback
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// TaskState 任务的最新回调状态
+type TaskState struct {
+	TaskID    string    // 任务ID
+	Status    string    // 状态
+	Sequence  int64     // 事件序号，0表示未提供
+	UpdatedAt time.Time // 状态更新时间，零值表示未提供
+}
+
+// StateStore 任务状态存储，用于检测乱序到达的回调
+type StateStore interface {
+	// Load 读取任务的最新状态，不存在时返回 false
+	Load(ctx context.Context, taskID string) (TaskState, bool, error)
+	// SaveIfNewer 原子地比较并保存：state 比已记录状态更新（见 IsNewer）或尚无记录时保存并返回 saved=true，
+	// 否则不修改记录并返回已记录的状态；并发投递同一任务的多个事件时，旧事件不会覆盖新状态
+	SaveIfNewer(ctx context.Context, state TaskState) (current TaskState, saved bool, err error)
+}
+
+// OutOfOrderFunc 检测到乱序事件时的回调，current 为已记录的最新状态
+type OutOfOrderFunc func(ctx context.Context, event *CallbackEvent, current TaskState)
+
+// WithOrdering 开启乱序保护：比已记录状态旧的事件会被确认但不再分发
+// 例如迟到的 processing 事件不会覆盖已经是 delivered 的终态
+func WithOrdering(store StateStore, onOutOfOrder OutOfOrderFunc) Option {
+	return func(h *Handler) {
+		h.stateStore = store
+		h.onOutOfOrder = onOutOfOrder
+	}
+}
+
+// IsTerminalStatus 判断是否为终态
+func IsTerminalStatus(status string) bool {
+	switch status {
+	case mlievpush.TaskStatusSuccess, mlievpush.TaskStatusFailed, mlievpush.TaskStatusCanceled,
+		mlievpush.CallbackStatusDelivered, mlievpush.CallbackStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsNewer 判断 next 是否比 prev 更新
+// 终态不会被非终态覆盖；双方都有序号时比较序号，否则比较更新时间，都没有时视为更新
+// 序号或更新时间相同时不视为更新，与序号比较的语义一致，重复投递的同一事件不会被再次分发
+func IsNewer(prev, next TaskState) bool {
+	if IsTerminalStatus(prev.Status) && !IsTerminalStatus(next.Status) {
+		return false
+	}
+	if prev.Sequence > 0 && next.Sequence > 0 {
+		return next.Sequence > prev.Sequence
+	}
+	if !prev.UpdatedAt.IsZero() && !next.UpdatedAt.IsZero() {
+		return next.UpdatedAt.After(prev.UpdatedAt)
+	}
+	return true
+}
+
+// eventState 从事件构造任务状态
+func eventState(event *CallbackEvent) TaskState {
+	state := TaskState{
+		TaskID:   event.TaskID,
+		Status:   event.Status,
+		Sequence: event.Sequence,
+	}
+	if t, err := time.Parse(time.RFC3339, event.UpdatedAt); err == nil {
+		state.UpdatedAt = t
+	}
+	return state
+}
+
+// memoryStateEntry 内存状态存储中的记录
+type memoryStateEntry struct {
+	state    TaskState // 任务状态
+	expireAt time.Time // 过期时间
+}
+
+// MemoryStateStore 基于内存的任务状态存储，状态在最后一次更新 ttl 时长后清理
+type MemoryStateStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration               // 状态保留时间
+	states    map[string]memoryStateEntry // 任务ID -> 记录
+	nextSweep time.Time                   // 下次清理时间
+	now       func() time.Time
+}
+
+// NewMemoryStateStore 创建内存任务状态存储，ttl 为状态保留时间，小于等于0时使用 DefaultStoreTTL
+// ttl 应大于网关重试投递的时间窗口，过期后迟到的旧事件无法再被识别为乱序
+func NewMemoryStateStore(ttl time.Duration) *MemoryStateStore {
+	if ttl <= 0 {
+		ttl = DefaultStoreTTL
+	}
+	return &MemoryStateStore{
+		ttl:    ttl,
+		states: make(map[string]memoryStateEntry),
+		now:    time.Now,
+	}
+}
+
+// Load 实现 StateStore 接口
+func (s *MemoryStateStore) Load(ctx context.Context, taskID string) (TaskState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.states[taskID]
+	if !ok || !s.now().Before(entry.expireAt) {
+		return TaskState{}, false, nil
+	}
+	return entry.state, true, nil
+}
+
+// SaveIfNewer 实现 StateStore 接口
+func (s *MemoryStateStore) SaveIfNewer(ctx context.Context, state TaskState) (TaskState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if entry, ok := s.states[state.TaskID]; ok && now.Before(entry.expireAt) && !IsNewer(entry.state, state) {
+		return entry.state, false, nil
+	}
+	s.states[state.TaskID] = memoryStateEntry{state: state, expireAt: now.Add(s.ttl)}
+	s.evictExpired(now)
+	return state, true, nil
+}
+
+// Save 无条件保存任务的最新状态
+func (s *MemoryStateStore) Save(ctx context.Context, state TaskState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.states[state.TaskID] = memoryStateEntry{state: state, expireAt: now.Add(s.ttl)}
+	s.evictExpired(now)
+	return nil
+}
+
+// Len 返回保存的任务状态数量（含尚未清理的过期记录）
+func (s *MemoryStateStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.states)
+}
+
+// evictExpired 定期清理过期记录（调用方需持有锁）
+func (s *MemoryStateStore) evictExpired(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(memoryStoreSweepInterval)
+
+	for id, entry := range s.states {
+		if !now.Before(entry.expireAt) {
+			delete(s.states, id)
+		}
+	}
+}
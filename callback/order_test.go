@@ -0,0 +1,221 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// TestIsNewer 测试事件新旧判断
+func TestIsNewer(t *testing.T) {
+	base := time.Date(2025, 11, 25, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		prev TaskState
+		next TaskState
+		want bool
+	}{
+		{
+			name: "终态不被非终态覆盖",
+			prev: TaskState{Status: mlievpush.CallbackStatusDelivered, Sequence: 1},
+			next: TaskState{Status: mlievpush.TaskStatusProcessing, Sequence: 2},
+			want: false,
+		},
+		{
+			name: "序号递增",
+			prev: TaskState{Status: mlievpush.TaskStatusPending, Sequence: 1},
+			next: TaskState{Status: mlievpush.TaskStatusProcessing, Sequence: 2},
+			want: true,
+		},
+		{
+			name: "序号回退",
+			prev: TaskState{Status: mlievpush.TaskStatusProcessing, Sequence: 3},
+			next: TaskState{Status: mlievpush.CallbackStatusFailed, Sequence: 2},
+			want: false,
+		},
+		{
+			name: "按更新时间比较",
+			prev: TaskState{Status: mlievpush.TaskStatusPending, UpdatedAt: base.Add(time.Second)},
+			next: TaskState{Status: mlievpush.TaskStatusProcessing, UpdatedAt: base},
+			want: false,
+		},
+		{
+			name: "更新时间相同不视为更新",
+			prev: TaskState{Status: mlievpush.TaskStatusPending, UpdatedAt: base},
+			next: TaskState{Status: mlievpush.TaskStatusProcessing, UpdatedAt: base},
+			want: false,
+		},
+		{
+			name: "序号相同不视为更新",
+			prev: TaskState{Status: mlievpush.TaskStatusPending, Sequence: 2},
+			next: TaskState{Status: mlievpush.TaskStatusProcessing, Sequence: 2},
+			want: false,
+		},
+		{
+			name: "无排序信息时接受",
+			prev: TaskState{Status: mlievpush.TaskStatusPending},
+			next: TaskState{Status: mlievpush.TaskStatusProcessing},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNewer(tt.prev, tt.next); got != tt.want {
+				t.Errorf("IsNewer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMemoryStateStoreTTL 测试内存状态存储按保留时间清理
+func TestMemoryStateStoreTTL(t *testing.T) {
+	s := NewMemoryStateStore(time.Hour)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	s.Save(ctx, TaskState{TaskID: "a", Status: mlievpush.TaskStatusPending})
+	if _, ok, _ := s.Load(ctx, "a"); !ok {
+		t.Fatal("state should be loaded before expiry")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok, _ := s.Load(ctx, "a"); ok {
+		t.Error("expired state should not be loaded")
+	}
+	s.Save(ctx, TaskState{TaskID: "b", Status: mlievpush.TaskStatusPending})
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d, want expired state evicted", s.Len())
+	}
+}
+
+// TestHandlerOrdering 测试迟到事件不会覆盖终态
+func TestHandlerOrdering(t *testing.T) {
+	var dispatched []string
+	var outOfOrder []string
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error {
+		dispatched = append(dispatched, event.Status)
+		return nil
+	}, WithOrdering(NewMemoryStateStore(time.Hour), func(ctx context.Context, event *CallbackEvent, current TaskState) {
+		outOfOrder = append(outOfOrder, event.Status+"<"+current.Status)
+	}))
+
+	deliver := func(eventID, status string, seq int) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newSignedRequest(t, testSecret, map[string]interface{}{
+			"event_id": eventID,
+			"task_id":  "task-1",
+			"status":   status,
+			"sequence": seq,
+		}))
+		return w.Code
+	}
+
+	deliver("e1", mlievpush.TaskStatusPending, 1)
+	deliver("e3", mlievpush.CallbackStatusDelivered, 3)
+	if code := deliver("e2", mlievpush.TaskStatusProcessing, 2); code != http.StatusOK {
+		t.Errorf("out-of-order event status = %d, want %d", code, http.StatusOK)
+	}
+
+	if len(dispatched) != 2 || dispatched[1] != mlievpush.CallbackStatusDelivered {
+		t.Errorf("dispatched = %v", dispatched)
+	}
+	if len(outOfOrder) != 1 || outOfOrder[0] != "processing<delivered" {
+		t.Errorf("outOfOrder = %v", outOfOrder)
+	}
+}
+
+// TestHandlerOutOfOrderPanic 测试乱序事件回调中的panic上报到错误回调，事件仍被确认
+func TestHandlerOutOfOrderPanic(t *testing.T) {
+	var reported error
+	h := NewHandler(testSecret, nil, WithOrdering(NewMemoryStateStore(time.Hour), func(ctx context.Context, event *CallbackEvent, current TaskState) {
+		panic("boom")
+	}), WithErrorHandler(func(r *http.Request, err error) { reported = err }))
+
+	for _, eventID := range []string{"e1", "e2"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newSignedRequest(t, testSecret, map[string]interface{}{
+			"event_id": eventID,
+			"task_id":  "task-1",
+			"status":   mlievpush.CallbackStatusDelivered,
+			"sequence": 1,
+		}))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+	}
+
+	var panicErr *mlievpush.PanicError
+	if !errors.As(reported, &panicErr) || panicErr.Callback != "OnOutOfOrder" || len(panicErr.Stack) == 0 {
+		t.Errorf("reported = %v, want *PanicError from OnOutOfOrder", reported)
+	}
+}
+
+// TestMemoryStateStoreSaveIfNewer 测试并发保存同一任务的状态时，旧状态不会覆盖终态
+func TestMemoryStateStoreSaveIfNewer(t *testing.T) {
+	s := NewMemoryStateStore(time.Hour)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var delivered atomic.Int32
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			s.SaveIfNewer(ctx, TaskState{TaskID: "a", Status: mlievpush.TaskStatusProcessing, Sequence: 1})
+		}()
+		go func() {
+			defer wg.Done()
+			if _, saved, _ := s.SaveIfNewer(ctx, TaskState{TaskID: "a", Status: mlievpush.CallbackStatusDelivered, Sequence: 2}); saved {
+				delivered.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	state, ok, _ := s.Load(ctx, "a")
+	if !ok || state.Status != mlievpush.CallbackStatusDelivered || delivered.Load() != 1 {
+		t.Errorf("state = %+v, delivered saved %d times, want delivered saved once", state, delivered.Load())
+	}
+	if current, saved, _ := s.SaveIfNewer(ctx, TaskState{TaskID: "a", Status: mlievpush.TaskStatusProcessing, Sequence: 1}); saved || current.Status != mlievpush.CallbackStatusDelivered {
+		t.Errorf("SaveIfNewer(older) = %+v, %v", current, saved)
+	}
+}
+
+// TestHandlerOrderingConcurrent 测试并发投递同一任务的新旧事件时，旧事件不会覆盖终态
+func TestHandlerOrderingConcurrent(t *testing.T) {
+	states := NewMemoryStateStore(time.Hour)
+	h := NewHandler(testSecret, nil, WithOrdering(states, nil))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		for seq, status := range []string{mlievpush.TaskStatusProcessing, mlievpush.CallbackStatusDelivered} {
+			req := newSignedRequest(t, testSecret, map[string]interface{}{
+				"event_id": fmt.Sprintf("e%d-%d", i, seq),
+				"task_id":  "task-1",
+				"status":   status,
+				"sequence": seq + 1,
+			})
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				h.ServeHTTP(httptest.NewRecorder(), req)
+			}()
+		}
+	}
+	wg.Wait()
+
+	if state, _, _ := states.Load(context.Background(), "task-1"); state.Status != mlievpush.CallbackStatusDelivered {
+		t.Errorf("state = %+v, want delivered", state)
+	}
+}
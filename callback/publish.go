@@ -0,0 +1,94 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Message 转发到消息队列的事件消息
+type Message struct {
+	Topic   string            // 主题（Kafka topic、NATS subject、Redis stream）
+	Key     string            // 分区键（任务ID，保证同一任务的事件有序）
+	Value   []byte            // 事件JSON
+	Headers map[string]string // 消息头：event_id、app_id、status
+}
+
+// Publisher 消息队列发布者，用于将已校验的回调事件转发给下游（Kafka、NATS、Redis Streams 等）
+type Publisher interface {
+	Publish(ctx context.Context, msg *Message) error
+}
+
+// PublisherFunc 函数形式的 Publisher
+//
+// 以 NATS 为例：
+//
+//	callback.PublisherFunc(func(ctx context.Context, msg *callback.Message) error {
+//		return nc.Publish(msg.Topic, msg.Value)
+//	})
+type PublisherFunc func(ctx context.Context, msg *Message) error
+
+// Publish 实现 Publisher 接口
+func (f PublisherFunc) Publish(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// XAddFunc Redis XADD 操作
+//
+// 以 go-redis 为例：
+//
+//	func(ctx context.Context, stream string, values map[string]interface{}) error {
+//		return rdb.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: values}).Err()
+//	}
+type XAddFunc func(ctx context.Context, stream string, values map[string]interface{}) error
+
+// NewRedisStreamPublisher 创建 Redis Streams 发布者，消息以 key、value 及消息头字段写入 stream
+func NewRedisStreamPublisher(xadd XAddFunc) Publisher {
+	return PublisherFunc(func(ctx context.Context, msg *Message) error {
+		values := map[string]interface{}{"key": msg.Key, "value": string(msg.Value)}
+		for k, v := range msg.Headers {
+			values[k] = v
+		}
+		return xadd(ctx, msg.Topic, values)
+	})
+}
+
+// NewMessage 将回调事件编码为消息
+func NewMessage(topic string, event *CallbackEvent) (*Message, error) {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event: %w", err)
+	}
+	return &Message{
+		Topic: topic,
+		Key:   event.TaskID,
+		Value: value,
+		Headers: map[string]string{
+			"event_id": event.EventID,
+			"app_id":   event.AppID,
+			"status":   event.Status,
+		},
+	}, nil
+}
+
+// PublishHandler 返回将事件转发到消息队列的处理函数
+// 发布失败时返回错误，网关会重试投递（至少一次语义，下游应按 event_id 去重）
+func PublishHandler(publisher Publisher, topic string) EventHandler {
+	return func(ctx context.Context, event *CallbackEvent) error {
+		msg, err := NewMessage(topic, event)
+		if err != nil {
+			return err
+		}
+		if err := publisher.Publish(ctx, msg); err != nil {
+			return fmt.Errorf("publish event %s: %w", event.EventID, err)
+		}
+		return nil
+	}
+}
+
+// WithPublisher 在处理函数成功后将事件转发到消息队列，下游分析系统无需依赖回调 HTTP 接口
+func WithPublisher(publisher Publisher, topic string) Option {
+	return func(h *Handler) {
+		h.publish = PublishHandler(publisher, topic)
+	}
+}
@@ -0,0 +1,67 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWithPublisher 测试事件转发到消息队列
+func TestWithPublisher(t *testing.T) {
+	var published []*Message
+	var fail bool
+	pub := PublisherFunc(func(ctx context.Context, msg *Message) error {
+		if fail {
+			return errors.New("broker down")
+		}
+		published = append(published, msg)
+		return nil
+	})
+
+	h := NewHandler(testSecret, nil, WithPublisher(pub, "push.delivery"))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusOK || len(published) != 1 {
+		t.Fatalf("status = %d, published = %d", w.Code, len(published))
+	}
+
+	msg := published[0]
+	if msg.Topic != "push.delivery" || msg.Key != "550e8400-e29b-41d4-a716-446655440000" ||
+		msg.Headers["event_id"] != "evt-1" || msg.Headers["app_id"] != "test_app_id" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	var event CallbackEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil || event.Status != "delivered" {
+		t.Errorf("unexpected value %s: %v", msg.Value, err)
+	}
+
+	// 发布失败时返回500，网关重试投递
+	fail = true
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, deliveredPayload()))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+}
+
+// TestRedisStreamPublisher 测试 Redis Streams 发布者
+func TestRedisStreamPublisher(t *testing.T) {
+	var stream string
+	var values map[string]interface{}
+	pub := NewRedisStreamPublisher(func(ctx context.Context, s string, v map[string]interface{}) error {
+		stream, values = s, v
+		return nil
+	})
+
+	handle := PublishHandler(pub, "push:events")
+	if err := handle(context.Background(), &CallbackEvent{EventID: "e1", TaskID: "t1", Status: "failed"}); err != nil {
+		t.Fatal(err)
+	}
+	if stream != "push:events" || values["key"] != "t1" || values["status"] != "failed" || values["value"] == "" {
+		t.Errorf("stream = %s, values = %v", stream, values)
+	}
+}
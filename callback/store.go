@@ -0,0 +1,108 @@
+package callback
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ClaimState 事件ID的占用结果
+type ClaimState int
+
+// 占用结果
+const (
+	ClaimAcquired   ClaimState = iota // 占用成功，由本次请求处理
+	ClaimInProgress                   // 其他请求正在处理，占用到期前不能重新处理
+	ClaimDone                         // 已处理完成
+)
+
+// ErrEventInProgress 同一事件正在被其他请求处理，响应 500 让网关稍后重试
+var ErrEventInProgress = errors.New("event is being processed")
+
+// Store 已处理事件存储
+// 网关以至少一次语义投递回调，Store 用于保证同一事件只被处理一次
+type Store interface {
+	// Claim 原子地占用事件ID（处理中），ttl 为占用时长，处理进程崩溃时占用到期后可以重新处理
+	// 已被占用时返回 ClaimInProgress 或 ClaimDone，不修改已有记录
+	Claim(ctx context.Context, id string, ttl time.Duration) (ClaimState, error)
+	// Complete 处理成功后将事件ID标记为已完成，记录保留 ttl 时长
+	Complete(ctx context.Context, id string, ttl time.Duration) error
+	// Release 处理失败时释放占用，使网关重试时可以重新处理
+	Release(ctx context.Context, id string) error
+}
+
+// memoryStoreSweepInterval 内存存储清理过期记录的间隔
+const memoryStoreSweepInterval = time.Minute
+
+// memoryEntry 内存存储中的事件记录
+type memoryEntry struct {
+	expireAt time.Time // 过期时间
+	done     bool      // 是否已处理完成
+}
+
+// MemoryStore 基于内存的事件存储，适用于单实例部署
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry // 事件ID -> 记录
+	nextSweep time.Time              // 下次清理时间
+	now       func() time.Time
+}
+
+// NewMemoryStore 创建内存事件存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Claim 实现 Store 接口
+func (s *MemoryStore) Claim(ctx context.Context, id string, ttl time.Duration) (ClaimState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if entry, ok := s.entries[id]; ok && now.Before(entry.expireAt) {
+		if entry.done {
+			return ClaimDone, nil
+		}
+		return ClaimInProgress, nil
+	}
+
+	s.entries[id] = memoryEntry{expireAt: now.Add(ttl)}
+	s.evictExpired(now)
+	return ClaimAcquired, nil
+}
+
+// Complete 实现 Store 接口
+func (s *MemoryStore) Complete(ctx context.Context, id string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[id] = memoryEntry{expireAt: s.now().Add(ttl), done: true}
+	return nil
+}
+
+// Release 实现 Store 接口
+func (s *MemoryStore) Release(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, id)
+	return nil
+}
+
+// evictExpired 定期清理过期记录（调用方需持有锁）
+func (s *MemoryStore) evictExpired(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(memoryStoreSweepInterval)
+
+	for id, entry := range s.entries {
+		if !now.Before(entry.expireAt) {
+			delete(s.entries, id)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 回调事件结构版本（主版本号）
+// 网关新增字段等兼容变更只提升次版本号（如 "1.2"），按主版本解析，新增字段可通过 CallbackEvent.Raw 读取
+const (
+	EventVersion1 = "1" // 初始版本，version 字段缺省时按该版本解析
+)
+
+// 回调事件类型
+const (
+	EventTypeStatus = "status" // 投递状态变更，type 字段缺省时为该类型
+	EventTypeTest   = "test"   // 测试事件（mlievpush.Client.TestCallback 触发），不对应真实任务
+)
+
+// EventDecoder 将指定主版本的回调请求体解析为 CallbackEvent
+type EventDecoder func(raw json.RawMessage) (*CallbackEvent, error)
+
+// UnknownEventVersion 回调事件的主版本未注册（网关升级后接收方尚未升级），原始请求体完整保留
+// Handler 默认响应 500 让网关稍后重试，可通过 WithUnknownVersionHandler 自行保存后确认
+type UnknownEventVersion struct {
+	Version string          // 事件版本
+	AppID   string          // 应用ID
+	Nonce   string          // 本次投递的随机数
+	Raw     json.RawMessage // 原始请求体
+}
+
+// Error 实现 error 接口
+func (e *UnknownEventVersion) Error() string {
+	return fmt.Sprintf("unknown callback event version %q", e.Version)
+}
+
+// eventDecoders 主版本 -> 解析函数
+var (
+	eventDecodersMu sync.RWMutex
+	eventDecoders   = map[string]EventDecoder{EventVersion1: decodeEventV1}
+)
+
+// RegisterEventVersion 注册或覆盖主版本的解析函数，用于在 SDK 升级前接收新版本的回调
+func RegisterEventVersion(major string, decode EventDecoder) {
+	eventDecodersMu.Lock()
+	defer eventDecodersMu.Unlock()
+	eventDecoders[major] = decode
+}
+
+// WithUnknownVersionHandler 设置未知版本事件的处理函数（如写入死信表），返回nil时确认回调，返回错误时响应 500
+func WithUnknownVersionHandler(fn func(ctx context.Context, event *UnknownEventVersion) error) Option {
+	return func(h *Handler) {
+		h.onUnknownVersion = fn
+	}
+}
+
+// decodeEvent 按 version 字段选择解析函数
+func decodeEvent(raw json.RawMessage) (*CallbackEvent, error) {
+	var envelope struct {
+		Version json.RawMessage `json:"version"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, err
+	}
+	version := eventVersion(envelope.Version)
+
+	eventDecodersMu.RLock()
+	decode, ok := eventDecoders[majorVersion(version)]
+	eventDecodersMu.RUnlock()
+	if !ok {
+		return nil, &UnknownEventVersion{Version: version, Raw: raw}
+	}
+
+	event, err := decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	event.Version = version
+	if event.Type == "" {
+		event.Type = EventTypeStatus
+	}
+	return event, nil
+}
+
+// decodeEventV1 解析版本1的事件
+func decodeEventV1(raw json.RawMessage) (*CallbackEvent, error) {
+	var event CallbackEvent
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// eventVersion 读取 version 字段，兼容字符串和数字，缺省为 EventVersion1
+func eventVersion(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return EventVersion1
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String()
+	}
+	return strconv.Quote(string(raw))
+}
+
+// majorVersion 主版本号，如 "1.2" -> "1"
+func majorVersion(version string) string {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	return major
+}
+
+// DecodeRaw 将原始请求体解析到 v，用于读取当前 SDK 版本尚未定义的新增字段
+func (e *CallbackEvent) DecodeRaw(v interface{}) error {
+	return json.Unmarshal(e.Raw, v)
+}
@@ -0,0 +1,89 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestEventVersion 测试按版本解析事件
+func TestEventVersion(t *testing.T) {
+	payload := deliveredPayload()
+	payload["version"] = "1.3"
+	payload["carrier"] = "中国移动"
+
+	event, err := ParseCallback(newSignedRequest(t, testSecret, payload), testSecret)
+	if err != nil {
+		t.Fatalf("ParseCallback() error = %v", err)
+	}
+	if event.Version != "1.3" || event.Type != EventTypeStatus {
+		t.Errorf("unexpected version/type: %q %q", event.Version, event.Type)
+	}
+	var extra struct {
+		Carrier string `json:"carrier"`
+	}
+	if err := event.DecodeRaw(&extra); err != nil || extra.Carrier != "中国移动" {
+		t.Errorf("DecodeRaw() = %+v, %v", extra, err)
+	}
+
+	// 缺省版本和数字版本
+	event, err = ParseCallback(newSignedRequest(t, testSecret, deliveredPayload()), testSecret)
+	if err != nil || event.Version != EventVersion1 {
+		t.Errorf("expected default version, got %+v, %v", event, err)
+	}
+	payload = deliveredPayload()
+	payload["version"] = 1
+	if event, err = ParseCallback(newSignedRequest(t, testSecret, payload), testSecret); err != nil || event.Version != "1" {
+		t.Errorf("expected numeric version, got %+v, %v", event, err)
+	}
+}
+
+// TestUnknownEventVersion 测试未知版本
+func TestUnknownEventVersion(t *testing.T) {
+	payload := deliveredPayload()
+	payload["version"] = "9"
+
+	_, err := ParseCallback(newSignedRequest(t, testSecret, payload), testSecret)
+	var unknown *UnknownEventVersion
+	if !errors.As(err, &unknown) || unknown.Version != "9" || unknown.AppID != "test_app_id" {
+		t.Fatalf("expected UnknownEventVersion, got %v", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(unknown.Raw, &raw); err != nil || raw["event_id"] != "evt-1" {
+		t.Errorf("raw body not preserved: %s", unknown.Raw)
+	}
+
+	// 默认响应500，网关稍后重试
+	h := NewHandler(testSecret, func(ctx context.Context, event *CallbackEvent) error { return nil })
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, payload))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", w.Code)
+	}
+
+	// 自定义处理后确认
+	var saved *UnknownEventVersion
+	h = NewHandler(testSecret, nil, WithUnknownVersionHandler(func(ctx context.Context, event *UnknownEventVersion) error {
+		saved = event
+		return nil
+	}))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newSignedRequest(t, testSecret, payload))
+	if w.Code != http.StatusOK || saved == nil {
+		t.Errorf("expected 200 and saved event, got %d %v", w.Code, saved)
+	}
+
+	// 注册新版本后可以解析
+	RegisterEventVersion("9", decodeEventV1)
+	defer func() {
+		eventDecodersMu.Lock()
+		delete(eventDecoders, "9")
+		eventDecodersMu.Unlock()
+	}()
+	if event, err := ParseCallback(newSignedRequest(t, testSecret, payload), testSecret); err != nil || event.Version != "9" {
+		t.Errorf("expected registered version, got %+v, %v", event, err)
+	}
+}
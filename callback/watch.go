@@ -0,0 +1,75 @@
+package callback
+
+import (
+	"sync"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// TaskWatcher 将收到的回调通知给等待中的 WaitForTask，实现 mlievpush.TaskNotifier
+//
+//	watcher := callback.NewTaskWatcher()
+//	h := callback.NewHandler(appSecret, handle, callback.WithTaskWatcher(watcher))
+//	client := mlievpush.NewClient(baseURL, appID, appSecret, mlievpush.WithTaskNotifier(watcher))
+type TaskWatcher struct {
+	mu   sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+// 编译期检查 *TaskWatcher 实现了 mlievpush.TaskNotifier
+var _ mlievpush.TaskNotifier = (*TaskWatcher)(nil)
+
+// NewTaskWatcher 创建任务通知器
+func NewTaskWatcher() *TaskWatcher {
+	return &TaskWatcher{subs: make(map[string]map[chan struct{}]struct{})}
+}
+
+// Subscribe 实现 mlievpush.TaskNotifier 接口
+func (w *TaskWatcher) Subscribe(taskID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	w.mu.Lock()
+	if w.subs[taskID] == nil {
+		w.subs[taskID] = make(map[chan struct{}]struct{})
+	}
+	w.subs[taskID][ch] = struct{}{}
+	w.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			delete(w.subs[taskID], ch)
+			if len(w.subs[taskID]) == 0 {
+				delete(w.subs, taskID)
+			}
+		})
+	}
+}
+
+// Notify 通知任务的所有订阅者，订阅者尚未处理上一次通知时合并
+func (w *TaskWatcher) Notify(taskID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.subs[taskID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// WithTaskWatcher 处理函数成功后通知等待该任务的 WaitForTask
+func WithTaskWatcher(watcher *TaskWatcher) Option {
+	return func(h *Handler) {
+		h.watcher = watcher
+	}
+}
+
+// notify 通知任务订阅者
+func (h *Handler) notify(event *CallbackEvent) {
+	if h.watcher != nil && event.TaskID != "" {
+		h.watcher.Notify(event.TaskID)
+	}
+}
@@ -0,0 +1,94 @@
+package mlievpush
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// CallOption 单次调用选项，覆盖客户端级别的配置，无需为个别调用创建新的 Client
+type CallOption func(*callOptions)
+
+// callOptions 单次调用配置
+type callOptions struct {
+	header  http.Header   // 附加请求头
+	timeout time.Duration // 整个调用（含重试）的超时时间
+	noRetry bool          // 是否禁用自动重试
+
+	batchDetail bool // 批量发送是否请求逐条结果（SendBatchDetailed）
+
+	appID     string // 本次调用使用的应用ID，为空时使用客户端的凭证
+	appSecret string // 本次调用使用的应用密钥
+}
+
+// callOptionsKey 单次调用配置在 context 中的键
+type callOptionsKey struct{}
+
+// WithHeader 为本次调用附加请求头，不能覆盖签名相关的请求头（X-App-Id、X-Signature 等）
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithCallTimeout 设置本次调用的超时时间（包括所有重试和等待），与 ctx 的截止时间取较早者
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithNoRetry 本次调用不自动重试（如非幂等的发送请求由调用方自行决定是否重试）
+func WithNoRetry() CallOption {
+	return func(o *callOptions) {
+		o.noRetry = true
+	}
+}
+
+// WithCredentials 本次调用使用指定应用的凭证签名，用于通过同一个客户端代表多个应用操作的管理工具
+func WithCredentials(appID, appSecret string) CallOption {
+	return func(o *callOptions) {
+		o.appID = appID
+		o.appSecret = appSecret
+	}
+}
+
+// ContextWithCallOptions 将单次调用选项写入 context，用于不接受 CallOption 参数的方法（如 ListTasks、模板管理）
+// 方法参数中的 CallOption 在 context 中的选项之后应用；返回的 cancel 必须在调用结束后执行
+//
+//	ctx, cancel := mlievpush.ContextWithCallOptions(ctx, mlievpush.WithCredentials(tenantAppID, tenantSecret))
+//	defer cancel()
+//	tasks, err := client.ListTasks(ctx, opts)
+func ContextWithCallOptions(ctx context.Context, opts ...CallOption) (context.Context, context.CancelFunc) {
+	return withCallOptions(ctx, opts)
+}
+
+// withCallOptions 将单次调用配置写入 context（合并 context 中已有的配置），返回的 cancel 必须在调用结束后执行
+func withCallOptions(ctx context.Context, opts []CallOption) (context.Context, context.CancelFunc) {
+	if len(opts) == 0 {
+		return ctx, func() {}
+	}
+	o := &callOptions{}
+	if prev := callOptionsFrom(ctx); prev != nil {
+		*o = *prev
+		o.header = prev.header.Clone()
+		o.timeout = 0 // 已有的超时已作用于 ctx
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	ctx = context.WithValue(ctx, callOptionsKey{}, o)
+	if o.timeout > 0 {
+		return context.WithTimeout(ctx, o.timeout)
+	}
+	return ctx, func() {}
+}
+
+// callOptionsFrom 获取 context 中的单次调用配置，未设置时返回nil
+func callOptionsFrom(ctx context.Context) *callOptions {
+	o, _ := ctx.Value(callOptionsKey{}).(*callOptions)
+	return o
+}
@@ -0,0 +1,108 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCallOptions 测试单次调用选项
+func TestCallOptions(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/api/v1/messages/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		if got := r.Header.Get("X-Tenant"); got != "" && got != "acme" {
+			t.Errorf("X-Tenant = %q", got)
+		}
+		if r.Header.Get(HeaderSignature) == "forged" {
+			t.Error("call option overrode the signature header")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeNetworkTimeout, "message": "busy"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1", "status": "pending"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithRetry(3, Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1}))
+	ctx := context.Background()
+
+	if _, err := client.QueryTask(ctx, "t1", WithHeader("X-Tenant", "acme"), WithHeader(HeaderSignature, "forged")); err != nil {
+		t.Errorf("QueryTask() error = %v", err)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", SignatureName: "test"}
+	if _, err := client.SendMessage(ctx, req, WithNoRetry()); err == nil || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("SendMessage(WithNoRetry) calls = %d, err = %v", calls, err)
+	}
+	atomic.StoreInt32(&calls, 0)
+	if _, err := client.SendMessage(ctx, req); err == nil || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("SendMessage() calls = %d, want 3 attempts", calls)
+	}
+
+	if _, err := client.QueryTask(ctx, "slow", WithCallTimeout(20*time.Millisecond)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("QueryTask(WithCallTimeout) error = %v, want deadline exceeded", err)
+	}
+}
+
+// TestWithCredentials 测试单次调用使用其他应用的凭证
+func TestWithCredentials(t *testing.T) {
+	secrets := map[string]string{"test_app_id": "test_secret", "tenant_app": "tenant_secret"}
+	verifier := NewVerifier(func(ctx context.Context, appID string) (string, error) {
+		secret, ok := secrets[appID]
+		if !ok {
+			return "", ErrUnknownApp
+		}
+		return secret, nil
+	})
+	var apps []string
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, _ := VerifiedRequestFromContext(r.Context())
+		apps = append(apps, verified.AppID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	})))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	ctx := context.Background()
+
+	if _, err := client.QueryTask(ctx, "t1", WithCredentials("tenant_app", "tenant_secret")); err != nil {
+		t.Fatalf("QueryTask() error = %v", err)
+	}
+
+	// 不接受 CallOption 的方法通过 context 传递，方法参数中的选项与 context 中的合并
+	callCtx, cancel := ContextWithCallOptions(ctx, WithCredentials("tenant_app", "tenant_secret"))
+	defer cancel()
+	if _, err := client.CancelTask(callCtx, "t1"); err != nil {
+		t.Fatalf("CancelTask() error = %v", err)
+	}
+	if _, err := client.QueryTask(callCtx, "t1", WithHeader("X-Tenant", "acme")); err != nil {
+		t.Fatalf("QueryTask() error = %v", err)
+	}
+	if _, err := client.QueryTask(ctx, "t1"); err != nil {
+		t.Fatalf("QueryTask() error = %v", err)
+	}
+
+	want := []string{"tenant_app", "tenant_app", "tenant_app", "test_app_id"}
+	if len(apps) != len(want) {
+		t.Fatalf("apps = %v, want %v", apps, want)
+	}
+	for i := range want {
+		if apps[i] != want[i] {
+			t.Errorf("apps = %v, want %v", apps, want)
+		}
+	}
+}
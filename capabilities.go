@@ -0,0 +1,76 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ChannelCapabilities 通道能力
+type ChannelCapabilities struct {
+	ChannelID    int      `json:"channel_id"`    // 通道ID
+	MessageTypes []string `json:"message_types"` // 支持的消息类型，见 MessageType* 常量
+	Regions      []string `json:"regions"`       // 支持的目的地区（ISO 3166-1 alpha-2），包含 "*" 表示不限地区
+
+	Throughput ChannelThroughput `json:"throughput"` // 吞吐限制
+	Encoding   ChannelEncoding   `json:"encoding"`   // 编码约束
+
+	RawData // 原始JSON，见 RawData
+}
+
+// ChannelThroughput 通道吞吐限制，0表示不限制
+type ChannelThroughput struct {
+	MaxPerSecond int `json:"max_per_second"` // 每秒最大发送数
+	MaxPerDay    int `json:"max_per_day"`    // 每日最大发送数
+	MaxBatchSize int `json:"max_batch_size"` // 单次批量发送的最大接收者数
+}
+
+// ChannelEncoding 通道编码约束
+type ChannelEncoding struct {
+	Charsets       []string `json:"charsets"`         // 支持的字符集（如 "GSM-7"、"UCS-2"、"UTF-8"）
+	Unicode        bool     `json:"unicode"`          // 是否支持 Unicode（中文、emoji 等）
+	MaxContentSize int      `json:"max_content_size"` // 内容最大长度（字符数），0表示不限制
+	MaxSegments    int      `json:"max_segments"`     // 短信最大拆分条数，0表示不限制
+}
+
+// SupportsRegion 判断通道是否支持发往指定地区
+func (c *ChannelCapabilities) SupportsRegion(region string) bool {
+	for _, r := range c.Regions {
+		if r == "*" || strings.EqualFold(r, region) {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsMessageType 判断通道是否支持指定消息类型
+func (c *ChannelCapabilities) SupportsMessageType(messageType string) bool {
+	for _, t := range c.MessageTypes {
+		if t == messageType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetChannelCapabilities 查询通道能力（支持的地区、消息类型、吞吐限制和编码约束）
+func (c *Client) GetChannelCapabilities(ctx context.Context, channelID int) (*ChannelCapabilities, error) {
+	if err := c.requireFeature(ctx, FeatureChannelCapabilities); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/channels/" + strconv.Itoa(channelID) + "/capabilities"
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ChannelCapabilities
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,51 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestGetChannelCapabilities 测试查询通道能力
+func TestGetChannelCapabilities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/channels/7/capabilities" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data": map[string]interface{}{
+				"channel_id":    7,
+				"message_types": []string{"sms"},
+				"regions":       []string{"US", "GB", "SG"},
+				"throughput":    map[string]interface{}{"max_per_second": 50, "max_batch_size": 1000},
+				"encoding":      map[string]interface{}{"charsets": []string{"GSM-7", "UCS-2"}, "unicode": true, "max_segments": 5},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	caps, err := client.GetChannelCapabilities(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetChannelCapabilities() error = %v", err)
+	}
+
+	if !caps.SupportsRegion("gb") || caps.SupportsRegion("CN") {
+		t.Errorf("unexpected region support: %v", caps.Regions)
+	}
+	if !caps.SupportsMessageType(MessageTypeSMS) || caps.SupportsMessageType(MessageTypeEmail) {
+		t.Errorf("unexpected message types: %v", caps.MessageTypes)
+	}
+	if caps.Throughput.MaxPerSecond != 50 || caps.Encoding.MaxSegments != 5 || !caps.Encoding.Unicode {
+		t.Errorf("unexpected capabilities: %+v", caps)
+	}
+
+	all := &ChannelCapabilities{Regions: []string{"*"}}
+	if !all.SupportsRegion("CN") {
+		t.Error(`"*" should match any region`)
+	}
+}
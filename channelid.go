@@ -0,0 +1,79 @@
+package mlievpush
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// ChannelID 类型化的通道ID，通过 Client.NewChannelID 或 Client.ChannelIDByAlias 创建，
+// 避免在大型代码库中把其他整数（模板ID、租户ID等）误当作通道ID传递
+// 请求结构体中的 ChannelID 字段仍为 int，使用 Int() 转换
+type ChannelID int
+
+// Int 返回原始通道ID
+func (id ChannelID) Int() int {
+	return int(id)
+}
+
+// String 实现 fmt.Stringer 接口，输出ID；需要别名时使用 Client.ChannelName
+func (id ChannelID) String() string {
+	return strconv.Itoa(int(id))
+}
+
+// channelCache 最近一次 ListChannels 返回的通道列表
+type channelCache struct {
+	mu       sync.RWMutex
+	channels map[int]ChannelInfo // 为nil表示尚未加载
+}
+
+// store 缓存通道列表
+func (c *channelCache) store(items []ChannelInfo) {
+	channels := make(map[int]ChannelInfo, len(items))
+	for _, ch := range items {
+		channels[ch.ID] = ch
+	}
+	c.mu.Lock()
+	c.channels = channels
+	c.mu.Unlock()
+}
+
+// lookup 查找缓存的通道，loaded 为 false 表示尚未加载通道列表
+func (c *channelCache) lookup(id int) (ch ChannelInfo, found, loaded bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.channels == nil {
+		return ChannelInfo{}, false, false
+	}
+	ch, found = c.channels[id]
+	return ch, found, true
+}
+
+// NewChannelID 校验并创建类型化的通道ID：ID 必须为正数；
+// 调用过 ListChannels 时还会校验通道存在且已启用，否则返回满足 errors.Is(err, ErrChannelNotFound/ErrChannelDisabled) 的错误
+// 未加载通道列表时只做本地校验，不会发送请求
+func (c *Client) NewChannelID(id int) (ChannelID, error) {
+	if id <= 0 {
+		return 0, &FieldError{Field: "channel_id", Reason: fmt.Sprintf("must be positive, got %d", id)}
+	}
+	ch, found, loaded := c.channels.lookup(id)
+	if !loaded {
+		return ChannelID(id), nil
+	}
+	if !found {
+		return 0, fmt.Errorf("channel %d: %w", id, ErrChannelNotFound)
+	}
+	if !ch.Enabled() {
+		return 0, fmt.Errorf("channel %d (%s): %w", id, ch.Name, ErrChannelDisabled)
+	}
+	return ChannelID(id), nil
+}
+
+// ChannelIDByAlias 根据别名（WithChannelAlias）创建类型化的通道ID，校验规则同 NewChannelID
+func (c *Client) ChannelIDByAlias(name string) (ChannelID, error) {
+	id, err := c.Channel(name)
+	if err != nil {
+		return 0, err
+	}
+	return c.NewChannelID(id)
+}
@@ -0,0 +1,62 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestChannelID 测试类型化通道ID的校验和输出
+func TestChannelID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"id": 701, "name": "marketing", "status": "enabled"},
+				{"id": 702, "name": "legacy", "status": "disabled"},
+			},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithChannelAlias("otp", 703))
+
+	// 未加载通道列表时只做本地校验
+	id, err := client.ChannelIDByAlias("otp")
+	if err != nil || id.Int() != 703 || id.String() != "703" || client.ChannelName(id.Int()) != "otp" {
+		t.Fatalf("ChannelIDByAlias() = %v, %v", id, err)
+	}
+	if _, err := client.NewChannelID(0); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected invalid channel error, got %v", err)
+	}
+	if _, err := client.ChannelIDByAlias("unknown"); err == nil {
+		t.Error("expected unknown alias error")
+	}
+
+	if _, err := client.ListChannels(context.Background()); err != nil {
+		t.Fatalf("ListChannels() error = %v", err)
+	}
+	id, err = client.NewChannelID(701)
+	if err != nil || fmt.Sprint(id) != "701" || client.ChannelName(701) != "marketing" {
+		t.Errorf("NewChannelID(701) = %v, %v", id, err)
+	}
+	if _, err := client.NewChannelID(702); !errors.Is(err, ErrChannelDisabled) {
+		t.Errorf("expected ErrChannelDisabled, got %v", err)
+	}
+	if _, err := client.ChannelIDByAlias("otp"); !errors.Is(err, ErrChannelNotFound) {
+		t.Errorf("expected ErrChannelNotFound, got %v", err)
+	}
+	if got := ChannelID(704).String(); got != "704" || client.ChannelName(704) != "" {
+		t.Errorf("String() = %q, ChannelName() = %q", got, client.ChannelName(704))
+	}
+
+	// 别名按客户端保存，其他客户端不受影响
+	other := NewClient(server.URL, "test_app_id", "test_secret", WithChannelAlias("sms", 703))
+	if client.ChannelName(703) != "otp" || other.ChannelName(703) != "sms" {
+		t.Errorf("ChannelName(703) = %q / %q, want per-client aliases", client.ChannelName(703), other.ChannelName(703))
+	}
+}
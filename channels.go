@@ -0,0 +1,121 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// 通道状态
+const (
+	ChannelStatusEnabled  = "enabled"  // 已启用
+	ChannelStatusDisabled = "disabled" // 已禁用
+)
+
+// ChannelInfo 通道信息
+type ChannelInfo struct {
+	ID          int    `json:"id"`           // 通道ID
+	Name        string `json:"name"`         // 通道名称
+	MessageType string `json:"message_type"` // 消息类型，见 MessageType* 常量
+	Status      string `json:"status"`       // 通道状态，见 ChannelStatus* 常量
+	Provider    string `json:"provider"`     // 服务商标识（如 "aliyun"、"tencent"）
+	Description string `json:"description"`  // 通道说明
+	CreatedAt   string `json:"created_at"`   // 创建时间
+	UpdatedAt   string `json:"updated_at"`   // 更新时间
+
+	RawData // 原始JSON，见 RawData
+}
+
+// Enabled 判断通道是否已启用
+func (ch *ChannelInfo) Enabled() bool {
+	return ch.Status == ChannelStatusEnabled
+}
+
+// ListChannelsData 通道列表数据
+type ListChannelsData struct {
+	Items []ChannelInfo `json:"items"` // 通道列表
+	Total int           `json:"total"` // 通道总数
+
+	RawData // 原始JSON，见 RawData
+}
+
+// ByName 按名称查找通道
+func (d *ListChannelsData) ByName(name string) (*ChannelInfo, bool) {
+	for i := range d.Items {
+		if d.Items[i].Name == name {
+			return &d.Items[i], true
+		}
+	}
+	return nil, false
+}
+
+// ListChannels 查询应用可用的通道，运行时发现通道ID、消息类型、状态和服务商，无需在代码中硬编码通道ID
+// 结果会被缓存，供 NewChannelID 校验通道是否存在
+func (c *Client) ListChannels(ctx context.Context) (*ListChannelsData, error) {
+	if err := c.requireFeature(ctx, FeatureChannels); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/channels", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ListChannelsData
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+	c.channels.store(data.Items)
+
+	return &data, nil
+}
+
+// GetChannel 查询单个通道信息
+func (c *Client) GetChannel(ctx context.Context, channelID int) (*ChannelInfo, error) {
+	if err := c.requireFeature(ctx, FeatureChannels); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/channels/" + strconv.Itoa(channelID)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ChannelInfo
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// WithChannelAlias 设置通道别名，业务代码通过 Client.Channel 按名称获取通道ID，避免在各服务中硬编码通道ID
+func WithChannelAlias(name string, channelID int) ClientOption {
+	return func(c *Client) {
+		c.channelAliases[name] = channelID
+		c.channelNames[channelID] = name
+	}
+}
+
+// Channel 根据别名获取通道ID
+func (c *Client) Channel(name string) (int, error) {
+	id, ok := c.channelAliases[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown channel alias %q", name)
+	}
+	return id, nil
+}
+
+// ChannelName 获取通道名称，用于日志等输出：优先使用 WithChannelAlias 设置的别名，其次使用 ListChannels 返回的通道名称，未知时返回空
+// 别名按客户端保存，多个客户端为同一通道ID设置不同别名时互不影响
+func (c *Client) ChannelName(channelID int) string {
+	if name, ok := c.channelNames[channelID]; ok {
+		return name
+	}
+	if ch, found, _ := c.channels.lookup(channelID); found {
+		return ch.Name
+	}
+	return ""
+}
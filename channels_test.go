@@ -0,0 +1,48 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestListChannels 测试通道列表和详情
+func TestListChannels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data interface{}
+		switch r.URL.Path {
+		case "/api/v1/channels":
+			data = map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": 1, "name": "otp", "message_type": "sms", "status": "enabled", "provider": "aliyun"},
+					{"id": 2, "name": "alert", "message_type": "dingtalk", "status": "disabled"},
+				},
+				"total": 2,
+			}
+		case "/api/v1/channels/2":
+			data = map[string]interface{}{"id": 2, "name": "alert", "message_type": "dingtalk", "status": "disabled"}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": data})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	list, err := client.ListChannels(context.Background())
+	if err != nil {
+		t.Fatalf("ListChannels() error = %v", err)
+	}
+	otp, ok := list.ByName("otp")
+	if !ok || otp.ID != 1 || !otp.Enabled() || otp.Provider != "aliyun" || list.Total != 2 {
+		t.Errorf("unexpected channels: %+v", list)
+	}
+
+	ch, err := client.GetChannel(context.Background(), 2)
+	if err != nil || ch.Enabled() || ch.MessageType != MessageTypeDingtalk {
+		t.Errorf("GetChannel() = %+v, %v", ch, err)
+	}
+}
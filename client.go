@@ -4,13 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/google/uuid"
 )
 
 // Client 消息推送客户端
@@ -19,15 +20,83 @@ type Client struct {
 	appID      string       // 应用ID
 	appSecret  string       // 应用密钥
 	httpClient *http.Client // HTTP客户端
+
+	credMu              sync.RWMutex        // 保护 appID、appSecret（SetCredentials）
+	credentialsProvider CredentialsProvider // 凭证提供者（为nil时使用 appID、appSecret）
+
+	attachmentLimits       map[int]AttachmentLimit // 按通道配置的附件限制
+	defaultAttachmentLimit AttachmentLimit         // 默认附件限制
+
+	hooks         []Hooks      // 请求生命周期钩子
+	logger        Logger       // 日志输出（为nil时不输出）
+	logLevel      atomic.Int32 // 日志详细级别，见 LogLevel
+	recoverPanics bool         // 是否捕获用户回调中的panic
+
+	stats *clientStats // 运行统计
+
+	deadlineFromContext bool // 是否根据context截止时间填充 DeadlineAt
+
+	channelTypes       map[int]string               // 通道ID -> 消息类型
+	receiverValidators map[string]ReceiverValidator // 消息类型 -> 接收者校验规则
+	contentLimits      map[string]ContentLimit      // 消息类型 -> 内容长度限制
+	channelTemplates   map[int]string               // 通道ID -> 模板内容
+	channelAliases     map[string]int               // 通道别名 -> 通道ID
+	channelNames       map[int]string               // 通道ID -> 别名
+	channels           *channelCache                // 最近一次 ListChannels 返回的通道列表
+
+	signatureDebug func(SignatureDebugInfo) // 签名失败时的调试回调
+	contentDigest  bool                     // 是否发送请求体摘要并参与签名
+	signer         Signer                   // 签名算法（为nil时使用 SignerV1）
+
+	maxAttempts int     // 最大尝试次数（含首次）
+	backoff     Backoff // 重试退避策略
+
+	redactor Redactor // 脱敏策略
+
+	discoverCapabilities bool             // 是否探测服务端能力
+	capabilities         *capabilityCache // 服务端能力缓存
+
+	rand io.Reader // 随机源（为nil时使用 crypto/rand）
+
+	batchChunkSize int // 批量发送自动分片大小，0表示不分片
+
+	simulatedFailureRate  float64 // 模拟失败概率（仅预发/测试环境）
+	simulatedFailureCodes []int   // 模拟失败的错误码
+
+	limiter *rateLimiter // 客户端限流（为nil时不限流）
+
+	environment string            // 运行环境
+	guardrails  StagingGuardrails // 预发环境防护规则
+
+	partialFailureError bool // 批量发送部分失败时是否返回错误
+	skipValidation      bool // 是否跳过发送前的请求字段校验
+
+	breakers    *breakerSet        // 按通道的熔断器（为nil时不熔断）
+	degradation *DegradationPolicy // 网关故障时的降级策略（为nil时不降级）
+	spoolCounts spoolCounters      // 暂存累计计数
+
+	taskNotifier TaskNotifier // 任务状态变更通知（为nil时 WaitForTask 只轮询）
+
+	clock     *clockSync       // 时钟校准（为nil时不校准）
+	now       func() time.Time // 签名时间戳的时间源（为nil时使用 time.Now）
+	nonceFunc func() string    // 请求随机数生成函数（为nil时使用 UUID v4）
 }
 
 // ClientOption 客户端配置选项
 type ClientOption func(*Client)
 
-// WithHTTPClient 设置自定义HTTP客户端
+// WithHTTPClient 设置自定义HTTP客户端，客户端保存其浅拷贝，WithTimeout、WithProxy 等选项不会修改传入的 http.Client（可以是 http.DefaultClient）
 func WithHTTPClient(client *http.Client) ClientOption {
 	return func(c *Client) {
-		c.httpClient = client
+		cp := *client
+		c.httpClient = &cp
+	}
+}
+
+// WithTransport 设置HTTP传输层，例如 GOOS=js 下的 NewFetchTransport 或测试用的桩实现
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
 	}
 }
 
@@ -47,8 +116,25 @@ func NewClient(baseURL, appID, appSecret string, opts ...ClientOption) *Client {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		attachmentLimits:       make(map[int]AttachmentLimit),
+		defaultAttachmentLimit: DefaultAttachmentLimit,
+		recoverPanics:          true,
+		stats:                  newClientStats(defaultRecentErrors),
+		channelTypes:           make(map[int]string),
+		receiverValidators:     make(map[string]ReceiverValidator),
+		contentLimits:          make(map[string]ContentLimit),
+		channelTemplates:       make(map[int]string),
+		channelAliases:         make(map[string]int),
+		channelNames:           make(map[int]string),
+		channels:               &channelCache{},
+		maxAttempts:            1,
+		backoff:                DefaultBackoff,
+		capabilities:           &capabilityCache{},
+		redactor:               DefaultRedactor,
 	}
 
+	c.logLevel.Store(int32(LogLevelDebug))
+
 	// 应用配置选项
 	for _, opt := range opts {
 		opt(c)
@@ -57,90 +143,311 @@ func NewClient(baseURL, appID, appSecret string, opts ...ClientOption) *Client {
 	return c
 }
 
-// doRequest 执行HTTP请求
+// doRequest 执行HTTP请求（按配置自动重试）并触发生命周期钩子
 func (c *Client) doRequest(ctx context.Context, method, path string, reqData interface{}) (*Response, error) {
-	// 生成时间戳和随机数
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-	nonce := uuid.New().String()
+	resp, _, err := c.execute(ctx, method, path, reqData)
+	return resp, err
+}
 
-	// 构建请求体和参数map（用于签名）
-	var bodyBytes []byte
-	var params map[string]interface{}
+// execute 执行请求（按配置自动重试），返回最后一次尝试的HTTP状态码
+func (c *Client) execute(ctx context.Context, method, path string, reqData interface{}) (*Response, int, error) {
+	operationID := c.newNonce()
+	maxAttempts := c.maxAttempts
+	if o := callOptionsFrom(ctx); o != nil && o.noRetry {
+		maxAttempts = 1
+	}
 
-	if reqData != nil {
-		// 序列化请求数据
-		var err error
-		bodyBytes, err = json.Marshal(reqData)
-		if err != nil {
-			return nil, fmt.Errorf("marshal request data: %w", err)
+	// 记录请求阶段，超时或取消时写入 TimeoutError/CanceledError
+	tracker := &phaseTracker{}
+	ctx = context.WithValue(tracker.trace(ctx), phaseTrackerKey{}, tracker)
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		resp, statusCode, err := c.attempt(ctx, method, path, reqData, operationID, attempt)
+		if err == nil {
+			return resp, statusCode, nil
 		}
+		retry, reason := classifyRetry(err, statusCode)
+		if retry && attempt >= maxAttempts {
+			retry, reason = false, RetryReasonMaxAttempts
+		}
+		var delay time.Duration
+		if retry {
+			tracker.set(PhaseBackoff)
+			delay = c.retryDelay(attempt, err)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+				retry, reason = false, RetryReasonDeadline
+			}
+		}
+		c.fireRetry(ctx, newRetryInfo(method, path, reqData, operationID, attempt, maxAttempts, retry, reason, delay, err, c.breakers))
+		if retry && sleepContext(ctx, delay) {
+			continue
+		}
+		err = notSupported(method, path, statusCode, err)
+		err = contextError(ctx, err, method, path, time.Since(start), attempt, tracker.current())
+		c.fireError(ctx, err)
+		return resp, statusCode, err
+	}
+}
 
-		// 将请求数据转换为map（用于签名）
-		if err := json.Unmarshal(bodyBytes, &params); err != nil {
-			return nil, fmt.Errorf("unmarshal request data to map: %w", err)
+// attempt 执行单次请求尝试，每次尝试触发 OnRequest/OnResponse 钩子
+func (c *Client) attempt(ctx context.Context, method, path string, reqData interface{}, operationID string, attempt int) (*Response, int, error) {
+	start := time.Now()
+	channelID := requestChannelID(reqData)
+	requestID := newRequestID(operationID, attempt)
+
+	c.stats.begin()
+	c.fireRequest(ctx, &RequestInfo{
+		Method:      method,
+		Path:        path,
+		ChannelID:   channelID,
+		Receivers:   redactReceivers(c.redactor, requestReceivers(reqData)),
+		OperationID: operationID,
+		RequestID:   requestID,
+		Attempt:     attempt,
+	})
+
+	var resp *Response
+	var statusCode int
+	err := c.breakers.allow(channelID)
+	if err == nil {
+		resp, statusCode, err = c.send(ctx, method, path, reqData, requestID)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Code == ErrCodeInvalidTimestamp && c.clock.adjust(apiErr.ServerTime, c.timeNow()) {
+			// 按服务端时间校准后重新签名发送一次
+			resp, statusCode, err = c.send(ctx, method, path, reqData, requestID)
 		}
+		c.breakers.record(channelID, err, statusCode)
+	}
+	err = withRequestID(err, requestID)
+	c.stats.end(method, path, time.Since(start), err, c.redactor)
+
+	info := &ResponseInfo{
+		Method:      method,
+		Path:        path,
+		ChannelID:   channelID,
+		OperationID: operationID,
+		RequestID:   requestID,
+		Attempt:     attempt,
+		StatusCode:  statusCode,
+		Duration:    time.Since(start),
+		Err:         err,
+	}
+	if resp != nil {
+		info.Code = resp.Code
+	}
+	c.fireResponse(ctx, info)
+
+	return resp, statusCode, err
+}
+
+// send 签名并发送HTTP请求，返回解析后的响应和HTTP状态码
+func (c *Client) send(ctx context.Context, method, path string, reqData interface{}, requestID string) (*Response, int, error) {
+	if apiErr := c.simulateFailure(method, path); apiErr != nil {
+		return nil, 0, apiErr
+	}
+	if c.limiter != nil {
+		setPhase(ctx, PhaseRateLimit)
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// 获取凭证，生成时间戳和随机数
+	appID, appSecret, err := c.credentials(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	timestamp := strconv.FormatInt(c.clock.adjusted(c.timeNow()).Unix(), 10)
+	nonce := c.requestNonce()
+
+	// 构建请求体和参数map（用于签名）
+	body, err := encodeRequestBody(reqData)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// 生成签名
-	signature := generateSignature(method, path, params, timestamp, nonce, c.appSecret)
+	sortedParams := body.signParams
+	var digest string
+	if c.contentDigest {
+		digest = ContentSHA256(body.data)
+	}
+	input := SignatureInput{Method: method, Path: path, Body: sortedParams, Timestamp: timestamp, Nonce: nonce, ContentSHA256: digest}
+	signature, signatureVersion, err := c.sign(input, appSecret)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// 构建HTTP请求
 	url := c.baseURL + path
-	var body io.Reader
-	if len(bodyBytes) > 0 {
-		body = bytes.NewReader(bodyBytes)
+	var bodyReader io.Reader
+	if len(body.data) > 0 {
+		bodyReader = bytes.NewReader(body.data)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+
+	// 设置请求头（单次调用附加的请求头先设置，签名相关请求头不会被覆盖）
+	if o := callOptionsFrom(ctx); o != nil {
+		for key, values := range o.header {
+			req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+	if len(body.data) > 0 && body.contentType != "" {
+		req.Header.Set("Content-Type", body.contentType)
+	}
+	req.Header.Set(HeaderAppID, appID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, signature)
+	req.Header.Set(HeaderRequestID, requestID)
+	if digest != "" {
+		req.Header.Set(HeaderContentSHA256, digest)
+	}
+	if signatureVersion != SignatureVersion1 {
+		req.Header.Set(HeaderSignatureVersion, signatureVersion)
 	}
 
-	// 设置请求头
-	if method == http.MethodPost {
-		req.Header.Set("Content-Type", "application/json")
+	if c.logEnabled(LogLevelTrace) {
+		c.traceRequest(input, sortedParams, body, requestID)
 	}
-	req.Header.Set("X-App-Id", c.appID)
-	req.Header.Set("X-Timestamp", timestamp)
-	req.Header.Set("X-Nonce", nonce)
-	req.Header.Set("X-Signature", signature)
 
-	// 发送请求
+	// 发送请求（连接复用时直接进入 write_request 阶段）
+	setPhase(ctx, PhaseConnect)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
+		return nil, 0, fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// 读取响应体
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response body: %w", err)
+	var result Response
+	var respBody []byte
+	if streamer := responseStreamerFrom(ctx); streamer != nil && streamer.streams(resp) {
+		// 导出数据、批量逐条结果等大响应边读边处理，不整体读入内存
+		streamed, err := streamer.consume(resp)
+		if err != nil {
+			return nil, resp.StatusCode, err
+		}
+		result = *streamed
+	} else {
+		// 读取响应体
+		respBody, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("read response body: %w", err)
+		}
+		if c.logEnabled(LogLevelTrace) {
+			c.logger.Debugf("mlievpush: trace response %s %s request_id=%s status=%d body=%s",
+				method, c.redactor.RedactText(path), requestID, resp.StatusCode, c.redactor.RedactText(string(respBody)))
+		}
+
+		// 解析响应
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, resp.StatusCode, newHTTPError(resp, respBody, err, c.redactor)
+		}
 	}
 
-	// 解析响应
-	var result Response
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, fmt.Errorf("unmarshal response: %w", err)
+	// 签名校验失败时输出调试信息
+	if result.Code == ErrCodeInvalidSignature && c.signatureDebug != nil {
+		// 仅对参数部分脱敏，时间戳、随机数等保持原样便于与服务端对比
+		redacted := input
+		redacted.Body = c.redactor.RedactText(sortedParams)
+		info := SignatureDebugInfo{
+			AppID:           appID,
+			Method:          method,
+			Path:            path,
+			SortedParams:    redacted.Body,
+			Timestamp:       timestamp,
+			Nonce:           nonce,
+			ContentSHA256:   digest,
+			CanonicalString: redacted.Canonical(),
+			Signature:       signature,
+			Version:         signatureVersion,
+		}
+		c.safeCall(ctx, "SignatureDebug", func() { c.signatureDebug(info) })
 	}
 
 	// 检查业务错误
 	if result.Code != 0 {
-		return &result, NewAPIError(result.Code, result.Message)
+		apiErr := NewAPIError(result.Code, result.Message)
+		// HTTP 日期格式的 Retry-After 是服务端时间，与签名时间戳一样按校准后的时钟计算
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), c.clock.adjusted(c.timeNow()))
+		if result.Code == ErrCodeInvalidTimestamp {
+			apiErr.ServerTime = parseServerTime(resp.Header)
+		}
+		return &result, resp.StatusCode, apiErr
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// 非2xx但没有业务错误码，通常是代理或网关返回的通用JSON错误
+		return nil, resp.StatusCode, newHTTPError(resp, respBody, nil, c.redactor)
 	}
 
-	return &result, nil
+	return &result, resp.StatusCode, nil
 }
 
 // SendMessage 发送单条消息
-func (c *Client) SendMessage(ctx context.Context, req *SendMessageRequest) (*SendMessageData, error) {
+func (c *Client) SendMessage(ctx context.Context, req *SendMessageRequest, opts ...CallOption) (*SendMessageData, error) {
+	ctx, cancel := withCallOptions(ctx, opts)
+	defer cancel()
+
+	if !c.skipValidation {
+		if err := req.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	params, err := MarshalParams(req.TemplateParams)
+	if err != nil {
+		return nil, err
+	}
+	params, err = c.guard(params, req.Receiver)
+	if err != nil {
+		return nil, err
+	}
+	cp := *req
+	cp.TemplateParams = params
+	cp.IdempotencyKey = c.retryIdempotencyKey(ctx, cp.IdempotencyKey)
+	req = &cp
+
+	// 上传前在本地校验附件，避免等到服务商返回错误
+	if err := c.attachmentLimit(req.ChannelID).Validate(req.Attachments); err != nil {
+		return nil, err
+	}
+	if err := c.validateReceivers(req.ChannelID, req.Receiver); err != nil {
+		return nil, err
+	}
+	if err := c.checkContentLength(req.ChannelID, req.SignatureName, req.TemplateParams); err != nil {
+		return nil, err
+	}
+
+	if req.DeadlineAt == "" {
+		if deadline := c.contextDeadline(ctx); deadline != "" {
+			cp := *req
+			cp.DeadlineAt = deadline
+			req = &cp
+		}
+	}
+	if req.CountryCode == "" {
+		// 国际号码由网关路由到国际通道
+		if code, region := c.detectCountry(req.ChannelID, req.Receiver); code != "" {
+			cp := *req
+			cp.CountryCode = code
+			if cp.Region == "" {
+				cp.Region = region
+			}
+			req = &cp
+		}
+	}
+
 	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages", req)
 	if err != nil {
 		return nil, err
 	}
 
 	var data SendMessageData
-	if err := json.Unmarshal(resp.Data, &data); err != nil {
+	if err := decodeData(resp.Data, &data); err != nil {
 		return nil, fmt.Errorf("unmarshal response data: %w", err)
 	}
 
@@ -148,22 +455,94 @@ func (c *Client) SendMessage(ctx context.Context, req *SendMessageRequest) (*Sen
 }
 
 // SendBatch 批量发送消息
-func (c *Client) SendBatch(ctx context.Context, req *SendBatchRequest) (*SendBatchData, error) {
-	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages/batch", req)
+// 配置 WithBatchChunkSize 后接收者超出分片大小时按顺序分片发送，设置 IdempotencyKey 时各分片使用派生的幂等键
+func (c *Client) SendBatch(ctx context.Context, req *SendBatchRequest, opts ...CallOption) (*SendBatchData, error) {
+	ctx, cancel := withCallOptions(ctx, opts)
+	defer cancel()
+
+	if !c.skipValidation {
+		if err := req.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	params, err := MarshalParams(req.TemplateParams)
+	if err != nil {
+		return nil, err
+	}
+	params, err = c.guard(params, req.Receivers...)
+	if err != nil {
+		return nil, err
+	}
+	cp := *req
+	cp.TemplateParams = params
+	cp.IdempotencyKey = c.retryIdempotencyKey(ctx, cp.IdempotencyKey)
+	req = &cp
+
+	if err := c.attachmentLimit(req.ChannelID).Validate(req.Attachments); err != nil {
+		return nil, err
+	}
+	if err := c.validateReceivers(req.ChannelID, req.Receivers...); err != nil {
+		return nil, err
+	}
+	if err := c.checkContentLength(req.ChannelID, req.SignatureName, req.TemplateParams); err != nil {
+		return nil, err
+	}
+
+	if req.DeadlineAt == "" {
+		if deadline := c.contextDeadline(ctx); deadline != "" {
+			cp := *req
+			cp.DeadlineAt = deadline
+			req = &cp
+		}
+	}
+	if req.CountryCode == "" {
+		// 国际号码由网关路由到国际通道
+		if code, region := c.detectCountry(req.ChannelID, req.Receivers...); code != "" {
+			cp := *req
+			cp.CountryCode = code
+			if cp.Region == "" {
+				cp.Region = region
+			}
+			req = &cp
+		}
+	}
+
+	var data *SendBatchData
+	if c.batchChunkSize > 0 && len(req.Receivers) > c.batchChunkSize {
+		data, err = c.sendBatchChunks(ctx, req)
+	} else {
+		data, err = c.sendBatch(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.partialFailureError {
+		return data, partialFailure(data)
+	}
+	return data, nil
+}
+
+// sendBatch 发送单个批量请求
+func (c *Client) sendBatch(ctx context.Context, req *SendBatchRequest) (*SendBatchData, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, batchPath(ctx), req)
 	if err != nil {
 		return nil, err
 	}
 
 	var data SendBatchData
-	if err := json.Unmarshal(resp.Data, &data); err != nil {
+	if err := decodeData(resp.Data, &data); err != nil {
 		return nil, fmt.Errorf("unmarshal response data: %w", err)
 	}
+	fillFailedReceivers(&data)
 
 	return &data, nil
 }
 
 // QueryTask 查询任务状态
-func (c *Client) QueryTask(ctx context.Context, taskID string) (*QueryTaskData, error) {
+func (c *Client) QueryTask(ctx context.Context, taskID string, opts ...CallOption) (*QueryTaskData, error) {
+	ctx, cancel := withCallOptions(ctx, opts)
+	defer cancel()
+
 	path := "/api/v1/messages/" + taskID
 	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
@@ -171,7 +550,47 @@ func (c *Client) QueryTask(ctx context.Context, taskID string) (*QueryTaskData,
 	}
 
 	var data QueryTaskData
-	if err := json.Unmarshal(resp.Data, &data); err != nil {
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// QueryBatch 查询批量任务状态（包含各接收者的子任务状态）
+func (c *Client) QueryBatch(ctx context.Context, batchID string) (*QueryBatchData, error) {
+	if err := c.requireFeature(ctx, FeatureBatchDetail); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/messages/batch/" + batchID
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data QueryBatchData
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// AnnotateTask 为任务添加备注，备注会出现在 QueryTask 返回的 Annotations 中
+func (c *Client) AnnotateTask(ctx context.Context, taskID, note string) (*TaskAnnotation, error) {
+	if err := c.requireFeature(ctx, FeatureAnnotations); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/messages/" + taskID + "/annotations"
+	resp, err := c.doRequest(ctx, http.MethodPost, path, &AnnotateTaskRequest{Note: note})
+	if err != nil {
+		return nil, err
+	}
+
+	var data TaskAnnotation
+	if err := decodeData(resp.Data, &data); err != nil {
 		return nil, fmt.Errorf("unmarshal response data: %w", err)
 	}
 
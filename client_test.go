@@ -3,8 +3,10 @@ package mlievpush
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -283,6 +285,57 @@ func TestQueryTask(t *testing.T) {
 	}
 }
 
+// TestQueryBatch 测试查询批量任务
+func TestQueryBatch(t *testing.T) {
+	batchID := "batch-20251125-001"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		expectedPath := "/api/v1/messages/batch/" + batchID
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		resp := map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data": map[string]interface{}{
+				"batch_id":      batchID,
+				"channel_id":    1,
+				"total_count":   2,
+				"success_count": 1,
+				"failed_count":  1,
+				"tasks": []map[string]interface{}{
+					{"task_id": "t1", "receiver": "13800138000", "status": "success", "callback_status": "delivered"},
+					{"task_id": "t2", "receiver": "13800138001", "status": "failed", "error_message": "空号"},
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	data, err := client.QueryBatch(context.Background(), batchID)
+	if err != nil {
+		t.Fatalf("QueryBatch() error = %v", err)
+	}
+
+	if data.BatchID != batchID {
+		t.Errorf("BatchID = %v, want %v", data.BatchID, batchID)
+	}
+	if len(data.Tasks) != 2 {
+		t.Fatalf("len(Tasks) = %d, want 2", len(data.Tasks))
+	}
+	if data.Tasks[1].Status != TaskStatusFailed || data.Tasks[1].ErrorMessage != "空号" {
+		t.Errorf("Tasks[1] = %+v", data.Tasks[1])
+	}
+}
+
 // TestAPIError 测试API错误处理
 func TestAPIError(t *testing.T) {
 	// 创建mock服务器返回错误
@@ -393,3 +446,81 @@ func TestContextCancellation(t *testing.T) {
 		t.Fatal("expected cancellation error, got nil")
 	}
 }
+
+// TestAnnotateTask 测试添加任务备注
+func TestAnnotateTask(t *testing.T) {
+	taskID := "550e8400-e29b-41d4-a716-446655440000"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		expectedPath := "/api/v1/messages/" + taskID + "/annotations"
+		if r.URL.Path != expectedPath {
+			t.Errorf("expected %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		var body AnnotateTaskRequest
+		json.NewDecoder(r.Body).Decode(&body)
+
+		resp := map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data": map[string]interface{}{
+				"id":         1,
+				"note":       body.Note,
+				"author":     "test_app_id",
+				"created_at": "2025-11-25T10:32:00Z",
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	data, err := client.AnnotateTask(context.Background(), taskID, "用户确认10:32已收到")
+	if err != nil {
+		t.Fatalf("AnnotateTask() error = %v", err)
+	}
+	if data.Note != "用户确认10:32已收到" {
+		t.Errorf("Note = %v, want %v", data.Note, "用户确认10:32已收到")
+	}
+}
+
+// recordingTransport 记录请求的 RoundTripper
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"code":0,"message":"success","data":{"task_id":"t1"}}`)),
+		Request:    req,
+	}, nil
+}
+
+// TestWithTransport 测试自定义传输层
+func TestWithTransport(t *testing.T) {
+	transport := &recordingTransport{}
+	client := NewClient("https://push.example.com", "test_app_id", "test_secret", WithTransport(transport))
+
+	_, err := client.SendMessage(context.Background(), &SendMessageRequest{
+		ChannelID: 1,
+		Receiver:  "13800138000",
+	})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if len(transport.requests) != 1 {
+		t.Fatalf("requests = %d, want 1", len(transport.requests))
+	}
+	if got := transport.requests[0].Header.Get(HeaderSignature); got == "" {
+		t.Error("request should be signed")
+	}
+}
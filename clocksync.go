@@ -0,0 +1,116 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HeaderServerTime 服务端时间响应头（Unix秒），缺失时使用 Date 响应头
+const HeaderServerTime = "X-Server-Time"
+
+// ErrNoServerTime 响应中没有服务端时间
+var ErrNoServerTime = errors.New("mlievpush: response has no server time")
+
+// WithClockSync 开启时钟校准：请求因时间戳无效（20004）被拒绝时，按响应中的服务端时间记录偏移量，
+// 之后的请求使用校准后的时间戳签名，并立即重新签名发送一次，避免本机时钟漂移导致请求持续失败
+func WithClockSync() ClientOption {
+	return func(c *Client) {
+		c.clock = &clockSync{}
+	}
+}
+
+// WithClock 设置签名时间戳的时间源，用于测试中得到确定的签名，传入 nil 时恢复默认（time.Now）
+// 开启 WithClockSync 时，时钟偏移量叠加在该时间源之上
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
+// timeNow 返回时间源的当前时间
+func (c *Client) timeNow() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// clockSync 本机与服务端的时钟偏移
+type clockSync struct {
+	mu     sync.RWMutex
+	offset time.Duration // 服务端时间 - 本机时间
+}
+
+// adjusted 返回按偏移量校准后的时间
+func (s *clockSync) adjusted(t time.Time) time.Time {
+	if s == nil {
+		return t
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return t.Add(s.offset)
+}
+
+// adjust 按服务端时间更新偏移量，未开启校准或没有服务端时间时返回false
+func (s *clockSync) adjust(serverTime, localTime time.Time) bool {
+	if s == nil || serverTime.IsZero() {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset = serverTime.Sub(localTime)
+	return true
+}
+
+// ClockOffset 返回当前使用的时钟偏移量（服务端时间 - 本机时间），未开启 WithClockSync 时为0
+func (c *Client) ClockOffset() time.Duration {
+	if c.clock == nil {
+		return 0
+	}
+	c.clock.mu.RLock()
+	defer c.clock.mu.RUnlock()
+	return c.clock.offset
+}
+
+// SyncClock 主动向网关获取服务端时间并更新时钟偏移量，适合在启动时调用
+// 发送一个不签名的 HEAD 请求，读取 X-Server-Time 或 Date 响应头；需要先开启 WithClockSync
+func (c *Client) SyncClock(ctx context.Context) error {
+	if c.clock == nil {
+		return errors.New("mlievpush: clock sync is not enabled")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	resp.Body.Close()
+
+	serverTime := parseServerTime(resp.Header)
+	if serverTime.IsZero() {
+		return ErrNoServerTime
+	}
+	c.clock.adjust(serverTime, c.timeNow())
+	return nil
+}
+
+// parseServerTime 从响应头读取服务端时间，优先 X-Server-Time（Unix秒），其次 Date
+func parseServerTime(header http.Header) time.Time {
+	if value := strings.TrimSpace(header.Get(HeaderServerTime)); value != "" {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+			return time.Unix(seconds, 0)
+		}
+	}
+	if t, err := http.ParseTime(header.Get("Date")); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,101 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSkewedServer 创建时钟比本机快 skew 的服务端，时间戳偏差超过1分钟时拒绝请求
+func newSkewedServer(t *testing.T, skew time.Duration) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverNow := time.Now().Add(skew)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(HeaderServerTime, strconv.FormatInt(serverNow.Unix(), 10))
+		if r.Method == http.MethodHead {
+			return
+		}
+		calls.Add(1)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if d := serverNow.Sub(time.Unix(ts, 0)); d > time.Minute || d < -time.Minute {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeInvalidTimestamp, "message": "时间戳无效"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// TestClockSync 测试时间戳无效时校准时钟并重发
+func TestClockSync(t *testing.T) {
+	server, calls := newSkewedServer(t, time.Hour)
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}
+
+	var apiErr *APIError
+	_, err := NewClient(server.URL, "test_app_id", "test_secret").SendMessage(context.Background(), req)
+	if !errors.Is(err, ErrTimestampSkew) || !errors.As(err, &apiErr) || apiErr.ServerTime.IsZero() {
+		t.Fatalf("expected ErrTimestampSkew with server time, got %v", err)
+	}
+
+	calls.Store(0)
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithClockSync())
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls, got %d", calls.Load())
+	}
+	if offset := client.ClockOffset(); offset < 59*time.Minute || offset > 61*time.Minute {
+		t.Errorf("ClockOffset() = %v", offset)
+	}
+
+	// 校准后的请求不再被拒绝
+	if _, err := client.SendMessage(context.Background(), req); err != nil || calls.Load() != 3 {
+		t.Errorf("SendMessage() error = %v, calls = %d", err, calls.Load())
+	}
+}
+
+// TestSyncClock 测试主动获取服务端时间
+func TestSyncClock(t *testing.T) {
+	server, calls := newSkewedServer(t, -2*time.Hour)
+
+	if err := NewClient(server.URL, "test_app_id", "test_secret").SyncClock(context.Background()); err == nil {
+		t.Error("expected error when clock sync is not enabled")
+	}
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithClockSync())
+	if err := client.SyncClock(context.Background()); err != nil {
+		t.Fatalf("SyncClock() error = %v", err)
+	}
+	if _, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 call, got %d", calls.Load())
+	}
+}
+
+// TestParseServerTime 测试读取服务端时间响应头
+func TestParseServerTime(t *testing.T) {
+	h := http.Header{}
+	if !parseServerTime(h).IsZero() {
+		t.Error("expected zero time without headers")
+	}
+	h.Set("Date", "Wed, 21 Oct 2015 07:28:00 GMT")
+	if got := parseServerTime(h); got.Unix() != 1445412480 {
+		t.Errorf("Date: got %v", got)
+	}
+	h.Set(HeaderServerTime, "1700000000")
+	if got := parseServerTime(h); got.Unix() != 1700000000 {
+		t.Errorf("X-Server-Time: got %v", got)
+	}
+}
@@ -0,0 +1,416 @@
+// Command mliev-push 消息推送命令行工具
+//
+// 基于SDK发送测试消息、查询任务、查看通道和模板，供运维人员在不写Go代码的情况下使用。
+//
+// 凭证按以下优先级读取：命令行参数 > 环境变量（MLIEV_PUSH_URL、MLIEV_PUSH_APP_ID、MLIEV_PUSH_APP_SECRET）
+// > 配置文件（-config 或 MLIEV_PUSH_CONFIG，命名配置通过 -profile 或 MLIEV_PUSH_PROFILE 选择）。
+//
+// 用法：
+//
+//	mliev-push send -channel 1 -signature 木雷科技 -receiver 13800138000 -param code=123456
+//	mliev-push batch -channel 1 -signature 木雷科技 -receivers 13800138000,13900139000
+//	mliev-push query -wait 30s <task_id>
+//	mliev-push channels -o table
+//	mliev-push templates -channel 1
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// usage 命令用法
+const usage = `Usage: mliev-push <command> [flags]
+
+Commands:
+  send       send a single message
+  batch      send a message to multiple receivers
+  query      query a task (or a batch with -batch)
+  channels   list channels
+  templates  list templates
+
+Run "mliev-push <command> -h" for command flags.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 执行命令，返回进程退出码
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	commands := map[string]func(*env, []string) error{
+		"send":      cmdSend,
+		"batch":     cmdBatch,
+		"query":     cmdQuery,
+		"channels":  cmdChannels,
+		"templates": cmdTemplates,
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	e := &env{stdout: stdout, stderr: stderr}
+	if err := cmd(e, args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		if requestID := mlievpush.RequestIDFromError(err); requestID != "" {
+			fmt.Fprintf(stderr, "request id: %s\n", requestID)
+		}
+		return 1
+	}
+	return 0
+}
+
+// env 命令执行环境：公共参数和输出
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	url       string
+	appID     string
+	appSecret string
+	config    string
+	profile   string
+	output    string
+	timeout   time.Duration
+}
+
+// flags 创建子命令的参数集并注册公共参数
+func (e *env) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.url, "url", os.Getenv(mlievpush.EnvBaseURL), "gateway base URL")
+	fs.StringVar(&e.appID, "app-id", os.Getenv(mlievpush.EnvAppID), "application ID")
+	fs.StringVar(&e.appSecret, "app-secret", os.Getenv(mlievpush.EnvAppSecret), "application secret (prefer the MLIEV_PUSH_APP_SECRET environment variable)")
+	fs.StringVar(&e.config, "config", os.Getenv(mlievpush.EnvConfig), "config file (see NewClientFromConfig)")
+	fs.StringVar(&e.profile, "profile", os.Getenv(mlievpush.EnvProfile), "config profile (empty for the default profile)")
+	fs.StringVar(&e.output, "o", "json", "output format: json or table")
+	fs.DurationVar(&e.timeout, "timeout", 10*time.Second, "request timeout")
+	return fs
+}
+
+// client 按优先级解析凭证并创建客户端
+func (e *env) client() (*mlievpush.Client, error) {
+	if e.output != "json" && e.output != "table" {
+		return nil, fmt.Errorf("unknown output format %q", e.output)
+	}
+
+	var p mlievpush.ProfileConfig
+	if e.config != "" {
+		cfg, err := mlievpush.LoadConfig(e.config)
+		if err != nil {
+			return nil, err
+		}
+		profile, err := cfg.Profile(e.profile)
+		if err != nil {
+			return nil, err
+		}
+		p = *profile
+	}
+
+	// 命令行参数和环境变量覆盖配置文件
+	if e.url != "" {
+		p.BaseURL = e.url
+	}
+	if e.appID != "" {
+		p.AppID = e.appID
+	}
+	if e.appSecret != "" {
+		p.AppSecret, p.AppSecretEnv, p.AppSecretFile = e.appSecret, "", ""
+	}
+	if p.BaseURL == "" || p.AppID == "" {
+		return nil, errors.New("missing credentials: set -url/-app-id/-app-secret, MLIEV_PUSH_* environment variables or -config")
+	}
+	return p.NewClient(mlievpush.WithTimeout(e.timeout))
+}
+
+// channelID 解析通道参数，支持通道ID或配置文件中的通道别名
+func channelID(client *mlievpush.Client, value string) (int, error) {
+	if value == "" {
+		return 0, errors.New("-channel is required")
+	}
+	if id, err := strconv.Atoi(value); err == nil {
+		return id, nil
+	}
+	return client.Channel(value)
+}
+
+// params 模板参数（可重复的 key=value）
+type params map[string]interface{}
+
+// String 实现 flag.Value 接口
+func (p params) String() string {
+	pairs := make([]string, 0, len(p))
+	for k, v := range p {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set 实现 flag.Value 接口
+func (p params) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("param must be key=value, got %q", value)
+	}
+	p[key] = val
+	return nil
+}
+
+// cmdSend 发送单条消息
+func cmdSend(e *env, args []string) error {
+	fs := e.flags("send")
+	channel := fs.String("channel", "", "channel ID or alias")
+	signature := fs.String("signature", "", "signature name")
+	receiver := fs.String("receiver", "", "receiver")
+	idempotencyKey := fs.String("idempotency-key", "", "idempotency key")
+	templateParams := params{}
+	fs.Var(templateParams, "param", "template param key=value (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	id, err := channelID(client, *channel)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	data, err := client.SendMessage(ctx, &mlievpush.SendMessageRequest{
+		ChannelID:      id,
+		SignatureName:  *signature,
+		Receiver:       *receiver,
+		TemplateParams: templateParams,
+		IdempotencyKey: *idempotencyKey,
+	})
+	if err != nil {
+		return err
+	}
+	return e.print(data, []string{"TASK ID", "STATUS", "CREATED AT"}, [][]string{{data.TaskID, data.Status, data.CreatedAt}})
+}
+
+// cmdBatch 批量发送消息
+func cmdBatch(e *env, args []string) error {
+	fs := e.flags("batch")
+	channel := fs.String("channel", "", "channel ID or alias")
+	signature := fs.String("signature", "", "signature name")
+	receivers := fs.String("receivers", "", "comma-separated receivers")
+	receiversFile := fs.String("receivers-file", "", "file with one receiver per line (- for stdin)")
+	idempotencyKey := fs.String("idempotency-key", "", "idempotency key")
+	templateParams := params{}
+	fs.Var(templateParams, "param", "template param key=value (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	list, err := readReceivers(*receivers, *receiversFile)
+	if err != nil {
+		return err
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	id, err := channelID(client, *channel)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	data, err := client.SendBatch(ctx, &mlievpush.SendBatchRequest{
+		ChannelID:      id,
+		SignatureName:  *signature,
+		Receivers:      list,
+		TemplateParams: templateParams,
+		IdempotencyKey: *idempotencyKey,
+	})
+	if err != nil {
+		return err
+	}
+	return e.print(data,
+		[]string{"BATCH ID", "TOTAL", "SUCCESS", "FAILED", "CREATED AT"},
+		[][]string{{data.BatchID, strconv.Itoa(data.TotalCount), strconv.Itoa(data.SuccessCount), strconv.Itoa(data.FailedCount), data.CreatedAt}})
+}
+
+// readReceivers 合并命令行和文件中的接收者
+func readReceivers(inline, path string) ([]string, error) {
+	var list []string
+	for _, r := range strings.Split(inline, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			list = append(list, r)
+		}
+	}
+	if path != "" {
+		var data []byte
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read receivers: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				list = append(list, line)
+			}
+		}
+	}
+	if len(list) == 0 {
+		return nil, errors.New("-receivers or -receivers-file is required")
+	}
+	return list, nil
+}
+
+// cmdQuery 查询任务或批次
+func cmdQuery(e *env, args []string) error {
+	fs := e.flags("query")
+	batch := fs.Bool("batch", false, "query a batch instead of a task")
+	wait := fs.Duration("wait", 0, "wait up to this long for the task to finish")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: mliev-push query [flags] <task_id|batch_id>")
+	}
+	id := fs.Arg(0)
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	timeout := e.timeout
+	if *wait > timeout {
+		timeout = *wait
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if *batch {
+		data, err := client.QueryBatch(ctx, id)
+		if err != nil {
+			return err
+		}
+		return e.print(data,
+			[]string{"BATCH ID", "CHANNEL", "TOTAL", "SUCCESS", "FAILED", "CREATED AT"},
+			[][]string{{data.BatchID, strconv.Itoa(data.ChannelID), strconv.Itoa(data.TotalCount), strconv.Itoa(data.SuccessCount), strconv.Itoa(data.FailedCount), data.CreatedAt}})
+	}
+
+	var data *mlievpush.QueryTaskData
+	if *wait > 0 {
+		data, err = client.WaitForTask(ctx, id, mlievpush.PollOptions{})
+	} else {
+		data, err = client.QueryTask(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	return e.print(data,
+		[]string{"TASK ID", "CHANNEL", "RECEIVER", "STATUS", "CALLBACK", "ERROR", "UPDATED AT"},
+		[][]string{{data.TaskID, strconv.Itoa(data.ChannelID), data.Receiver, data.Status, data.CallbackStatus, data.ErrorMessage, data.UpdatedAt}})
+}
+
+// cmdChannels 列出通道
+func cmdChannels(e *env, args []string) error {
+	fs := e.flags("channels")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	data, err := client.ListChannels(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(data.Items))
+	for _, ch := range data.Items {
+		rows = append(rows, []string{strconv.Itoa(ch.ID), ch.Name, ch.MessageType, ch.Provider, ch.Status})
+	}
+	return e.print(data, []string{"ID", "NAME", "TYPE", "PROVIDER", "STATUS"}, rows)
+}
+
+// cmdTemplates 列出模板
+func cmdTemplates(e *env, args []string) error {
+	fs := e.flags("templates")
+	channel := fs.String("channel", "", "filter by channel ID or alias")
+	status := fs.String("status", "", "filter by review status")
+	page := fs.Int("page", 1, "page number")
+	pageSize := fs.Int("page-size", 0, "page size (0 for the gateway default)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	opts := &mlievpush.ListTemplatesOptions{Status: *status, Page: *page, PageSize: *pageSize}
+	if *channel != "" {
+		if opts.ChannelID, err = channelID(client, *channel); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	data, err := client.ListTemplates(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(data.Items))
+	for _, t := range data.Items {
+		rows = append(rows, []string{strconv.Itoa(t.ID), strconv.Itoa(t.ChannelID), t.Name, t.Status, t.Content})
+	}
+	return e.print(data, []string{"ID", "CHANNEL", "NAME", "STATUS", "CONTENT"}, rows)
+}
+
+// print 按输出格式输出结果，json 输出完整响应数据，table 输出摘要列
+func (e *env) print(data interface{}, header []string, rows [][]string) error {
+	if e.output == "table" {
+		w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(data)
+}
@@ -0,0 +1,289 @@
+// Command mlievpush-loadtest 消息推送SDK压测工具
+//
+// 以固定RPS向目标网关（或内置的模拟服务器）发送消息，输出延迟分位数、错误分布和重试次数，
+// 用于大促前的容量评估。
+//
+// 用法：
+//
+//	mlievpush-loadtest -rps 200 -duration 1m                       # 使用内置模拟服务器
+//	mlievpush-loadtest -url https://push.example.com -rps 50 -channel 1 -receiver 13800138000
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// config 压测配置
+type config struct {
+	url           string
+	appID         string
+	appSecret     string
+	rps           int
+	duration      time.Duration
+	concurrency   int
+	timeout       time.Duration
+	maxAttempts   int
+	channelID     int
+	signatureName string
+	receiver      string
+	fakeLatency   time.Duration
+	fakeErrorRate float64
+}
+
+func main() {
+	cfg := parseFlags()
+	if err := cfg.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "mlievpush-loadtest: %v\n", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if cfg.url == "" {
+		server := newFakeServer(cfg.fakeLatency, cfg.fakeErrorRate)
+		defer server.Close()
+		cfg.url = server.URL
+		fmt.Printf("using built-in fake server at %s\n", server.URL)
+	}
+
+	result := run(cfg)
+	result.print(os.Stdout)
+}
+
+// parseFlags 解析命令行参数
+func parseFlags() *config {
+	cfg := &config{}
+	flag.StringVar(&cfg.url, "url", "", "gateway base URL (empty to use the built-in fake server)")
+	flag.StringVar(&cfg.appID, "app-id", envOr("MLIEV_PUSH_APP_ID", "loadtest_app"), "application ID")
+	flag.StringVar(&cfg.appSecret, "app-secret", envOr("MLIEV_PUSH_APP_SECRET", "loadtest_secret"), "application secret")
+	flag.IntVar(&cfg.rps, "rps", 100, "target requests per second")
+	flag.DurationVar(&cfg.duration, "duration", 30*time.Second, "test duration")
+	flag.IntVar(&cfg.concurrency, "concurrency", 50, "maximum in-flight requests")
+	flag.DurationVar(&cfg.timeout, "timeout", 10*time.Second, "per-request timeout")
+	flag.IntVar(&cfg.maxAttempts, "max-attempts", 1, "maximum attempts per send including retries (1 disables retry)")
+	flag.IntVar(&cfg.channelID, "channel", 1, "channel ID")
+	flag.StringVar(&cfg.signatureName, "signature", "【压测】", "signature name")
+	flag.StringVar(&cfg.receiver, "receiver", "13800138000", "receiver")
+	flag.DurationVar(&cfg.fakeLatency, "fake-latency", 20*time.Millisecond, "mean latency of the fake server")
+	flag.Float64Var(&cfg.fakeErrorRate, "fake-error-rate", 0.01, "error rate of the fake server (0-1)")
+	flag.Parse()
+	return cfg
+}
+
+// validate 校验压测参数，rps 上限为每纳秒一次（time.NewTicker 要求间隔大于0）
+func (c *config) validate() error {
+	switch {
+	case c.rps <= 0 || c.rps > int(time.Second):
+		return fmt.Errorf("-rps must be between 1 and %d, got %d", int(time.Second), c.rps)
+	case c.concurrency <= 0:
+		return fmt.Errorf("-concurrency must be positive, got %d", c.concurrency)
+	case c.duration <= 0:
+		return fmt.Errorf("-duration must be positive, got %s", c.duration)
+	}
+	return nil
+}
+
+// envOr 读取环境变量，未设置时返回默认值
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// result 压测结果
+type result struct {
+	mu        sync.Mutex
+	elapsed   time.Duration
+	latencies []time.Duration
+	errors    map[string]int
+	success   int
+	dropped   int64
+	attempts  int64
+}
+
+// run 执行压测
+func run(cfg *config) *result {
+	res := &result{errors: make(map[string]int)}
+	transport := &countingTransport{next: http.DefaultTransport, count: &res.attempts}
+	client := mlievpush.NewClient(cfg.url, cfg.appID, cfg.appSecret,
+		mlievpush.WithHTTPClient(&http.Client{Transport: transport, Timeout: cfg.timeout}),
+		mlievpush.WithRetry(cfg.maxAttempts, mlievpush.DefaultBackoff),
+	)
+
+	req := &mlievpush.SendMessageRequest{
+		ChannelID:      cfg.channelID,
+		SignatureName:  cfg.signatureName,
+		Receiver:       cfg.receiver,
+		TemplateParams: map[string]interface{}{"code": "123456"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.duration)
+	defer cancel()
+
+	jobs := make(chan struct{}, cfg.concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				start := time.Now()
+				_, err := client.SendMessage(context.Background(), req)
+				res.record(time.Since(start), err)
+			}
+		}()
+	}
+
+	interval := time.Second / time.Duration(cfg.rps)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+			select {
+			case jobs <- struct{}{}:
+			default:
+				// 所有worker都在忙，说明目标无法承载当前RPS
+				atomic.AddInt64(&res.dropped, 1)
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	res.elapsed = time.Since(start)
+
+	return res
+}
+
+// record 记录单次请求结果
+func (r *result) record(latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.latencies = append(r.latencies, latency)
+	if err == nil {
+		r.success++
+		return
+	}
+	r.errors[classifyError(err)]++
+}
+
+// classifyError 对错误分类
+func classifyError(err error) string {
+	var apiErr *mlievpush.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		return "api:" + strconv.Itoa(apiErr.Code)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		var netErr interface{ Timeout() bool }
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	}
+}
+
+// print 输出压测报告
+func (r *result) print(w io.Writer) {
+	total := len(r.latencies)
+	fmt.Fprintf(w, "\nrequests:  %d (%.1f req/s)\n", total, float64(total)/r.elapsed.Seconds())
+	fmt.Fprintf(w, "success:   %d\n", r.success)
+	fmt.Fprintf(w, "failed:    %d\n", total-r.success)
+	fmt.Fprintf(w, "dropped:   %d (concurrency saturated)\n", r.dropped)
+	fmt.Fprintf(w, "attempts:  %d (retries: %d)\n", r.attempts, r.attempts-int64(total))
+
+	if total > 0 {
+		sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+		fmt.Fprintf(w, "\nlatency:\n")
+		for _, p := range []float64{50, 90, 95, 99} {
+			fmt.Fprintf(w, "  p%-4v %v\n", p, percentile(r.latencies, p))
+		}
+		fmt.Fprintf(w, "  max   %v\n", r.latencies[total-1])
+	}
+
+	if len(r.errors) > 0 {
+		kinds := make([]string, 0, len(r.errors))
+		for k := range r.errors {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		fmt.Fprintf(w, "\nerrors:\n")
+		for _, k := range kinds {
+			fmt.Fprintf(w, "  %-12s %d\n", k, r.errors[k])
+		}
+	}
+}
+
+// percentile 计算已排序延迟的分位数
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted)-1) * p / 100)
+	return sorted[idx]
+}
+
+// countingTransport 统计实际HTTP请求次数（包含重试）
+type countingTransport struct {
+	next  http.RoundTripper
+	count *int64
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(t.count, 1)
+	return t.next.RoundTrip(req)
+}
+
+// newFakeServer 创建模拟网关，按配置的延迟和错误率返回响应
+func newFakeServer(latency time.Duration, errorRate float64) *httptest.Server {
+	var mu sync.Mutex
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		delay := time.Duration(rnd.ExpFloat64() * float64(latency))
+		fail := rnd.Float64() < errorRate
+		mu.Unlock()
+
+		time.Sleep(delay)
+
+		resp := map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data": map[string]interface{}{
+				"task_id":    fmt.Sprintf("%d", time.Now().UnixNano()),
+				"status":     mlievpush.TaskStatusPending,
+				"created_at": time.Now().Format(time.RFC3339),
+			},
+		}
+		if fail {
+			resp = map[string]interface{}{
+				"code":    mlievpush.ErrCodeProviderError,
+				"message": mlievpush.GetErrorMessage(mlievpush.ErrCodeProviderError),
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
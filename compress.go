@@ -0,0 +1,135 @@
+package mlievpush
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Compression 文件压缩格式，用于导出的报表和暂存文件（WithSpoolCompression）等本地文件
+// 内置 gzip；zstd 等其他格式可以实现该接口后通过 RegisterCompression 注册，SDK 本身不引入额外依赖
+type Compression interface {
+	Name() string                                  // 格式名称，如 "gzip"
+	Extension() string                             // 文件扩展名，如 ".gz"
+	NewWriter(w io.Writer) (io.WriteCloser, error) // 创建压缩写入器，Close 时写出剩余数据（不关闭 w）
+	NewReader(r io.Reader) (io.ReadCloser, error)  // 创建解压读取器
+}
+
+// Gzip 默认压缩级别的 gzip 压缩
+var Gzip Compression = NewGzipCompression(gzip.DefaultCompression)
+
+// NewGzipCompression 创建指定压缩级别的 gzip 压缩（gzip.BestSpeed 到 gzip.BestCompression）
+func NewGzipCompression(level int) Compression {
+	return gzipCompression{level: level}
+}
+
+// gzipCompression gzip 压缩
+type gzipCompression struct {
+	level int
+}
+
+// Name 实现 Compression 接口
+func (g gzipCompression) Name() string { return "gzip" }
+
+// Extension 实现 Compression 接口
+func (g gzipCompression) Extension() string { return ".gz" }
+
+// NewWriter 实现 Compression 接口
+func (g gzipCompression) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, g.level)
+}
+
+// NewReader 实现 Compression 接口
+func (g gzipCompression) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// compressions 已注册的压缩格式（扩展名 -> 格式）
+var (
+	compressionsMu sync.RWMutex
+	compressions   = map[string]Compression{".gz": Gzip}
+)
+
+// RegisterCompression 注册或覆盖压缩格式，之后按文件扩展名自动选择（见 CompressionForFile）
+func RegisterCompression(c Compression) {
+	compressionsMu.Lock()
+	defer compressionsMu.Unlock()
+	compressions[strings.ToLower(c.Extension())] = c
+}
+
+// CompressionForFile 按文件扩展名选择已注册的压缩格式（如 "report.csv.gz" 选择 gzip），未注册的扩展名返回nil
+func CompressionForFile(path string) Compression {
+	compressionsMu.RLock()
+	defer compressionsMu.RUnlock()
+	return compressions[strings.ToLower(filepath.Ext(path))]
+}
+
+// compressionByName 按格式名称查找已注册的压缩格式，未注册时返回nil
+func compressionByName(name string) Compression {
+	compressionsMu.RLock()
+	defer compressionsMu.RUnlock()
+	for _, c := range compressions {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// CreateFile 创建文件并按扩展名压缩写入，Close 时依次关闭压缩写入器和文件
+func CreateFile(path string) (io.WriteCloser, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create file: %w", err)
+	}
+	c := CompressionForFile(path)
+	if c == nil {
+		return file, nil
+	}
+	w, err := c.NewWriter(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("create %s writer: %w", c.Name(), err)
+	}
+	return &stackedCloser{Writer: w, closers: []io.Closer{w, file}}, nil
+}
+
+// OpenFile 打开文件并按扩展名解压读取，Close 时依次关闭解压读取器和文件
+func OpenFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	c := CompressionForFile(path)
+	if c == nil {
+		return file, nil
+	}
+	r, err := c.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("create %s reader: %w", c.Name(), err)
+	}
+	return &stackedCloser{Reader: r, closers: []io.Closer{r, file}}, nil
+}
+
+// stackedCloser 按顺序关闭多层读写器，返回第一个错误
+type stackedCloser struct {
+	io.Writer
+	io.Reader
+	closers []io.Closer
+}
+
+// Close 实现 io.Closer 接口
+func (s *stackedCloser) Close() error {
+	var first error
+	for _, c := range s.closers {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,87 @@
+package mlievpush
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// upperCompression 测试用的压缩格式：写入时转大写，读取时原样返回
+type upperCompression struct{}
+
+func (upperCompression) Name() string      { return "upper" }
+func (upperCompression) Extension() string { return ".up" }
+
+func (upperCompression) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{upperWriter{w}}, nil
+}
+
+func (upperCompression) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+type upperWriter struct{ w io.Writer }
+
+func (u upperWriter) Write(p []byte) (int, error) {
+	return u.w.Write(bytes.ToUpper(p))
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// TestWriteCSVFileGzip 测试按扩展名压缩导出
+func TestWriteCSVFileGzip(t *testing.T) {
+	report := &ReconciliationReport{BatchID: "b1"}
+	report.add(ReconciliationEntry{Receiver: "13800138000", TaskID: "t1", Outcome: OutcomeDelivered})
+
+	path := filepath.Join(t.TempDir(), "reconcile.csv.gz")
+	if err := report.WriteCSVFile(path); err != nil {
+		t.Fatalf("WriteCSVFile() error = %v", err)
+	}
+
+	raw, _ := os.ReadFile(path)
+	if _, err := gzip.NewReader(bytes.NewReader(raw)); err != nil {
+		t.Fatalf("expected gzip file: %v", err)
+	}
+
+	f, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil || len(records) != 2 || records[1][1] != "13800138000" {
+		t.Errorf("records = %v, err = %v", records, err)
+	}
+}
+
+// TestRegisterCompression 测试注册自定义压缩格式
+func TestRegisterCompression(t *testing.T) {
+	if CompressionForFile("report.csv") != nil {
+		t.Error("expected no compression for .csv")
+	}
+	if CompressionForFile("report.CSV.GZ") == nil {
+		t.Error("expected gzip for .GZ")
+	}
+
+	RegisterCompression(upperCompression{})
+	path := filepath.Join(t.TempDir(), "report.up")
+	w, err := CreateFile(path)
+	if err != nil {
+		t.Fatalf("CreateFile() error = %v", err)
+	}
+	io.WriteString(w, "hello")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	raw, _ := os.ReadFile(path)
+	if string(raw) != "HELLO" {
+		t.Errorf("file content = %q", raw)
+	}
+}
@@ -0,0 +1,240 @@
+package mlievpush
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config 客户端配置文件，一个文件可以包含多个命名配置（如 prod、staging 或按租户划分）
+//
+//	{
+//	  "default_profile": "prod",
+//	  "profiles": {
+//	    "prod": {
+//	      "base_url": "https://push.example.com",
+//	      "app_id": "your_app_id",
+//	      "app_secret_env": "PUSH_APP_SECRET",
+//	      "timeout": "5s",
+//	      "retry": {"max_attempts": 3, "initial": "200ms", "max": "5s"},
+//	      "rate_limit": {"per_second": 50, "burst": 10},
+//	      "channels": {"otp": {"id": 1, "type": "sms"}, "report": {"id": 3, "type": "email"}}
+//	    }
+//	  }
+//	}
+type Config struct {
+	DefaultProfile string                   `json:"default_profile,omitempty"` // 未指定配置名时使用的配置
+	Profiles       map[string]ProfileConfig `json:"profiles"`                  // 配置名 -> 配置
+}
+
+// ProfileConfig 单个命名配置
+type ProfileConfig struct {
+	BaseURL       string `json:"base_url"`                  // 基础URL
+	AppID         string `json:"app_id"`                    // 应用ID
+	AppSecret     string `json:"app_secret,omitempty"`      // 应用密钥（不推荐明文写入配置文件）
+	AppSecretEnv  string `json:"app_secret_env,omitempty"`  // 从环境变量读取应用密钥
+	AppSecretFile string `json:"app_secret_file,omitempty"` // 从文件读取应用密钥（如 Kubernetes Secret 挂载），首尾空白会被去除
+
+	Timeout   Duration                 `json:"timeout,omitempty"`    // 请求超时时间
+	Proxy     string                   `json:"proxy,omitempty"`      // HTTP代理地址（如 "http://proxy.internal:3128"），为空时使用 HTTP_PROXY 等环境变量
+	Retry     *RetryConfig             `json:"retry,omitempty"`      // 自动重试
+	RateLimit *RateLimitConfig         `json:"rate_limit,omitempty"` // 客户端限流
+	Quota     *QuotaConfig             `json:"quota,omitempty"`      // 账号发送配额（仅 ClientManager 使用）
+	Channels  map[string]ChannelConfig `json:"channels,omitempty"`   // 通道别名 -> 通道配置
+
+	Environment       string             `json:"environment,omitempty"`        // 运行环境，见 Environment* 常量
+	StagingGuardrails *StagingGuardrails `json:"staging_guardrails,omitempty"` // 预发环境防护规则
+
+	LogLevel LogLevel `json:"log_level,omitempty"` // 日志详细级别（"error"、"warn"、"info"、"debug"、"trace"），需配合 WithLogger 使用
+}
+
+// RetryConfig 重试配置，未设置的退避参数使用 DefaultBackoff
+type RetryConfig struct {
+	MaxAttempts int      `json:"max_attempts"`         // 总尝试次数（含首次）
+	Initial     Duration `json:"initial,omitempty"`    // 首次重试前的等待时间
+	Max         Duration `json:"max,omitempty"`        // 单次等待时间上限
+	Multiplier  float64  `json:"multiplier,omitempty"` // 等待时间增长倍数
+	Jitter      float64  `json:"jitter,omitempty"`     // 随机抖动比例
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	PerSecond float64 `json:"per_second"`      // 每秒请求数
+	Burst     int     `json:"burst,omitempty"` // 突发请求数
+}
+
+// QuotaConfig 账号配额配置，见 AccountQuota
+type QuotaConfig struct {
+	Limit  int      `json:"limit"`            // 窗口内最多发送的消息数
+	Period Duration `json:"period,omitempty"` // 计数窗口，默认24小时
+}
+
+// ChannelConfig 通道配置
+type ChannelConfig struct {
+	ID   int    `json:"id"`             // 通道ID
+	Type string `json:"type,omitempty"` // 消息类型（可选），用于本地校验接收者格式，见 MessageType* 常量
+}
+
+// Duration 配置文件中的时间长度，使用 time.ParseDuration 格式（如 "5s"、"200ms"）
+type Duration time.Duration
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// LoadConfig 读取配置文件
+// 配置文件为 JSON 格式；SDK 不依赖 YAML 解析库，.yaml/.yml 文件需使用 JSON 兼容的写法（JSON 是 YAML 的子集）
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+			return nil, fmt.Errorf("parse config %s: only JSON-compatible YAML is supported: %w", path, err)
+		}
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Profile 获取命名配置，name 为空时使用 DefaultProfile；只有一个配置时可省略
+func (c *Config) Profile(name string) (*ProfileConfig, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" && len(c.Profiles) == 1 {
+		for _, p := range c.Profiles {
+			return &p, nil
+		}
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in config", name)
+	}
+	return &p, nil
+}
+
+// secret 解析应用密钥
+func (p *ProfileConfig) secret() (string, error) {
+	switch {
+	case p.AppSecretEnv != "":
+		secret, ok := os.LookupEnv(p.AppSecretEnv)
+		if !ok || secret == "" {
+			return "", fmt.Errorf("environment variable %s is not set", p.AppSecretEnv)
+		}
+		return secret, nil
+	case p.AppSecretFile != "":
+		data, err := os.ReadFile(p.AppSecretFile)
+		if err != nil {
+			return "", fmt.Errorf("read app secret file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case p.AppSecret != "":
+		return p.AppSecret, nil
+	default:
+		return "", errors.New("app secret is not configured")
+	}
+}
+
+// Options 将配置转换为客户端配置选项
+func (p *ProfileConfig) Options() []ClientOption {
+	var opts []ClientOption
+	if p.Timeout > 0 {
+		opts = append(opts, WithTimeout(time.Duration(p.Timeout)))
+	}
+	if proxy, err := url.Parse(p.Proxy); p.Proxy != "" && err == nil {
+		opts = append(opts, WithProxy(proxy))
+	}
+	if r := p.Retry; r != nil {
+		backoff := DefaultBackoff
+		if r.Initial > 0 {
+			backoff.Initial = time.Duration(r.Initial)
+		}
+		if r.Max > 0 {
+			backoff.Max = time.Duration(r.Max)
+		}
+		if r.Multiplier > 0 {
+			backoff.Multiplier = r.Multiplier
+		}
+		if r.Jitter > 0 {
+			backoff.Jitter = r.Jitter
+		}
+		opts = append(opts, WithRetry(r.MaxAttempts, backoff))
+	}
+	if r := p.RateLimit; r != nil {
+		opts = append(opts, WithRateLimit(r.PerSecond, r.Burst))
+	}
+	if p.Environment != "" {
+		opts = append(opts, WithEnvironment(p.Environment))
+	}
+	if p.StagingGuardrails != nil {
+		opts = append(opts, WithStagingGuardrails(*p.StagingGuardrails))
+	}
+	if p.LogLevel != 0 {
+		opts = append(opts, WithLogLevel(p.LogLevel))
+	}
+	for name, ch := range p.Channels {
+		opts = append(opts, WithChannelAlias(name, ch.ID))
+		if ch.Type != "" {
+			opts = append(opts, WithChannelType(ch.ID, ch.Type))
+		}
+	}
+	return opts
+}
+
+// NewClient 根据配置创建客户端，opts 在配置之后应用，可覆盖配置文件中的设置
+func (p *ProfileConfig) NewClient(opts ...ClientOption) (*Client, error) {
+	if p.BaseURL == "" || p.AppID == "" {
+		return nil, errors.New("base_url and app_id are required")
+	}
+	if p.Proxy != "" {
+		if _, err := url.Parse(p.Proxy); err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+	}
+	secret, err := p.secret()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(p.BaseURL, p.AppID, secret, append(p.Options(), opts...)...), nil
+}
+
+// NewClientFromConfig 读取配置文件并使用指定的命名配置创建客户端，profile 为空时使用默认配置
+func NewClientFromConfig(path, profile string, opts ...ClientOption) (*Client, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := cfg.Profile(profile)
+	if err != nil {
+		return nil, err
+	}
+	client, err := p.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", profile, err)
+	}
+	return client, nil
+}
@@ -0,0 +1,87 @@
+package mlievpush
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testConfig = `{
+  "default_profile": "prod",
+  "profiles": {
+    "prod": {
+      "base_url": "https://push.example.com",
+      "app_id": "prod_app",
+      "app_secret_env": "MLIEV_PUSH_TEST_SECRET",
+      "timeout": "3s",
+      "retry": {"max_attempts": 3, "initial": "100ms"},
+      "rate_limit": {"per_second": 20, "burst": 5},
+      "channels": {"otp": {"id": 7, "type": "sms"}}
+    },
+    "staging": {
+      "base_url": "https://push-staging.example.com",
+      "app_id": "staging_app",
+      "app_secret": "staging_secret"
+    }
+  }
+}`
+
+// writeTestConfig 写入测试配置文件
+func writeTestConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestNewClientFromConfig 测试从配置文件创建客户端
+func TestNewClientFromConfig(t *testing.T) {
+	path := writeTestConfig(t, "push.json", testConfig)
+	t.Setenv("MLIEV_PUSH_TEST_SECRET", "prod_secret")
+
+	client, err := NewClientFromConfig(path, "")
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() error = %v", err)
+	}
+	if client.appID != "prod_app" || client.appSecret != "prod_secret" || client.httpClient.Timeout != 3*time.Second {
+		t.Errorf("unexpected client: app=%s timeout=%v", client.appID, client.httpClient.Timeout)
+	}
+	if client.maxAttempts != 3 || client.backoff.Initial != 100*time.Millisecond || client.backoff.Max != DefaultBackoff.Max {
+		t.Errorf("unexpected retry: %d %+v", client.maxAttempts, client.backoff)
+	}
+	if client.limiter == nil || client.limiter.rate != 20 {
+		t.Error("rate limit not applied")
+	}
+	if id, err := client.Channel("otp"); err != nil || id != 7 || client.channelTypes[7] != MessageTypeSMS {
+		t.Errorf("Channel(otp) = %d, %v", id, err)
+	}
+	if _, err := client.Channel("unknown"); err == nil {
+		t.Error("expected unknown alias error")
+	}
+
+	staging, err := NewClientFromConfig(path, "staging", WithTimeout(time.Second))
+	if err != nil || staging.appSecret != "staging_secret" || staging.httpClient.Timeout != time.Second {
+		t.Errorf("staging profile: %v", err)
+	}
+
+	if _, err := NewClientFromConfig(path, "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected missing profile error, got %v", err)
+	}
+}
+
+// TestConfigSecretErrors 测试密钥缺失
+func TestConfigSecretErrors(t *testing.T) {
+	path := writeTestConfig(t, "push.yaml", testConfig)
+	if _, err := NewClientFromConfig(path, "prod"); err == nil || !strings.Contains(err.Error(), "MLIEV_PUSH_TEST_SECRET") {
+		t.Errorf("expected env error, got %v", err)
+	}
+
+	yaml := writeTestConfig(t, "push.yml", "profiles:\n  prod:\n    app_id: x\n")
+	if _, err := LoadConfig(yaml); err == nil || !strings.Contains(err.Error(), "JSON-compatible YAML") {
+		t.Errorf("expected yaml error, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package mlievpush
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ContentLimit 消息内容长度限制，0表示不限制
+type ContentLimit struct {
+	MaxBytes       int `json:"max_bytes,omitempty"`        // 最大字节数（UTF-8）
+	MaxChars       int `json:"max_chars,omitempty"`        // 最大字符数
+	MaxSMSSegments int `json:"max_sms_segments,omitempty"` // 短信最大拆分条数
+}
+
+// DefaultContentLimits 各消息类型的默认内容长度限制
+var DefaultContentLimits = map[string]ContentLimit{
+	MessageTypeSMS:        {MaxChars: 500},
+	MessageTypeDingtalk:   {MaxBytes: 20000},
+	MessageTypeWechatWork: {MaxBytes: 2048},
+	MessageTypeEmail:      {MaxBytes: DefaultMaxAttachmentSize},
+}
+
+// ContentLengthError 消息内容超出长度限制（本地校验，不会发送请求）
+type ContentLengthError struct {
+	MessageType string // 消息类型
+	Unit        string // 计量单位：bytes、chars、segments
+	Length      int    // 实际长度（估算值）
+	Limit       int    // 限制
+}
+
+// Error 实现 error 接口
+func (e *ContentLengthError) Error() string {
+	return fmt.Sprintf("%s content too long: %d %s exceeds limit of %d", e.MessageType, e.Length, e.Unit, e.Limit)
+}
+
+// WithContentLimit 设置指定消息类型的内容长度限制，覆盖默认值
+func WithContentLimit(messageType string, limit ContentLimit) ClientOption {
+	return func(c *Client) {
+		c.contentLimits[messageType] = limit
+	}
+}
+
+// WithChannelTemplate 声明通道使用的模板内容，用于在本地渲染后估算内容长度
+// 模板占位符格式为 ${name}
+func WithChannelTemplate(channelID int, template string) ClientOption {
+	return func(c *Client) {
+		c.channelTemplates[channelID] = template
+	}
+}
+
+// Check 校验内容是否超出限制，返回 *ContentLengthError
+func (l ContentLimit) Check(messageType, content string) error {
+	if l.MaxBytes > 0 && len(content) > l.MaxBytes {
+		return &ContentLengthError{MessageType: messageType, Unit: "bytes", Length: len(content), Limit: l.MaxBytes}
+	}
+	if chars := utf8.RuneCountInString(content); l.MaxChars > 0 && chars > l.MaxChars {
+		return &ContentLengthError{MessageType: messageType, Unit: "chars", Length: chars, Limit: l.MaxChars}
+	}
+	if segments := SMSSegments(content); l.MaxSMSSegments > 0 && segments > l.MaxSMSSegments {
+		return &ContentLengthError{MessageType: messageType, Unit: "segments", Length: segments, Limit: l.MaxSMSSegments}
+	}
+	return nil
+}
+
+// templatePlaceholder 模板占位符 ${name}
+var templatePlaceholder = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// RenderTemplate 使用模板参数渲染模板，缺失的参数保留原占位符
+func RenderTemplate(template string, params map[string]interface{}) string {
+	return templatePlaceholder.ReplaceAllStringFunc(template, func(m string) string {
+		name := m[2 : len(m)-1]
+		if v, ok := params[name]; ok {
+			return paramString(v)
+		}
+		return m
+	})
+}
+
+// SMSSegments 估算短信拆分条数
+// 纯GSM字符单条160字符、长短信每条153字符；含中文等字符时单条70字符、长短信每条67字符
+func SMSSegments(content string) int {
+	chars := utf8.RuneCountInString(content)
+	if chars == 0 {
+		return 0
+	}
+
+	single, multi := 160, 153
+	if !isGSMText(content) {
+		single, multi = 70, 67
+	}
+	if chars <= single {
+		return 1
+	}
+	return (chars + multi - 1) / multi
+}
+
+// isGSMText 判断内容是否只包含GSM 7-bit基本字符（近似为可打印ASCII）
+func isGSMText(content string) bool {
+	for _, r := range content {
+		if r > 0x7e || (r < 0x20 && r != '\n' && r != '\r') {
+			return false
+		}
+	}
+	return true
+}
+
+// estimateContent 估算渲染后的消息内容
+// 已声明通道模板时渲染模板，否则以模板参数值的拼接作为下限估算；短信内容包含签名
+func (c *Client) estimateContent(channelID int, messageType, signatureName string, params map[string]interface{}) string {
+	var content string
+	if template, ok := c.channelTemplates[channelID]; ok {
+		content = RenderTemplate(template, params)
+	} else {
+		keys := make([]string, 0, len(params))
+		for k := range params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var sb strings.Builder
+		for _, k := range keys {
+			sb.WriteString(paramString(params[k]))
+		}
+		content = sb.String()
+	}
+
+	if messageType == MessageTypeSMS {
+		content = signatureName + content
+	}
+	return content
+}
+
+// checkContentLength 按通道消息类型校验内容长度，类型未知的通道不做校验
+func (c *Client) checkContentLength(channelID int, signatureName string, params map[string]interface{}) error {
+	messageType, ok := c.channelType(channelID)
+	if !ok {
+		return nil
+	}
+
+	limit, ok := c.contentLimits[messageType]
+	if !ok {
+		limit, ok = DefaultContentLimits[messageType]
+	}
+	if !ok {
+		return nil
+	}
+
+	content := c.estimateContent(channelID, messageType, signatureName, params)
+	return limit.Check(messageType, content)
+}
@@ -0,0 +1,87 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestSMSSegments 测试短信条数估算
+func TestSMSSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"空内容", "", 0},
+		{"英文单条", strings.Repeat("a", 160), 1},
+		{"英文长短信", strings.Repeat("a", 161), 2},
+		{"中文单条", strings.Repeat("中", 70), 1},
+		{"中文长短信", strings.Repeat("中", 71), 2},
+		{"中文三条", strings.Repeat("中", 135), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SMSSegments(tt.content); got != tt.want {
+				t.Errorf("SMSSegments() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRenderTemplate 测试模板渲染
+func TestRenderTemplate(t *testing.T) {
+	got := RenderTemplate("您的验证码是${code}，${minutes}分钟内有效。${missing}", map[string]interface{}{
+		"code":    "123456",
+		"minutes": 5,
+	})
+	want := "您的验证码是123456，5分钟内有效。${missing}"
+	if got != want {
+		t.Errorf("RenderTemplate() = %q, want %q", got, want)
+	}
+}
+
+// TestContentLimitCheck 测试内容长度校验
+func TestContentLimitCheck(t *testing.T) {
+	limit := ContentLimit{MaxBytes: 10}
+	if err := limit.Check(MessageTypeDingtalk, "12345"); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+
+	err := limit.Check(MessageTypeDingtalk, "中文中文")
+	var lenErr *ContentLengthError
+	if !errors.As(err, &lenErr) || lenErr.Unit != "bytes" || lenErr.Length != 12 {
+		t.Errorf("expected bytes ContentLengthError, got %v", err)
+	}
+
+	limit = ContentLimit{MaxSMSSegments: 1}
+	if err := limit.Check(MessageTypeSMS, strings.Repeat("中", 71)); !errors.As(err, &lenErr) || lenErr.Unit != "segments" {
+		t.Errorf("expected segments ContentLengthError, got %v", err)
+	}
+}
+
+// TestSendMessageContentTooLong 测试渲染后内容超长时本地失败
+func TestSendMessageContentTooLong(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1", "test_app_id", "test_secret",
+		WithChannelType(1, MessageTypeSMS),
+		WithChannelTemplate(1, "通知：${content}"),
+		WithContentLimit(MessageTypeSMS, ContentLimit{MaxSMSSegments: 2}),
+	)
+
+	_, err := client.SendMessage(context.Background(), &SendMessageRequest{
+		ChannelID:      1,
+		SignatureName:  "【测试签名】",
+		Receiver:       "13800138000",
+		TemplateParams: map[string]interface{}{"content": strings.Repeat("中", 130)},
+	})
+
+	var lenErr *ContentLengthError
+	if !errors.As(err, &lenErr) {
+		t.Fatalf("expected ContentLengthError, got %v", err)
+	}
+	if lenErr.Length != 3 {
+		t.Errorf("segments = %d, want 3", lenErr.Length)
+	}
+}
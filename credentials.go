@@ -0,0 +1,128 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Credentials 应用凭证
+type Credentials struct {
+	AppID     string // 应用ID
+	AppSecret string // 应用密钥
+}
+
+// CredentialsProvider 凭证提供者，每次请求尝试签名前调用，可从 Vault、KMS 等密钥服务获取凭证，实现不停机轮换密钥
+// 实现需并发安全；每次请求都访问远端服务时可用 NewCachedCredentials 包装
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsProviderFunc 函数形式的凭证提供者
+type CredentialsProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials 实现 CredentialsProvider 接口
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// CredentialsError 凭证提供者返回错误或空凭证，请求未发送，不会自动重试
+type CredentialsError struct {
+	Err error // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *CredentialsError) Error() string {
+	return fmt.Sprintf("fetch credentials: %v", e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *CredentialsError) Unwrap() error {
+	return e.Err
+}
+
+// WithCredentialsProvider 设置凭证提供者，设置后 NewClient 传入的应用ID和密钥不再使用
+// 单次调用的 WithCredentials 优先于凭证提供者
+func WithCredentialsProvider(provider CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.credentialsProvider = provider
+	}
+}
+
+// SetCredentials 运行时替换应用ID和密钥，并发安全，之后发出的请求（包括重试）立即使用新凭证
+// 已设置 WithCredentialsProvider 时以凭证提供者为准
+func (c *Client) SetCredentials(appID, appSecret string) {
+	c.credMu.Lock()
+	defer c.credMu.Unlock()
+	c.appID, c.appSecret = appID, appSecret
+}
+
+// staticCredentials 返回 NewClient 或 SetCredentials 设置的应用ID和密钥
+func (c *Client) staticCredentials() (appID, appSecret string) {
+	c.credMu.RLock()
+	defer c.credMu.RUnlock()
+	return c.appID, c.appSecret
+}
+
+// credentials 返回本次调用使用的应用ID和密钥：单次调用凭证 > 凭证提供者 > 客户端凭证
+func (c *Client) credentials(ctx context.Context) (appID, appSecret string, err error) {
+	if o := callOptionsFrom(ctx); o != nil && o.appID != "" {
+		return o.appID, o.appSecret, nil
+	}
+	if c.credentialsProvider == nil {
+		appID, appSecret = c.staticCredentials()
+		return appID, appSecret, nil
+	}
+	creds, err := c.credentialsProvider.Credentials(ctx)
+	if err != nil {
+		return "", "", &CredentialsError{Err: err}
+	}
+	if creds.AppID == "" || creds.AppSecret == "" {
+		return "", "", &CredentialsError{Err: errors.New("empty app id or secret")}
+	}
+	return creds.AppID, creds.AppSecret, nil
+}
+
+// CachedCredentials 缓存凭证提供者的结果，过期后在下一次请求时刷新
+// 刷新失败时继续使用上一次获取的凭证（避免密钥服务短暂不可用导致发送中断），从未获取成功时返回错误
+type CachedCredentials struct {
+	provider CredentialsProvider
+	ttl      time.Duration
+
+	mu        sync.Mutex
+	creds     Credentials
+	fetchedAt time.Time
+	valid     bool
+}
+
+// NewCachedCredentials 创建带缓存的凭证提供者，ttl 小于等于0时每次请求都刷新
+func NewCachedCredentials(provider CredentialsProvider, ttl time.Duration) *CachedCredentials {
+	return &CachedCredentials{provider: provider, ttl: ttl}
+}
+
+// Credentials 实现 CredentialsProvider 接口
+func (c *CachedCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.valid && time.Since(c.fetchedAt) < c.ttl {
+		return c.creds, nil
+	}
+	creds, err := c.provider.Credentials(ctx)
+	if err != nil {
+		if c.valid {
+			return c.creds, nil
+		}
+		return Credentials{}, err
+	}
+	c.creds, c.fetchedAt, c.valid = creds, time.Now(), true
+	return creds, nil
+}
+
+// Invalidate 使缓存失效，下一次请求重新获取凭证，如收到凭证轮换通知或 ErrUnauthorized 后调用
+func (c *CachedCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetchedAt = time.Time{}
+}
@@ -0,0 +1,159 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRotatingServer 创建按当前密钥校验签名的测试服务器，返回设置密钥的函数
+func newRotatingServer(t *testing.T, secret string) (*httptest.Server, func(string)) {
+	t.Helper()
+	var mu sync.Mutex
+	verifier := NewVerifier(func(ctx context.Context, appID string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return secret, nil
+	})
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	})))
+	t.Cleanup(server.Close)
+	return server, func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		secret = s
+	}
+}
+
+// TestSetCredentials 测试运行时替换密钥
+func TestSetCredentials(t *testing.T) {
+	server, rotate := newRotatingServer(t, "old_secret")
+	client := NewClient(server.URL, "test_app_id", "old_secret")
+	ctx := context.Background()
+
+	if _, err := client.QueryTask(ctx, "t1"); err != nil {
+		t.Fatalf("QueryTask() error = %v", err)
+	}
+	rotate("new_secret")
+	if _, err := client.QueryTask(ctx, "t1"); err == nil {
+		t.Fatal("old secret should be rejected after rotation")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.SetCredentials("test_app_id", "new_secret")
+			client.Snapshot()
+		}()
+	}
+	wg.Wait()
+	if _, err := client.QueryTask(ctx, "t1"); err != nil {
+		t.Fatalf("QueryTask() after SetCredentials error = %v", err)
+	}
+	if got := client.Snapshot().AppSecret; got == "new_secret" || got == "" {
+		t.Errorf("Snapshot().AppSecret = %q, want masked", got)
+	}
+}
+
+// TestCredentialsProvider 测试每次请求从凭证提供者获取凭证
+func TestCredentialsProvider(t *testing.T) {
+	server, rotate := newRotatingServer(t, "v1")
+	var current atomic.Value
+	current.Store("v1")
+	var calls atomic.Int32
+	provider := CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		calls.Add(1)
+		return Credentials{AppID: "vault_app", AppSecret: current.Load().(string)}, nil
+	})
+	client := NewClient(server.URL, "", "", WithCredentialsProvider(provider))
+	ctx := context.Background()
+
+	if _, err := client.QueryTask(ctx, "t1"); err != nil {
+		t.Fatalf("QueryTask() error = %v", err)
+	}
+	rotate("v2")
+	current.Store("v2")
+	if _, err := client.QueryTask(ctx, "t1"); err != nil {
+		t.Fatalf("QueryTask() after rotation error = %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("provider calls = %d, want 2", calls.Load())
+	}
+
+	// 单次调用凭证优先
+	if _, err := client.QueryTask(ctx, "t1", WithCredentials("tenant_app", "v2")); err != nil || calls.Load() != 2 {
+		t.Errorf("QueryTask(WithCredentials) error = %v, provider calls = %d", err, calls.Load())
+	}
+}
+
+// TestCredentialsProviderError 测试凭证提供者失败时不发送请求也不重试
+func TestCredentialsProviderError(t *testing.T) {
+	server, requests := newFlakyServer(t, 0, nil)
+	var calls atomic.Int32
+	vaultDown := errors.New("vault sealed")
+	client := NewClient(server.URL, "", "",
+		WithRetry(3, Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1}),
+		WithCredentialsProvider(CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+			calls.Add(1)
+			return Credentials{}, vaultDown
+		})))
+
+	_, err := client.QueryTask(context.Background(), "t1")
+	var credErr *CredentialsError
+	if !errors.As(err, &credErr) || !errors.Is(err, vaultDown) {
+		t.Fatalf("QueryTask() error = %v, want *CredentialsError", err)
+	}
+	if calls.Load() != 1 || requests.Load() != 0 {
+		t.Errorf("provider calls = %d, requests = %d, want 1 call and no request", calls.Load(), requests.Load())
+	}
+}
+
+// TestCachedCredentials 测试凭证缓存、失效和刷新失败时使用旧凭证
+func TestCachedCredentials(t *testing.T) {
+	var calls atomic.Int32
+	var fail atomic.Bool
+	cached := NewCachedCredentials(CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		if fail.Load() {
+			return Credentials{}, errors.New("vault unavailable")
+		}
+		n := calls.Add(1)
+		return Credentials{AppID: "app", AppSecret: fmt.Sprintf("secret%d", n)}, nil
+	}), time.Hour)
+	ctx := context.Background()
+
+	first, err := cached.Credentials(ctx)
+	if err != nil || first.AppSecret != "secret1" {
+		t.Fatalf("Credentials() = %+v, %v", first, err)
+	}
+	if again, _ := cached.Credentials(ctx); again != first || calls.Load() != 1 {
+		t.Errorf("cached Credentials() = %+v, calls = %d", again, calls.Load())
+	}
+
+	cached.Invalidate()
+	if next, _ := cached.Credentials(ctx); next.AppSecret != "secret2" {
+		t.Errorf("Credentials() after Invalidate = %+v", next)
+	}
+
+	fail.Store(true)
+	cached.Invalidate()
+	if stale, err := cached.Credentials(ctx); err != nil || stale.AppSecret != "secret2" {
+		t.Errorf("Credentials() on refresh failure = %+v, %v, want stale", stale, err)
+	}
+	empty := NewCachedCredentials(CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{}, errors.New("vault unavailable")
+	}), time.Hour)
+	if _, err := empty.Credentials(ctx); err == nil {
+		t.Error("Credentials() without cached value should fail")
+	}
+}
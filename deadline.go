@@ -0,0 +1,31 @@
+package mlievpush
+
+import (
+	"context"
+	"time"
+)
+
+// WithDeadlineFromContext 未显式设置 DeadlineAt 时使用context的截止时间作为投递截止时间
+// 适用于验证码等时效性消息：context超时即意味着消息已无意义，网关可以直接丢弃而不是延迟送达
+func WithDeadlineFromContext() ClientOption {
+	return func(c *Client) {
+		c.deadlineFromContext = true
+	}
+}
+
+// FormatDeadline 将时间格式化为 DeadlineAt 使用的 ISO 8601 格式
+func FormatDeadline(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// contextDeadline 获取context截止时间对应的 DeadlineAt，未开启或无截止时间时返回空字符串
+func (c *Client) contextDeadline(ctx context.Context) string {
+	if !c.deadlineFromContext {
+		return ""
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ""
+	}
+	return FormatDeadline(deadline)
+}
@@ -0,0 +1,50 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestDeadlineAt 测试投递截止时间的传递
+func TestDeadlineAt(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "data": map[string]interface{}{}})
+	}))
+	defer server.Close()
+
+	deadline := time.Now().Add(2 * time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	// 默认不从context推导
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	client.SendMessage(ctx, &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"})
+	if _, ok := got["deadline_at"]; ok {
+		t.Errorf("deadline_at should be omitted by default, got %v", got["deadline_at"])
+	}
+
+	// 从context推导
+	client = NewClient(server.URL, "test_app_id", "test_secret", WithDeadlineFromContext())
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}
+	client.SendMessage(ctx, req)
+	if got["deadline_at"] != FormatDeadline(deadline) {
+		t.Errorf("deadline_at = %v, want %v", got["deadline_at"], FormatDeadline(deadline))
+	}
+	if req.DeadlineAt != "" {
+		t.Error("caller's request should not be modified")
+	}
+
+	// 显式设置优先
+	explicit := FormatDeadline(deadline.Add(time.Hour))
+	client.SendBatch(ctx, &SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000"}, DeadlineAt: explicit})
+	if got["deadline_at"] != explicit {
+		t.Errorf("deadline_at = %v, want %v", got["deadline_at"], explicit)
+	}
+}
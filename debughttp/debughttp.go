@@ -0,0 +1,151 @@
+// Package debughttp 暴露SDK内部状态的调试HTTP接口
+//
+// 提供客户端统计、最近错误（已脱敏）、配置（密钥已掩码）等只读接口，
+// 以及供熔断器、发送队列等组件注册的扩展分区。仅应挂载在内网管理端口上。
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/debug/push/", http.StripPrefix("/debug/push", debughttp.NewHandler(client)))
+//	go http.ListenAndServe("127.0.0.1:6060", mux)
+package debughttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// SectionFunc 扩展分区数据提供函数，返回值会被序列化为JSON
+type SectionFunc func() interface{}
+
+// Handler 调试接口处理器
+type Handler struct {
+	client *mlievpush.Client
+	mux    *http.ServeMux
+
+	mu       sync.RWMutex
+	sections map[string]SectionFunc
+}
+
+// Option 调试接口配置选项
+type Option func(*Handler)
+
+// WithSection 注册扩展分区，如熔断器状态、队列深度，可通过 /sections/{name} 访问
+func WithSection(name string, fn SectionFunc) Option {
+	return func(h *Handler) {
+		h.sections[name] = fn
+	}
+}
+
+// NewHandler 创建调试接口处理器
+//
+// 路由：
+//
+//	/          汇总所有信息
+//	/healthz   存活检查
+//	/stats     客户端统计
+//	/errors    最近错误
+//	/config    客户端配置
+//	/sections/ 扩展分区
+func NewHandler(client *mlievpush.Client, opts ...Option) *Handler {
+	h := &Handler{
+		client:   client,
+		mux:      http.NewServeMux(),
+		sections: make(map[string]SectionFunc),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("/healthz", h.serveHealth)
+	h.mux.HandleFunc("/stats", h.serveJSON(func() interface{} { return client.Stats() }))
+	h.mux.HandleFunc("/errors", h.serveJSON(func() interface{} { return client.RecentErrors() }))
+	h.mux.HandleFunc("/config", h.serveJSON(func() interface{} { return client.Snapshot() }))
+	h.mux.HandleFunc("/sections/", h.serveSection)
+	h.mux.HandleFunc("/", h.serveJSON(h.summary))
+
+	return h
+}
+
+// Register 在运行时注册扩展分区
+func (h *Handler) Register(name string, fn SectionFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sections[name] = fn
+}
+
+// ServeHTTP 实现 http.Handler 接口
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// summary 汇总所有信息
+func (h *Handler) summary() interface{} {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	sections := make(map[string]interface{}, len(h.sections))
+	for name, fn := range h.sections {
+		sections[name] = fn()
+	}
+
+	return map[string]interface{}{
+		"stats":    h.client.Stats(),
+		"errors":   h.client.RecentErrors(),
+		"config":   h.client.Snapshot(),
+		"sections": sections,
+	}
+}
+
+// serveHealth 存活检查
+func (h *Handler) serveHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("ok\n"))
+}
+
+// serveSection 输出单个扩展分区，名称为空时列出所有分区
+func (h *Handler) serveSection(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/sections/")
+
+	h.mu.RLock()
+	fn, ok := h.sections[name]
+	names := make([]string, 0, len(h.sections))
+	for n := range h.sections {
+		names = append(names, n)
+	}
+	h.mu.RUnlock()
+
+	if name == "" {
+		sort.Strings(names)
+		writeJSON(w, names)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, fn())
+}
+
+// serveJSON 将数据提供函数包装为JSON处理函数
+func (h *Handler) serveJSON(fn func() interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fn())
+	}
+}
+
+// writeJSON 输出格式化的JSON
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -0,0 +1,62 @@
+package debughttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// TestHandler 测试调试接口
+func TestHandler(t *testing.T) {
+	client := mlievpush.NewClient("https://push.example.com", "test_app_id", "secret123456789")
+	h := NewHandler(client, WithSection("queue", func() interface{} {
+		return map[string]int{"depth": 3}
+	}))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if w := get("/healthz"); w.Code != http.StatusOK {
+		t.Errorf("/healthz status = %d", w.Code)
+	}
+
+	w := get("/config")
+	if strings.Contains(w.Body.String(), "secret123456789") {
+		t.Error("/config leaks app secret")
+	}
+
+	var stats mlievpush.Stats
+	if err := json.Unmarshal(get("/stats").Body.Bytes(), &stats); err != nil {
+		t.Errorf("/stats: %v", err)
+	}
+
+	var queue map[string]int
+	json.Unmarshal(get("/sections/queue").Body.Bytes(), &queue)
+	if queue["depth"] != 3 {
+		t.Errorf("/sections/queue = %v", queue)
+	}
+	if w := get("/sections/missing"); w.Code != http.StatusNotFound {
+		t.Errorf("/sections/missing status = %d", w.Code)
+	}
+
+	var summary map[string]json.RawMessage
+	json.Unmarshal(get("/").Body.Bytes(), &summary)
+	for _, key := range []string{"stats", "errors", "config", "sections"} {
+		if _, ok := summary[key]; !ok {
+			t.Errorf("summary missing %q", key)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/stats", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d", w.Code)
+	}
+}
@@ -0,0 +1,304 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 降级策略默认配置
+const (
+	DefaultDrainInterval = 5 * time.Second // 暂存消息的默认重发检查间隔
+	DefaultDrainBatch    = 100             // 每轮最多重发的默认消息数
+)
+
+// 发送结果状态
+const (
+	SendStatusSent             = "sent"               // 已提交到网关
+	SendStatusAcceptedForLater = "accepted_for_later" // 网关不可用，消息已暂存，恢复后自动重发
+)
+
+// ErrSpoolExpired 暂存消息超过所属分类的 MaxAge，不再重发
+var ErrSpoolExpired = errors.New("mlievpush: spooled message expired")
+
+// ErrSpoolCredentials 使用单次调用凭证（WithCredentials）的消息不会暂存，应用密钥不写入暂存文件，重发时也无法还原
+var ErrSpoolCredentials = errors.New("mlievpush: messages sent with per-call credentials cannot be spooled")
+
+// DegradationPolicy 网关故障时的降级策略，配合 WithCircuitBreaker 使用
+type DegradationPolicy struct {
+	Spool         Spool             // 暂存（必填），如 NewFileSpool
+	DrainInterval time.Duration     // RunSpoolDrainer 的检查间隔，0 使用 DefaultDrainInterval
+	DrainBatch    int               // 每轮最多重发的消息数，0 使用 DefaultDrainBatch
+	OnDrain       func(DrainResult) // 每条暂存消息的重发结果回调（可选），读取暂存失败时 Message 为nil
+
+	// Categorize 暂存时确定消息分类（可选），如按模板或通道区分验证码、账单通知，分类随消息一同保存
+	Categorize func(req *SendMessageRequest) string
+	// DrainRules 按分类的重发规则（可选），未列出的分类及未分类的消息使用键为 "" 的规则，都未设置时不限制
+	DrainRules map[string]DrainRule
+}
+
+// DrainRule 暂存消息的重发规则
+type DrainRule struct {
+	MaxAge time.Duration // 暂存超过该时长的消息不再重发，从暂存中删除并以 ErrSpoolExpired 回调；0 表示始终重发
+}
+
+// DrainResult 暂存消息的重发结果
+type DrainResult struct {
+	Message *SpooledMessage  // 暂存的消息
+	Data    *SendMessageData // 重发成功时的响应数据
+	Err     error            // 不可重试的失败或过期（消息已从暂存中删除），或读取暂存的错误
+}
+
+// SendResult SendMessageOrSpool 的结果，Status 区分已提交与已暂存
+type SendResult struct {
+	Status    string           // 结果状态，见 SendStatus* 常量
+	Data      *SendMessageData // 已提交时的响应数据
+	SpoolID   string           // 已暂存时的暂存ID
+	Reason    error            // 暂存原因（满足 errors.Is(err, ErrCircuitOpen)）
+	RetryFrom time.Time        // 预计恢复探测的时间（本地熔断器打开时），零值表示未知
+}
+
+// Deferred 判断消息是否已暂存、尚未提交到网关
+func (r *SendResult) Deferred() bool {
+	return r.Status == SendStatusAcceptedForLater
+}
+
+// WithDegradation 设置降级策略：熔断器打开（本地熔断或网关返回 40007）时，SendMessageOrSpool 将消息写入暂存并返回
+// accepted_for_later 结果而不是错误，DrainSpool/RunSpoolDrainer 在网关恢复后按暂存顺序重发
+// SendMessage 等其他方法的行为不变，熔断时仍返回 *CircuitOpenError
+func WithDegradation(policy DegradationPolicy) ClientOption {
+	return func(c *Client) {
+		if policy.Spool == nil {
+			c.degradation = nil
+			return
+		}
+		if policy.DrainInterval <= 0 {
+			policy.DrainInterval = DefaultDrainInterval
+		}
+		if policy.DrainBatch <= 0 {
+			policy.DrainBatch = DefaultDrainBatch
+		}
+		c.degradation = &policy
+	}
+}
+
+// SendMessageOrSpool 发送单条消息，网关熔断时按降级策略暂存消息并返回 Status 为 accepted_for_later 的结果
+// 校验失败、鉴权错误等非熔断错误照常返回；暂存失败时返回同时包含熔断错误和暂存错误的错误
+// WithHeader、WithNoRetry 随消息一同暂存，重发时同样生效；使用 WithCredentials 的消息不暂存，
+// 熔断时返回同时满足 errors.Is(err, ErrCircuitOpen) 和 errors.Is(err, ErrSpoolCredentials) 的错误
+// 未设置 WithDegradation 时等同于 SendMessage
+func (c *Client) SendMessageOrSpool(ctx context.Context, req *SendMessageRequest, opts ...CallOption) (*SendResult, error) {
+	data, err := c.SendMessage(ctx, req, opts...)
+	if err == nil {
+		return &SendResult{Status: SendStatusSent, Data: data}, nil
+	}
+	if c.degradation == nil || !errors.Is(err, ErrCircuitOpen) {
+		return nil, err
+	}
+	o := mergeCallOptions(ctx, opts)
+	if o.appID != "" {
+		return nil, errors.Join(err, ErrSpoolCredentials)
+	}
+
+	// 暂存前设置幂等键，重发时即使网关已处理过也不会重复投递
+	cp := *req
+	if cp.IdempotencyKey == "" {
+		cp.IdempotencyKey = c.newNonce()
+	}
+	msg := &SpooledMessage{ID: c.newNonce(), Request: &cp, SpooledAt: c.timeNow(), Reason: err.Error(), Header: o.header, NoRetry: o.noRetry}
+	if categorize := c.degradation.Categorize; categorize != nil {
+		c.safeCall(ctx, "Categorize", func() { msg.Category = categorize(&cp) })
+	}
+	if putErr := c.degradation.Spool.Put(ctx, msg); putErr != nil {
+		return nil, errors.Join(err, fmt.Errorf("spool message: %w", putErr))
+	}
+	c.spoolCounts.spooled.Add(1)
+
+	result := &SendResult{Status: SendStatusAcceptedForLater, SpoolID: msg.ID, Reason: err}
+	var openErr *CircuitOpenError
+	if errors.As(err, &openErr) {
+		result.RetryFrom = openErr.OpenUntil
+	}
+	return result, nil
+}
+
+// DrainSpool 按暂存顺序重发一轮（最多 DrainBatch 条）暂存消息，返回重发成功的消息数
+// 熔断器仍打开的通道跳过且不计入 DrainBatch，继续读取后面其他通道的消息，单个通道的故障不会阻塞其他通道；
+// 熔断、网络错误、超出速率限制等可重试的失败保留在暂存中等待下一轮，同一通道本轮不再重发以保持顺序；
+// 其他失败（如参数错误）从暂存中删除并通过 OnDrain 回调，未设置 WithDegradation 时不做任何操作
+// 超过所属分类 MaxAge 的消息不再重发（熔断中的通道也一样），从暂存中删除并以 ErrSpoolExpired 回调，不计入 DrainBatch
+func (c *Client) DrainSpool(ctx context.Context) (int, error) {
+	p := c.degradation
+	if p == nil {
+		return 0, nil
+	}
+
+	sent, attempted := 0, 0
+	blocked := make(map[int]bool) // 本轮不再尝试的通道，保持同一通道的发送顺序
+	var after *SpooledMessage
+	for attempted < p.DrainBatch {
+		msgs, err := p.Spool.Peek(ctx, after, p.DrainBatch)
+		if err != nil {
+			return sent, fmt.Errorf("peek spool: %w", err)
+		}
+		if len(msgs) == 0 {
+			break
+		}
+		after = msgs[len(msgs)-1]
+
+		for _, msg := range msgs {
+			if attempted >= p.DrainBatch {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return sent, err
+			}
+			if expired := c.spoolExpired(msg); expired != nil {
+				if finErr := c.finishSpooled(ctx, msg, nil, expired); finErr != nil {
+					return sent, finErr
+				}
+				continue
+			}
+			channelID := msg.Request.ChannelID
+			if blocked[channelID] || c.breakers.stateOf(channelID) == BreakerOpen {
+				blocked[channelID] = true
+				continue
+			}
+
+			attempted++
+			data, err := c.SendMessage(ctx, msg.Request, msg.callOptions()...)
+			if err != nil && spoolRetryable(err) {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return sent, ctxErr
+				}
+				blocked[channelID] = true
+				if recErr := c.recordSpoolFailure(ctx, msg, err); recErr != nil {
+					return sent, recErr
+				}
+				continue
+			}
+			if finErr := c.finishSpooled(ctx, msg, data, err); finErr != nil {
+				return sent, finErr
+			}
+			if err == nil {
+				sent++
+			}
+		}
+	}
+	return sent, nil
+}
+
+// RunSpoolDrainer 按 DrainInterval 定期重发暂存消息，直到 ctx 结束并返回 ctx.Err()
+// 一轮消息全部处理完且可能还有剩余时立即开始下一轮；读取暂存的错误通过 OnDrain 回调
+func (c *Client) RunSpoolDrainer(ctx context.Context) error {
+	p := c.degradation
+	if p == nil {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	ticker := time.NewTicker(p.DrainInterval)
+	defer ticker.Stop()
+	for {
+		sent, err := c.DrainSpool(ctx)
+		if err != nil && ctx.Err() == nil {
+			c.fireDrain(ctx, DrainResult{Err: err})
+		}
+		if err == nil && sent == p.DrainBatch {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordSpoolFailure 记录可重试的重发失败，消息保留在暂存中
+func (c *Client) recordSpoolFailure(ctx context.Context, msg *SpooledMessage, err error) error {
+	msg.Attempts++
+	msg.LastError = err.Error()
+	msg.LastAttemptAt = c.timeNow()
+	if putErr := c.degradation.Spool.Put(ctx, msg); putErr != nil {
+		return fmt.Errorf("update spooled message: %w", putErr)
+	}
+	return nil
+}
+
+// finishSpooled 重发成功或不可重试的失败后从暂存中删除消息并回调结果
+func (c *Client) finishSpooled(ctx context.Context, msg *SpooledMessage, data *SendMessageData, err error) error {
+	if rmErr := c.degradation.Spool.Remove(ctx, msg.ID); rmErr != nil {
+		return fmt.Errorf("remove spooled message: %w", rmErr)
+	}
+	if err == nil {
+		c.spoolCounts.drained.Add(1)
+	} else {
+		c.spoolCounts.dropped.Add(1)
+	}
+	c.fireDrain(ctx, DrainResult{Message: msg, Data: data, Err: err})
+	return nil
+}
+
+// mergeCallOptions 合并 context 与参数中的单次调用选项，用于确定需要随暂存消息保存的选项
+func mergeCallOptions(ctx context.Context, opts []CallOption) *callOptions {
+	o := &callOptions{}
+	if prev := callOptionsFrom(ctx); prev != nil {
+		*o = *prev
+		o.header = prev.header.Clone()
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// callOptions 还原暂存时保存的单次调用选项
+func (m *SpooledMessage) callOptions() []CallOption {
+	var opts []CallOption
+	for key, values := range m.Header {
+		for _, value := range values {
+			opts = append(opts, WithHeader(key, value))
+		}
+	}
+	if m.NoRetry {
+		opts = append(opts, WithNoRetry())
+	}
+	return opts
+}
+
+// spoolExpired 判断暂存消息是否超过所属分类的 MaxAge，超过时返回满足 errors.Is(err, ErrSpoolExpired) 的错误
+func (c *Client) spoolExpired(msg *SpooledMessage) error {
+	rule, ok := c.degradation.DrainRules[msg.Category]
+	if !ok {
+		rule = c.degradation.DrainRules[""]
+	}
+	if rule.MaxAge <= 0 {
+		return nil
+	}
+	if age := c.timeNow().Sub(msg.SpooledAt); age > rule.MaxAge {
+		return fmt.Errorf("spooled %s ago, category %q max age %s: %w", age.Round(time.Second), msg.Category, rule.MaxAge, ErrSpoolExpired)
+	}
+	return nil
+}
+
+// fireDrain 回调重发结果
+func (c *Client) fireDrain(ctx context.Context, result DrainResult) {
+	if c.degradation.OnDrain != nil {
+		c.safeCall(ctx, "OnDrain", func() { c.degradation.OnDrain(result) })
+	}
+}
+
+// spoolRetryable 判断重发失败是否应保留在暂存中
+func spoolRetryable(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var statusCode int
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		statusCode = httpErr.StatusCode
+	}
+	retry, _ := classifyRetry(err, statusCode)
+	return retry
+}
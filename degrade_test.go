@@ -0,0 +1,294 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSendMessageOrSpool 测试熔断时暂存消息、恢复后按顺序重发
+func TestSendMessageOrSpool(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	var mu sync.Mutex
+	var delivered []SendMessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if down.Load() {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeProviderError, "message": "服务商错误"})
+			return
+		}
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		delivered = append(delivered, req)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	defer server.Close()
+
+	spool, err := NewFileSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	var drained []DrainResult
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithCircuitBreaker(BreakerConfig{FailureThreshold: 1, Cooldown: time.Minute}),
+		WithDegradation(DegradationPolicy{Spool: spool, OnDrain: func(r DrainResult) { drained = append(drained, r) }}))
+	now := time.Now()
+	client.breakers.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 熔断前的失败照常返回错误
+	if _, err := client.SendMessageOrSpool(ctx, &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}); !IsAPIError(err) {
+		t.Fatalf("SendMessageOrSpool() error = %v, want APIError", err)
+	}
+	for _, receiver := range []string{"13800138001", "13800138002"} {
+		result, err := client.SendMessageOrSpool(ctx, &SendMessageRequest{ChannelID: 1, Receiver: receiver})
+		if err != nil {
+			t.Fatalf("SendMessageOrSpool() error = %v", err)
+		}
+		if !result.Deferred() || result.SpoolID == "" || !errors.Is(result.Reason, ErrCircuitOpen) || !result.RetryFrom.Equal(now.Add(time.Minute)) {
+			t.Errorf("SendMessageOrSpool() = %+v, want accepted_for_later", result)
+		}
+	}
+	// 校验失败不会暂存
+	if _, err := client.SendMessageOrSpool(ctx, &SendMessageRequest{Receiver: "13800138003"}); err == nil {
+		t.Error("invalid request should fail")
+	}
+	if n, _ := spool.Len(ctx); n != 2 {
+		t.Fatalf("spool len = %d, want 2", n)
+	}
+
+	// 熔断器仍打开时不重发
+	if sent, err := client.DrainSpool(ctx); sent != 0 || err != nil {
+		t.Errorf("DrainSpool() while open = %d, %v", sent, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	down.Store(false)
+	if sent, err := client.DrainSpool(ctx); sent != 2 || err != nil {
+		t.Fatalf("DrainSpool() = %d, %v, want 2", sent, err)
+	}
+	if n, _ := spool.Len(ctx); n != 0 {
+		t.Errorf("spool len after drain = %d", n)
+	}
+	if len(delivered) != 2 || delivered[0].Receiver != "13800138001" || delivered[1].Receiver != "13800138002" {
+		t.Fatalf("delivered = %+v, want spool order", delivered)
+	}
+	if delivered[0].IdempotencyKey == "" || delivered[0].IdempotencyKey == delivered[1].IdempotencyKey {
+		t.Errorf("spooled messages should carry distinct idempotency keys: %+v", delivered)
+	}
+	if len(drained) != 2 || drained[0].Data == nil || drained[0].Err != nil {
+		t.Errorf("OnDrain results = %+v", drained)
+	}
+}
+
+// TestDrainSpoolSkipsBlockedChannels 测试熔断通道的消息不会阻塞后面其他通道的消息
+func TestDrainSpoolSkipsBlockedChannels(t *testing.T) {
+	server := newSuccessServer(t)
+	spool, err := NewFileSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithCircuitBreaker(BreakerConfig{FailureThreshold: 1, Cooldown: time.Minute}),
+		WithDegradation(DegradationPolicy{Spool: spool, DrainBatch: 2}))
+	client.breakers.record(1, NewAPIError(ErrCodeProviderError, "服务商错误"), http.StatusOK)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		spool.Put(ctx, &SpooledMessage{ID: fmt.Sprintf("blocked-%d", i), Request: &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}, SpooledAt: time.Unix(int64(i), 0)})
+	}
+	spool.Put(ctx, &SpooledMessage{ID: "other", Request: &SendMessageRequest{ChannelID: 2, Receiver: "13800138000"}, SpooledAt: time.Unix(10, 0)})
+
+	if sent, err := client.DrainSpool(ctx); sent != 1 || err != nil {
+		t.Fatalf("DrainSpool() = %d, %v, want 1", sent, err)
+	}
+	if n, _ := spool.Len(ctx); n != 5 {
+		t.Errorf("spool len = %d, want 5", n)
+	}
+}
+
+// TestDrainSpoolDropsPermanentFailures 测试不可重试的失败从暂存中删除并回调
+func TestDrainSpoolDropsPermanentFailures(t *testing.T) {
+	server := newSuccessServer(t)
+	spool, err := NewFileSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	var drained []DrainResult
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithDegradation(DegradationPolicy{Spool: spool, OnDrain: func(r DrainResult) { drained = append(drained, r) }}))
+	ctx := context.Background()
+
+	spool.Put(ctx, &SpooledMessage{ID: "bad", Request: &SendMessageRequest{Receiver: "13800138000"}, SpooledAt: time.Unix(1, 0)})
+	spool.Put(ctx, &SpooledMessage{ID: "good", Request: &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}, SpooledAt: time.Unix(2, 0)})
+
+	if sent, err := client.DrainSpool(ctx); sent != 1 || err != nil {
+		t.Fatalf("DrainSpool() = %d, %v, want 1", sent, err)
+	}
+	if n, _ := spool.Len(ctx); n != 0 {
+		t.Errorf("spool len = %d, want 0", n)
+	}
+	if len(drained) != 2 || drained[0].Message.ID != "bad" || !errors.Is(drained[0].Err, ErrInvalidRequest) {
+		t.Errorf("OnDrain results = %+v", drained)
+	}
+
+	// 未设置降级策略时等同于 SendMessage
+	plain := NewClient(server.URL, "test_app_id", "test_secret")
+	result, err := plain.SendMessageOrSpool(ctx, &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"})
+	if err != nil || result.Status != SendStatusSent || result.Data.TaskID != "t1" {
+		t.Errorf("SendMessageOrSpool() = %+v, %v", result, err)
+	}
+}
+
+// TestDrainSpoolRateLimited 测试恢复期间重发被限流时消息保留在暂存中，不计为删除
+func TestDrainSpoolRateLimited(t *testing.T) {
+	var limited atomic.Bool
+	limited.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if limited.Load() {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeRateLimitExceeded, "message": "超出速率限制"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	defer server.Close()
+	spool, err := NewFileSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	var drained []DrainResult
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithDegradation(DegradationPolicy{Spool: spool, OnDrain: func(r DrainResult) { drained = append(drained, r) }}))
+	ctx := context.Background()
+	spool.Put(ctx, &SpooledMessage{ID: "a", Request: &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}, SpooledAt: time.Unix(1, 0)})
+
+	if sent, err := client.DrainSpool(ctx); sent != 0 || err != nil {
+		t.Fatalf("DrainSpool() while rate limited = %d, %v", sent, err)
+	}
+	msg, err := spool.Get(ctx, "a")
+	if err != nil || msg.Attempts != 1 || len(drained) != 0 {
+		t.Fatalf("spooled message = %+v, %v, OnDrain results = %+v", msg, err, drained)
+	}
+
+	limited.Store(false)
+	if sent, err := client.DrainSpool(ctx); sent != 1 || err != nil {
+		t.Fatalf("DrainSpool() = %d, %v, want 1", sent, err)
+	}
+	if stats, _ := client.SpoolStats(ctx); stats.Pending != 0 || stats.Drained != 1 || stats.Dropped != 0 {
+		t.Errorf("SpoolStats() = %+v", stats)
+	}
+}
+
+// TestSendMessageOrSpoolCallOptions 测试单次调用的请求头随消息暂存并在重发时附加，单次调用凭证的消息不暂存
+func TestSendMessageOrSpoolCallOptions(t *testing.T) {
+	var mu sync.Mutex
+	var tenants []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		tenants = append(tenants, r.Header.Get("X-Tenant"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	defer server.Close()
+	spool, err := NewFileSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithCircuitBreaker(BreakerConfig{FailureThreshold: 1, Cooldown: time.Minute}),
+		WithDegradation(DegradationPolicy{Spool: spool}))
+	now := time.Now()
+	client.breakers.now = func() time.Time { return now }
+	client.breakers.record(1, NewAPIError(ErrCodeProviderError, "服务商错误"), http.StatusOK)
+	ctx := context.Background()
+	req := &SendMessageRequest{ChannelID: 1, SignatureName: "test", Receiver: "13800138000"}
+
+	_, err = client.SendMessageOrSpool(ctx, req, WithCredentials("tenant_app", "tenant_secret"))
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrSpoolCredentials) {
+		t.Fatalf("SendMessageOrSpool() with credentials error = %v, want ErrSpoolCredentials", err)
+	}
+	result, err := client.SendMessageOrSpool(ctx, req, WithHeader("X-Tenant", "a"), WithNoRetry())
+	if err != nil || !result.Deferred() {
+		t.Fatalf("SendMessageOrSpool() = %+v, %v", result, err)
+	}
+	if msg, _ := spool.Get(ctx, result.SpoolID); msg == nil || msg.Header.Get("X-Tenant") != "a" || !msg.NoRetry {
+		t.Fatalf("spooled message = %+v", msg)
+	}
+	if n, _ := spool.Len(ctx); n != 1 {
+		t.Fatalf("spool len = %d, want 1", n)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if sent, err := client.DrainSpool(ctx); sent != 1 || err != nil {
+		t.Fatalf("DrainSpool() = %d, %v, want 1", sent, err)
+	}
+	if len(tenants) != 1 || tenants[0] != "a" {
+		t.Errorf("resent headers X-Tenant = %v, want [a]", tenants)
+	}
+}
+
+// TestDrainSpoolRules 测试按分类的 MaxAge：过期的验证码不再重发（熔断中的通道也一样），账单通知始终重发
+func TestDrainSpoolRules(t *testing.T) {
+	server := newSuccessServer(t)
+	spool, err := NewFileSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	var drained []DrainResult
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithCircuitBreaker(BreakerConfig{FailureThreshold: 1, Cooldown: time.Hour}),
+		WithDegradation(DegradationPolicy{
+			Spool:   spool,
+			OnDrain: func(r DrainResult) { drained = append(drained, r) },
+			Categorize: func(req *SendMessageRequest) string {
+				if _, ok := req.TemplateParams["code"]; ok {
+					return "otp"
+				}
+				return "billing"
+			},
+			DrainRules: map[string]DrainRule{"otp": {MaxAge: 5 * time.Minute}},
+		}))
+	now := time.Now()
+	client.now = func() time.Time { return now }
+	client.breakers.record(1, NewAPIError(ErrCodeProviderError, "服务商错误"), http.StatusOK)
+	ctx := context.Background()
+
+	result, err := client.SendMessageOrSpool(ctx, &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", TemplateParams: map[string]interface{}{"code": "1234"}})
+	if err != nil || !result.Deferred() {
+		t.Fatalf("SendMessageOrSpool() = %+v, %v", result, err)
+	}
+	if msg, err := client.GetSpooled(ctx, result.SpoolID); err != nil || msg.Category != "otp" {
+		t.Fatalf("GetSpooled() = %+v, %v, want category otp", msg, err)
+	}
+	spool.Put(ctx, &SpooledMessage{ID: "billing", Category: "billing", Request: &SendMessageRequest{ChannelID: 2, Receiver: "13800138001"}, SpooledAt: now.Add(-2 * time.Hour)})
+	spool.Put(ctx, &SpooledMessage{ID: "stale-otp", Category: "otp", Request: &SendMessageRequest{ChannelID: 2, Receiver: "13800138002"}, SpooledAt: now.Add(-10 * time.Minute)})
+
+	now = now.Add(10 * time.Minute)
+	spool.Put(ctx, &SpooledMessage{ID: "fresh-otp", Category: "otp", Request: &SendMessageRequest{ChannelID: 2, Receiver: "13800138003"}, SpooledAt: now.Add(-time.Minute)})
+	if sent, err := client.DrainSpool(ctx); sent != 2 || err != nil {
+		t.Fatalf("DrainSpool() = %d, %v, want 2", sent, err)
+	}
+	results := make(map[string]error)
+	for _, r := range drained {
+		results[r.Message.ID] = r.Err
+	}
+	if len(results) != 4 || results["billing"] != nil || !errors.Is(results["stale-otp"], ErrSpoolExpired) ||
+		results["fresh-otp"] != nil || !errors.Is(results[result.SpoolID], ErrSpoolExpired) {
+		t.Errorf("OnDrain results = %+v", results)
+	}
+	if n, _ := spool.Len(ctx); n != 0 {
+		t.Errorf("spool len = %d, want 0", n)
+	}
+}
@@ -0,0 +1,86 @@
+package mlievpush
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 客户端配置环境变量
+const (
+	EnvBaseURL     = "MLIEV_PUSH_URL"          // 网关地址
+	EnvAppID       = "MLIEV_PUSH_APP_ID"       // 应用ID
+	EnvAppSecret   = "MLIEV_PUSH_APP_SECRET"   // 应用密钥
+	EnvTimeout     = "MLIEV_PUSH_TIMEOUT"      // 请求超时时间（time.ParseDuration 格式，如 "5s"）
+	EnvMaxAttempts = "MLIEV_PUSH_MAX_ATTEMPTS" // 最大尝试次数（含首次），使用 DefaultBackoff 退避
+	EnvProxy       = "MLIEV_PUSH_PROXY"        // HTTP代理地址
+	EnvLogLevel    = "MLIEV_PUSH_LOG_LEVEL"    // 日志详细级别（error、warn、info、debug、trace）
+	EnvConfig      = "MLIEV_PUSH_CONFIG"       // 配置文件路径（可选），环境变量覆盖文件中的同名设置
+	EnvProfile     = "MLIEV_PUSH_PROFILE"      // 配置文件中的命名配置，为空时使用默认配置
+)
+
+// NewClientFromEnv 根据 MLIEV_PUSH_* 环境变量创建客户端，适用于容器等通过环境变量注入配置的部署
+// 设置了 MLIEV_PUSH_CONFIG 时先读取配置文件，再用其余环境变量覆盖；opts 最后应用
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	p := &ProfileConfig{}
+	if path := os.Getenv(EnvConfig); path != "" {
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		if p, err = cfg.Profile(os.Getenv(EnvProfile)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.applyEnv(); err != nil {
+		return nil, err
+	}
+	client, err := p.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("client from environment: %w", err)
+	}
+	return client, nil
+}
+
+// applyEnv 用环境变量覆盖配置
+func (p *ProfileConfig) applyEnv() error {
+	if v := os.Getenv(EnvBaseURL); v != "" {
+		p.BaseURL = v
+	}
+	if v := os.Getenv(EnvAppID); v != "" {
+		p.AppID = v
+	}
+	if v := os.Getenv(EnvAppSecret); v != "" {
+		p.AppSecret, p.AppSecretEnv, p.AppSecretFile = v, "", ""
+	}
+	if v := os.Getenv(EnvTimeout); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", EnvTimeout, err)
+		}
+		p.Timeout = Duration(d)
+	}
+	if v := os.Getenv(EnvMaxAttempts); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", EnvMaxAttempts, err)
+		}
+		if p.Retry == nil {
+			p.Retry = &RetryConfig{}
+		}
+		p.Retry.MaxAttempts = n
+	}
+	if v := os.Getenv(EnvProxy); v != "" {
+		p.Proxy = v
+	}
+	if v := os.Getenv(EnvLogLevel); v != "" {
+		level, err := ParseLogLevel(v)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", EnvLogLevel, err)
+		}
+		p.LogLevel = level
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package mlievpush
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewClientFromEnv 测试从环境变量创建客户端
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv(EnvBaseURL, "https://push.example.com")
+	t.Setenv(EnvAppID, "env_app")
+	t.Setenv(EnvAppSecret, "env_secret")
+	t.Setenv(EnvTimeout, "2s")
+	t.Setenv(EnvMaxAttempts, "4")
+	t.Setenv(EnvProxy, "http://proxy.internal:3128")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("NewClientFromEnv() error = %v", err)
+	}
+	if client.baseURL != "https://push.example.com" || client.appID != "env_app" || client.appSecret != "env_secret" {
+		t.Errorf("unexpected credentials: %s %s", client.baseURL, client.appID)
+	}
+	if client.httpClient.Timeout != 2*time.Second || client.maxAttempts != 4 {
+		t.Errorf("unexpected timeout/retry: %v %d", client.httpClient.Timeout, client.maxAttempts)
+	}
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.httpClient.Transport)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://push.example.com", nil)
+	if proxy, err := transport.Proxy(req); err != nil || proxy.Host != "proxy.internal:3128" {
+		t.Errorf("unexpected proxy: %v, %v", proxy, err)
+	}
+}
+
+// TestNewClientFromEnvConfig 测试环境变量覆盖配置文件
+func TestNewClientFromEnvConfig(t *testing.T) {
+	t.Setenv(EnvConfig, writeTestConfig(t, "push.json", testConfig))
+	t.Setenv(EnvProfile, "staging")
+	t.Setenv(EnvAppID, "override_app")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("NewClientFromEnv() error = %v", err)
+	}
+	if client.appID != "override_app" || client.appSecret != "staging_secret" {
+		t.Errorf("unexpected client: %s %s", client.appID, client.appSecret)
+	}
+
+	t.Setenv(EnvTimeout, "soon")
+	if _, err := NewClientFromEnv(); err == nil || !strings.Contains(err.Error(), EnvTimeout) {
+		t.Errorf("expected timeout parse error, got %v", err)
+	}
+}
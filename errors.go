@@ -1,11 +1,26 @@
 package mlievpush
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // APIError API错误
 type APIError struct {
-	Code    int    // 错误码
-	Message string // 错误消息
+	Code      int    // 错误码
+	Message   string // 错误消息
+	RequestID string // 客户端请求ID（X-Request-Id），便于与网关日志对照
+
+	Retryable       bool          // 是否可以原样重试
+	RetryAfter      time.Duration // 建议的重试等待时间（来自 Retry-After 响应头），0表示未指定
+	SuggestedAction Action        // 建议的处理方式
+	ServerTime      time.Time     // 服务端时间（仅时间戳无效时从响应头读取），零值表示未提供
+
+	Simulated bool // 是否为 WithSimulatedFailures 注入的模拟错误
 }
 
 // Error 实现 error 接口
@@ -14,17 +29,74 @@ func (e *APIError) Error() string {
 }
 
 // NewAPIError 创建API错误
+// 重试建议根据错误码分类自动填充
 func NewAPIError(code int, message string) *APIError {
+	advice := GetErrorAdvice(code)
 	return &APIError{
-		Code:    code,
-		Message: message,
+		Code:            code,
+		Message:         message,
+		Retryable:       advice.Retryable,
+		SuggestedAction: advice.SuggestedAction,
 	}
 }
 
-// IsAPIError 判断是否为API错误
+// IsAPIError 判断是否为API错误（包括被 fmt.Errorf("%w") 等包装的 *APIError）
 func IsAPIError(err error) bool {
-	_, ok := err.(*APIError)
-	return ok
+	var apiErr *APIError
+	return errors.As(err, &apiErr)
+}
+
+// 错误分类哨兵，*APIError 按错误码匹配，可用 errors.Is 判断而无需比较整数错误码：
+//
+//	if errors.Is(err, mlievpush.ErrRateLimited) { ... }
+//
+// 签名和时间戳错误分别匹配 ErrInvalidSignature、ErrTimestampSkew，所有 1xxxx 请求错误匹配 ErrInvalidRequest
+var (
+	ErrUnauthorized       = errors.New("mlievpush: unauthorized")         // 鉴权错误（2xxxx）
+	ErrRateLimited        = errors.New("mlievpush: rate limited")         // 超出速率限制
+	ErrQuotaExceeded      = errors.New("mlievpush: quota exceeded")       // 超出配额限制
+	ErrChannelNotFound    = errors.New("mlievpush: channel not found")    // 通道不存在
+	ErrChannelDisabled    = errors.New("mlievpush: channel disabled")     // 通道已禁用
+	ErrTemplateNotFound   = errors.New("mlievpush: template not found")   // 模板不存在
+	ErrNoAvailableChannel = errors.New("mlievpush: no available channel") // 无可用通道
+	ErrTaskNotFound       = errors.New("mlievpush: task not found")       // 任务不存在
+	ErrBatchNotFound      = errors.New("mlievpush: batch not found")      // 批量任务不存在
+	ErrTaskNotCancelable  = errors.New("mlievpush: task not cancelable")  // 任务无法取消
+	ErrInvalidReceiver    = errors.New("mlievpush: invalid receiver")     // 接收者格式错误（网关返回或本地校验）
+	ErrServerError        = errors.New("mlievpush: server error")         // 网关系统错误（4xxxx）
+)
+
+// codeSentinels 错误码 -> 哨兵错误
+var codeSentinels = map[int]error{
+	ErrCodeInvalidReceiver:    ErrInvalidReceiver,
+	ErrCodeInvalidSignature:   ErrInvalidSignature,
+	ErrCodeInvalidTimestamp:   ErrTimestampSkew,
+	ErrCodeRateLimitExceeded:  ErrRateLimited,
+	ErrCodeQuotaExceeded:      ErrQuotaExceeded,
+	ErrCodeChannelNotFound:    ErrChannelNotFound,
+	ErrCodeChannelDisabled:    ErrChannelDisabled,
+	ErrCodeTemplateNotFound:   ErrTemplateNotFound,
+	ErrCodeNoAvailableChannel: ErrNoAvailableChannel,
+	ErrCodeTaskNotFound:       ErrTaskNotFound,
+	ErrCodeBatchNotFound:      ErrBatchNotFound,
+	ErrCodeTaskNotCancelable:  ErrTaskNotCancelable,
+	ErrCodeCircuitOpen:        ErrCircuitOpen,
+}
+
+// Is 按错误码匹配哨兵错误，使 errors.Is(err, ErrRateLimited) 等成立
+func (e *APIError) Is(target error) bool {
+	if sentinel, ok := codeSentinels[e.Code]; ok && sentinel == target {
+		return true
+	}
+	switch e.Code / 10000 {
+	case 1:
+		return target == ErrInvalidRequest
+	case 2:
+		return target == ErrUnauthorized
+	case 4:
+		return target == ErrServerError
+	}
+	return false
 }
 
 // 错误码常量定义
@@ -59,6 +131,7 @@ const (
 	ErrCodeNoAvailableChannel = 30006 // 无可用通道
 	ErrCodeTaskNotFound       = 30007 // 任务不存在
 	ErrCodeBatchNotFound      = 30008 // 批量任务不存在
+	ErrCodeTaskNotCancelable  = 30009 // 任务已开始发送或已结束，无法取消
 )
 
 // 系统错误 (4xxxx)
@@ -99,6 +172,7 @@ var ErrorCodeMessages = map[int]string{
 	ErrCodeNoAvailableChannel: "无可用通道",
 	ErrCodeTaskNotFound:       "任务不存在",
 	ErrCodeBatchNotFound:      "批量任务不存在",
+	ErrCodeTaskNotCancelable:  "任务不可取消",
 
 	// 系统错误
 	ErrCodeInternalError:  "内部错误",
@@ -110,6 +184,88 @@ var ErrorCodeMessages = map[int]string{
 	ErrCodeCircuitOpen:    "熔断器打开",
 }
 
+// Action 错误的建议处理方式
+type Action string
+
+// 建议处理方式
+const (
+	ActionRetry            Action = "retry"             // 稍后原样重试（退避）
+	ActionRetryLater       Action = "retry_later"       // 等待 RetryAfter 或更长时间后重试
+	ActionFixRequest       Action = "fix_request"       // 修正请求参数，重试无效
+	ActionCheckCredentials Action = "check_credentials" // 检查应用ID、密钥、签名或IP白名单
+	ActionCheckClock       Action = "check_clock"       // 检查本机时钟同步
+	ActionCheckConfig      Action = "check_config"      // 检查通道、模板等服务端配置
+	ActionContactSupport   Action = "contact_support"   // 联系服务方（配额、应用状态等）
+)
+
+// ErrorAdvice 错误码对应的处理建议
+type ErrorAdvice struct {
+	Retryable       bool   // 是否可以原样重试
+	SuggestedAction Action // 建议的处理方式
+}
+
+// ErrorCodeAdvice 错误码对应的处理建议，未列出的错误码按错误码分类（首位数字）处理
+var ErrorCodeAdvice = map[int]ErrorAdvice{
+	// 鉴权错误
+	ErrCodeInvalidTimestamp: {SuggestedAction: ActionCheckClock},
+	ErrCodeAppDisabled:      {SuggestedAction: ActionContactSupport},
+
+	// 业务错误
+	ErrCodeRateLimitExceeded:  {Retryable: true, SuggestedAction: ActionRetryLater},
+	ErrCodeQuotaExceeded:      {SuggestedAction: ActionContactSupport},
+	ErrCodeChannelNotFound:    {SuggestedAction: ActionCheckConfig},
+	ErrCodeChannelDisabled:    {SuggestedAction: ActionCheckConfig},
+	ErrCodeTemplateNotFound:   {SuggestedAction: ActionCheckConfig},
+	ErrCodeNoAvailableChannel: {Retryable: true, SuggestedAction: ActionRetryLater},
+	ErrCodeTaskNotFound:       {SuggestedAction: ActionFixRequest},
+	ErrCodeBatchNotFound:      {SuggestedAction: ActionFixRequest},
+	ErrCodeTaskNotCancelable:  {SuggestedAction: ActionFixRequest},
+
+	// 系统错误：仅网络超时与熔断为瞬时故障，其余重试通常无效且可能重复下发
+	ErrCodeInternalError:  {SuggestedAction: ActionContactSupport},
+	ErrCodeDatabaseError:  {SuggestedAction: ActionContactSupport},
+	ErrCodeRedisError:     {SuggestedAction: ActionContactSupport},
+	ErrCodeQueueError:     {SuggestedAction: ActionContactSupport},
+	ErrCodeProviderError:  {SuggestedAction: ActionContactSupport},
+	ErrCodeNetworkTimeout: {Retryable: true, SuggestedAction: ActionRetry},
+	ErrCodeCircuitOpen:    {Retryable: true, SuggestedAction: ActionRetryLater},
+}
+
+// GetErrorAdvice 根据错误码获取处理建议
+func GetErrorAdvice(code int) ErrorAdvice {
+	if advice, ok := ErrorCodeAdvice[code]; ok {
+		return advice
+	}
+	switch code / 10000 {
+	case 1: // 请求错误
+		return ErrorAdvice{SuggestedAction: ActionFixRequest}
+	case 2: // 鉴权错误
+		return ErrorAdvice{SuggestedAction: ActionCheckCredentials}
+	case 3: // 业务错误
+		return ErrorAdvice{SuggestedAction: ActionCheckConfig}
+	default: // 系统错误及未知错误码
+		return ErrorAdvice{SuggestedAction: ActionContactSupport}
+	}
+}
+
+// parseRetryAfter 解析 Retry-After 响应头（秒数或HTTP日期）
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
 // GetErrorMessage 根据错误码获取错误消息
 func GetErrorMessage(code int) string {
 	if msg, ok := ErrorCodeMessages[code]; ok {
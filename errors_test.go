@@ -0,0 +1,137 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGetErrorAdvice 测试错误码处理建议
+func TestGetErrorAdvice(t *testing.T) {
+	tests := []struct {
+		code      int
+		retryable bool
+		action    Action
+	}{
+		{ErrCodeInvalidReceiver, false, ActionFixRequest},
+		{ErrCodeInvalidSignature, false, ActionCheckCredentials},
+		{ErrCodeInvalidTimestamp, false, ActionCheckClock},
+		{ErrCodeRateLimitExceeded, true, ActionRetryLater},
+		{ErrCodeQuotaExceeded, false, ActionContactSupport},
+		{ErrCodeTemplateNotFound, false, ActionCheckConfig},
+		{ErrCodeProviderError, false, ActionContactSupport},
+		{ErrCodeInternalError, false, ActionContactSupport},
+		{ErrCodeNetworkTimeout, true, ActionRetry},
+		{ErrCodeCircuitOpen, true, ActionRetryLater},
+		{49999, false, ActionContactSupport},
+		{99999, false, ActionContactSupport},
+	}
+
+	for _, tt := range tests {
+		advice := GetErrorAdvice(tt.code)
+		if advice.Retryable != tt.retryable || advice.SuggestedAction != tt.action {
+			t.Errorf("GetErrorAdvice(%d) = %+v, want retryable=%v action=%s", tt.code, advice, tt.retryable, tt.action)
+		}
+	}
+}
+
+// TestParseRetryAfter 测试 Retry-After 解析
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 11, 25, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"-1", 0},
+		{"Tue, 25 Nov 2025 10:02:00 GMT", 2 * time.Minute},
+		{"Tue, 25 Nov 2025 09:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestAPIErrorRetryAdvice 测试API错误携带重试建议
+func TestAPIErrorRetryAdvice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeRateLimitExceeded, "message": "超出速率限制"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	_, err := client.QueryTask(context.Background(), "t1")
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if !apiErr.Retryable || apiErr.RetryAfter != 5*time.Second || apiErr.SuggestedAction != ActionRetryLater {
+		t.Errorf("unexpected advice: retryable=%v retryAfter=%v action=%s", apiErr.Retryable, apiErr.RetryAfter, apiErr.SuggestedAction)
+	}
+}
+
+// TestAPIErrorRetryAfterClock 测试 HTTP 日期格式的 Retry-After 按客户端时间源计算
+func TestAPIErrorRetryAfterClock(t *testing.T) {
+	now := time.Date(2025, 11, 25, 10, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", now.Add(30*time.Second).Format(http.TimeFormat))
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeRateLimitExceeded, "message": "超出速率限制"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithClock(func() time.Time { return now }))
+	_, err := client.QueryTask(context.Background(), "t1")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 30*time.Second {
+		t.Errorf("QueryTask() error = %v, want RetryAfter 30s", err)
+	}
+}
+
+// TestAPIErrorIs 测试 errors.Is 按错误码匹配哨兵错误
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		code   int
+		target error
+		want   bool
+	}{
+		{ErrCodeRateLimitExceeded, ErrRateLimited, true},
+		{ErrCodeRateLimitExceeded, ErrQuotaExceeded, false},
+		{ErrCodeChannelNotFound, ErrChannelNotFound, true},
+		{ErrCodeInvalidSignature, ErrInvalidSignature, true},
+		{ErrCodeInvalidSignature, ErrUnauthorized, true},
+		{ErrCodeIPNotAllowed, ErrUnauthorized, true},
+		{ErrCodeInvalidReceiver, ErrInvalidReceiver, true},
+		{ErrCodeInvalidReceiver, ErrInvalidRequest, true},
+		{ErrCodeProviderError, ErrServerError, true},
+		{ErrCodeTaskNotFound, ErrServerError, false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("send: %w", NewAPIError(tt.code, "x"))
+		if got := errors.Is(err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%d, %v) = %v, want %v", tt.code, tt.target, got, tt.want)
+		}
+	}
+
+	if !IsAPIError(fmt.Errorf("wrapped: %w", NewAPIError(ErrCodeInternalError, "x"))) {
+		t.Error("IsAPIError(wrapped) = false")
+	}
+	if !errors.Is(ValidateReceiver(MessageTypeSMS, "abc"), ErrInvalidReceiver) {
+		t.Error("ReceiverError should match ErrInvalidReceiver")
+	}
+}
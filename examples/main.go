@@ -111,7 +111,7 @@ func queryTaskStatus(ctx context.Context, client *mlievpush.Client) {
 	fmt.Printf("  应用ID: %s\n", data.AppID)
 	fmt.Printf("  通道ID: %d\n", data.ChannelID)
 	fmt.Printf("  消息类型: %s\n", data.MessageType)
-	fmt.Printf("  接收者: %s\n", data.Receiver)
+	fmt.Printf("  接收者: %s\n", client.Redactor().RedactReceiver(data.Receiver)) // 日志中脱敏输出
 	fmt.Printf("  内容: %s\n", data.Content)
 	fmt.Printf("  状态: %s\n", data.Status)
 	fmt.Printf("  回调状态: %s\n", data.CallbackStatus)
@@ -0,0 +1,205 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// 投递报表导出格式
+const (
+	ExportFormatCSV    = "csv"    // CSV，首行为表头
+	ExportFormatNDJSON = "ndjson" // 每行一个 JSON 对象
+)
+
+// exportContentTypes 导出格式对应的 Accept 请求头
+var exportContentTypes = map[string]string{
+	ExportFormatCSV:    "text/csv",
+	ExportFormatNDJSON: "application/x-ndjson",
+}
+
+// ExportQuery 投递报表导出条件，零值字段不参与过滤
+type ExportQuery struct {
+	Format    string    // 导出格式，见 ExportFormat* 常量，为空时使用 CSV
+	ChannelID int       // 通道ID
+	BatchID   string    // 批次ID
+	Status    string    // 任务状态，见 TaskStatus* 常量
+	Since     time.Time // 创建时间下限（含）
+	Until     time.Time // 创建时间上限（不含）
+
+	OnProgress func(ExportProgress) // 每写入一段数据后的回调（可选），用于展示下载进度
+}
+
+// ExportProgress 导出进度
+type ExportProgress struct {
+	Bytes int64 // 已写入的字节数
+	Lines int64 // 已写入的换行符数量（CSV 含表头，字段内换行也会计入）
+	Total int64 // 响应总字节数，网关未返回 Content-Length 时为 -1
+}
+
+// ExportInterruptedError 导出数据已开始写入后下载中断，不会自动重试（避免重复写入），需要重新导出
+type ExportInterruptedError struct {
+	Written int64 // 中断前已写入的字节数
+	Err     error // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *ExportInterruptedError) Error() string {
+	return fmt.Sprintf("export interrupted after %d bytes: %v", e.Written, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *ExportInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// query 编码为查询参数
+func (q *ExportQuery) query() url.Values {
+	query := url.Values{}
+	query.Set("format", q.format())
+	if q.ChannelID > 0 {
+		query.Set("channel_id", strconv.Itoa(q.ChannelID))
+	}
+	if q.BatchID != "" {
+		query.Set("batch_id", q.BatchID)
+	}
+	if q.Status != "" {
+		query.Set("status", q.Status)
+	}
+	if !q.Since.IsZero() {
+		query.Set("start_time", FormatDeadline(q.Since))
+	}
+	if !q.Until.IsZero() {
+		query.Set("end_time", FormatDeadline(q.Until))
+	}
+	return query
+}
+
+// format 导出格式，为空时使用 CSV
+func (q *ExportQuery) format() string {
+	if q.Format == "" {
+		return ExportFormatCSV
+	}
+	return q.Format
+}
+
+// ExportReport 导出投递报表并以流的方式写入 w，返回写入的字节数；query 为 nil 时导出全部任务的 CSV
+// 数据边下载边写入，不会整体读入内存，适合百万级结果；w 可以是 CreateFile 创建的压缩文件
+// 开始写入前的失败按 WithRetry 配置重试；写入开始后中断返回 *ExportInterruptedError，w 中可能已有部分数据
+// 客户端超时（WithTimeout）包含下载时间，导出大报表时应调大超时或通过 ctx 控制
+func (c *Client) ExportReport(ctx context.Context, query *ExportQuery, w io.Writer) (int64, error) {
+	if query == nil {
+		query = &ExportQuery{}
+	}
+	accept, ok := exportContentTypes[query.format()]
+	if !ok {
+		return 0, &FieldError{Field: "format", Reason: fmt.Sprintf("unsupported export format %q", query.Format)}
+	}
+	if err := c.requireFeature(ctx, FeatureReportExport); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := withCallOptions(ctx, []CallOption{WithHeader("Accept", accept)})
+	defer cancel()
+	target := &exportTarget{w: w}
+	if query.OnProgress != nil {
+		target.onProgress = func(p ExportProgress) {
+			c.safeCall(ctx, "OnProgress", func() { query.OnProgress(p) })
+		}
+	}
+	ctx = withResponseStreamer(ctx, target)
+
+	path := "/api/v1/reports/export?" + query.query().Encode()
+	if _, err := c.doRequest(ctx, http.MethodGet, path, nil); err != nil {
+		return target.written, err
+	}
+	return target.written, nil
+}
+
+// exportTarget 导出数据的写入目标
+type exportTarget struct {
+	w          io.Writer
+	onProgress func(ExportProgress)
+	written    int64
+	lines      int64
+}
+
+// streams 判断响应是否为导出数据流（成功且不是 JSON 信封）
+func (t *exportTarget) streams(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mediaType != "application/json"
+}
+
+// consume 将响应体写入目标，成功时返回空的成功响应
+func (t *exportTarget) consume(resp *http.Response) (*Response, error) {
+	if err := t.copy(resp); err != nil {
+		return nil, err
+	}
+	return &Response{Message: "success"}, nil
+}
+
+// copy 将响应体写入目标并回调进度
+func (t *exportTarget) copy(resp *http.Response) error {
+	total := resp.ContentLength
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := t.w.Write(buf[:n]); err != nil {
+				return &ExportInterruptedError{Written: t.written, Err: fmt.Errorf("write export: %w", err)}
+			}
+			t.written += int64(n)
+			t.lines += int64(bytes.Count(buf[:n], []byte{'\n'}))
+			if t.onProgress != nil {
+				t.onProgress(ExportProgress{Bytes: t.written, Lines: t.lines, Total: total})
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			err := fmt.Errorf("read export body: %w", readErr)
+			if t.written == 0 {
+				return err
+			}
+			return &ExportInterruptedError{Written: t.written, Err: err}
+		}
+	}
+}
+
+// responseStreamer 流式处理响应体，避免大响应整体读入内存
+type responseStreamer interface {
+	streams(resp *http.Response) bool               // 是否流式处理该响应
+	consume(resp *http.Response) (*Response, error) // 处理响应体，返回响应信封（Data 可以为空）
+}
+
+// responseStreamerKey context 中流式处理器的键
+type responseStreamerKey struct{}
+
+// withResponseStreamer 在 ctx 中设置流式处理器
+func withResponseStreamer(ctx context.Context, s responseStreamer) context.Context {
+	return context.WithValue(ctx, responseStreamerKey{}, s)
+}
+
+// responseStreamerFrom 获取 ctx 中的流式处理器
+func responseStreamerFrom(ctx context.Context) responseStreamer {
+	s, _ := ctx.Value(responseStreamerKey{}).(responseStreamer)
+	return s
+}
+
+// isStreamInterrupted 判断是否为已开始处理数据后的流式响应中断（重试会导致重复处理）
+func isStreamInterrupted(err error) bool {
+	var exportErr *ExportInterruptedError
+	var batchErr *BatchStreamError
+	return errors.As(err, &exportErr) || errors.As(err, &batchErr)
+}
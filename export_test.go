@@ -0,0 +1,96 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestExportReport 测试流式导出投递报表
+func TestExportReport(t *testing.T) {
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	var rows strings.Builder
+	rows.WriteString("task_id,receiver,status\n")
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&rows, "t%d,13800138000,success\n", i)
+	}
+	var query, accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.Verify(r); err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+		query, accept = r.URL.RawQuery, r.Header.Get("Accept")
+		if r.URL.Query().Get("format") == ExportFormatNDJSON {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeInvalidParams, "message": "不支持的格式"})
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Length", strconv.Itoa(rows.Len()))
+		w.Write([]byte(rows.String()))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	var buf bytes.Buffer
+	var last ExportProgress
+	var calls int
+	n, err := client.ExportReport(context.Background(), &ExportQuery{
+		BatchID:    "b1",
+		OnProgress: func(p ExportProgress) { last = p; calls++ },
+	}, &buf)
+	if err != nil {
+		t.Fatalf("ExportReport() error = %v", err)
+	}
+	if query != "batch_id=b1&format=csv" || accept != "text/csv" {
+		t.Errorf("query = %q, accept = %q", query, accept)
+	}
+	if n != int64(rows.Len()) || buf.String() != rows.String() {
+		t.Errorf("written = %d, want %d", n, rows.Len())
+	}
+	if calls < 2 || last.Bytes != n || last.Lines != 5001 || last.Total != n {
+		t.Errorf("progress calls = %d, last = %+v", calls, last)
+	}
+
+	_, err = client.ExportReport(context.Background(), &ExportQuery{Format: ExportFormatNDJSON}, &buf)
+	if apiErr, ok := err.(*APIError); !ok || apiErr.Code != ErrCodeInvalidParams {
+		t.Errorf("expected APIError, got %v", err)
+	}
+	if _, err := client.ExportReport(context.Background(), &ExportQuery{Format: "xlsx"}, &buf); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected invalid format error, got %v", err)
+	}
+}
+
+// TestExportReportInterrupted 测试写入开始后中断不重试
+func TestExportReportInterrupted(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("task_id,status\nt1,success\n"))
+		w.(http.Flusher).Flush()
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithRetry(3, Backoff{}))
+	var buf bytes.Buffer
+	n, err := client.ExportReport(context.Background(), nil, &buf)
+	var interrupted *ExportInterruptedError
+	if !errors.As(err, &interrupted) || interrupted.Written != n || n == 0 {
+		t.Fatalf("expected ExportInterruptedError, got n = %d, err = %v", n, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1 (no retry after partial write)", calls.Load())
+	}
+}
@@ -0,0 +1,152 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// capabilityCacheTTL 服务端能力缓存有效期
+const capabilityCacheTTL = 10 * time.Minute
+
+// 可选功能，由服务端能力探测接口声明
+const (
+	FeatureBatchDetail         = "batch_detail"         // 批量任务明细（QueryBatch、ListBatchTasks、ReconcileBatch）
+	FeatureStatistics          = "statistics"           // 统计汇总（GetBatchSummary、GetBatchSummaries、GetTemplateStats）
+	FeatureCancel              = "cancel"               // 取消任务（CancelTask）
+	FeatureAnnotations         = "annotations"          // 任务备注（AnnotateTask）
+	FeatureAttachmentUpload    = "attachment_upload"    // 附件上传（UploadAttachment）
+	FeatureChannelCapabilities = "channel_capabilities" // 通道能力（GetChannelCapabilities）
+	FeatureTaskList            = "task_list"            // 任务列表（ListTasks）
+	FeatureChannels            = "channels"             // 通道管理（ListChannels、GetChannel）
+	FeatureTemplates           = "templates"            // 模板管理（ListTemplates、GetTemplate、CreateTemplate、UpdateTemplate）
+	FeatureSignatures          = "signatures"           // 签名管理（ListSignatures、CreateSignature、CheckSignature）
+	FeatureTaskTimeline        = "task_timeline"        // 任务时间线（GetTaskTimeline）
+	FeatureCallbackTest        = "callback_test"        // 测试回调（TestCallback）
+	FeatureScheduled           = "scheduled"            // 定时消息管理（ListScheduled、RescheduleTask、CancelScheduled）
+	FeatureReportExport        = "report_export"        // 投递报表导出（ExportReport）
+	FeatureBatchResults        = "batch_results"        // 批量发送逐条结果（SendBatchDetailed）
+)
+
+// ErrNotSupportedByServer 目标网关不支持该功能（服务端版本过旧），可用 errors.Is 判断
+var ErrNotSupportedByServer = errors.New("mlievpush: not supported by server")
+
+// NotSupportedError 目标网关缺少某个接口或功能
+// 由能力探测判定时 StatusCode 为0；由接口返回 404/405 判定时 Err 为原始错误
+type NotSupportedError struct {
+	Feature    string // 功能名称，见 Feature* 常量（由接口404/405判定时为空）
+	Method     string // 请求方法
+	Path       string // 请求路径
+	StatusCode int    // HTTP状态码（404/405）
+	Err        error  // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *NotSupportedError) Error() string {
+	if e.Feature != "" {
+		return fmt.Sprintf("%v: %s", ErrNotSupportedByServer, e.Feature)
+	}
+	return fmt.Sprintf("%v: %s %s returned %d", ErrNotSupportedByServer, e.Method, e.Path, e.StatusCode)
+}
+
+// Is 使 errors.Is(err, ErrNotSupportedByServer) 成立
+func (e *NotSupportedError) Is(target error) bool {
+	return target == ErrNotSupportedByServer
+}
+
+// Unwrap 返回原始错误
+func (e *NotSupportedError) Unwrap() error {
+	return e.Err
+}
+
+// notSupported 将接口不存在（404/405 且不是业务错误）转换为 *NotSupportedError
+func notSupported(method, path string, statusCode int, err error) error {
+	if err == nil || (statusCode != http.StatusNotFound && statusCode != http.StatusMethodNotAllowed) {
+		return err
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		// 业务错误（如任务不存在）由网关正常返回，不是接口缺失
+		return err
+	}
+	return &NotSupportedError{Method: method, Path: path, StatusCode: statusCode, Err: err}
+}
+
+// ServerCapabilities 服务端能力
+type ServerCapabilities struct {
+	Version  string   `json:"version"`  // 服务端版本
+	Features []string `json:"features"` // 支持的可选功能，见 Feature* 常量
+
+	RawData // 原始JSON，见 RawData
+}
+
+// Supports 判断服务端是否支持指定功能
+func (s *ServerCapabilities) Supports(feature string) bool {
+	for _, f := range s.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// capabilityCache 服务端能力缓存
+type capabilityCache struct {
+	mu        sync.Mutex
+	caps      *ServerCapabilities
+	fetchedAt time.Time
+}
+
+// WithCapabilityDiscovery 开启服务端能力探测
+// 开启后首次调用可选功能时会查询 /api/v1/capabilities 并缓存结果，目标网关不支持的功能直接返回 *NotSupportedError，
+// 而不是令人困惑的 404；不提供探测接口的旧版网关视为不支持任何可选功能
+func WithCapabilityDiscovery(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.discoverCapabilities = enabled
+	}
+}
+
+// Capabilities 查询服务端能力，结果会缓存一段时间
+func (c *Client) Capabilities(ctx context.Context) (*ServerCapabilities, error) {
+	cache := c.capabilities
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.caps != nil && time.Since(cache.fetchedAt) < capabilityCacheTTL {
+		return cache.caps, nil
+	}
+
+	resp, statusCode, err := c.execute(ctx, http.MethodGet, "/api/v1/capabilities", nil)
+	switch {
+	case statusCode == http.StatusNotFound:
+		// 旧版网关没有探测接口
+		cache.caps = &ServerCapabilities{}
+	case err != nil:
+		return nil, fmt.Errorf("discover server capabilities: %w", err)
+	default:
+		var caps ServerCapabilities
+		if err := decodeData(resp.Data, &caps); err != nil {
+			return nil, fmt.Errorf("unmarshal response data: %w", err)
+		}
+		cache.caps = &caps
+	}
+
+	cache.fetchedAt = time.Now()
+	return cache.caps, nil
+}
+
+// requireFeature 检查服务端是否支持可选功能，未开启探测时不检查
+// 探测请求本身失败（网络错误等）时放行，由实际请求返回错误
+func (c *Client) requireFeature(ctx context.Context, feature string) error {
+	if !c.discoverCapabilities {
+		return nil
+	}
+	caps, err := c.Capabilities(ctx)
+	if err != nil || caps.Supports(feature) {
+		return nil
+	}
+	return &NotSupportedError{Feature: feature}
+}
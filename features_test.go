@@ -0,0 +1,95 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestCapabilityDiscovery 测试服务端能力探测与降级
+func TestCapabilityDiscovery(t *testing.T) {
+	var discoveries atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/capabilities":
+			discoveries.Add(1)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"code":    0,
+				"message": "success",
+				"data":    map[string]interface{}{"version": "1.4.0", "features": []string{FeatureBatchDetail}},
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{}})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithCapabilityDiscovery(true))
+	ctx := context.Background()
+
+	if _, err := client.QueryBatch(ctx, "b1"); err != nil {
+		t.Errorf("QueryBatch() error = %v", err)
+	}
+	if _, err := client.GetBatchSummary(ctx, "b1"); !errors.Is(err, ErrNotSupportedByServer) {
+		t.Errorf("GetBatchSummary() error = %v, want %v", err, ErrNotSupportedByServer)
+	}
+	if _, err := client.AnnotateTask(ctx, "t1", "note"); !errors.Is(err, ErrNotSupportedByServer) {
+		t.Errorf("AnnotateTask() error = %v, want %v", err, ErrNotSupportedByServer)
+	}
+	if discoveries.Load() != 1 {
+		t.Errorf("discoveries = %d, want 1 (cached)", discoveries.Load())
+	}
+}
+
+// TestCapabilityDiscoveryLegacyServer 测试旧版网关没有探测接口
+func TestCapabilityDiscoveryLegacyServer(t *testing.T) {
+	var optionalCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/capabilities" {
+			http.NotFound(w, r)
+			return
+		}
+		optionalCalls.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithCapabilityDiscovery(true))
+	_, err := client.QueryBatch(context.Background(), "b1")
+	if !errors.Is(err, ErrNotSupportedByServer) {
+		t.Errorf("QueryBatch() error = %v, want %v", err, ErrNotSupportedByServer)
+	}
+	if optionalCalls.Load() != 0 {
+		t.Errorf("unsupported endpoint should not be called")
+	}
+
+	// 未开启探测时直接请求，接口返回404同样转换为 *NotSupportedError
+	plain := NewClient(server.URL, "test_app_id", "test_secret")
+	_, err = plain.QueryBatch(context.Background(), "b1")
+	var nse *NotSupportedError
+	if !errors.As(err, &nse) || nse.StatusCode != http.StatusNotFound {
+		t.Errorf("QueryBatch() error = %v, want *NotSupportedError with 404", err)
+	}
+	if optionalCalls.Load() != 1 {
+		t.Errorf("optional calls = %d, want 1", optionalCalls.Load())
+	}
+}
+
+// TestNotSupportedKeepsAPIError 测试业务404错误不被误判为接口缺失
+func TestNotSupportedKeepsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeTaskNotFound, "message": "任务不存在"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	_, err := client.QueryTask(context.Background(), "t1")
+	if !IsAPIError(err) || errors.Is(err, ErrNotSupportedByServer) {
+		t.Errorf("QueryTask() error = %v, want *APIError", err)
+	}
+}
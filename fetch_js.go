@@ -0,0 +1,47 @@
+//go:build js && wasm
+
+package mlievpush
+
+import "net/http"
+
+// FetchOptions 浏览器 fetch 请求选项，对应 RequestInit 中的同名字段
+type FetchOptions struct {
+	Mode        string // 请求模式：cors、no-cors、same-origin，为空时使用浏览器默认值
+	Credentials string // 凭据策略：omit、same-origin、include，为空时使用浏览器默认值
+	Redirect    string // 重定向策略：follow、error、manual，为空时使用浏览器默认值
+}
+
+// fetchTransport 基于浏览器 fetch API 的传输层
+type fetchTransport struct {
+	base http.RoundTripper
+	opts FetchOptions
+}
+
+// NewFetchTransport 创建基于浏览器 fetch API 的传输层（仅 GOOS=js GOARCH=wasm 可用）
+//
+// 注意：网关需要允许跨域并在 Access-Control-Allow-Headers 中放行 RequestHeaders 返回的全部请求头和 Content-Type
+// （X-App-Id、X-Timestamp、X-Nonce、X-Signature、X-Request-Id，以及按配置发送的 X-Content-SHA256、X-Signature-Version），
+// 否则浏览器会拦截预检请求。
+func NewFetchTransport(opts FetchOptions) http.RoundTripper {
+	return &fetchTransport{
+		base: http.DefaultTransport,
+		opts: opts,
+	}
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *fetchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	// net/http 在 js/wasm 下通过这些特殊请求头设置 fetch 选项，发送前会被移除
+	setFetchOption(req, "js.fetch:mode", t.opts.Mode)
+	setFetchOption(req, "js.fetch:credentials", t.opts.Credentials)
+	setFetchOption(req, "js.fetch:redirect", t.opts.Redirect)
+	return t.base.RoundTrip(req)
+}
+
+// setFetchOption 设置非空的 fetch 选项
+func setFetchOption(req *http.Request, key, value string) {
+	if value != "" {
+		req.Header.Set(key, value)
+	}
+}
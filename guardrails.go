@@ -0,0 +1,131 @@
+package mlievpush
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 运行环境
+const (
+	EnvironmentProduction = "production" // 生产环境（默认，不做额外限制）
+	EnvironmentStaging    = "staging"    // 预发/测试环境，启用 StagingGuardrails
+)
+
+// DefaultStagingWatermark 预发环境默认的内容水印
+const DefaultStagingWatermark = "[TEST]"
+
+// ParamWatermark 预发环境写入模板参数的水印键，模板中可通过 ${watermark} 引用
+const ParamWatermark = "watermark"
+
+// ErrGuardrailBlocked 发送被预发环境防护规则拦截，可用 errors.Is 判断
+var ErrGuardrailBlocked = errors.New("mlievpush: blocked by staging guardrail")
+
+// StagingGuardrails 预发环境防护规则，防止测试任务把消息发给真实用户
+type StagingGuardrails struct {
+	AllowedReceivers []string `json:"allowed_receivers"`        // 允许的接收者，以 "*" 结尾表示前缀匹配（如 "1380013*"）；为空时拒绝所有发送
+	MaxBatchSize     int      `json:"max_batch_size,omitempty"` // 单次批量发送的最大接收者数，0 使用默认值 10
+	Watermark        string   `json:"watermark,omitempty"`      // 内容水印，为空使用 DefaultStagingWatermark
+}
+
+// defaultStagingMaxBatchSize 预发环境默认的批量发送上限
+const defaultStagingMaxBatchSize = 10
+
+// GuardrailError 发送被预发环境防护规则拦截（本地拦截，不会发送请求）
+type GuardrailError struct {
+	Receiver string // 被拦截的接收者（批量超限时为空）
+	Reason   string // 拦截原因
+
+	redactor Redactor // 脱敏策略，为nil时使用 DefaultRedactor
+}
+
+// Error 实现 error 接口，接收者已脱敏
+func (e *GuardrailError) Error() string {
+	if e.Receiver == "" {
+		return fmt.Sprintf("%v: %s", ErrGuardrailBlocked, e.Reason)
+	}
+	redactor := e.redactor
+	if redactor == nil {
+		redactor = DefaultRedactor
+	}
+	return fmt.Sprintf("%v: receiver %q %s", ErrGuardrailBlocked, redactor.RedactReceiver(e.Receiver), e.Reason)
+}
+
+// Is 使 errors.Is(err, ErrGuardrailBlocked) 成立
+func (e *GuardrailError) Is(target error) bool {
+	return target == ErrGuardrailBlocked
+}
+
+// WithEnvironment 设置运行环境，EnvironmentStaging 下启用防护规则（未通过 WithStagingGuardrails 配置时拒绝所有接收者）
+func WithEnvironment(env string) ClientOption {
+	return func(c *Client) {
+		c.environment = env
+	}
+}
+
+// WithStagingGuardrails 设置预发环境防护规则，仅在 EnvironmentStaging 下生效：
+// 接收者必须在白名单内、批量发送数量受限，并在模板参数中写入水印（邮件主题和 Markdown 标题会加上水印前缀）
+func WithStagingGuardrails(g StagingGuardrails) ClientOption {
+	return func(c *Client) {
+		c.guardrails = g
+	}
+}
+
+// Environment 返回客户端的运行环境
+func (c *Client) Environment() string {
+	if c.environment == "" {
+		return EnvironmentProduction
+	}
+	return c.environment
+}
+
+// guard 按预发环境防护规则检查接收者，返回加上水印的模板参数（非预发环境原样返回）
+func (c *Client) guard(params map[string]interface{}, receivers ...string) (map[string]interface{}, error) {
+	if c.environment != EnvironmentStaging {
+		return params, nil
+	}
+
+	g := c.guardrails
+	maxBatch := g.MaxBatchSize
+	if maxBatch <= 0 {
+		maxBatch = defaultStagingMaxBatchSize
+	}
+	if len(receivers) > maxBatch {
+		return nil, &GuardrailError{Reason: fmt.Sprintf("batch of %d receivers exceeds staging limit of %d", len(receivers), maxBatch)}
+	}
+	for _, receiver := range receivers {
+		if !g.allows(receiver) {
+			return nil, &GuardrailError{Receiver: receiver, Reason: "is not in the staging allowlist", redactor: c.redactor}
+		}
+	}
+
+	watermark := g.Watermark
+	if watermark == "" {
+		watermark = DefaultStagingWatermark
+	}
+	marked := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		marked[k] = v
+	}
+	marked[ParamWatermark] = watermark
+	for _, key := range []string{ParamSubject, ParamMarkdownTitle} {
+		if s, ok := marked[key].(string); ok && !strings.HasPrefix(s, watermark) {
+			marked[key] = watermark + " " + s
+		}
+	}
+	return marked, nil
+}
+
+// allows 判断接收者是否在白名单内
+func (g StagingGuardrails) allows(receiver string) bool {
+	for _, allowed := range g.AllowedReceivers {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(receiver, prefix) {
+				return true
+			}
+		} else if receiver == allowed {
+			return true
+		}
+	}
+	return false
+}
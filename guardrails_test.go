@@ -0,0 +1,69 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestStagingGuardrails 测试预发环境防护规则
+func TestStagingGuardrails(t *testing.T) {
+	var sent SendMessageRequest
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"success","data":{"task_id":"t1"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithEnvironment(EnvironmentStaging),
+		WithStagingGuardrails(StagingGuardrails{AllowedReceivers: []string{"1380013*", "qa@example.com"}, MaxBatchSize: 2}),
+	)
+	ctx := context.Background()
+
+	_, err := client.SendMessage(ctx, &SendMessageRequest{ChannelID: 1, Receiver: "13900139000"})
+	var guardErr *GuardrailError
+	if !errors.As(err, &guardErr) || !errors.Is(err, ErrGuardrailBlocked) || strings.Contains(err.Error(), "13900139000") {
+		t.Errorf("expected redacted guardrail error, got %v", err)
+	}
+
+	_, err = client.SendBatch(ctx, &SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000", "13800138001", "13800138002"}})
+	if !errors.Is(err, ErrGuardrailBlocked) {
+		t.Errorf("expected batch size guardrail error, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("blocked sends should not reach gateway, calls = %d", calls)
+	}
+
+	req := &SendMessageRequest{ChannelID: 3, Receiver: "qa@example.com", TemplateParams: map[string]interface{}{ParamSubject: "账单"}}
+	if _, err := client.SendMessage(ctx, req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if sent.TemplateParams[ParamWatermark] != DefaultStagingWatermark || sent.TemplateParams[ParamSubject] != "[TEST] 账单" {
+		t.Errorf("unexpected params: %v", sent.TemplateParams)
+	}
+	if req.TemplateParams[ParamSubject] != "账单" {
+		t.Error("caller's params should not be modified")
+	}
+}
+
+// TestStagingDeniesByDefault 测试未配置白名单时拒绝所有发送
+func TestStagingDeniesByDefault(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1", "id", "secret", WithEnvironment(EnvironmentStaging))
+	if client.Environment() != EnvironmentStaging {
+		t.Errorf("Environment() = %s", client.Environment())
+	}
+	if _, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}); !errors.Is(err, ErrGuardrailBlocked) {
+		t.Errorf("expected guardrail error, got %v", err)
+	}
+	if NewClient("http://x", "id", "secret").Environment() != EnvironmentProduction {
+		t.Error("default environment should be production")
+	}
+}
@@ -0,0 +1,195 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// RequestInfo 请求信息，传递给 OnRequest 钩子
+type RequestInfo struct {
+	Method      string   // 请求方法
+	Path        string   // 请求路径
+	ChannelID   int      // 通道ID，非发送类请求为0
+	Receivers   []string // 接收者（已按 Redactor 脱敏），非发送类请求为空
+	OperationID string   // 逻辑调用ID，同一调用的多次重试相同
+	RequestID   string   // 本次尝试的请求ID（X-Request-Id）
+	Attempt     int      // 尝试次数，从1开始
+}
+
+// ResponseInfo 响应信息，传递给 OnResponse 钩子
+type ResponseInfo struct {
+	Method      string        // 请求方法
+	Path        string        // 请求路径
+	ChannelID   int           // 通道ID，非发送类请求为0
+	OperationID string        // 逻辑调用ID，同一调用的多次重试相同
+	RequestID   string        // 本次尝试的请求ID（X-Request-Id）
+	Attempt     int           // 尝试次数，从1开始
+	StatusCode  int           // HTTP状态码，请求未完成时为0
+	Code        int           // 业务状态码
+	Duration    time.Duration // 请求耗时
+	Err         error         // 请求错误
+}
+
+// RetryInfo 重试决策信息，传递给 OnRetry 钩子
+// 每次尝试失败后触发一次：Retry 为 true 表示将在 Backoff 后重试，否则 Reason 说明放弃的原因
+type RetryInfo struct {
+	Method       string        // 请求方法
+	Path         string        // 请求路径
+	ChannelID    int           // 通道ID，非发送类请求为0
+	OperationID  string        // 逻辑调用ID，同一调用的多次重试相同
+	Attempt      int           // 失败的尝试次数，从1开始
+	MaxAttempts  int           // 最大尝试次数（含首次）
+	Retry        bool          // 是否重试
+	Reason       string        // 决策原因，见 RetryReason* 常量
+	Backoff      time.Duration // 重试前的等待时间（Retry 为 false 时为0）
+	RetryAfter   time.Duration // 服务端建议的重试等待时间（Retry-After），Backoff 不小于该值
+	BreakerState BreakerState  // 通道熔断器状态，未开启熔断器时为空
+	Err          error         // 本次尝试的错误
+}
+
+// newRetryInfo 构造重试决策信息
+func newRetryInfo(method, path string, reqData interface{}, operationID string, attempt, maxAttempts int,
+	retry bool, reason string, backoff time.Duration, err error, breakers *breakerSet) *RetryInfo {
+	channelID := requestChannelID(reqData)
+	info := &RetryInfo{
+		Method:       method,
+		Path:         path,
+		ChannelID:    channelID,
+		OperationID:  operationID,
+		Attempt:      attempt,
+		MaxAttempts:  maxAttempts,
+		Retry:        retry,
+		Reason:       reason,
+		Backoff:      backoff,
+		BreakerState: breakers.stateOf(channelID),
+		Err:          err,
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		info.RetryAfter = apiErr.RetryAfter
+	}
+	return info
+}
+
+// Hooks 请求生命周期钩子，未设置的钩子会被忽略
+type Hooks struct {
+	OnRequest  func(ctx context.Context, info *RequestInfo)  // 发送请求前
+	OnResponse func(ctx context.Context, info *ResponseInfo) // 请求完成后（无论成功失败）
+	OnRetry    func(ctx context.Context, info *RetryInfo)    // 每次尝试失败后的重试决策
+	OnError    func(ctx context.Context, err error)          // 请求失败或用户回调panic时
+}
+
+// WithHooks 添加请求生命周期钩子，多次调用时按添加顺序依次触发
+func WithHooks(hooks Hooks) ClientOption {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, hooks)
+	}
+}
+
+// WithPanicRecovery 设置是否捕获用户回调中的panic（默认开启）
+// 开启时panic会被转换为 *PanicError 并通过 OnError 钩子上报，而不会导致进程崩溃
+func WithPanicRecovery(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.recoverPanics = enabled
+	}
+}
+
+// PanicError 用户回调发生panic时上报的错误
+type PanicError struct {
+	Callback string      // 回调名称
+	Value    interface{} // panic的值
+	Stack    []byte      // 调用栈
+}
+
+// Error 实现 error 接口
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %v", e.Callback, e.Value)
+}
+
+// safeCall 调用用户回调，按配置捕获panic
+func (c *Client) safeCall(ctx context.Context, name string, fn func()) {
+	if !c.recoverPanics {
+		fn()
+		return
+	}
+
+	defer func() {
+		if v := recover(); v != nil {
+			c.reportPanic(ctx, &PanicError{Callback: name, Value: v, Stack: debug.Stack()})
+		}
+	}()
+	fn()
+}
+
+// safeCallErr 调用返回错误的用户回调，按配置捕获panic，捕获的panic上报后作为 *PanicError 返回
+func (c *Client) safeCallErr(ctx context.Context, name string, fn func() error) (err error) {
+	if !c.recoverPanics {
+		return fn()
+	}
+
+	defer func() {
+		if v := recover(); v != nil {
+			panicErr := &PanicError{Callback: name, Value: v, Stack: debug.Stack()}
+			c.reportPanic(ctx, panicErr)
+			err = panicErr
+		}
+	}()
+	return fn()
+}
+
+// reportPanic 上报用户回调中的panic，上报过程本身的panic会被忽略
+func (c *Client) reportPanic(ctx context.Context, err *PanicError) {
+	if err.Callback == "OnError" {
+		return
+	}
+
+	for _, h := range c.hooks {
+		if h.OnError != nil {
+			func() {
+				defer func() {
+					recover()
+				}()
+				h.OnError(ctx, err)
+			}()
+		}
+	}
+}
+
+// fireRequest 触发 OnRequest 钩子
+func (c *Client) fireRequest(ctx context.Context, info *RequestInfo) {
+	for _, h := range c.hooks {
+		if h.OnRequest != nil {
+			c.safeCall(ctx, "OnRequest", func() { h.OnRequest(ctx, info) })
+		}
+	}
+}
+
+// fireResponse 触发 OnResponse 钩子
+func (c *Client) fireResponse(ctx context.Context, info *ResponseInfo) {
+	for _, h := range c.hooks {
+		if h.OnResponse != nil {
+			c.safeCall(ctx, "OnResponse", func() { h.OnResponse(ctx, info) })
+		}
+	}
+}
+
+// fireRetry 触发 OnRetry 钩子
+func (c *Client) fireRetry(ctx context.Context, info *RetryInfo) {
+	for _, h := range c.hooks {
+		if h.OnRetry != nil {
+			c.safeCall(ctx, "OnRetry", func() { h.OnRetry(ctx, info) })
+		}
+	}
+}
+
+// fireError 触发 OnError 钩子
+func (c *Client) fireError(ctx context.Context, err error) {
+	for _, h := range c.hooks {
+		if h.OnError != nil {
+			c.safeCall(ctx, "OnError", func() { h.OnError(ctx, err) })
+		}
+	}
+}
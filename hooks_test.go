@@ -0,0 +1,103 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newSuccessServer 创建返回成功响应的mock服务器
+func newSuccessServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data":    map[string]interface{}{"task_id": "t1", "status": "pending"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestHooks 测试生命周期钩子
+func TestHooks(t *testing.T) {
+	server := newSuccessServer(t)
+
+	var requests []*RequestInfo
+	var responses []*ResponseInfo
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithHooks(Hooks{
+		OnRequest:  func(ctx context.Context, info *RequestInfo) { requests = append(requests, info) },
+		OnResponse: func(ctx context.Context, info *ResponseInfo) { responses = append(responses, info) },
+	}))
+
+	_, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	if len(requests) != 1 || requests[0].Path != "/api/v1/messages" {
+		t.Fatalf("unexpected OnRequest calls: %+v", requests)
+	}
+	if len(responses) != 1 || responses[0].StatusCode != http.StatusOK || responses[0].Err != nil {
+		t.Fatalf("unexpected OnResponse calls: %+v", responses)
+	}
+}
+
+// TestHookPanicRecovery 测试钩子panic被捕获并上报
+func TestHookPanicRecovery(t *testing.T) {
+	server := newSuccessServer(t)
+
+	var reported error
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithHooks(Hooks{
+		OnRequest: func(ctx context.Context, info *RequestInfo) { panic("boom") },
+		OnError:   func(ctx context.Context, err error) { reported = err },
+	}))
+
+	if _, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	var panicErr *PanicError
+	if !errors.As(reported, &panicErr) {
+		t.Fatalf("expected PanicError, got %v", reported)
+	}
+	if panicErr.Callback != "OnRequest" || panicErr.Value != "boom" {
+		t.Errorf("unexpected PanicError: %v", panicErr)
+	}
+}
+
+// TestHookPanicInOnError 测试OnError自身panic不会导致崩溃
+func TestHookPanicInOnError(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1", "test_app_id", "test_secret", WithHooks(Hooks{
+		OnError: func(ctx context.Context, err error) { panic("boom") },
+	}))
+
+	if _, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+// TestHookPanicRecoveryDisabled 测试关闭panic捕获
+func TestHookPanicRecoveryDisabled(t *testing.T) {
+	server := newSuccessServer(t)
+
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithPanicRecovery(false),
+		WithHooks(Hooks{
+			OnRequest: func(ctx context.Context, info *RequestInfo) { panic("boom") },
+		}),
+	)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic to propagate")
+		}
+	}()
+	client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"})
+}
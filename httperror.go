@@ -0,0 +1,84 @@
+package mlievpush
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxHTTPErrorBody HTTPError 中保留的响应体最大字节数
+const maxHTTPErrorBody = 512
+
+// HTTPError 网关返回非2xx状态码或无法解析的响应体（通常来自代理、负载均衡或网关异常）
+// 网关正常返回的业务错误仍为 *APIError
+type HTTPError struct {
+	StatusCode int         // HTTP状态码
+	Status     string      // HTTP状态行，如 "502 Bad Gateway"
+	Header     http.Header // 响应头
+	RequestID  string      // 响应头中的 X-Request-Id（代理或网关未回传时为空）
+	Body       string      // 响应体片段（最多512字节，已按 Redactor 脱敏）
+	Truncated  bool        // 响应体是否被截断
+	Err        error       // 解析响应体的错误，响应体为合法JSON时为nil
+}
+
+// Error 实现 error 接口
+func (e *HTTPError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "http %s", e.Status)
+	if e.Err != nil {
+		fmt.Fprintf(&sb, ": unmarshal response: %v", e.Err)
+	}
+	if e.Body != "" {
+		fmt.Fprintf(&sb, ": body %q", e.Body)
+		if e.Truncated {
+			sb.WriteString("...")
+		}
+	}
+	return sb.String()
+}
+
+// Unwrap 返回解析响应体的错误
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Is 按状态码匹配哨兵错误：5xx 匹配 ErrServerError，401/403 匹配 ErrUnauthorized，429 匹配 ErrRateLimited
+func (e *HTTPError) Is(target error) bool {
+	switch {
+	case e.StatusCode >= http.StatusInternalServerError:
+		return target == ErrServerError
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden:
+		return target == ErrUnauthorized
+	case e.StatusCode == http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	}
+	return false
+}
+
+// newHTTPError 根据响应创建 *HTTPError，响应体截断并脱敏
+func newHTTPError(resp *http.Response, body []byte, err error, redactor Redactor) *HTTPError {
+	e := &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header.Clone(),
+		RequestID:  resp.Header.Get(HeaderRequestID),
+		Err:        err,
+	}
+	if e.Status == "" {
+		e.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if len(body) > maxHTTPErrorBody {
+		body = body[:maxHTTPErrorBody]
+		// 避免截断在多字节字符中间
+		for len(body) > 0 && !utf8.Valid(body) {
+			body = body[:len(body)-1]
+		}
+		e.Truncated = true
+	}
+	if redactor == nil {
+		redactor = DefaultRedactor
+	}
+	e.Body = redactor.RedactText(string(body))
+	return e
+}
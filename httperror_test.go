@@ -0,0 +1,59 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestHTTPError 测试非2xx或无法解析的响应返回 *HTTPError
+func TestHTTPError(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantErr   bool
+		truncated bool
+		serverErr bool
+	}{
+		{name: "html from proxy", status: http.StatusBadGateway, body: "<html>502 Bad Gateway 13800138000</html>", wantErr: true, serverErr: true},
+		{name: "json without code", status: http.StatusServiceUnavailable, body: `{"error":"upstream unavailable"}`, serverErr: true},
+		{name: "long body", status: http.StatusOK, body: strings.Repeat("中", 400), wantErr: true, truncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(HeaderRequestID, "gw-123")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test_app_id", "test_secret")
+			_, err := client.QueryTask(context.Background(), "t1")
+
+			var httpErr *HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("error = %v, want *HTTPError", err)
+			}
+			if httpErr.StatusCode != tt.status || httpErr.RequestID != "gw-123" {
+				t.Errorf("HTTPError = %+v", httpErr)
+			}
+			if (httpErr.Err != nil) != tt.wantErr {
+				t.Errorf("Err = %v, want error %v", httpErr.Err, tt.wantErr)
+			}
+			if httpErr.Truncated != tt.truncated || len(httpErr.Body) > maxHTTPErrorBody {
+				t.Errorf("Truncated = %v, body length = %d", httpErr.Truncated, len(httpErr.Body))
+			}
+			if strings.Contains(httpErr.Body, "13800138000") {
+				t.Errorf("Body is not redacted: %s", httpErr.Body)
+			}
+			if errors.Is(err, ErrServerError) != tt.serverErr {
+				t.Errorf("errors.Is(ErrServerError) = %v", !tt.serverErr)
+			}
+		})
+	}
+}
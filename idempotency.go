@@ -0,0 +1,79 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithBatchChunkSize 设置批量发送的自动分片大小，接收者数量超出时 SendBatch 按顺序分片发送，小于等于0表示不分片
+// 设置了 IdempotencyKey 的批量请求，每个分片使用 ChunkIdempotencyKey 派生的幂等键：
+// 任务中途崩溃后使用相同的幂等键重新执行，已发送的分片会被网关去重，不会重复发送给整个受众
+func WithBatchChunkSize(size int) ClientOption {
+	return func(c *Client) {
+		c.batchChunkSize = size
+	}
+}
+
+// ChunkIdempotencyKey 派生第 index 个分片（从0开始）的幂等键: <key>:<index>/<total>
+// 分片总数参与派生，接收者列表变化导致分片方式不同时不会误命中旧分片
+func ChunkIdempotencyKey(key string, index, total int) string {
+	if key == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d/%d", key, index, total)
+}
+
+// BatchChunkError 分片发送失败，之前的分片已发送成功
+// 使用相同的 IdempotencyKey 重新调用 SendBatch 时，已发送的分片会被网关去重
+type BatchChunkError struct {
+	Index int             // 失败分片的序号（从0开始）
+	Total int             // 分片总数
+	Sent  []SendBatchData // 已发送成功的分片结果
+	Err   error           // 失败原因
+}
+
+// Error 实现 error 接口
+func (e *BatchChunkError) Error() string {
+	return fmt.Sprintf("send batch chunk %d/%d: %v", e.Index+1, e.Total, e.Err)
+}
+
+// Unwrap 返回失败原因
+func (e *BatchChunkError) Unwrap() error {
+	return e.Err
+}
+
+// sendBatchChunks 按分片大小顺序发送批量请求并汇总结果
+func (c *Client) sendBatchChunks(ctx context.Context, req *SendBatchRequest) (*SendBatchData, error) {
+	size := c.batchChunkSize
+	total := (len(req.Receivers) + size - 1) / size
+
+	result := &SendBatchData{}
+	for i := 0; i < total; i++ {
+		end := (i + 1) * size
+		if end > len(req.Receivers) {
+			end = len(req.Receivers)
+		}
+
+		chunk := *req
+		chunk.Receivers = req.Receivers[i*size : end]
+		chunk.IdempotencyKey = ChunkIdempotencyKey(req.IdempotencyKey, i, total)
+
+		data, err := c.sendBatch(ctx, &chunk)
+		if err != nil {
+			return nil, &BatchChunkError{Index: i, Total: total, Sent: result.Chunks, Err: err}
+		}
+
+		if result.BatchID == "" {
+			result.BatchID = data.BatchID
+			result.CreatedAt = data.CreatedAt
+		}
+		result.TotalCount += data.TotalCount
+		result.SuccessCount += data.SuccessCount
+		result.FailedCount += data.FailedCount
+		result.FailedReceivers = append(result.FailedReceivers, data.FailedReceivers...)
+		result.Results = append(result.Results, data.Results...)
+		result.Chunks = append(result.Chunks, *data)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestSendBatchChunks 测试批量发送自动分片和派生幂等键
+func TestSendBatchChunks(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var keys []string
+	failAt := -1
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SendBatchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if len(keys) == failAt {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeInvalidParams, "message": "bad chunk"})
+			return
+		}
+		keys = append(keys, req.IdempotencyKey)
+		count := len(req.Receivers)
+		if seen[req.IdempotencyKey] {
+			count = 0 // 网关对重复的幂等键不再发送
+		}
+		seen[req.IdempotencyKey] = true
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data":    map[string]interface{}{"batch_id": fmt.Sprintf("b%d", len(keys)), "total_count": count, "success_count": count},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithBatchChunkSize(2))
+	req := &SendBatchRequest{ChannelID: 1, Receivers: []string{"1", "2", "3", "4", "5"}, IdempotencyKey: "campaign-1"}
+
+	// 第二个分片失败
+	failAt = 1
+	_, err := client.SendBatch(context.Background(), req)
+	var chunkErr *BatchChunkError
+	if !errors.As(err, &chunkErr) || chunkErr.Index != 1 || chunkErr.Total != 3 || len(chunkErr.Sent) != 1 {
+		t.Fatalf("expected *BatchChunkError at chunk 1, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("chunk error should unwrap to *APIError: %v", err)
+	}
+
+	// 使用相同幂等键重跑，已发送的分片被去重
+	failAt = -1
+	data, err := client.SendBatch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SendBatch() error = %v", err)
+	}
+	want := []string{"campaign-1:0/3", "campaign-1:0/3", "campaign-1:1/3", "campaign-1:2/3"}
+	if fmt.Sprint(keys) != fmt.Sprint(want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	if len(data.Chunks) != 3 || data.BatchID != "b2" || data.TotalCount != 3 {
+		t.Errorf("unexpected merged result: %+v", data)
+	}
+}
+
+// TestChunkIdempotencyKey 测试派生幂等键
+func TestChunkIdempotencyKey(t *testing.T) {
+	if got := ChunkIdempotencyKey("k", 1, 4); got != "k:1/4" {
+		t.Errorf("ChunkIdempotencyKey() = %q", got)
+	}
+	if got := ChunkIdempotencyKey("", 1, 4); got != "" {
+		t.Errorf("empty key should stay empty, got %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+package mlievpush
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// PushClient 消息推送客户端接口，*Client 实现了该接口
+// 下游代码依赖该接口即可在单元测试中注入模拟实现，而无需启动 httptest 服务器
+type PushClient interface {
+	// 发送
+	SendMessage(ctx context.Context, req *SendMessageRequest, opts ...CallOption) (*SendMessageData, error)
+	SendMessageOrSpool(ctx context.Context, req *SendMessageRequest, opts ...CallOption) (*SendResult, error)
+	SendBatch(ctx context.Context, req *SendBatchRequest, opts ...CallOption) (*SendBatchData, error)
+	SendBatchDetailed(ctx context.Context, req *SendBatchRequest, opts ...CallOption) (*SendBatchData, error)
+	SendBatchStream(ctx context.Context, req *SendBatchRequest, onResult func(BatchResult) error, opts ...CallOption) (*SendBatchData, error)
+	UploadAttachment(ctx context.Context, channelID int, att Attachment) (*UploadAttachmentData, error)
+	SendAll(ctx context.Context, reqs []*SendMessageRequest, concurrency int, opts ...SendAllOption) ([]StreamResult, error)
+	SendStream(ctx context.Context, next func() (*SendMessageRequest, bool), opts StreamOptions) (*StreamSummary, error)
+
+	// 任务
+	QueryTask(ctx context.Context, taskID string, opts ...CallOption) (*QueryTaskData, error)
+	AnnotateTask(ctx context.Context, taskID, note string) (*TaskAnnotation, error)
+	ListTasks(ctx context.Context, opts *ListTasksOptions) (*ListTasksData, error)
+	WaitForTask(ctx context.Context, taskID string, opts PollOptions) (*QueryTaskData, error)
+	CancelTask(ctx context.Context, taskID string) (*CancelTaskData, error)
+	ReplayTask(ctx context.Context, taskID string) (*SendMessageData, error)
+	GetTaskTimeline(ctx context.Context, taskID string) (*TaskTimeline, error)
+
+	// 定时消息
+	ListScheduled(ctx context.Context, opts *ListScheduledOptions) (*ListTasksData, error)
+	RescheduleTask(ctx context.Context, taskID string, newTime time.Time) (*RescheduleTaskData, error)
+	CancelScheduled(ctx context.Context, taskID string) (*CancelTaskData, error)
+
+	// 批次
+	QueryBatch(ctx context.Context, batchID string) (*QueryBatchData, error)
+	GetBatchSummary(ctx context.Context, batchID string) (*BatchSummary, error)
+	GetBatchSummaries(ctx context.Context, batchIDs []string) (*BatchSummaries, error)
+	ListBatchTasks(ctx context.Context, batchID string, page, pageSize int) (*BatchTasksPage, error)
+	ReconcileBatch(ctx context.Context, batchID string, submitted ...string) (*ReconciliationReport, error)
+	ExportReport(ctx context.Context, query *ExportQuery, w io.Writer) (int64, error)
+
+	// 模板
+	ListTemplates(ctx context.Context, opts *ListTemplatesOptions) (*ListTemplatesData, error)
+	GetTemplate(ctx context.Context, templateID int) (*Template, error)
+	CreateTemplate(ctx context.Context, req *CreateTemplateRequest) (*Template, error)
+	UpdateTemplate(ctx context.Context, templateID int, req *UpdateTemplateRequest) (*Template, error)
+	GetTemplateStats(ctx context.Context, templateID int, r TimeRange) (*TemplateStats, error)
+
+	// 签名
+	ListSignatures(ctx context.Context, opts *ListSignaturesOptions) (*ListSignaturesData, error)
+	CreateSignature(ctx context.Context, req *CreateSignatureRequest) (*Signature, error)
+	CheckSignature(ctx context.Context, channelID int, name string) (*Signature, error)
+
+	// 回调
+	TestCallback(ctx context.Context) (*TestCallbackData, error)
+
+	// 服务端能力
+	Capabilities(ctx context.Context) (*ServerCapabilities, error)
+
+	// 通道
+	ListChannels(ctx context.Context) (*ListChannelsData, error)
+	GetChannel(ctx context.Context, channelID int) (*ChannelInfo, error)
+	GetChannelCapabilities(ctx context.Context, channelID int) (*ChannelCapabilities, error)
+}
+
+// 编译期检查 *Client 实现了 PushClient
+var _ PushClient = (*Client)(nil)
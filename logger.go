@@ -0,0 +1,138 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Logger 日志接口，可适配 slog、logr 或任意日志库
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// WarnLogger 可选的 warn 级别日志接口，Logger 同时实现该接口时重试决策通过 Warnf 输出，否则使用 Infof
+type WarnLogger interface {
+	Warnf(format string, args ...interface{})
+}
+
+// WithLogger 设置日志输出，输出内容由 WithLogLevel/SetLogLevel 控制（默认 LogLevelDebug）：
+// 最终失败以 error 级别记录，重试决策以 warn 级别记录，失败的请求尝试以 info 级别记录，
+// 成功的请求尝试以 debug 级别记录方法、路径、HTTP状态码、业务码和耗时；trace 级别另记录签名原文和完整的请求体、响应体
+// 日志中不包含密钥和签名，路径、请求体、响应体和错误信息按 Redactor 脱敏；多次调用时 trace 日志只输出到最后设置的 Logger
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		if logger == nil {
+			return
+		}
+		c.logger = logger
+		warnf := logger.Infof
+		if w, ok := logger.(WarnLogger); ok {
+			warnf = w.Warnf
+		}
+		c.hooks = append(c.hooks, Hooks{
+			OnResponse: func(ctx context.Context, info *ResponseInfo) {
+				if info.Err != nil {
+					if c.LogLevel() >= LogLevelInfo {
+						logger.Infof("mlievpush: %s %s status=%d code=%d duration=%s attempt=%d request_id=%s error=%q",
+							info.Method, c.redactor.RedactText(info.Path), info.StatusCode, info.Code, info.Duration,
+							info.Attempt, info.RequestID, c.redactor.RedactText(info.Err.Error()))
+					}
+					return
+				}
+				if c.LogLevel() >= LogLevelDebug {
+					logger.Debugf("mlievpush: %s %s status=%d code=%d duration=%s attempt=%d request_id=%s",
+						info.Method, c.redactor.RedactText(info.Path), info.StatusCode, info.Code, info.Duration,
+						info.Attempt, info.RequestID)
+				}
+			},
+			OnRetry: func(ctx context.Context, info *RetryInfo) {
+				if !info.Retry || c.LogLevel() < LogLevelWarn {
+					return
+				}
+				warnf("mlievpush: %s %s retrying attempt=%d/%d reason=%s backoff=%s breaker=%s",
+					info.Method, c.redactor.RedactText(info.Path), info.Attempt+1, info.MaxAttempts, info.Reason, info.Backoff, info.BreakerState)
+			},
+			OnError: func(ctx context.Context, err error) {
+				logger.Errorf("mlievpush: request failed: %s", c.redactor.RedactText(err.Error()))
+			},
+		})
+	}
+}
+
+// traceRequest 以 trace 级别记录签名原文和请求体，参数部分按 Redactor 脱敏，时间戳、随机数保持原样便于与服务端对比
+func (c *Client) traceRequest(input SignatureInput, sortedParams string, body *requestBody, requestID string) {
+	redacted := input
+	redacted.Body = c.redactor.RedactText(sortedParams)
+	payload := c.redactor.RedactText(string(body.data))
+	if strings.HasPrefix(body.contentType, "multipart/") {
+		payload = fmt.Sprintf("<multipart %d bytes>", len(body.data))
+	}
+	c.logger.Debugf("mlievpush: trace request %s %s request_id=%s canonical=%q body=%s",
+		input.Method, c.redactor.RedactText(input.Path), requestID, redacted.Canonical(), payload)
+}
+
+// slogLogger slog 适配器
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// FromSlog 将 *slog.Logger 适配为 Logger
+func FromSlog(l *slog.Logger) Logger {
+	return slogLogger{l: l}
+}
+
+// Debugf 实现 Logger 接口
+func (s slogLogger) Debugf(format string, args ...interface{}) {
+	s.l.Debug(fmt.Sprintf(format, args...))
+}
+
+// Infof 实现 Logger 接口
+func (s slogLogger) Infof(format string, args ...interface{}) {
+	s.l.Info(fmt.Sprintf(format, args...))
+}
+
+// Warnf 实现 WarnLogger 接口
+func (s slogLogger) Warnf(format string, args ...interface{}) {
+	s.l.Warn(fmt.Sprintf(format, args...))
+}
+
+// Errorf 实现 Logger 接口
+func (s slogLogger) Errorf(format string, args ...interface{}) {
+	s.l.Error(fmt.Sprintf(format, args...))
+}
+
+// LogrLogger logr.Logger 的方法子集，github.com/go-logr/logr 的 Logger 满足该接口（SDK 不直接依赖 logr）
+type LogrLogger interface {
+	Info(msg string, keysAndValues ...interface{})
+	Error(err error, msg string, keysAndValues ...interface{})
+}
+
+// logrLogger logr 适配器
+type logrLogger struct {
+	l LogrLogger
+}
+
+// FromLogr 将 logr.Logger 适配为 Logger
+// logr 没有独立的 debug 级别，debug 和 info 日志都通过 Info 输出；传入 logger.V(1) 可降低其详细级别（Error 不受 V 影响）
+func FromLogr(l LogrLogger) Logger {
+	return logrLogger{l: l}
+}
+
+// Debugf 实现 Logger 接口
+func (g logrLogger) Debugf(format string, args ...interface{}) {
+	g.l.Info(fmt.Sprintf(format, args...))
+}
+
+// Infof 实现 Logger 接口
+func (g logrLogger) Infof(format string, args ...interface{}) {
+	g.l.Info(fmt.Sprintf(format, args...))
+}
+
+// Errorf 实现 Logger 接口
+func (g logrLogger) Errorf(format string, args ...interface{}) {
+	g.l.Error(nil, fmt.Sprintf(format, args...))
+}
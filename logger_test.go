@@ -0,0 +1,136 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithLogger 测试请求日志及脱敏
+func TestWithLogger(t *testing.T) {
+	server := newSuccessServer(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithLogger(FromSlog(logger)))
+
+	if _, err := client.ListTasks(context.Background(), &ListTasksOptions{Receiver: "13800138000"}); err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"level=DEBUG", "GET /api/v1/messages?receiver=138****8000", "status=200", "code=0", "duration="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q should contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "13800138000") || strings.Contains(out, "test_secret") {
+		t.Errorf("log should be redacted: %s", out)
+	}
+}
+
+// recordingLogr 记录 logr 风格调用
+type recordingLogr struct {
+	infos, errors []string
+}
+
+func (r *recordingLogr) Info(msg string, keysAndValues ...interface{}) {
+	r.infos = append(r.infos, msg)
+}
+func (r *recordingLogr) Error(err error, msg string, keysAndValues ...interface{}) {
+	r.errors = append(r.errors, msg)
+}
+
+// TestFromLogr 测试 logr 适配器
+func TestFromLogr(t *testing.T) {
+	rec := &recordingLogr{}
+	client := NewClient("http://127.0.0.1:1", "test_app_id", "test_secret", WithLogger(FromLogr(rec)))
+
+	if _, err := client.QueryTask(context.Background(), "t1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(rec.infos) != 1 || len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "request failed") {
+		t.Errorf("infos = %v, errors = %v", rec.infos, rec.errors)
+	}
+}
+
+// TestLogLevel 测试日志详细级别及运行时调整
+func TestLogLevel(t *testing.T) {
+	server, _ := newFlakyServer(t, 1, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithLogger(FromSlog(logger)), WithLogLevel(LogLevelWarn),
+		WithRetry(2, Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1}))
+
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", TemplateParams: map[string]interface{}{"code": "1234"}}
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "retrying attempt=2/2") {
+		t.Errorf("warn level should log retry decision: %s", out)
+	}
+	if strings.Contains(out, "level=INFO") || strings.Contains(out, "level=DEBUG") {
+		t.Errorf("warn level should not log attempts: %s", out)
+	}
+
+	buf.Reset()
+	client.SetLogLevel(LogLevelTrace)
+	if got := client.LogLevel(); got != LogLevelTrace {
+		t.Fatalf("LogLevel() = %v, want trace", got)
+	}
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	out = buf.String()
+	for _, want := range []string{"trace request POST /api/v1/messages", "canonical=", "138****8000", "trace response POST /api/v1/messages", "status=200"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("trace log should contain %q: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "13800138000") || strings.Contains(out, "test_secret") {
+		t.Errorf("trace log should be redacted: %s", out)
+	}
+
+	buf.Reset()
+	client.SetLogLevel(LogLevelError)
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("error level should not log successful requests: %s", buf.String())
+	}
+}
+
+// TestParseLogLevel 测试日志级别解析
+func TestParseLogLevel(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want LogLevel
+	}{{"error", LogLevelError}, {"WARNING", LogLevelWarn}, {" info ", LogLevelInfo}, {"debug", LogLevelDebug}, {"Trace", LogLevelTrace}} {
+		got, err := ParseLogLevel(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLogLevel(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseLogLevel("verbose"); err == nil {
+		t.Error("ParseLogLevel(verbose) should fail")
+	}
+
+	var p ProfileConfig
+	if err := json.Unmarshal([]byte(`{"log_level":"trace"}`), &p); err != nil || p.LogLevel != LogLevelTrace {
+		t.Errorf("unmarshal log_level = %v, %v", p.LogLevel, err)
+	}
+	if err := json.Unmarshal([]byte(`{"log_level":"loud"}`), &p); err == nil {
+		t.Error("unmarshal unknown log_level should fail")
+	}
+}
@@ -0,0 +1,91 @@
+package mlievpush
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LogLevel SDK 日志详细级别，级别越高输出越多，每个级别包含更低级别的全部日志
+type LogLevel int32
+
+// SDK 日志级别
+const (
+	LogLevelError LogLevel = iota + 1 // 只记录最终失败（Errorf）
+	LogLevelWarn                      // 另记录重试决策（Warnf，Logger 未实现时使用 Infof）
+	LogLevelInfo                      // 另记录失败的请求尝试（Infof）
+	LogLevelDebug                     // 另记录成功的请求尝试（Debugf），默认级别
+	LogLevelTrace                     // 另记录签名原文和脱敏后的完整请求体、响应体（Debugf），仅用于排查问题
+)
+
+// logLevelNames 日志级别名称
+var logLevelNames = map[LogLevel]string{
+	LogLevelError: "error",
+	LogLevelWarn:  "warn",
+	LogLevelInfo:  "info",
+	LogLevelDebug: "debug",
+	LogLevelTrace: "trace",
+}
+
+// String 实现 fmt.Stringer 接口
+func (l LogLevel) String() string {
+	if name, ok := logLevelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LogLevel(%d)", int32(l))
+}
+
+// ParseLogLevel 解析日志级别名称（error、warn、info、debug、trace，不区分大小写，"warning" 等同于 "warn"）
+func ParseLogLevel(s string) (LogLevel, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "warning" {
+		name = "warn"
+	}
+	for level, n := range logLevelNames {
+		if n == name {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口
+func (l LogLevel) MarshalText() ([]byte, error) {
+	if _, ok := logLevelNames[l]; !ok {
+		return nil, fmt.Errorf("unknown log level %d", int32(l))
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口，可直接用于 JSON/YAML 配置
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	level, err := ParseLogLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// WithLogLevel 设置 WithLogger 的日志详细级别，默认 LogLevelDebug
+func WithLogLevel(level LogLevel) ClientOption {
+	return func(c *Client) {
+		c.SetLogLevel(level)
+	}
+}
+
+// SetLogLevel 运行时调整日志详细级别，并发安全，可在配置热加载时调用；未知级别被忽略
+func (c *Client) SetLogLevel(level LogLevel) {
+	if _, ok := logLevelNames[level]; ok {
+		c.logLevel.Store(int32(level))
+	}
+}
+
+// LogLevel 返回当前的日志详细级别
+func (c *Client) LogLevel() LogLevel {
+	return LogLevel(c.logLevel.Load())
+}
+
+// logEnabled 判断是否输出指定级别的日志
+func (c *Client) logEnabled(level LogLevel) bool {
+	return c.logger != nil && c.LogLevel() >= level
+}
@@ -0,0 +1,292 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultQuotaPeriod 账号配额默认的计数窗口
+const DefaultQuotaPeriod = 24 * time.Hour
+
+// AccountQuota 账号发送配额（固定窗口计数），用于在本地限制营销等账号的发送量
+type AccountQuota struct {
+	Limit  int           // 窗口内最多发送的消息数（批量发送按接收者计），0 表示不限
+	Period time.Duration // 计数窗口，0 使用 DefaultQuotaPeriod
+}
+
+// QuotaError 账号本地配额已用尽（本地拦截，不会发送请求）
+type QuotaError struct {
+	Account string        // 账号名称
+	Limit   int           // 配额
+	Period  time.Duration // 计数窗口
+}
+
+// Error 实现 error 接口
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%v: account %q limit of %d per %s", ErrQuotaExceeded, e.Account, e.Limit, e.Period)
+}
+
+// Is 使 errors.Is(err, ErrQuotaExceeded) 成立
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// managedAccount 客户端管理器中的账号
+type managedAccount struct {
+	client *Client
+	quota  AccountQuota
+
+	mu          sync.Mutex
+	windowStart time.Time
+	used        int
+}
+
+// reserve 预占配额
+func (a *managedAccount) reserve(name string, n int) error {
+	if a.quota.Limit <= 0 {
+		return nil
+	}
+	period := a.quota.Period
+	if period <= 0 {
+		period = DefaultQuotaPeriod
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	if now.Sub(a.windowStart) >= period {
+		a.windowStart = now
+		a.used = 0
+	}
+	if a.used+n > a.quota.Limit {
+		return &QuotaError{Account: name, Limit: a.quota.Limit, Period: period}
+	}
+	a.used += n
+	return nil
+}
+
+// release 归还未使用的配额
+func (a *managedAccount) release(n int) {
+	if a.quota.Limit <= 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.used -= n; a.used < 0 {
+		a.used = 0
+	}
+}
+
+// ClientManager 多账号客户端管理器，按名称管理不同 appID/密钥 的客户端（如营销账号与通知账号分开）
+type ClientManager struct {
+	mu       sync.RWMutex
+	accounts map[string]*managedAccount
+}
+
+// NewClientManager 创建多账号客户端管理器
+func NewClientManager() *ClientManager {
+	return &ClientManager{accounts: make(map[string]*managedAccount)}
+}
+
+// Add 添加或替换账号，quota 为零值时不限制发送量
+func (m *ClientManager) Add(name string, client *Client, quota AccountQuota) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts[name] = &managedAccount{client: client, quota: quota}
+}
+
+// Remove 移除账号，账号不存在时不做任何操作；已在进行中的请求不受影响
+func (m *ClientManager) Remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.accounts, name)
+}
+
+// Client 获取账号的客户端
+func (m *ClientManager) Client(name string) (*Client, error) {
+	a, err := m.account(name)
+	if err != nil {
+		return nil, err
+	}
+	return a.client, nil
+}
+
+// Accounts 返回所有账号名称（按名称排序）
+func (m *ClientManager) Accounts() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.accounts))
+	for name := range m.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// account 获取账号
+func (m *ClientManager) account(name string) (*managedAccount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %q", name)
+	}
+	return a, nil
+}
+
+// SendMessage 通过指定账号发送单条消息，账号配额用尽时返回 *QuotaError
+// 发送失败时只在确定消息未被网关受理时归还配额
+func (m *ClientManager) SendMessage(ctx context.Context, account string, req *SendMessageRequest) (*SendMessageData, error) {
+	a, err := m.account(account)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.reserve(account, 1); err != nil {
+		return nil, err
+	}
+	data, err := a.client.SendMessage(ctx, req)
+	if err != nil {
+		if !possiblySent(err) {
+			a.release(1)
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// SendBatch 通过指定账号批量发送消息，按接收者数量计算配额
+// 发送失败时只归还确定未被网关受理的接收者的配额（见 possiblySent），超时等无法确定结果的失败不归还
+func (m *ClientManager) SendBatch(ctx context.Context, account string, req *SendBatchRequest) (*SendBatchData, error) {
+	a, err := m.account(account)
+	if err != nil {
+		return nil, err
+	}
+	n := len(req.Receivers)
+	if err := a.reserve(account, n); err != nil {
+		return nil, err
+	}
+	data, err := a.client.SendBatch(ctx, req)
+	a.release(a.client.unsentReceivers(n, data, err))
+	return data, err
+}
+
+// unsentReceivers 计算批量发送中确定未被网关受理的接收者数量，只有这部分配额会被归还
+// 部分失败时只归还失败的接收者；分片发送失败时归还未发出的分片
+func (c *Client) unsentReceivers(n int, data *SendBatchData, err error) int {
+	if data != nil {
+		// 成功或 *PartialFailureError：网关已明确拒绝失败的接收者
+		return data.FailedCount
+	}
+	var chunkErr *BatchChunkError
+	if errors.As(err, &chunkErr) {
+		done := chunkErr.Index
+		if possiblySent(chunkErr.Err) {
+			done++
+		}
+		if unsent := n - done*c.batchChunkSize; unsent > 0 {
+			return unsent
+		}
+		return 0
+	}
+	if possiblySent(err) {
+		return 0
+	}
+	return n
+}
+
+// possiblySent 判断失败的请求是否可能已被网关受理，这种情况下不归还配额，避免超发
+// 请求发出后的网络错误、超时或取消、HTTP 5xx 以及网关系统错误（熔断除外）无法确定消息是否已发送；
+// 本地校验、熔断、凭证等请求发出前的错误和网关明确拒绝的业务错误视为未发送
+func possiblySent(err error) bool {
+	if err == nil {
+		return false
+	}
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return timeoutErr.Attempts > 1 || !beforeWrite(timeoutErr.Phase)
+	}
+	var canceledErr *CanceledError
+	if errors.As(err, &canceledErr) {
+		return canceledErr.Attempts > 1 || !beforeWrite(canceledErr.Phase)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code/10000 == 4 && apiErr.Code != ErrCodeCircuitOpen && !apiErr.Simulated
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		// 2xx 响应体无法解析时网关可能已受理
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode < 300
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || isStreamInterrupted(err)
+}
+
+// beforeWrite 判断阶段是否在发出请求之前
+func beforeWrite(phase string) bool {
+	switch phase {
+	case PhaseRateLimit, PhaseDNS, PhaseConnect, PhaseTLSHandshake:
+		return true
+	default:
+		return false
+	}
+}
+
+// FanOutResult 多账号发送结果，按账号名称索引
+type FanOutResult struct {
+	Results map[string]*SendMessageData // 发送成功的账号 -> 响应数据
+	Errors  map[string]error            // 发送失败的账号 -> 错误
+}
+
+// FanOut 通过多个账号并发发送同一条消息，accounts 为空时使用所有账号
+// 各账号独立计算配额和错误，部分账号失败不影响其他账号；请求中的通道ID需在各账号下有效
+func (m *ClientManager) FanOut(ctx context.Context, req *SendMessageRequest, accounts ...string) *FanOutResult {
+	if len(accounts) == 0 {
+		accounts = m.Accounts()
+	}
+
+	result := &FanOutResult{
+		Results: make(map[string]*SendMessageData),
+		Errors:  make(map[string]error),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range accounts {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			data, err := m.SendMessage(ctx, name, req)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Errors[name] = err
+				return
+			}
+			result.Results[name] = data
+		}(name)
+	}
+	wg.Wait()
+	return result
+}
+
+// NewClientManager 为配置文件中的每个命名配置创建一个账号，账号名称即配置名，opts 应用于所有客户端
+func (c *Config) NewClientManager(opts ...ClientOption) (*ClientManager, error) {
+	m := NewClientManager()
+	for name, p := range c.Profiles {
+		client, err := p.NewClient(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		var quota AccountQuota
+		if q := p.Quota; q != nil {
+			quota = AccountQuota{Limit: q.Limit, Period: time.Duration(q.Period)}
+		}
+		m.Add(name, client, quota)
+	}
+	return m, nil
+}
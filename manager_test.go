@@ -0,0 +1,108 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestClientManagerFanOut 测试多账号发送和账号配额
+func TestClientManagerFanOut(t *testing.T) {
+	server := newSuccessServer(t)
+
+	m := NewClientManager()
+	m.Add("transactional", NewClient(server.URL, "app_tx", "secret_tx"), AccountQuota{})
+	m.Add("marketing", NewClient(server.URL, "app_mkt", "secret_mkt"), AccountQuota{Limit: 1, Period: time.Hour})
+
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", SignatureName: "test"}
+	ctx := context.Background()
+
+	result := m.FanOut(ctx, req)
+	if len(result.Results) != 2 || len(result.Errors) != 0 {
+		t.Fatalf("FanOut() = %+v", result)
+	}
+
+	result = m.FanOut(ctx, req, "transactional", "marketing", "unknown")
+	if result.Results["transactional"] == nil {
+		t.Errorf("transactional error = %v", result.Errors["transactional"])
+	}
+	var quotaErr *QuotaError
+	if err := result.Errors["marketing"]; !errors.As(err, &quotaErr) || !errors.Is(err, ErrQuotaExceeded) || quotaErr.Account != "marketing" {
+		t.Errorf("marketing error = %v, want *QuotaError", err)
+	}
+	if result.Errors["unknown"] == nil {
+		t.Error("unknown account should fail")
+	}
+
+	if got := m.Accounts(); len(got) != 2 || got[0] != "marketing" {
+		t.Errorf("Accounts() = %v", got)
+	}
+}
+
+// TestClientManagerQuotaRelease 测试发送失败时归还配额
+func TestClientManagerQuotaRelease(t *testing.T) {
+	server := newSuccessServer(t)
+
+	m := NewClientManager()
+	m.Add("marketing", NewClient(server.URL, "app", "secret", WithChannelType(1, MessageTypeSMS)), AccountQuota{Limit: 1})
+	ctx := context.Background()
+
+	if _, err := m.SendMessage(ctx, "marketing", &SendMessageRequest{ChannelID: 1, Receiver: "bad", SignatureName: "test"}); err == nil {
+		t.Fatal("SendMessage(invalid receiver) error = nil")
+	}
+	if _, err := m.SendMessage(ctx, "marketing", &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", SignatureName: "test"}); err != nil {
+		t.Errorf("SendMessage() error = %v, quota should have been released", err)
+	}
+	if _, err := m.SendBatch(ctx, "marketing", &SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000"}, SignatureName: "test"}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("SendBatch() error = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+// TestClientManagerQuotaKeptWhenPossiblySent 测试结果不确定时不归还配额，部分失败只归还失败的接收者
+func TestClientManagerQuotaKeptWhenPossiblySent(t *testing.T) {
+	var resp map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	m := NewClientManager()
+	m.Add("marketing", NewClient(server.URL, "app", "secret", WithPartialFailureError(true)), AccountQuota{Limit: 5})
+	ctx := context.Background()
+	used := func() int {
+		a, _ := m.account("marketing")
+		return a.used
+	}
+
+	// 服务商错误时消息可能已提交，不归还
+	resp = map[string]interface{}{"code": ErrCodeProviderError, "message": "服务商错误"}
+	if _, err := m.SendMessage(ctx, "marketing", &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", SignatureName: "test"}); err == nil {
+		t.Fatal("SendMessage() error = nil")
+	}
+	if used() != 1 {
+		t.Errorf("used = %d after provider error, want 1", used())
+	}
+
+	// 网关明确拒绝时归还
+	resp = map[string]interface{}{"code": ErrCodeTemplateNotFound, "message": "模板不存在"}
+	m.SendMessage(ctx, "marketing", &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", SignatureName: "test"})
+	if used() != 1 {
+		t.Errorf("used = %d after rejection, want 1", used())
+	}
+
+	// 部分失败只归还失败的接收者
+	resp = map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{
+		"batch_id": "b1", "total_count": 3, "success_count": 2, "failed_count": 1,
+	}}
+	_, err := m.SendBatch(ctx, "marketing", &SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000", "13800138001", "13800138002"}, SignatureName: "test"})
+	if !errors.Is(err, ErrPartialFailure) {
+		t.Fatalf("SendBatch() error = %v, want ErrPartialFailure", err)
+	}
+	if used() != 3 {
+		t.Errorf("used = %d after partial failure, want 3", used())
+	}
+}
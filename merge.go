@@ -0,0 +1,123 @@
+package mlievpush
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultMergeReceiverColumn CSV 合并时默认的接收者列名
+const DefaultMergeReceiverColumn = "receiver"
+
+// MergeOptions CSV 合并配置
+type MergeOptions struct {
+	ReceiverColumn string // 接收者列名，为空时使用 DefaultMergeReceiverColumn
+	AllowEmpty     bool   // 是否允许变量值为空字符串，默认空值视为缺失
+	Comma          rune   // 字段分隔符，0 使用逗号
+}
+
+// MergeRowError CSV 中某一行缺少模板变量（本地校验，不会发送请求），可用 errors.Is(err, ErrInvalidRequest) 判断
+type MergeRowError struct {
+	Line     int      // 行号（从1开始，含表头）
+	Receiver string   // 接收者
+	Missing  []string // 缺少或为空的模板变量
+}
+
+// Error 实现 error 接口，接收者已脱敏
+func (e *MergeRowError) Error() string {
+	return fmt.Sprintf("line %d (receiver %q): missing template params %s",
+		e.Line, DefaultRedactor.RedactReceiver(e.Receiver), strings.Join(e.Missing, ", "))
+}
+
+// Is 使 errors.Is(err, ErrInvalidRequest) 成立
+func (e *MergeRowError) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// MergeTemplateCSV 将每行一个接收者及其变量的 CSV 与模板合并，生成个性化的单条发送请求（邮件合并）
+// CSV 首行为表头：接收者列（见 MergeOptions.ReceiverColumn）之外的列都作为模板参数，base.TemplateParams 作为所有行共用的默认值
+// base 的其余字段复制到每个请求，ChannelID 为 0 时使用模板的通道
+// 每一行都必须提供模板的全部参数（tmpl.Params），否则返回包含所有问题行 *MergeRowError 的错误且不返回任何请求，
+// 避免活动只发出一部分；返回的请求可交给 SendAll、SendStream 或 BatchSender 发送
+func MergeTemplateCSV(r io.Reader, tmpl *Template, base SendMessageRequest, opts MergeOptions) ([]*SendMessageRequest, error) {
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("merge csv: missing header")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("merge csv: read header: %w", err)
+	}
+
+	receiverColumn := opts.ReceiverColumn
+	if receiverColumn == "" {
+		receiverColumn = DefaultMergeReceiverColumn
+	}
+	receiverIndex := -1
+	for i, name := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if header[i] == receiverColumn {
+			receiverIndex = i
+		}
+	}
+	if receiverIndex < 0 {
+		return nil, fmt.Errorf("merge csv: receiver column %q not found", receiverColumn)
+	}
+
+	if base.ChannelID == 0 {
+		base.ChannelID = tmpl.ChannelID
+	}
+
+	var (
+		reqs    []*SendMessageRequest
+		rowErrs []error
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("merge csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		params := make(map[string]interface{}, len(base.TemplateParams)+len(header)-1)
+		for k, v := range base.TemplateParams {
+			params[k] = v
+		}
+		for i, value := range record {
+			if i == receiverIndex || header[i] == "" || (value == "" && !opts.AllowEmpty) {
+				continue
+			}
+			params[header[i]] = value
+		}
+
+		receiver := strings.TrimSpace(record[receiverIndex])
+		missing := tmpl.MissingParams(params)
+		if receiver == "" {
+			missing = append([]string{receiverColumn}, missing...)
+		}
+		if len(missing) > 0 {
+			rowErrs = append(rowErrs, &MergeRowError{Line: line, Receiver: receiver, Missing: missing})
+			continue
+		}
+
+		req := base
+		req.Receiver = receiver
+		req.TemplateParams = params
+		reqs = append(reqs, &req)
+	}
+
+	if len(rowErrs) > 0 {
+		return nil, errors.Join(rowErrs...)
+	}
+	return reqs, nil
+}
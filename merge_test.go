@@ -0,0 +1,66 @@
+package mlievpush
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestMergeTemplateCSV 测试 CSV 与模板合并
+func TestMergeTemplateCSV(t *testing.T) {
+	tmpl := &Template{ChannelID: 2, Content: "${name}您好，优惠码${code}，${days}天内有效", Params: []string{"name", "code", "days"}}
+	csvData := "\ufeffreceiver,name,code\n13800138000,张三,A1\n13900139000, 李四 ,B2\n"
+	base := SendMessageRequest{SignatureName: "公司", TemplateParams: map[string]interface{}{"days": "7"}}
+
+	reqs, err := MergeTemplateCSV(strings.NewReader(csvData), tmpl, base, MergeOptions{})
+	if err != nil {
+		t.Fatalf("MergeTemplateCSV() error = %v", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("len(reqs) = %d, want 2", len(reqs))
+	}
+	if r := reqs[1]; r.Receiver != "13900139000" || r.ChannelID != 2 || r.SignatureName != "公司" ||
+		r.TemplateParams["name"] != "李四 " || r.TemplateParams["code"] != "B2" || r.TemplateParams["days"] != "7" {
+		t.Errorf("unexpected request: %+v", r)
+	}
+	if got := tmpl.Render(reqs[0].TemplateParams); got != "张三您好，优惠码A1，7天内有效" {
+		t.Errorf("Render() = %q", got)
+	}
+	if len(base.TemplateParams) != 1 {
+		t.Errorf("base params modified: %v", base.TemplateParams)
+	}
+}
+
+// TestMergeTemplateCSVErrors 测试缺少变量的行和表头错误
+func TestMergeTemplateCSVErrors(t *testing.T) {
+	tmpl := &Template{Params: []string{"name", "code"}}
+	csvData := "phone;name;code\n13800138000;张三;A1\n13900139000;;B2\n;王五;C3\n"
+
+	reqs, err := MergeTemplateCSV(strings.NewReader(csvData), tmpl, SendMessageRequest{}, MergeOptions{ReceiverColumn: "phone", Comma: ';'})
+	if err == nil || reqs != nil {
+		t.Fatalf("expected error, got %v, %v", reqs, err)
+	}
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("errors.Is(err, ErrInvalidRequest) = false")
+	}
+	var rowErr *MergeRowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 3 || rowErr.Missing[0] != "name" {
+		t.Errorf("unexpected row error: %+v", rowErr)
+	}
+	if !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), "phone") {
+		t.Errorf("error = %v", err)
+	}
+
+	reqs, err = MergeTemplateCSV(strings.NewReader("phone;name;code\n13900139000;;B2\n"), tmpl, SendMessageRequest{},
+		MergeOptions{ReceiverColumn: "phone", Comma: ';', AllowEmpty: true})
+	if err != nil || reqs[0].TemplateParams["name"] != "" {
+		t.Errorf("AllowEmpty: %v, %v", reqs, err)
+	}
+
+	if _, err := MergeTemplateCSV(strings.NewReader("mobile,name\n"), tmpl, SendMessageRequest{}, MergeOptions{}); err == nil {
+		t.Error("expected missing receiver column error")
+	}
+	if _, err := MergeTemplateCSV(strings.NewReader("receiver,name,code\n1,a\n"), tmpl, SendMessageRequest{}, MergeOptions{}); err == nil {
+		t.Error("expected malformed row error")
+	}
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// retryKey 重试计数的标签组合
+type retryKey struct {
+	channelID int
+	reason    string
+	retry     bool
+}
+
+// Retries 按通道和决策原因统计SDK内部的重试决策，并发安全
+// 请求延迟升高时，对比重试次数与网关耗时即可判断是SDK在内部重试还是网关本身变慢
+type Retries struct {
+	mu      sync.Mutex
+	counts  map[retryKey]int64
+	backoff map[int]time.Duration
+}
+
+// NewRetries 创建重试统计
+func NewRetries() *Retries {
+	return &Retries{
+		counts:  make(map[retryKey]int64),
+		backoff: make(map[int]time.Duration),
+	}
+}
+
+// Hooks 返回用于 mlievpush.WithHooks 的钩子，自动统计重试决策
+func (r *Retries) Hooks() mlievpush.Hooks {
+	return mlievpush.Hooks{
+		OnRetry: func(ctx context.Context, info *mlievpush.RetryInfo) {
+			r.Observe(info)
+		},
+	}
+}
+
+// Observe 记录一次重试决策
+func (r *Retries) Observe(info *mlievpush.RetryInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counts[retryKey{channelID: info.ChannelID, reason: info.Reason, retry: info.Retry}]++
+	r.backoff[info.ChannelID] += info.Backoff
+}
+
+// Count 获取通道按原因统计的决策次数，retry 区分重试与放弃
+func (r *Retries) Count(channelID int, reason string, retry bool) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[retryKey{channelID: channelID, reason: reason, retry: retry}]
+}
+
+// WriteTo 以 Prometheus 文本格式输出指标
+func (r *Retries) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]retryKey, 0, len(r.counts))
+	for k := range r.counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].channelID != keys[j].channelID {
+			return keys[i].channelID < keys[j].channelID
+		}
+		if keys[i].reason != keys[j].reason {
+			return keys[i].reason < keys[j].reason
+		}
+		return !keys[i].retry && keys[j].retry
+	})
+	channels := make([]int, 0, len(r.backoff))
+	for id := range r.backoff {
+		channels = append(channels, id)
+	}
+	sort.Ints(channels)
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	fmt.Fprintln(cw, "# HELP mlievpush_retry_decisions_total Retry decisions made by the SDK after a failed attempt.")
+	fmt.Fprintln(cw, "# TYPE mlievpush_retry_decisions_total counter")
+	for _, k := range keys {
+		fmt.Fprintf(cw, "mlievpush_retry_decisions_total{channel=\"%d\",reason=\"%s\",retry=\"%t\"} %d\n",
+			k.channelID, k.reason, k.retry, r.counts[k])
+	}
+
+	fmt.Fprintln(cw, "# HELP mlievpush_retry_backoff_seconds_total Total time the SDK waited before retrying.")
+	fmt.Fprintln(cw, "# TYPE mlievpush_retry_backoff_seconds_total counter")
+	for _, id := range channels {
+		fmt.Fprintf(cw, "mlievpush_retry_backoff_seconds_total{channel=\"%d\"} %s\n",
+			id, strconv.FormatFloat(r.backoff[id].Seconds(), 'g', -1, 64))
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP 实现 http.Handler 接口，可直接作为抓取端点
+func (r *Retries) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// TestRetries 测试重试决策统计和 Prometheus 文本输出
+func TestRetries(t *testing.T) {
+	r := NewRetries()
+	hooks := r.Hooks()
+	ctx := context.Background()
+
+	hooks.OnRetry(ctx, &mlievpush.RetryInfo{ChannelID: 3, Retry: true, Reason: mlievpush.RetryReasonNetwork, Backoff: 200 * time.Millisecond})
+	hooks.OnRetry(ctx, &mlievpush.RetryInfo{ChannelID: 3, Retry: true, Reason: mlievpush.RetryReasonNetwork, Backoff: 400 * time.Millisecond})
+	hooks.OnRetry(ctx, &mlievpush.RetryInfo{ChannelID: 3, Reason: mlievpush.RetryReasonMaxAttempts})
+
+	if got := r.Count(3, mlievpush.RetryReasonNetwork, true); got != 2 {
+		t.Errorf("Count() = %d, want 2", got)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`mlievpush_retry_decisions_total{channel="3",reason="network",retry="true"} 2`,
+		`mlievpush_retry_decisions_total{channel="3",reason="max_attempts",retry="false"} 1`,
+		`mlievpush_retry_backoff_seconds_total{channel="3"} 0.6`,
+		"# TYPE mlievpush_retry_decisions_total counter",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("output missing %q:\n%s", want, body)
+		}
+	}
+}
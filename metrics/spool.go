@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// spoolStatser 提供暂存统计的客户端，*mlievpush.Client 实现了该接口
+type spoolStatser interface {
+	SpoolStats(ctx context.Context) (*mlievpush.SpoolStats, error)
+}
+
+// Spool 暂存积压指标，每次抓取时读取客户端的暂存统计
+// 暂存积压增长或最早消息的等待时间持续升高，说明网关长时间不可用或重发一直失败
+type Spool struct {
+	client spoolStatser
+	now    func() time.Time
+}
+
+// NewSpool 创建暂存指标，client 需设置 mlievpush.WithDegradation
+func NewSpool(client *mlievpush.Client) *Spool {
+	return &Spool{client: client, now: time.Now}
+}
+
+// WriteTo 以 Prometheus 文本格式输出指标
+func (s *Spool) WriteTo(w io.Writer) (int64, error) {
+	return s.write(context.Background(), w)
+}
+
+// write 读取暂存统计并输出指标
+func (s *Spool) write(ctx context.Context, w io.Writer) (int64, error) {
+	stats, err := s.client.SpoolStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	channels := make([]int, 0, len(stats.ByChannel))
+	for id := range stats.ByChannel {
+		channels = append(channels, id)
+	}
+	sort.Ints(channels)
+
+	var oldestAge float64
+	if !stats.Oldest.IsZero() {
+		oldestAge = s.now().Sub(stats.Oldest).Seconds()
+	}
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	fmt.Fprintln(cw, "# HELP mlievpush_spool_pending Messages waiting in the spool.")
+	fmt.Fprintln(cw, "# TYPE mlievpush_spool_pending gauge")
+	for _, id := range channels {
+		fmt.Fprintf(cw, "mlievpush_spool_pending{channel=\"%d\"} %d\n", id, stats.ByChannel[id])
+	}
+
+	fmt.Fprintln(cw, "# HELP mlievpush_spool_failing Spooled messages whose redelivery has failed at least once.")
+	fmt.Fprintln(cw, "# TYPE mlievpush_spool_failing gauge")
+	fmt.Fprintf(cw, "mlievpush_spool_failing %d\n", stats.Failing)
+
+	fmt.Fprintln(cw, "# HELP mlievpush_spool_oldest_age_seconds Age of the oldest spooled message.")
+	fmt.Fprintln(cw, "# TYPE mlievpush_spool_oldest_age_seconds gauge")
+	fmt.Fprintf(cw, "mlievpush_spool_oldest_age_seconds %s\n", strconv.FormatFloat(oldestAge, 'g', -1, 64))
+
+	counters := []struct {
+		name, help string
+		value      int64
+	}{
+		{"mlievpush_spool_spooled_total", "Messages written to the spool.", stats.Spooled},
+		{"mlievpush_spool_drained_total", "Spooled messages delivered on redelivery.", stats.Drained},
+		{"mlievpush_spool_dropped_total", "Spooled messages removed after a permanent failure or expiry.", stats.Dropped},
+	}
+	for _, m := range counters {
+		fmt.Fprintf(cw, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(cw, "# TYPE %s counter\n", m.name)
+		fmt.Fprintf(cw, "%s %d\n", m.name, m.value)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP 实现 http.Handler 接口，可直接作为抓取端点
+func (s *Spool) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var buf bytes.Buffer
+	if _, err := s.write(req.Context(), &buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	buf.WriteTo(w)
+}
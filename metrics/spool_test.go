@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// fakeSpoolStats 测试用的暂存统计
+type fakeSpoolStats struct {
+	stats *mlievpush.SpoolStats
+}
+
+// SpoolStats 实现 spoolStatser 接口
+func (f *fakeSpoolStats) SpoolStats(ctx context.Context) (*mlievpush.SpoolStats, error) {
+	return f.stats, nil
+}
+
+// TestSpool 测试暂存积压指标的 Prometheus 文本输出
+func TestSpool(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := &Spool{
+		client: &fakeSpoolStats{stats: &mlievpush.SpoolStats{
+			Pending:   3,
+			ByChannel: map[int]int{2: 1, 1: 2},
+			Failing:   1,
+			Oldest:    now.Add(-90 * time.Second),
+			Spooled:   5,
+			Drained:   2,
+		}},
+		now: func() time.Time { return now },
+	}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`mlievpush_spool_pending{channel="1"} 2`,
+		`mlievpush_spool_pending{channel="2"} 1`,
+		"mlievpush_spool_failing 1",
+		"mlievpush_spool_oldest_age_seconds 90",
+		"mlievpush_spool_spooled_total 5",
+		"mlievpush_spool_dropped_total 0",
+		"# TYPE mlievpush_spool_drained_total counter",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("output missing %q:\n%s", want, body)
+		}
+	}
+}
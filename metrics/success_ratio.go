@@ -0,0 +1,212 @@
+// Package metrics 面向告警的SDK指标
+//
+// SuccessRatio 按通道统计滑动窗口内的请求成功率，并以 Prometheus 文本格式输出，
+// 可直接用于告警规则（如 mlievpush_channel_success_ratio{window="5m"} < 0.95），无需额外的 recording rules。
+//
+// Retries 按通道和原因统计SDK内部的重试决策，用于区分延迟升高是SDK重试还是网关变慢。
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// DefaultWindows 默认统计窗口
+var DefaultWindows = []time.Duration{5 * time.Minute, 30 * time.Minute, time.Hour}
+
+// DefaultResolution 默认统计精度（桶宽度）
+const DefaultResolution = 10 * time.Second
+
+// bucket 单个时间桶的计数
+type bucket struct {
+	index   int64 // 桶序号（时间 / 精度）
+	success int64 // 成功数
+	total   int64 // 总数
+}
+
+// series 单个通道的时间桶环
+type series struct {
+	buckets []bucket
+}
+
+// SuccessRatio 按通道统计滑动窗口成功率，并发安全
+type SuccessRatio struct {
+	mu         sync.Mutex
+	resolution time.Duration
+	windows    []time.Duration
+	channels   map[int]*series
+	now        func() time.Time
+}
+
+// NewSuccessRatio 创建成功率统计，windows 为空时使用 DefaultWindows
+func NewSuccessRatio(windows ...time.Duration) *SuccessRatio {
+	if len(windows) == 0 {
+		windows = DefaultWindows
+	}
+	windows = append([]time.Duration(nil), windows...)
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+
+	return &SuccessRatio{
+		resolution: DefaultResolution,
+		windows:    windows,
+		channels:   make(map[int]*series),
+		now:        time.Now,
+	}
+}
+
+// Hooks 返回用于 mlievpush.WithHooks 的钩子，自动统计发送类请求
+func (s *SuccessRatio) Hooks() mlievpush.Hooks {
+	return mlievpush.Hooks{
+		OnResponse: func(ctx context.Context, info *mlievpush.ResponseInfo) {
+			if info.ChannelID != 0 {
+				s.Observe(info.ChannelID, info.Err == nil)
+			}
+		},
+	}
+}
+
+// Observe 记录一次请求结果
+func (s *SuccessRatio) Observe(channelID int, success bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ser, ok := s.channels[channelID]
+	if !ok {
+		size := int(s.windows[len(s.windows)-1]/s.resolution) + 1
+		ser = &series{buckets: make([]bucket, size)}
+		s.channels[channelID] = ser
+	}
+
+	index := s.now().UnixNano() / int64(s.resolution)
+	b := &ser.buckets[index%int64(len(ser.buckets))]
+	if b.index != index {
+		*b = bucket{index: index}
+	}
+	b.total++
+	if success {
+		b.success++
+	}
+}
+
+// Ratio 获取通道在窗口内的成功率和请求数，窗口内无请求时成功率为 NaN
+func (s *SuccessRatio) Ratio(channelID int, window time.Duration) (float64, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ratio(channelID, window)
+}
+
+// ratio 计算成功率（调用方需持有锁）
+func (s *SuccessRatio) ratio(channelID int, window time.Duration) (float64, int64) {
+	ser, ok := s.channels[channelID]
+	if !ok {
+		return math.NaN(), 0
+	}
+
+	current := s.now().UnixNano() / int64(s.resolution)
+	oldest := current - int64(window/s.resolution) + 1
+
+	var success, total int64
+	for _, b := range ser.buckets {
+		if b.index >= oldest && b.index <= current {
+			success += b.success
+			total += b.total
+		}
+	}
+	if total == 0 {
+		return math.NaN(), 0
+	}
+	return float64(success) / float64(total), total
+}
+
+// WriteTo 以 Prometheus 文本格式输出指标
+func (s *SuccessRatio) WriteTo(w io.Writer) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channels := make([]int, 0, len(s.channels))
+	for id := range s.channels {
+		channels = append(channels, id)
+	}
+	sort.Ints(channels)
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	fmt.Fprintln(cw, "# HELP mlievpush_channel_success_ratio Success ratio of push requests per channel over a sliding window.")
+	fmt.Fprintln(cw, "# TYPE mlievpush_channel_success_ratio gauge")
+	for _, id := range channels {
+		for _, window := range s.windows {
+			ratio, _ := s.ratio(id, window)
+			fmt.Fprintf(cw, "mlievpush_channel_success_ratio{channel=\"%d\",window=\"%s\"} %s\n",
+				id, formatWindow(window), formatFloat(ratio))
+		}
+	}
+
+	fmt.Fprintln(cw, "# HELP mlievpush_channel_window_requests Number of push requests per channel over a sliding window.")
+	fmt.Fprintln(cw, "# TYPE mlievpush_channel_window_requests gauge")
+	for _, id := range channels {
+		for _, window := range s.windows {
+			_, total := s.ratio(id, window)
+			fmt.Fprintf(cw, "mlievpush_channel_window_requests{channel=\"%d\",window=\"%s\"} %d\n",
+				id, formatWindow(window), total)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP 实现 http.Handler 接口，可直接作为抓取端点
+func (s *SuccessRatio) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.WriteTo(w)
+}
+
+// formatWindow 将窗口格式化为 Prometheus 风格的时长（如 5m、1h）
+func formatWindow(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return strconv.FormatInt(int64(d/time.Hour), 10) + "h"
+	case d%time.Minute == 0:
+		return strconv.FormatInt(int64(d/time.Minute), 10) + "m"
+	default:
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+}
+
+// formatFloat 按 Prometheus 文本格式输出浮点数
+func formatFloat(v float64) string {
+	if math.IsNaN(v) {
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// countingWriter 统计写入字节数
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+// Write 实现 io.Writer 接口
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
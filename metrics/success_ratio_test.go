@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// TestSuccessRatio 测试滑动窗口成功率
+func TestSuccessRatio(t *testing.T) {
+	s := NewSuccessRatio(time.Minute, 5*time.Minute)
+	now := time.Date(2025, 11, 25, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if ratio, total := s.Ratio(1, time.Minute); !math.IsNaN(ratio) || total != 0 {
+		t.Errorf("empty ratio = %v/%d, want NaN/0", ratio, total)
+	}
+
+	for i := 0; i < 8; i++ {
+		s.Observe(1, true)
+	}
+	s.Observe(1, false)
+	s.Observe(1, false)
+
+	// 4分钟后，1分钟窗口内只有新请求
+	now = now.Add(4 * time.Minute)
+	s.Observe(1, false)
+
+	if ratio, total := s.Ratio(1, time.Minute); ratio != 0 || total != 1 {
+		t.Errorf("1m ratio = %v/%d, want 0/1", ratio, total)
+	}
+	if ratio, total := s.Ratio(1, 5*time.Minute); total != 11 || math.Abs(ratio-8.0/11) > 1e-9 {
+		t.Errorf("5m ratio = %v/%d, want %v/11", ratio, total, 8.0/11)
+	}
+
+	// 超出最大窗口的数据被淘汰
+	now = now.Add(10 * time.Minute)
+	if _, total := s.Ratio(1, 5*time.Minute); total != 0 {
+		t.Errorf("expired total = %d, want 0", total)
+	}
+}
+
+// TestSuccessRatioExposition 测试 Prometheus 文本输出和钩子
+func TestSuccessRatioExposition(t *testing.T) {
+	s := NewSuccessRatio(5 * time.Minute)
+	hooks := s.Hooks()
+	ctx := context.Background()
+
+	hooks.OnResponse(ctx, &mlievpush.ResponseInfo{ChannelID: 3})
+	hooks.OnResponse(ctx, &mlievpush.ResponseInfo{ChannelID: 3, Err: errors.New("timeout")})
+	hooks.OnResponse(ctx, &mlievpush.ResponseInfo{Path: "/api/v1/messages/x"}) // 非发送请求不统计
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+
+	for _, want := range []string{
+		`mlievpush_channel_success_ratio{channel="3",window="5m"} 0.5`,
+		`mlievpush_channel_window_requests{channel="3",window="5m"} 2`,
+		"# TYPE mlievpush_channel_success_ratio gauge",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("output missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `channel="0"`) {
+		t.Errorf("non-send requests should not be counted:\n%s", body)
+	}
+}
@@ -0,0 +1,405 @@
+// Package mlievpushtest 提供用于测试的模拟推送网关
+//
+// Server 基于 httptest 启动，默认校验请求签名、记录收到的请求，
+// 并对发送、批量发送、查询任务返回合理的默认响应；也可以按路由编排响应，
+// 用于测试业务代码对错误码、限流、HTTP 错误的处理。
+//
+//	srv := mlievpushtest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	srv.Enqueue(http.MethodPost, "/api/v1/messages", mlievpushtest.Error(mlievpush.ErrCodeRateLimitExceeded))
+//	_, err := client.SendMessage(ctx, req) // errors.Is(err, mlievpush.ErrRateLimited)
+package mlievpushtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// 默认凭证
+const (
+	DefaultAppID     = "test_app_id"
+	DefaultAppSecret = "test_secret"
+)
+
+// maxUploadSize 签名校验允许的请求体大小，需容纳附件上传
+const maxUploadSize = 64 << 20
+
+// Request 模拟网关收到的请求
+type Request struct {
+	Method string      // 请求方法
+	Path   string      // 请求路径（不含查询参数）
+	Query  string      // 查询参数
+	Header http.Header // 请求头
+	Body   []byte      // 原始请求体
+
+	VerifyErr error // 签名校验错误，校验通过或未启用校验时为nil
+}
+
+// Decode 将请求体解析到 v
+func (r *Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Response 模拟网关的响应
+type Response struct {
+	Status  int         // HTTP状态码，0表示200
+	Code    int         // 业务状态码，0表示成功
+	Message string      // 状态描述，为空时按错误码填充
+	Data    interface{} // 响应数据，序列化为JSON
+
+	Header http.Header   // 附加的响应头（如 Retry-After）
+	Delay  time.Duration // 响应前的延迟，用于测试超时
+	Raw    []byte        // 原样返回的响应体，非nil时忽略 Code、Message、Data
+}
+
+// OK 返回成功响应
+func OK(data interface{}) Response {
+	return Response{Data: data}
+}
+
+// Error 返回业务错误响应，消息按错误码填充
+func Error(code int) Response {
+	return Response{Code: code, Message: mlievpush.GetErrorMessage(code)}
+}
+
+// Status 返回指定HTTP状态码的响应（如 502 网关错误），响应体为原始文本
+func Status(status int, body string) Response {
+	return Response{Status: status, Raw: []byte(body)}
+}
+
+// HandlerFunc 按请求生成响应
+type HandlerFunc func(req *Request) Response
+
+// Option 模拟网关配置选项
+type Option func(*Server)
+
+// WithCredentials 设置网关接受的应用ID和密钥
+func WithCredentials(appID, appSecret string) Option {
+	return func(s *Server) {
+		s.AppID = appID
+		s.AppSecret = appSecret
+	}
+}
+
+// WithoutSignatureVerification 关闭签名校验，适用于测试签名以外的逻辑或使用自定义签名的客户端
+func WithoutSignatureVerification() Option {
+	return func(s *Server) {
+		s.verify = false
+	}
+}
+
+// route 路由键
+type route struct {
+	method string
+	path   string
+}
+
+// Server 模拟推送网关
+type Server struct {
+	URL       string // 网关地址
+	AppID     string // 接受的应用ID
+	AppSecret string // 接受的应用密钥
+
+	server   *httptest.Server
+	verifier *mlievpush.Verifier
+	verify   bool
+
+	mu       sync.Mutex
+	requests []*Request
+	handlers map[route]HandlerFunc
+	queued   map[route][]Response
+	tasks    map[string]*mlievpush.QueryTaskData
+	seq      int
+}
+
+// NewServer 启动模拟网关，使用完毕后需调用 Close
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		AppID:     DefaultAppID,
+		AppSecret: DefaultAppSecret,
+		verify:    true,
+		handlers:  make(map[route]HandlerFunc),
+		queued:    make(map[route][]Response),
+		tasks:     make(map[string]*mlievpush.QueryTaskData),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.verifier = mlievpush.NewVerifier(func(ctx context.Context, appID string) (string, error) {
+		if appID != s.AppID {
+			return "", mlievpush.ErrUnknownApp
+		}
+		return s.AppSecret, nil
+	}, mlievpush.WithMaxBodySize(maxUploadSize))
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close 关闭模拟网关
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client 创建指向模拟网关、使用网关凭证的客户端
+func (s *Server) Client(opts ...mlievpush.ClientOption) *mlievpush.Client {
+	return mlievpush.NewClient(s.URL, s.AppID, s.AppSecret, opts...)
+}
+
+// Handle 设置路由的响应函数，覆盖默认行为
+func (s *Server) Handle(method, path string, fn HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[route{method, path}] = fn
+}
+
+// Enqueue 追加路由的一次性响应，按顺序消费，用完后恢复 Handle 设置的或默认的行为
+func (s *Server) Enqueue(method, path string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := route{method, path}
+	s.queued[key] = append(s.queued[key], responses...)
+}
+
+// SetTaskStatus 设置任务状态，影响默认的任务查询响应
+func (s *Server) SetTaskStatus(taskID, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[taskID]
+	if !ok {
+		task = &mlievpush.QueryTaskData{TaskID: taskID, AppID: s.AppID}
+		s.tasks[taskID] = task
+	}
+	task.Status = status
+}
+
+// Requests 返回收到的全部请求（按接收顺序）
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Request(nil), s.requests...)
+}
+
+// LastRequest 返回最后收到的请求，没有时返回nil
+func (s *Server) LastRequest() *Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return nil
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// Reset 清空已记录的请求、编排的响应和任务
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.handlers = make(map[route]HandlerFunc)
+	s.queued = make(map[route][]Response)
+	s.tasks = make(map[string]*mlievpush.QueryTaskData)
+}
+
+// serveHTTP 校验签名、记录请求并写出响应
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	req := &Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Header: r.Header.Clone()}
+
+	if s.verify {
+		if _, err := s.verifier.Verify(r); err != nil {
+			req.VerifyErr = err
+		}
+	}
+	req.Body, _ = io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	var resp Response
+	if req.VerifyErr != nil {
+		resp = verifyErrorResponse(req.VerifyErr)
+	} else {
+		resp = s.respond(req)
+	}
+	s.write(w, r, resp)
+}
+
+// respond 按一次性响应、自定义响应函数、默认行为的顺序生成响应
+func (s *Server) respond(req *Request) Response {
+	key := route{req.Method, req.Path}
+
+	s.mu.Lock()
+	if queue := s.queued[key]; len(queue) > 0 {
+		s.queued[key] = queue[1:]
+		s.mu.Unlock()
+		return queue[0]
+	}
+	fn := s.handlers[key]
+	s.mu.Unlock()
+
+	if fn != nil {
+		return fn(req)
+	}
+	return s.defaultResponse(req)
+}
+
+// defaultResponse 默认行为：发送创建任务、批量发送返回全部入队（请求逐条结果时为每个接收者创建任务）、查询返回已创建的任务
+func (s *Server) defaultResponse(req *Request) Response {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	switch {
+	case req.Method == http.MethodPost && req.Path == "/api/v1/messages":
+		var body mlievpush.SendMessageRequest
+		if err := req.Decode(&body); err != nil {
+			return Error(mlievpush.ErrCodeInvalidJSON)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.seq++
+		task := &mlievpush.QueryTaskData{
+			ID:             s.seq,
+			TaskID:         fmt.Sprintf("task-%d", s.seq),
+			AppID:          s.AppID,
+			ChannelID:      body.ChannelID,
+			Receiver:       body.Receiver,
+			Status:         mlievpush.TaskStatusPending,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			SignatureName:  body.SignatureName,
+			TemplateParams: body.TemplateParams,
+			IdempotencyKey: body.IdempotencyKey,
+			CallbackURL:    body.CallbackURL,
+		}
+		s.tasks[task.TaskID] = task
+		return OK(mlievpush.SendMessageData{TaskID: task.TaskID, Status: task.Status, CreatedAt: now})
+
+	case req.Method == http.MethodPost && req.Path == "/api/v1/messages/batch":
+		var body mlievpush.SendBatchRequest
+		if err := req.Decode(&body); err != nil {
+			return Error(mlievpush.ErrCodeInvalidJSON)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.seq++
+		data := mlievpush.SendBatchData{
+			BatchID:      fmt.Sprintf("batch-%d", s.seq),
+			TotalCount:   len(body.Receivers),
+			SuccessCount: len(body.Receivers),
+			CreatedAt:    now,
+		}
+		if strings.Contains(req.Query, "detail=true") {
+			// 逐条结果：为每个接收者创建任务
+			for _, receiver := range body.Receivers {
+				s.seq++
+				task := &mlievpush.QueryTaskData{
+					ID:        s.seq,
+					TaskID:    fmt.Sprintf("task-%d", s.seq),
+					AppID:     s.AppID,
+					ChannelID: body.ChannelID,
+					Receiver:  receiver,
+					Status:    mlievpush.TaskStatusPending,
+					CreatedAt: now,
+					UpdatedAt: now,
+				}
+				s.tasks[task.TaskID] = task
+				data.Results = append(data.Results, mlievpush.BatchResult{
+					Receiver: receiver, Status: mlievpush.BatchResultAccepted, TaskID: task.TaskID,
+				})
+			}
+		}
+		return OK(data)
+
+	case req.Method == http.MethodGet && strings.HasPrefix(req.Path, "/api/v1/messages/"):
+		taskID := strings.TrimPrefix(req.Path, "/api/v1/messages/")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		task, ok := s.tasks[taskID]
+		if !ok || strings.Contains(taskID, "/") {
+			return Error(mlievpush.ErrCodeTaskNotFound)
+		}
+		return OK(task)
+	}
+
+	resp := Error(mlievpush.ErrCodeInvalidParams)
+	resp.Status = http.StatusNotFound
+	resp.Message = fmt.Sprintf("no handler for %s %s", req.Method, req.Path)
+	return resp
+}
+
+// verifyErrorResponse 签名校验失败时的响应，错误码与网关一致
+func verifyErrorResponse(err error) Response {
+	code := mlievpush.ErrCodeUnauthorized
+	switch {
+	case errors.Is(err, mlievpush.ErrUnknownApp):
+		code = mlievpush.ErrCodeInvalidAppID
+	case errors.Is(err, mlievpush.ErrTimestampSkew):
+		code = mlievpush.ErrCodeInvalidTimestamp
+	case errors.Is(err, mlievpush.ErrInvalidSignature):
+		code = mlievpush.ErrCodeInvalidSignature
+	case errors.Is(err, mlievpush.ErrInvalidRequest):
+		code = mlievpush.ErrCodeInvalidParams
+	}
+	resp := Response{Status: mlievpush.VerifyErrorStatus(err), Code: code, Message: err.Error()}
+	if code == mlievpush.ErrCodeInvalidTimestamp {
+		// 返回服务端时间，便于测试 WithClockSync
+		resp.Header = http.Header{mlievpush.HeaderServerTime: {strconv.FormatInt(time.Now().Unix(), 10)}}
+	}
+	return resp
+}
+
+// write 写出响应
+func (s *Server) write(w http.ResponseWriter, r *http.Request, resp Response) {
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	if resp.Raw != nil {
+		w.WriteHeader(status)
+		w.Write(resp.Raw)
+		return
+	}
+
+	message := resp.Message
+	if message == "" {
+		message = "success"
+		if resp.Code != 0 {
+			message = mlievpush.GetErrorMessage(resp.Code)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    resp.Code,
+		"message": message,
+		"data":    resp.Data,
+	})
+}
@@ -0,0 +1,118 @@
+package mlievpushtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	mlievpush "github.com/muleiwu/mliev-push-go"
+)
+
+// TestServerDefaults 测试默认响应和请求记录
+func TestServerDefaults(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	data, err := client.SendMessage(ctx, &mlievpush.SendMessageRequest{ChannelID: 1, Receiver: "13800138000"})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	req := srv.LastRequest()
+	if req == nil || req.Path != "/api/v1/messages" || req.VerifyErr != nil {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	var body mlievpush.SendMessageRequest
+	if err := req.Decode(&body); err != nil || body.Receiver != "13800138000" {
+		t.Errorf("Decode() = %+v, %v", body, err)
+	}
+
+	srv.SetTaskStatus(data.TaskID, mlievpush.TaskStatusSuccess)
+	task, err := client.QueryTask(ctx, data.TaskID)
+	if err != nil {
+		t.Fatalf("QueryTask() error = %v", err)
+	}
+	if task.Status != mlievpush.TaskStatusSuccess || task.Receiver != "13800138000" {
+		t.Errorf("unexpected task: %+v", task)
+	}
+
+	if _, err := client.QueryTask(ctx, "missing"); !errors.Is(err, mlievpush.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+
+	batch, err := client.SendBatch(ctx, &mlievpush.SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000", "13900139000"}})
+	if err != nil || batch.SuccessCount != 2 {
+		t.Errorf("SendBatch() = %+v, %v", batch, err)
+	}
+	if got := len(srv.Requests()); got != 4 {
+		t.Errorf("expected 4 recorded requests, got %d", got)
+	}
+
+	detailed, err := client.SendBatchDetailed(ctx, &mlievpush.SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000"}})
+	if err != nil || len(detailed.Results) != 1 {
+		t.Fatalf("SendBatchDetailed() = %+v, %v", detailed, err)
+	}
+	if task, err := client.QueryTask(ctx, detailed.TaskIDs()["13800138000"]); err != nil || task.Receiver != "13800138000" {
+		t.Errorf("QueryTask() = %+v, %v", task, err)
+	}
+}
+
+// TestServerProgrammedResponses 测试编排响应
+func TestServerProgrammedResponses(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+	req := &mlievpush.SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}
+
+	srv.Enqueue(http.MethodPost, "/api/v1/messages",
+		Error(mlievpush.ErrCodeRateLimitExceeded),
+		Status(http.StatusBadGateway, "bad gateway"),
+	)
+
+	if _, err := client.SendMessage(ctx, req); !errors.Is(err, mlievpush.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	var httpErr *mlievpush.HTTPError
+	if _, err := client.SendMessage(ctx, req); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected HTTPError 502, got %v", err)
+	}
+	if _, err := client.SendMessage(ctx, req); err != nil {
+		t.Errorf("expected default response after queue drained, got %v", err)
+	}
+
+	srv.Handle(http.MethodGet, "/api/v1/channels", func(r *Request) Response {
+		return OK(mlievpush.ListChannelsData{Items: []mlievpush.ChannelInfo{{ID: 7, Name: "sms"}}, Total: 1})
+	})
+	channels, err := client.ListChannels(ctx)
+	if err != nil || len(channels.Items) != 1 || channels.Items[0].ID != 7 {
+		t.Errorf("ListChannels() = %+v, %v", channels, err)
+	}
+}
+
+// TestServerSignatureVerification 测试签名校验
+func TestServerSignatureVerification(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := mlievpush.NewClient(srv.URL, srv.AppID, "wrong_secret")
+	_, err := client.SendMessage(context.Background(), &mlievpush.SendMessageRequest{ChannelID: 1, Receiver: "13800138000"})
+	if !errors.Is(err, mlievpush.ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+	if req := srv.LastRequest(); req == nil || req.VerifyErr == nil {
+		t.Errorf("expected VerifyErr to be recorded, got %+v", req)
+	}
+
+	unverified := NewServer(WithoutSignatureVerification())
+	defer unverified.Close()
+	client = mlievpush.NewClient(unverified.URL, "any", "any")
+	if _, err := client.SendMessage(context.Background(), &mlievpush.SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}); err != nil {
+		t.Errorf("SendMessage() error = %v", err)
+	}
+}
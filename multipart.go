@@ -0,0 +1,199 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+)
+
+// ContentSHA256Param multipart 请求签名时表示请求体哈希的参数名
+//
+// multipart/form-data 请求的签名参数由所有普通表单字段（文件字段除外）加上该参数组成，
+// 其值为完整请求体的 SHA-256 十六进制摘要，签名算法与 JSON 请求相同。
+const ContentSHA256Param = "content_sha256"
+
+// requestBody 编码后的请求体
+type requestBody struct {
+	contentType string // Content-Type
+	data        []byte // 请求体
+	signParams  string // 参与签名的规范化参数（见 CanonicalJSON）
+}
+
+// bodyEncoder 自定义请求体编码（非JSON请求）
+type bodyEncoder interface {
+	encodeBody() (*requestBody, error)
+}
+
+// encodeRequestBody 编码请求数据，默认使用JSON
+func encodeRequestBody(reqData interface{}) (*requestBody, error) {
+	if reqData == nil {
+		return &requestBody{}, nil
+	}
+	if encoder, ok := reqData.(bodyEncoder); ok {
+		return encoder.encodeBody()
+	}
+
+	data, err := json.Marshal(reqData)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request data: %w", err)
+	}
+
+	// 签名参数由实际发送的请求体规范化得到，数字保持原样
+	signParams, err := CanonicalJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requestBody{contentType: "application/json", data: data, signParams: signParams}, nil
+}
+
+// formField multipart 普通表单字段
+type formField struct {
+	name  string
+	value string
+}
+
+// formFile multipart 文件字段
+type formFile struct {
+	field      string
+	attachment Attachment
+}
+
+// multipartForm multipart/form-data 请求
+type multipartForm struct {
+	channel int
+	fields  []formField
+	files   []formFile
+}
+
+// channelID 实现 channelRequest 接口
+func (f *multipartForm) channelID() int { return f.channel }
+
+// encodeBody 实现 bodyEncoder 接口
+func (f *multipartForm) encodeBody() (*requestBody, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	params := make(map[string]interface{}, len(f.fields)+1)
+	for _, field := range f.fields {
+		if err := w.WriteField(field.name, field.value); err != nil {
+			return nil, fmt.Errorf("write form field %s: %w", field.name, err)
+		}
+		params[field.name] = field.value
+	}
+
+	for _, file := range f.files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name":     file.field,
+			"filename": file.attachment.Filename,
+		}))
+		header.Set("Content-Type", attachmentContentType(file.attachment))
+
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return nil, fmt.Errorf("create form file %s: %w", file.field, err)
+		}
+		if _, err := part.Write(file.attachment.Content); err != nil {
+			return nil, fmt.Errorf("write form file %s: %w", file.field, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	data := buf.Bytes()
+	params[ContentSHA256Param] = ContentSHA256(data)
+
+	return &requestBody{contentType: w.FormDataContentType(), data: data, signParams: sortParams(params)}, nil
+}
+
+// multipartSignParams 从 multipart 请求体中提取参与签名的参数（服务端校验使用）
+func multipartSignParams(contentType string, body []byte) (map[string]interface{}, error) {
+	_, mediaParams, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("parse content type: %w", err)
+	}
+	boundary := mediaParams["boundary"]
+	if boundary == "" {
+		return nil, fmt.Errorf("missing multipart boundary")
+	}
+
+	params := make(map[string]interface{})
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read multipart part: %w", err)
+		}
+		// 文件字段通过请求体哈希参与签名
+		if part.FileName() != "" {
+			continue
+		}
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("read form field %s: %w", part.FormName(), err)
+		}
+		if _, exists := params[part.FormName()]; !exists {
+			params[part.FormName()] = string(value)
+		}
+	}
+
+	params[ContentSHA256Param] = ContentSHA256(body)
+	return params, nil
+}
+
+// isMultipart 判断 Content-Type 是否为 multipart/form-data
+func isMultipart(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// UploadAttachment 以 multipart/form-data 上传附件，返回的附件ID可在发送消息时引用
+func (c *Client) UploadAttachment(ctx context.Context, channelID int, att Attachment) (*UploadAttachmentData, error) {
+	if att.ID != "" {
+		return nil, &AttachmentError{Filename: att.Filename, Reason: "already uploaded: ID must be empty"}
+	}
+	if len(att.Content) == 0 {
+		return nil, &AttachmentError{Filename: att.Filename, Reason: "missing content"}
+	}
+	if err := c.attachmentLimit(channelID).Validate([]Attachment{att}); err != nil {
+		return nil, err
+	}
+	if err := c.requireFeature(ctx, FeatureAttachmentUpload); err != nil {
+		return nil, err
+	}
+
+	form := &multipartForm{
+		channel: channelID,
+		fields: []formField{
+			{name: "channel_id", value: strconv.Itoa(channelID)},
+			{name: "filename", value: att.Filename},
+			{name: "content_type", value: attachmentContentType(att)},
+		},
+		files: []formFile{{field: "file", attachment: att}},
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/attachments", form)
+	if err != nil {
+		return nil, err
+	}
+
+	var data UploadAttachmentData
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+
+	return &data, nil
+}
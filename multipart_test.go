@@ -0,0 +1,112 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestUploadAttachment 测试 multipart 上传签名可被服务端校验
+func TestUploadAttachment(t *testing.T) {
+	content := []byte("%PDF-1.4 test document")
+
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/attachments" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		verified, _ := VerifiedRequestFromContext(r.Context())
+		if verified.Params["channel_id"] != "2" || verified.Params["filename"] != "report.pdf" {
+			t.Errorf("unexpected sign params: %v", verified.Params)
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile() error = %v", err)
+		}
+		got, _ := io.ReadAll(file)
+		if !bytes.Equal(got, content) {
+			t.Errorf("file content = %q, want %q", got, content)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data": map[string]interface{}{
+				"attachment_id": "att-1",
+				"filename":      header.Filename,
+				"size":          header.Size,
+			},
+		})
+	})))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	data, err := client.UploadAttachment(context.Background(), 2, Attachment{
+		Filename:    "report.pdf",
+		ContentType: "application/pdf",
+		Content:     content,
+	})
+	if err != nil {
+		t.Fatalf("UploadAttachment() error = %v", err)
+	}
+	if data.AttachmentID != "att-1" || data.Size != int64(len(content)) {
+		t.Errorf("unexpected data: %+v", data)
+	}
+
+	for _, tt := range []struct {
+		att    Attachment
+		reason string
+	}{
+		{Attachment{ID: "att-1", Filename: "a.pdf", Content: content}, "already uploaded"},
+		{Attachment{Filename: "a.pdf"}, "missing content"},
+	} {
+		var attErr *AttachmentError
+		if _, err := client.UploadAttachment(context.Background(), 2, tt.att); !errors.As(err, &attErr) || !strings.Contains(attErr.Reason, tt.reason) {
+			t.Errorf("UploadAttachment(%+v) error = %v, want %q", tt.att, err, tt.reason)
+		}
+	}
+}
+
+// TestMultipartTamperedBody 测试篡改 multipart 文件内容后签名失效
+func TestMultipartTamperedBody(t *testing.T) {
+	form := &multipartForm{
+		fields: []formField{{name: "filename", value: "a.txt"}},
+		files:  []formFile{{field: "file", attachment: Attachment{Filename: "a.txt", Content: []byte("hello")}}},
+	}
+	body, err := form.encodeBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := signContent(canonicalString(http.MethodPost, "/upload", body.signParams, timestamp, "n1"), "secret")
+	tampered := bytes.Replace(body.data, []byte("hello"), []byte("HELLO"), 1)
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(tampered))
+	r.Header.Set("Content-Type", body.contentType)
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, "n1")
+	r.Header.Set(HeaderSignature, signature)
+
+	_, err = NewVerifier(StaticSecret("secret")).Verify(r)
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+// TestAttachmentReference 测试引用已上传附件时跳过内容校验
+func TestAttachmentReference(t *testing.T) {
+	limit := AttachmentLimit{MaxSize: 10, AllowedTypes: []string{"image/*"}}
+	if err := limit.Validate([]Attachment{{ID: "att-1", Filename: "report.pdf"}}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
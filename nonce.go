@@ -0,0 +1,79 @@
+package mlievpush
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NonceCache 随机数缓存，用于拒绝时间窗口内重复使用的随机数
+type NonceCache interface {
+	// Add 记录随机数，已存在（重复使用）时返回 false
+	Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// nonceCacheSweepInterval 内存缓存清理过期记录的间隔
+const nonceCacheSweepInterval = time.Minute
+
+// MemoryNonceCache 基于内存的随机数缓存，适用于单实例部署
+type MemoryNonceCache struct {
+	mu        sync.Mutex
+	entries   map[string]time.Time // 随机数 -> 过期时间
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryNonceCache 创建内存随机数缓存
+func NewMemoryNonceCache() *MemoryNonceCache {
+	return &MemoryNonceCache{
+		entries: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Add 实现 NonceCache 接口
+func (c *MemoryNonceCache) Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.After(c.nextSweep) {
+		for k, expireAt := range c.entries {
+			if !now.Before(expireAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.nextSweep = now.Add(nonceCacheSweepInterval)
+	}
+
+	if expireAt, ok := c.entries[nonce]; ok && now.Before(expireAt) {
+		return false, nil
+	}
+	c.entries[nonce] = now.Add(ttl)
+	return true, nil
+}
+
+// SetNXFunc Redis SET NX 操作，键不存在并写入成功时返回 true
+//
+// 以 go-redis 为例：
+//
+//	func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+//		return rdb.SetNX(ctx, key, 1, ttl).Result()
+//	}
+type SetNXFunc func(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+// RedisNonceCache 基于 Redis 的随机数缓存，适用于多实例部署
+type RedisNonceCache struct {
+	setNX  SetNXFunc
+	prefix string
+}
+
+// NewRedisNonceCache 创建 Redis 随机数缓存，prefix 为键前缀
+func NewRedisNonceCache(setNX SetNXFunc, prefix string) *RedisNonceCache {
+	return &RedisNonceCache{setNX: setNX, prefix: prefix}
+}
+
+// Add 实现 NonceCache 接口
+func (c *RedisNonceCache) Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return c.setNX(ctx, c.prefix+nonce, ttl)
+}
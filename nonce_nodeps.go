@@ -0,0 +1,10 @@
+//go:build mlievpush_nodeps
+
+package mlievpush
+
+import "crypto/rand"
+
+// newNonce 生成请求随机数（基于 crypto/rand 的 UUID v4，不依赖 google/uuid）
+func newNonce() string {
+	return uuidV4From(rand.Reader)
+}
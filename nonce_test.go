@@ -0,0 +1,23 @@
+package mlievpush
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestNewNonce 测试随机数格式（两种构建方式均为 UUID v4）
+func TestNewNonce(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		nonce := newNonce()
+		if !pattern.MatchString(nonce) {
+			t.Fatalf("newNonce() = %q, not a UUID v4", nonce)
+		}
+		if seen[nonce] {
+			t.Fatalf("newNonce() returned duplicate %q", nonce)
+		}
+		seen[nonce] = true
+	}
+}
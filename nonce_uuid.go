@@ -0,0 +1,10 @@
+//go:build !mlievpush_nodeps
+
+package mlievpush
+
+import "github.com/google/uuid"
+
+// newNonce 生成请求随机数（UUID v4）
+func newNonce() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,71 @@
+package mlievpush
+
+import (
+	"fmt"
+)
+
+// ParamMarshaler 自定义模板参数序列化，金额、日期等领域类型实现该接口后可直接作为模板参数值
+// 发送前参数值会被替换为 MarshalParam 的结果，请求体、签名和本地内容长度估算使用同一个字符串，无需调用方预先转换
+type ParamMarshaler interface {
+	MarshalParam() (string, error)
+}
+
+// ParamMarshalerFunc 函数形式的 ParamMarshaler，用于临时包装无法添加方法的类型
+type ParamMarshalerFunc func() (string, error)
+
+// MarshalParam 实现 ParamMarshaler 接口
+func (f ParamMarshalerFunc) MarshalParam() (string, error) {
+	return f()
+}
+
+// ParamError 模板参数序列化失败
+type ParamError struct {
+	Key string // 参数名
+	Err error  // MarshalParam 返回的错误
+}
+
+// Error 实现 error 接口
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("marshal template param %q: %v", e.Key, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalParams 将实现了 ParamMarshaler 的参数值替换为序列化结果，不修改原 map；没有需要替换的值时原样返回
+func MarshalParams(params map[string]interface{}) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	for k, v := range params {
+		m, ok := v.(ParamMarshaler)
+		if !ok {
+			continue
+		}
+		s, err := m.MarshalParam()
+		if err != nil {
+			return nil, &ParamError{Key: k, Err: err}
+		}
+		if out == nil {
+			out = make(map[string]interface{}, len(params))
+			for k2, v2 := range params {
+				out[k2] = v2
+			}
+		}
+		out[k] = s
+	}
+	if out == nil {
+		return params, nil
+	}
+	return out, nil
+}
+
+// paramString 将参数值格式化为渲染模板时使用的字符串
+func paramString(v interface{}) string {
+	if m, ok := v.(ParamMarshaler); ok {
+		if s, err := m.MarshalParam(); err == nil {
+			return s
+		}
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,73 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testMoney 测试用金额类型（单位：分）
+type testMoney int64
+
+// MarshalParam 实现 ParamMarshaler 接口
+func (m testMoney) MarshalParam() (string, error) {
+	if m < 0 {
+		return "", errors.New("negative amount")
+	}
+	return fmt.Sprintf("%d.%02d", m/100, m%100), nil
+}
+
+// TestMarshalParams 测试自定义参数序列化
+func TestMarshalParams(t *testing.T) {
+	params := map[string]interface{}{"amount": testMoney(1250), "name": "张三"}
+	got, err := MarshalParams(params)
+	if err != nil || got["amount"] != "12.50" || got["name"] != "张三" {
+		t.Errorf("MarshalParams() = %v, %v", got, err)
+	}
+	if params["amount"] != testMoney(1250) {
+		t.Error("MarshalParams() modified the input map")
+	}
+
+	var pe *ParamError
+	if _, err := MarshalParams(map[string]interface{}{"amount": testMoney(-1)}); !errors.As(err, &pe) || pe.Key != "amount" {
+		t.Errorf("MarshalParams(negative) error = %v", err)
+	}
+
+	if got := RenderTemplate("金额${amount}元", params); got != "金额12.50元" {
+		t.Errorf("RenderTemplate() = %q", got)
+	}
+}
+
+// TestSendMessageParamMarshaler 测试请求体和签名使用序列化后的参数
+func TestSendMessageParamMarshaler(t *testing.T) {
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, err := verifier.Verify(r)
+		if err != nil {
+			t.Errorf("Verify() error = %v", err)
+			return
+		}
+		params, _ := verified.Params["template_params"].(map[string]interface{})
+		if params["amount"] != "12.50" {
+			t.Errorf("template_params = %v", verified.Params["template_params"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	_, err := client.SendMessage(context.Background(), &SendMessageRequest{
+		ChannelID:      1,
+		Receiver:       "13800138000",
+		SignatureName:  "test",
+		TemplateParams: map[string]interface{}{"amount": testMoney(1250)},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package mlievpush
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPartialFailure 批量发送只有部分接收者被网关接受，可用 errors.Is 判断
+var ErrPartialFailure = errors.New("mlievpush: batch partially failed")
+
+// FailedReceiver 批量发送中未被接受的接收者（网关返回明细时）
+type FailedReceiver struct {
+	Receiver string `json:"receiver"`         // 接收者
+	Code     int    `json:"code,omitempty"`   // 错误码
+	Reason   string `json:"reason,omitempty"` // 失败原因
+}
+
+// PartialFailureError 批量发送部分失败（SuccessCount < TotalCount）
+// 仅在开启 WithPartialFailureError 后返回，SendBatch 同时返回非nil的响应数据
+type PartialFailureError struct {
+	BatchID      string           // 批次ID
+	TotalCount   int              // 总数量
+	SuccessCount int              // 成功入队数量
+	FailedCount  int              // 失败数量
+	Failed       []FailedReceiver // 失败的接收者明细（网关未返回明细时为空）
+}
+
+// Error 实现 error 接口，不包含接收者信息
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("%v: batch %s accepted %d of %d receivers", ErrPartialFailure, e.BatchID, e.SuccessCount, e.TotalCount)
+}
+
+// Is 使 errors.Is(err, ErrPartialFailure) 成立
+func (e *PartialFailureError) Is(target error) bool {
+	return target == ErrPartialFailure
+}
+
+// WithPartialFailureError 设置批量发送部分失败时是否返回 *PartialFailureError
+// 开启后 SendBatch 在 SuccessCount < TotalCount 时同时返回响应数据和错误，避免把部分失败误当作全部成功
+func WithPartialFailureError(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.partialFailureError = enabled
+	}
+}
+
+// partialFailure 检查批量发送结果，部分失败时返回 *PartialFailureError
+func partialFailure(data *SendBatchData) error {
+	if data.SuccessCount >= data.TotalCount {
+		return nil
+	}
+	return &PartialFailureError{
+		BatchID:      data.BatchID,
+		TotalCount:   data.TotalCount,
+		SuccessCount: data.SuccessCount,
+		FailedCount:  data.FailedCount,
+		Failed:       data.FailedReceivers,
+	}
+}
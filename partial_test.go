@@ -0,0 +1,48 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestPartialFailureError 测试批量发送部分失败
+func TestPartialFailureError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{
+			"batch_id":      "b1",
+			"total_count":   3,
+			"success_count": 2,
+			"failed_count":  1,
+			"failed_receivers": []interface{}{
+				map[string]interface{}{"receiver": "13800138002", "code": ErrCodeInvalidReceiver, "reason": "空号"},
+			},
+		}})
+	}))
+	defer server.Close()
+
+	req := &SendBatchRequest{ChannelID: 1, SignatureName: "test", Receivers: []string{"13800138000", "13800138001", "13800138002"}}
+
+	// 默认不返回错误
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	if data, err := client.SendBatch(context.Background(), req); err != nil || data.FailedCount != 1 || len(data.FailedReceivers) != 1 {
+		t.Errorf("SendBatch() = %+v, %v", data, err)
+	}
+
+	client = NewClient(server.URL, "test_app_id", "test_secret", WithPartialFailureError(true))
+	data, err := client.SendBatch(context.Background(), req)
+	if data == nil || data.BatchID != "b1" {
+		t.Errorf("SendBatch() data = %+v, want data alongside error", data)
+	}
+	var partialErr *PartialFailureError
+	if !errors.As(err, &partialErr) || !errors.Is(err, ErrPartialFailure) {
+		t.Fatalf("SendBatch() error = %v, want *PartialFailureError", err)
+	}
+	if partialErr.SuccessCount != 2 || partialErr.TotalCount != 3 || len(partialErr.Failed) != 1 || partialErr.Failed[0].Receiver != "13800138002" {
+		t.Errorf("PartialFailureError = %+v", partialErr)
+	}
+}
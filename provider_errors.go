@@ -0,0 +1,120 @@
+package mlievpush
+
+import (
+	"strings"
+	"sync"
+)
+
+// ProviderReason 归一化的服务商失败原因，用于跨服务商统一分组（ErrCodeProviderError 及回调失败的 ProviderCode）
+type ProviderReason string
+
+// 归一化失败原因
+const (
+	ReasonDelivered       ProviderReason = "delivered"        // 已送达（部分服务商用状态码表示成功）
+	ReasonInvalidReceiver ProviderReason = "invalid_receiver" // 号码/地址无效（空号、格式错误、不存在的邮箱）
+	ReasonUnreachable     ProviderReason = "unreachable"      // 暂时无法送达（关机、停机、不在服务区）
+	ReasonBlocked         ProviderReason = "blocked"          // 接收者拒收（黑名单、退订）
+	ReasonContentRejected ProviderReason = "content_rejected" // 内容被拦截（敏感词、运营商过滤）
+	ReasonRateLimited     ProviderReason = "rate_limited"     // 触发服务商频率限制
+	ReasonExpired         ProviderReason = "expired"          // 超过有效期未送达
+	ReasonAccount         ProviderReason = "account"          // 服务商账户问题（余额不足、服务停用）
+	ReasonTemplate        ProviderReason = "template"         // 模板或签名问题（未审核、参数缺失）
+	ReasonUnknown         ProviderReason = "unknown"          // 未识别的错误码
+)
+
+// providerCodes 服务商错误码（大写）-> 归一化原因
+var providerCodes = map[string]ProviderReason{
+	// CMPP/SMGP 运营商回执
+	"DELIVRD": ReasonDelivered,
+	"UNDELIV": ReasonUnreachable,
+	"EXPIRED": ReasonExpired,
+	"REJECTD": ReasonContentRejected,
+	"DELETED": ReasonExpired,
+	"UNKNOWN": ReasonUnknown,
+	"BLACK":   ReasonBlocked,
+	"MK:0001": ReasonInvalidReceiver,
+
+	// 阿里云短信
+	"ISV.MOBILE_NUMBER_ILLEGAL":         ReasonInvalidReceiver,
+	"ISV.BUSINESS_LIMIT_CONTROL":        ReasonRateLimited,
+	"ISV.DAY_LIMIT_CONTROL":             ReasonRateLimited,
+	"ISV.BLACK_KEY_CONTROL_LIMIT":       ReasonContentRejected,
+	"ISV.AMOUNT_NOT_ENOUGH":             ReasonAccount,
+	"ISV.OUT_OF_SERVICE":                ReasonAccount,
+	"ISV.SMS_TEMPLATE_ILLEGAL":          ReasonTemplate,
+	"ISV.SMS_SIGNATURE_ILLEGAL":         ReasonTemplate,
+	"ISV.TEMPLATE_MISSING_PARAMETERS":   ReasonTemplate,
+	"ISV.MOBILE_COUNT_OVER_LIMIT":       ReasonRateLimited,
+	"ISV.PARAM_LENGTH_LIMIT":            ReasonTemplate,
+	"ISV.SMS_CONTENT_ILLEGAL":           ReasonContentRejected,
+	"ISV.SMS_SIGN_ILLEGAL":              ReasonTemplate,
+	"ISV.ACCOUNT_ABNORMAL":              ReasonAccount,
+	"ISV.ACCOUNT_NOT_EXISTS":            ReasonAccount,
+	"ISV.TEMPLATE_PARAMS_ILLEGAL":       ReasonTemplate,
+	"ISV.INVALID_PARAMETERS":            ReasonTemplate,
+	"ISV.EXTEND_CODE_ERROR":             ReasonTemplate,
+	"ISV.DOMESTIC_NUMBER_NOT_SUPPORTED": ReasonInvalidReceiver,
+
+	// 腾讯云短信
+	"FAILEDOPERATION.PHONENUMBERINBLACKLIST":          ReasonBlocked,
+	"FAILEDOPERATION.CONTAINSENSITIVEWORD":            ReasonContentRejected,
+	"FAILEDOPERATION.INSUFFICIENTBALANCEINSMSPACKAGE": ReasonAccount,
+	"FAILEDOPERATION.TEMPLATEINCORRECTORUNAPPROVED":   ReasonTemplate,
+	"FAILEDOPERATION.SIGNATUREINCORRECTORUNAPPROVED":  ReasonTemplate,
+	"INVALIDPARAMETERVALUE.INCORRECTPHONENUMBER":      ReasonInvalidReceiver,
+	"LIMITEXCEEDED.PHONENUMBERDAILYLIMIT":             ReasonRateLimited,
+	"LIMITEXCEEDED.PHONENUMBERONEHOURLIMIT":           ReasonRateLimited,
+	"LIMITEXCEEDED.PHONENUMBERTHIRTYSECONDLIMIT":      ReasonRateLimited,
+	"LIMITEXCEEDED.DELIVERYFREQUENCYLIMIT":            ReasonRateLimited,
+
+	// Twilio
+	"21211": ReasonInvalidReceiver,
+	"21610": ReasonBlocked,
+	"30003": ReasonUnreachable,
+	"30005": ReasonInvalidReceiver,
+	"30006": ReasonInvalidReceiver,
+	"30007": ReasonContentRejected,
+	"30008": ReasonUnknown,
+}
+
+// providerCodePrefixes 未精确匹配时按前缀归类（运营商网关错误码系列）
+var providerCodePrefixes = []struct {
+	prefix string
+	reason ProviderReason
+}{
+	{"MK:", ReasonUnreachable},
+	{"MN:", ReasonUnreachable},
+	{"MI:", ReasonUnreachable},
+}
+
+// providerCodesMu 保护自定义错误码注册
+var providerCodesMu sync.RWMutex
+
+// NormalizeProviderError 将服务商/运营商错误码归一化为统一的失败原因，大小写不敏感，未识别时返回 ReasonUnknown
+func NormalizeProviderError(code string) ProviderReason {
+	key := strings.ToUpper(strings.TrimSpace(code))
+	if key == "" {
+		return ReasonUnknown
+	}
+
+	providerCodesMu.RLock()
+	reason, ok := providerCodes[key]
+	providerCodesMu.RUnlock()
+	if ok {
+		return reason
+	}
+
+	for _, p := range providerCodePrefixes {
+		if strings.HasPrefix(key, p.prefix) {
+			return p.reason
+		}
+	}
+	return ReasonUnknown
+}
+
+// RegisterProviderError 注册或覆盖服务商错误码的归一化原因，用于补充内置字典未覆盖的错误码
+func RegisterProviderError(code string, reason ProviderReason) {
+	providerCodesMu.Lock()
+	defer providerCodesMu.Unlock()
+	providerCodes[strings.ToUpper(strings.TrimSpace(code))] = reason
+}
@@ -0,0 +1,31 @@
+package mlievpush
+
+import "testing"
+
+// TestNormalizeProviderError 测试服务商错误码归一化
+func TestNormalizeProviderError(t *testing.T) {
+	tests := []struct {
+		code string
+		want ProviderReason
+	}{
+		{"UNDELIV", ReasonUnreachable},
+		{" undeliv ", ReasonUnreachable},
+		{"MK:0001", ReasonInvalidReceiver},
+		{"MK:0099", ReasonUnreachable},
+		{"isv.BUSINESS_LIMIT_CONTROL", ReasonRateLimited},
+		{"FailedOperation.PhoneNumberInBlacklist", ReasonBlocked},
+		{"30007", ReasonContentRejected},
+		{"", ReasonUnknown},
+		{"SOMETHING_NEW", ReasonUnknown},
+	}
+	for _, tt := range tests {
+		if got := NormalizeProviderError(tt.code); got != tt.want {
+			t.Errorf("NormalizeProviderError(%q) = %s, want %s", tt.code, got, tt.want)
+		}
+	}
+
+	RegisterProviderError("custom:42", ReasonAccount)
+	if got := NormalizeProviderError("CUSTOM:42"); got != ReasonAccount {
+		t.Errorf("registered code = %s, want %s", got, ReasonAccount)
+	}
+}
@@ -0,0 +1,92 @@
+package mlievpush
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"sync"
+)
+
+// WithRandSource 设置随机源，用于生成请求随机数、请求ID和重试退避抖动
+// 传入确定性随机源（如 math/rand.New(rand.NewSource(1))，*rand.Rand 实现了 io.Reader）可以在测试中复现完全一致的请求序列；
+// 生产环境请保持默认（crypto/rand），可预测的随机数会削弱防重放保护。传入 nil 时忽略
+func WithRandSource(r io.Reader) ClientOption {
+	return func(c *Client) {
+		if r != nil {
+			c.rand = &lockedReader{r: r}
+		}
+	}
+}
+
+// lockedReader 并发安全的随机源（math/rand.Rand 等实现不支持并发读取）
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+// Read 实现 io.Reader 接口
+func (l *lockedReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return io.ReadFull(l.r, p)
+}
+
+// WithNonceFunc 设置请求随机数（X-Nonce）生成函数，如单调递增计数器，或测试中返回固定值以得到确定的签名
+// 生成的随机数在时间戳窗口内必须唯一，否则会被网关当作重放请求拒绝。传入 nil 时恢复默认（UUID v4）
+func WithNonceFunc(fn func() string) ClientOption {
+	return func(c *Client) {
+		c.nonceFunc = fn
+	}
+}
+
+// requestNonce 生成签名用的请求随机数，设置了 WithNonceFunc 时使用自定义函数
+func (c *Client) requestNonce() string {
+	if c.nonceFunc != nil {
+		return c.nonceFunc()
+	}
+	return c.newNonce()
+}
+
+// newNonce 生成请求随机数，未设置随机源时使用默认实现
+func (c *Client) newNonce() string {
+	if c.rand == nil {
+		return newNonce()
+	}
+	return uuidV4From(c.rand)
+}
+
+// randFloat64 返回 [0, 1) 范围内的随机数，未设置随机源时返回 -1 表示使用默认实现
+func (c *Client) randFloat64() float64 {
+	if c.rand == nil {
+		return -1
+	}
+	var b [8]byte
+	if _, err := c.rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	// 取高53位，与 math/rand.Float64 的精度一致
+	return float64(binary.BigEndian.Uint64(b[:])>>11) / (1 << 53)
+}
+
+// uuidV4From 从随机源读取16字节生成 UUID v4 字符串
+func uuidV4From(r io.Reader) string {
+	var b [16]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		// 随机源读取失败时无法生成安全的随机数，与 uuid.New 的行为保持一致
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // 版本4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 变体
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
@@ -0,0 +1,100 @@
+package mlievpush
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestWithRandSource 测试相同随机源生成相同的请求随机数
+func TestWithRandSource(t *testing.T) {
+	nonces := func(seed int64) []string {
+		var got []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = append(got, r.Header.Get(HeaderNonce))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"code":0,"message":"success","data":{"task_id":"task"}}`))
+		}))
+		defer server.Close()
+		client := NewClient(server.URL, "test_app_id", "test_secret", WithRandSource(rand.New(rand.NewSource(seed))))
+		for i := 0; i < 3; i++ {
+			client.QueryTask(context.Background(), "task")
+		}
+		return got
+	}
+
+	a, b := nonces(1), nonces(1)
+	if len(a) != 3 {
+		t.Fatalf("got %d requests", len(a))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("nonce %d differs: %s != %s", i, a[i], b[i])
+		}
+	}
+	if c := nonces(2); c[0] == a[0] {
+		t.Error("different seeds should produce different nonces")
+	}
+}
+
+// TestRandSourceJitter 测试退避抖动使用注入的随机源
+func TestRandSourceJitter(t *testing.T) {
+	backoff := Backoff{Initial: time.Second, Multiplier: 2, Jitter: 0.5}
+	a := NewClient("http://example.com", "id", "secret", WithRetry(3, backoff), WithRandSource(rand.New(rand.NewSource(7))))
+	b := NewClient("http://example.com", "id", "secret", WithRetry(3, backoff), WithRandSource(rand.New(rand.NewSource(7))))
+
+	for retry := 1; retry <= 3; retry++ {
+		da, db := a.retryDelay(retry, nil), b.retryDelay(retry, nil)
+		if da != db {
+			t.Errorf("retry %d: %v != %v", retry, da, db)
+		}
+		base := time.Duration(1<<(retry-1)) * time.Second
+		if da < base/2 || da > base*3/2 {
+			t.Errorf("retry %d: delay %v outside jitter range", retry, da)
+		}
+	}
+}
+
+// TestWithNonceFuncAndClock 测试固定随机数和时间源得到确定的签名
+func TestWithNonceFuncAndClock(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"success","data":{"task_id":"task"}}`))
+	}))
+	defer server.Close()
+
+	fixed := time.Unix(1700000000, 0)
+	var counter int
+	newClient := func() *Client {
+		counter = 0
+		return NewClient(server.URL, "test_app_id", "test_secret",
+			WithClock(func() time.Time { return fixed }),
+			WithNonceFunc(func() string { counter++; return "nonce-" + strconv.Itoa(counter) }),
+		)
+	}
+	newClient().QueryTask(context.Background(), "task")
+	newClient().QueryTask(context.Background(), "task")
+
+	if len(headers) != 2 {
+		t.Fatalf("got %d requests", len(headers))
+	}
+	if got := headers[0].Get(HeaderTimestamp); got != "1700000000" {
+		t.Errorf("timestamp = %s", got)
+	}
+	if got := headers[0].Get(HeaderNonce); got != "nonce-1" {
+		t.Errorf("nonce = %s", got)
+	}
+	if headers[0].Get(HeaderSignature) != headers[1].Get(HeaderSignature) {
+		t.Error("expected identical signatures")
+	}
+	// 请求ID不受自定义随机数影响
+	if headers[0].Get(HeaderRequestID) == headers[1].Get(HeaderRequestID) {
+		t.Error("expected distinct request IDs")
+	}
+}
@@ -0,0 +1,62 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithRateLimit 设置客户端限流（令牌桶）：每秒最多 perSecond 次请求，允许 burst 次突发，小于等于0表示不限流
+// 每次请求尝试（包括重试）消耗一个令牌，令牌不足时等待；等待期间 ctx 结束会立即返回错误
+func WithRateLimit(perSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		if perSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.limiter = &rateLimiter{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+	}
+}
+
+// rateLimiter 令牌桶限流器
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64   // 每秒生成的令牌数
+	burst  float64   // 桶容量
+	tokens float64   // 当前令牌数，为负表示已被预约
+	last   time.Time // 上次补充令牌的时间
+}
+
+// wait 预约一个令牌并等待到可用
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 || sleepContext(ctx, delay) {
+		return nil
+	}
+
+	// 未使用的预约归还
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("wait for rate limit: %w", err)
+	}
+	return fmt.Errorf("wait for rate limit: %w", context.DeadlineExceeded)
+}
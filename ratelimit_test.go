@@ -0,0 +1,32 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRateLimiter 测试令牌桶限流
+func TestRateLimiter(t *testing.T) {
+	client := NewClient("http://example.com", "id", "secret", WithRateLimit(100, 2))
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := client.limiter.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 突发2次，其余2次各等待约10ms
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond || elapsed > 200*time.Millisecond {
+		t.Errorf("elapsed = %v", elapsed)
+	}
+
+	slow := NewClient("http://example.com", "id", "secret", WithRateLimit(1, 1))
+	slow.limiter.wait(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := slow.limiter.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
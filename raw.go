@@ -0,0 +1,50 @@
+package mlievpush
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// RawData 保留网关返回的原始JSON，嵌入在各响应数据结构中
+// 新版网关增加的字段在 SDK 发布新版本前即可通过 DecodeRaw 读取；只有接口直接返回的顶层结构会保留，
+// 列表中的元素、自动分片汇总等本地组装的数据没有原始JSON
+type RawData struct {
+	raw json.RawMessage
+}
+
+// Raw 返回原始JSON（响应的 data 字段），本地构造的数据返回nil
+func (r *RawData) Raw() json.RawMessage {
+	return r.raw
+}
+
+// DecodeRaw 将原始JSON解析到 v，用于读取当前 SDK 版本尚未定义的字段
+func (r *RawData) DecodeRaw(v interface{}) error {
+	if r.raw == nil {
+		return errNoRawData
+	}
+	return json.Unmarshal(r.raw, v)
+}
+
+// retainRaw 实现 rawRetainer 接口
+func (r *RawData) retainRaw(raw json.RawMessage) {
+	r.raw = raw
+}
+
+// errNoRawData 数据不是从网关响应解析的
+var errNoRawData = errors.New("no raw JSON: data was not decoded from a gateway response")
+
+// rawRetainer 可以保留原始JSON的响应数据
+type rawRetainer interface {
+	retainRaw(raw json.RawMessage)
+}
+
+// decodeData 解析响应数据，支持时保留原始JSON
+func decodeData(raw json.RawMessage, out interface{}) error {
+	if err := json.Unmarshal(raw, out); err != nil {
+		return err
+	}
+	if r, ok := out.(rawRetainer); ok {
+		r.retainRaw(raw)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRawData 测试保留原始JSON
+func TestRawData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{
+			"task_id":       "t1",
+			"status":        "success",
+			"provider_cost": 0.045,
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	data, err := client.QueryTask(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("QueryTask() error = %v", err)
+	}
+
+	var extra struct {
+		ProviderCost float64 `json:"provider_cost"`
+	}
+	if err := data.DecodeRaw(&extra); err != nil || extra.ProviderCost != 0.045 {
+		t.Errorf("DecodeRaw() = %+v, %v", extra, err)
+	}
+
+	// 原始JSON不参与序列化
+	out, _ := json.Marshal(data)
+	var fields map[string]interface{}
+	json.Unmarshal(out, &fields)
+	if _, ok := fields["RawData"]; ok {
+		t.Errorf("RawData should not be marshaled: %s", out)
+	}
+
+	var local SendMessageData
+	if local.Raw() != nil || local.DecodeRaw(&extra) == nil {
+		t.Error("locally constructed data should have no raw JSON")
+	}
+}
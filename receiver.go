@@ -0,0 +1,192 @@
+package mlievpush
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ReceiverValidator 接收者格式校验函数
+type ReceiverValidator func(receiver string) error
+
+// ReceiverError 接收者格式错误（本地校验，不会发送请求）
+type ReceiverError struct {
+	Receiver    string // 接收者
+	MessageType string // 消息类型
+	Reason      string // 错误原因
+
+	redactor Redactor // 脱敏策略，为nil时使用 DefaultRedactor
+}
+
+// Error 实现 error 接口，接收者已脱敏
+func (e *ReceiverError) Error() string {
+	redactor := e.redactor
+	if redactor == nil {
+		redactor = DefaultRedactor
+	}
+	return fmt.Sprintf("invalid %s receiver %q: %s", e.MessageType, redactor.RedactReceiver(e.Receiver), e.Reason)
+}
+
+// Code 返回对应的网关错误码 ErrCodeInvalidReceiver，便于与网关返回的 *APIError 统一处理
+func (e *ReceiverError) Code() int {
+	return ErrCodeInvalidReceiver
+}
+
+// Is 使 errors.Is(err, ErrInvalidReceiver) 成立
+func (e *ReceiverError) Is(target error) bool {
+	return target == ErrInvalidReceiver
+}
+
+// phoneNumberPattern 手机号格式（E.164，可省略"+"）
+var phoneNumberPattern = regexp.MustCompile(`^\+?[1-9]\d{6,14}$`)
+
+// defaultReceiverValidators 各消息类型的默认校验规则
+var defaultReceiverValidators = map[string]ReceiverValidator{
+	MessageTypeSMS:        validatePhoneNumber,
+	MessageTypeVoice:      validatePhoneNumber,
+	MessageTypeEmail:      validateEmail,
+	MessageTypeWebhook:    validateWebhookURL,
+	MessageTypeWechatWork: validateNonEmpty,
+	MessageTypeDingtalk:   validateNonEmpty,
+	MessageTypePush:       validateNonEmpty,
+}
+
+// WithChannelType 声明通道的消息类型，发送前按类型在本地校验接收者格式
+func WithChannelType(channelID int, messageType string) ClientOption {
+	return func(c *Client) {
+		c.channelTypes[channelID] = messageType
+	}
+}
+
+// WithReceiverValidator 设置指定消息类型的接收者校验规则，覆盖默认规则
+func WithReceiverValidator(messageType string, validator ReceiverValidator) ClientOption {
+	return func(c *Client) {
+		c.receiverValidators[messageType] = validator
+	}
+}
+
+// ValidateReceiver 按消息类型的默认规则校验接收者格式，未知类型不做校验
+func ValidateReceiver(messageType, receiver string) error {
+	validator, ok := defaultReceiverValidators[messageType]
+	if !ok {
+		return nil
+	}
+	return wrapReceiverError(messageType, receiver, validator(receiver))
+}
+
+// validateReceivers 按通道的消息类型校验接收者，类型未知的通道（未声明且不在通道列表缓存中）不做校验
+func (c *Client) validateReceivers(channelID int, receivers ...string) error {
+	messageType, ok := c.channelType(channelID)
+	if !ok {
+		return nil
+	}
+
+	validator, ok := c.receiverValidators[messageType]
+	if !ok {
+		validator, ok = defaultReceiverValidators[messageType]
+	}
+	if !ok || validator == nil {
+		return nil
+	}
+
+	for _, receiver := range receivers {
+		if err := validator(receiver); err != nil {
+			err = wrapReceiverError(messageType, receiver, err)
+			if receiverErr, ok := err.(*ReceiverError); ok && receiverErr.redactor == nil {
+				receiverErr.redactor = c.redactor
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// channelType 获取通道的消息类型：优先使用 WithChannelType 声明的类型，其次使用 ListChannels 缓存的通道信息
+func (c *Client) channelType(channelID int) (string, bool) {
+	if messageType, ok := c.channelTypes[channelID]; ok {
+		return messageType, true
+	}
+	if ch, found, _ := c.channels.lookup(channelID); found && ch.MessageType != "" {
+		return ch.MessageType, true
+	}
+	return "", false
+}
+
+// wrapReceiverError 将校验错误包装为 *ReceiverError
+func wrapReceiverError(messageType, receiver string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*ReceiverError); ok {
+		return err
+	}
+	return &ReceiverError{Receiver: receiver, MessageType: messageType, Reason: err.Error()}
+}
+
+// validatePhoneNumber 校验手机号（E.164格式，允许空格和连字符分隔），+86 号码按中国大陆手机号规则校验
+func validatePhoneNumber(receiver string) error {
+	normalized := strings.NewReplacer(" ", "", "-", "").Replace(receiver)
+	if !phoneNumberPattern.MatchString(normalized) {
+		return fmt.Errorf("not a valid phone number")
+	}
+	if national, ok := strings.CutPrefix(normalized, "+86"); ok && !cnMobilePattern.MatchString(national) {
+		return fmt.Errorf("not a valid mainland China mobile number")
+	}
+	return nil
+}
+
+// cnMobilePattern 中国大陆手机号（11位，1开头，第二位3-9）
+var cnMobilePattern = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+// ValidateCNMobile 校验中国大陆手机号，可带 +86/0086 前缀，允许空格和连字符分隔
+// 默认规则只对带 +86 前缀的号码按中国大陆规则校验；只面向国内发送的短信通道可通过 WithReceiverValidator 使用该规则
+func ValidateCNMobile(receiver string) error {
+	national := strings.NewReplacer(" ", "", "-", "").Replace(receiver)
+	for _, prefix := range []string{"+86", "0086"} {
+		if rest, ok := strings.CutPrefix(national, prefix); ok {
+			national = rest
+			break
+		}
+	}
+	if !cnMobilePattern.MatchString(national) {
+		return fmt.Errorf("not a valid mainland China mobile number")
+	}
+	return nil
+}
+
+// validateEmail 校验邮箱地址（RFC 5322，不允许包含显示名称）
+func validateEmail(receiver string) error {
+	addr, err := mail.ParseAddress(receiver)
+	if err != nil {
+		return fmt.Errorf("not a valid email address")
+	}
+	if addr.Address != receiver {
+		return fmt.Errorf("email address must not contain a display name")
+	}
+	return nil
+}
+
+// validateWebhookURL 校验Webhook地址（http/https绝对地址）
+func validateWebhookURL(receiver string) error {
+	u, err := url.Parse(receiver)
+	if err != nil {
+		return fmt.Errorf("not a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("URL scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("URL must contain a host")
+	}
+	return nil
+}
+
+// validateNonEmpty 校验用户ID/群ID非空
+func validateNonEmpty(receiver string) error {
+	if strings.TrimSpace(receiver) == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValidateReceiver 测试按消息类型校验接收者
+func TestValidateReceiver(t *testing.T) {
+	tests := []struct {
+		messageType string
+		receiver    string
+		wantErr     bool
+	}{
+		{MessageTypeSMS, "13800138000", false},
+		{MessageTypeSMS, "+8613800138000", false},
+		{MessageTypeSMS, "+8612800138000", true},
+		{MessageTypeSMS, "+86 138 0013 8000", false},
+		{MessageTypeSMS, "+1 415-555-2671", false},
+		{MessageTypeSMS, "1380013", false},
+		{MessageTypeSMS, "abc", true},
+		{MessageTypeSMS, "", true},
+		{MessageTypeVoice, "0123456789", true},
+		{MessageTypeEmail, "user@example.com", false},
+		{MessageTypeEmail, "User <user@example.com>", true},
+		{MessageTypeEmail, "user@", true},
+		{MessageTypeWebhook, "https://hooks.example.com/notify", false},
+		{MessageTypeWebhook, "ftp://example.com", true},
+		{MessageTypeWebhook, "/relative/path", true},
+		{MessageTypeDingtalk, "chat_123", false},
+		{MessageTypeWechatWork, "  ", true},
+		{"unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.messageType+"/"+tt.receiver, func(t *testing.T) {
+			err := ValidateReceiver(tt.messageType, tt.receiver)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateReceiver(%q, %q) error = %v, wantErr %v", tt.messageType, tt.receiver, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateCNMobile 测试中国大陆手机号校验
+func TestValidateCNMobile(t *testing.T) {
+	for receiver, wantErr := range map[string]bool{
+		"13800138000":       false,
+		"+86 138-0013-8000": false,
+		"008619912345678":   false,
+		"12800138000":       true,
+		"1380013800":        true,
+		"+14155552671":      true,
+	} {
+		if err := ValidateCNMobile(receiver); (err != nil) != wantErr {
+			t.Errorf("ValidateCNMobile(%q) error = %v, wantErr %v", receiver, err, wantErr)
+		}
+	}
+
+	err := wrapReceiverError(MessageTypeSMS, "12800138000", ValidateCNMobile("12800138000"))
+	var receiverErr *ReceiverError
+	if !errors.As(err, &receiverErr) || receiverErr.Code() != ErrCodeInvalidReceiver {
+		t.Errorf("error = %v, want *ReceiverError with code %d", err, ErrCodeInvalidReceiver)
+	}
+}
+
+// TestSendBatchInvalidReceiver 测试声明通道类型后发送前校验接收者
+func TestSendBatchInvalidReceiver(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1", "test_app_id", "test_secret",
+		WithChannelType(2, MessageTypeEmail),
+	)
+
+	_, err := client.SendBatch(context.Background(), &SendBatchRequest{
+		ChannelID: 2,
+		Receivers: []string{"user@example.com", "13800138000"},
+	})
+
+	var recvErr *ReceiverError
+	if !errors.As(err, &recvErr) {
+		t.Fatalf("expected ReceiverError, got %v", err)
+	}
+	if recvErr.Receiver != "13800138000" || recvErr.MessageType != MessageTypeEmail {
+		t.Errorf("unexpected ReceiverError: %+v", recvErr)
+	}
+	if strings.Contains(err.Error(), "13800138000") {
+		t.Errorf("error message should redact receiver: %v", err)
+	}
+}
+
+// TestCustomReceiverValidator 测试自定义校验规则
+func TestCustomReceiverValidator(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1", "test_app_id", "test_secret",
+		WithChannelType(1, MessageTypeSMS),
+		WithReceiverValidator(MessageTypeSMS, func(receiver string) error {
+			if !strings.HasPrefix(receiver, "1") {
+				return errors.New("only mainland numbers")
+			}
+			return nil
+		}),
+	)
+
+	_, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "+14155552671"})
+	var recvErr *ReceiverError
+	if !errors.As(err, &recvErr) || recvErr.Reason != "only mainland numbers" {
+		t.Fatalf("expected custom ReceiverError, got %v", err)
+	}
+}
+
+// TestValidateReceiverFromChannelCache 测试未声明类型时使用 ListChannels 缓存的通道消息类型
+func TestValidateReceiverFromChannelCache(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1", "test_app_id", "test_secret",
+		WithChannelType(3, MessageTypeSMS),
+	)
+	client.channels.store([]ChannelInfo{
+		{ID: 2, MessageType: MessageTypeEmail, Status: ChannelStatusEnabled},
+		{ID: 3, MessageType: MessageTypeEmail, Status: ChannelStatusEnabled},
+	})
+
+	_, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 2, Receiver: "13800138000"})
+	var recvErr *ReceiverError
+	if !errors.As(err, &recvErr) || recvErr.MessageType != MessageTypeEmail {
+		t.Fatalf("expected email ReceiverError, got %v", err)
+	}
+
+	// 声明的类型优先于缓存
+	_, err = client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 3, Receiver: "user@example.com"})
+	if !errors.As(err, &recvErr) || recvErr.MessageType != MessageTypeSMS {
+		t.Fatalf("expected sms ReceiverError, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// reconcilePageSize 对账时每页拉取的任务数
+const reconcilePageSize = 200
+
+// 对账结果
+const (
+	OutcomeDelivered = "delivered" // 已送达（回调确认）
+	OutcomeFailed    = "failed"    // 发送失败或被拒绝
+	OutcomeUnknown   = "unknown"   // 尚无回执或未找到任务
+)
+
+// BatchTasksPage 批次任务分页数据
+type BatchTasksPage struct {
+	Items    []QueryTaskData `json:"items"`     // 任务列表
+	Total    int             `json:"total"`     // 任务总数
+	Page     int             `json:"page"`      // 当前页码（从1开始）
+	PageSize int             `json:"page_size"` // 每页数量
+
+	RawData // 原始JSON，见 RawData
+}
+
+// ListBatchTasks 分页查询批次内的任务
+func (c *Client) ListBatchTasks(ctx context.Context, batchID string, page, pageSize int) (*BatchTasksPage, error) {
+	if err := c.requireFeature(ctx, FeatureBatchDetail); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("page_size", strconv.Itoa(pageSize))
+	path := "/api/v1/batches/" + batchID + "/tasks?" + query.Encode()
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data BatchTasksPage
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// ReconciliationEntry 单个接收者的对账记录
+type ReconciliationEntry struct {
+	Receiver       string // 接收者
+	TaskID         string // 任务ID，未找到任务时为空
+	Status         string // 任务状态
+	CallbackStatus string // 回调状态
+	Outcome        string // 对账结果，见 Outcome* 常量
+	Reason         string // 失败或未知的原因
+	UpdatedAt      string // 最后更新时间
+}
+
+// ReconciliationTotals 对账汇总
+type ReconciliationTotals struct {
+	Submitted int // 提交的接收者数
+	Delivered int // 已送达
+	Failed    int // 失败
+	Unknown   int // 未知
+}
+
+// ReconciliationReport 批次对账报告
+type ReconciliationReport struct {
+	BatchID     string                // 批次ID
+	GeneratedAt time.Time             // 生成时间
+	Totals      ReconciliationTotals  // 汇总
+	Entries     []ReconciliationEntry // 对账明细
+}
+
+// ReconcileBatch 生成批次对账报告，逐个比对接收者的送达/失败/未知状态
+// submitted 为提交时的接收者列表（可选），提供时网关中找不到任务的接收者会记为未知
+func (c *Client) ReconcileBatch(ctx context.Context, batchID string, submitted ...string) (*ReconciliationReport, error) {
+	report := &ReconciliationReport{BatchID: batchID, GeneratedAt: time.Now()}
+	found := make(map[string]bool)
+
+	for page := 1; ; page++ {
+		data, err := c.ListBatchTasks(ctx, batchID, page, reconcilePageSize)
+		if err != nil {
+			return nil, fmt.Errorf("list batch tasks page %d: %w", page, err)
+		}
+		for i := range data.Items {
+			entry := reconcileTask(&data.Items[i])
+			found[entry.Receiver] = true
+			report.add(entry)
+		}
+		if len(data.Items) == 0 || page*reconcilePageSize >= data.Total {
+			break
+		}
+	}
+
+	for _, receiver := range submitted {
+		if found[receiver] {
+			continue
+		}
+		found[receiver] = true
+		report.add(ReconciliationEntry{
+			Receiver: receiver,
+			Outcome:  OutcomeUnknown,
+			Reason:   "no task found for receiver",
+		})
+	}
+
+	return report, nil
+}
+
+// reconcileTask 根据任务状态和回调状态判断对账结果
+func reconcileTask(task *QueryTaskData) ReconciliationEntry {
+	entry := ReconciliationEntry{
+		Receiver:       task.Receiver,
+		TaskID:         task.TaskID,
+		Status:         task.Status,
+		CallbackStatus: task.CallbackStatus,
+		UpdatedAt:      task.UpdatedAt,
+	}
+
+	switch {
+	case task.CallbackStatus == CallbackStatusDelivered:
+		entry.Outcome = OutcomeDelivered
+	case task.CallbackStatus == CallbackStatusFailed, task.CallbackStatus == CallbackStatusRejected, task.Status == TaskStatusFailed:
+		entry.Outcome = OutcomeFailed
+		entry.Reason = task.ErrorMessage
+		if entry.Reason == "" {
+			entry.Reason = "callback status: " + task.CallbackStatus
+			if task.CallbackStatus == "" {
+				entry.Reason = "task status: " + task.Status
+			}
+		}
+	default:
+		entry.Outcome = OutcomeUnknown
+		entry.Reason = "no delivery receipt yet"
+	}
+
+	return entry
+}
+
+// add 添加对账记录并更新汇总
+func (r *ReconciliationReport) add(entry ReconciliationEntry) {
+	r.Entries = append(r.Entries, entry)
+	r.Totals.Submitted++
+	switch entry.Outcome {
+	case OutcomeDelivered:
+		r.Totals.Delivered++
+	case OutcomeFailed:
+		r.Totals.Failed++
+	default:
+		r.Totals.Unknown++
+	}
+}
+
+// WriteCSV 以CSV格式导出对账明细（含表头）
+func (r *ReconciliationReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"batch_id", "receiver", "task_id", "status", "callback_status", "outcome", "reason", "updated_at"}); err != nil {
+		return fmt.Errorf("write csv header: %w", err)
+	}
+	for _, e := range r.Entries {
+		record := []string{r.BatchID, e.Receiver, e.TaskID, e.Status, e.CallbackStatus, e.Outcome, e.Reason, e.UpdatedAt}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("write csv record: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteCSVFile 将对账明细导出为CSV文件，按扩展名压缩（如 "reconcile.csv.gz"，见 CompressionForFile）
+func (r *ReconciliationReport) WriteCSVFile(path string) error {
+	f, err := CreateFile(path)
+	if err != nil {
+		return err
+	}
+	if err := r.WriteCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,75 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestReconcileBatch 测试批次对账
+func TestReconcileBatch(t *testing.T) {
+	tasks := []map[string]interface{}{
+		{"task_id": "t1", "receiver": "13800138000", "status": "success", "callback_status": "delivered"},
+		{"task_id": "t2", "receiver": "13800138001", "status": "success", "callback_status": "rejected", "error_message": "空号"},
+		{"task_id": "t3", "receiver": "13800138002", "status": "failed"},
+		{"task_id": "t4", "receiver": "13800138003", "status": "processing"},
+	}
+
+	var pages []string
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/batches/b1/tasks" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		// 第一页返回前3个，第二页返回剩余
+		items := tasks[:3]
+		if page == "2" {
+			items = tasks[3:]
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data":    map[string]interface{}{"items": items, "total": reconcilePageSize + 1, "page_size": reconcilePageSize},
+		})
+	})))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	report, err := client.ReconcileBatch(context.Background(), "b1", "13800138000", "13800138009")
+	if err != nil {
+		t.Fatalf("ReconcileBatch() error = %v", err)
+	}
+
+	if len(pages) != 2 {
+		t.Errorf("pages fetched = %v, want 2", pages)
+	}
+	want := ReconciliationTotals{Submitted: 5, Delivered: 1, Failed: 2, Unknown: 2}
+	if report.Totals != want {
+		t.Errorf("Totals = %+v, want %+v", report.Totals, want)
+	}
+	if report.Entries[1].Reason != "空号" || report.Entries[2].Reason != "task status: failed" {
+		t.Errorf("unexpected reasons: %+v", report.Entries)
+	}
+	if last := report.Entries[4]; last.Receiver != "13800138009" || last.Outcome != OutcomeUnknown {
+		t.Errorf("missing receiver entry = %+v", last)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 || records[0][1] != "receiver" || records[2][5] != OutcomeFailed {
+		t.Errorf("unexpected csv: %v", records)
+	}
+}
@@ -0,0 +1,83 @@
+package mlievpush
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Redactor 脱敏策略，SDK 在钩子、错误信息、最近错误记录和签名调试信息中输出接收者或内容时统一使用
+// 安全团队可以通过 WithRedactor 一次性替换策略，而无需逐个审查日志输出点
+type Redactor interface {
+	RedactReceiver(receiver string) string // 脱敏单个接收者（手机号、邮箱等）
+	RedactText(text string) string         // 脱敏可能包含接收者的自由文本（错误信息、请求参数等）
+}
+
+// DefaultRedactor 默认脱敏策略：手机号保留前3位和后4位，邮箱隐藏用户名
+var DefaultRedactor Redactor = defaultRedactor{}
+
+// NoopRedactor 不做任何脱敏，仅用于本地调试
+var NoopRedactor Redactor = noopRedactor{}
+
+// WithRedactor 设置脱敏策略（默认 DefaultRedactor），传入nil时忽略
+func WithRedactor(redactor Redactor) ClientOption {
+	return func(c *Client) {
+		if redactor != nil {
+			c.redactor = redactor
+		}
+	}
+}
+
+// Redactor 返回客户端使用的脱敏策略，便于在自定义钩子中保持一致
+func (c *Client) Redactor() Redactor {
+	return c.redactor
+}
+
+// defaultRedactor 默认脱敏策略
+type defaultRedactor struct{}
+
+// RedactReceiver 实现 Redactor 接口
+func (defaultRedactor) RedactReceiver(receiver string) string {
+	return redactMessage(receiver)
+}
+
+// RedactText 实现 Redactor 接口
+func (defaultRedactor) RedactText(text string) string {
+	return redactMessage(text)
+}
+
+// noopRedactor 不脱敏
+type noopRedactor struct{}
+
+// RedactReceiver 实现 Redactor 接口
+func (noopRedactor) RedactReceiver(receiver string) string { return receiver }
+
+// RedactText 实现 Redactor 接口
+func (noopRedactor) RedactText(text string) string { return text }
+
+// redactReceivers 脱敏接收者列表
+func redactReceivers(redactor Redactor, receivers []string) []string {
+	if len(receivers) == 0 {
+		return nil
+	}
+	redacted := make([]string, len(receivers))
+	for i, receiver := range receivers {
+		redacted[i] = redactor.RedactReceiver(receiver)
+	}
+	return redacted
+}
+
+var (
+	phonePattern = regexp.MustCompile(`\d{7,}`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+)
+
+// redactMessage 对错误信息中的手机号、邮箱等接收者信息脱敏
+func redactMessage(msg string) string {
+	msg = emailPattern.ReplaceAllString(msg, "***@$1")
+	return phonePattern.ReplaceAllStringFunc(msg, func(s string) string {
+		if len(s) < 11 {
+			return strings.Repeat("*", len(s))
+		}
+		return s[:3] + strings.Repeat("*", len(s)-7) + s[len(s)-4:]
+	})
+}
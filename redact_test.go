@@ -0,0 +1,59 @@
+package mlievpush
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// hashRedactor 测试用脱敏策略
+type hashRedactor struct{}
+
+func (hashRedactor) RedactReceiver(receiver string) string { return "<receiver>" }
+func (hashRedactor) RedactText(text string) string {
+	return strings.ReplaceAll(text, "13800138000", "<receiver>")
+}
+
+// TestWithRedactor 测试自定义脱敏策略应用于钩子和错误
+func TestWithRedactor(t *testing.T) {
+	server := newSuccessServer(t)
+
+	var receivers []string
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithRedactor(hashRedactor{}),
+		WithChannelType(2, MessageTypeEmail),
+		WithHooks(Hooks{OnRequest: func(ctx context.Context, info *RequestInfo) {
+			receivers = info.Receivers
+		}}),
+	)
+
+	if _, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if len(receivers) != 1 || receivers[0] != "<receiver>" {
+		t.Errorf("hook receivers = %v", receivers)
+	}
+
+	_, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 2, Receiver: "not-an-email"})
+	if err == nil || !strings.Contains(err.Error(), "<receiver>") || strings.Contains(err.Error(), "not-an-email") {
+		t.Errorf("receiver error should use custom redactor: %v", err)
+	}
+}
+
+// TestDefaultRedactorHooks 测试默认脱敏策略
+func TestDefaultRedactorHooks(t *testing.T) {
+	server := newSuccessServer(t)
+
+	var receivers []string
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithHooks(Hooks{
+		OnRequest: func(ctx context.Context, info *RequestInfo) { receivers = info.Receivers },
+	}))
+
+	client.SendBatch(context.Background(), &SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000", "user@example.com"}})
+	if len(receivers) != 2 || receivers[0] != "138****8000" || receivers[1] != "***@example.com" {
+		t.Errorf("hook receivers = %v", receivers)
+	}
+	if client.Redactor() != DefaultRedactor {
+		t.Error("default redactor expected")
+	}
+}
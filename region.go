@@ -0,0 +1,128 @@
+package mlievpush
+
+import (
+	"fmt"
+	"strings"
+)
+
+// callingCodeRegions 国际电话区号 -> 主要地区（ISO 3166-1 alpha-2）
+// 多个地区共用的区号（如北美 "1"）取主要地区，需要精确地区时请显式设置 Region
+var callingCodeRegions = map[string]string{
+	"1":   "US",
+	"7":   "RU",
+	"20":  "EG",
+	"27":  "ZA",
+	"30":  "GR",
+	"31":  "NL",
+	"32":  "BE",
+	"33":  "FR",
+	"34":  "ES",
+	"39":  "IT",
+	"41":  "CH",
+	"43":  "AT",
+	"44":  "GB",
+	"45":  "DK",
+	"46":  "SE",
+	"47":  "NO",
+	"48":  "PL",
+	"49":  "DE",
+	"52":  "MX",
+	"55":  "BR",
+	"60":  "MY",
+	"61":  "AU",
+	"62":  "ID",
+	"63":  "PH",
+	"64":  "NZ",
+	"65":  "SG",
+	"66":  "TH",
+	"81":  "JP",
+	"82":  "KR",
+	"84":  "VN",
+	"86":  "CN",
+	"90":  "TR",
+	"91":  "IN",
+	"92":  "PK",
+	"234": "NG",
+	"351": "PT",
+	"353": "IE",
+	"852": "HK",
+	"853": "MO",
+	"855": "KH",
+	"856": "LA",
+	"880": "BD",
+	"886": "TW",
+	"966": "SA",
+	"971": "AE",
+	"972": "IL",
+	"974": "QA",
+	"977": "NP",
+}
+
+// phoneSeparators 手机号中允许的分隔符
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+
+// DetectCountry 根据 E.164 号码前缀识别国际区号和地区
+// 号码需以 "+" 或 "00" 开头，无法识别时 ok 为 false
+func DetectCountry(phone string) (countryCode, region string, ok bool) {
+	digits := phoneSeparators.Replace(strings.TrimSpace(phone))
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits = digits[1:]
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	default:
+		return "", "", false
+	}
+
+	// 区号最长3位，按最长前缀匹配
+	for n := 3; n >= 1; n-- {
+		if len(digits) <= n {
+			continue
+		}
+		if region, ok := callingCodeRegions[digits[:n]]; ok {
+			return digits[:n], region, true
+		}
+	}
+	return "", "", false
+}
+
+// NormalizePhoneNumber 将手机号规范化为 E.164 格式（+<区号><号码>）
+// 去除空格、连字符、括号等分隔符，"00" 前缀视为国际前缀；
+// 不带国际前缀的号码使用 defaultCountryCode（如 "86"）补全，并去掉国内长途前缀 "0"
+func NormalizePhoneNumber(phone, defaultCountryCode string) (string, error) {
+	digits := phoneSeparators.Replace(strings.TrimSpace(phone))
+	switch {
+	case strings.HasPrefix(digits, "+"):
+	case strings.HasPrefix(digits, "00"):
+		digits = "+" + digits[2:]
+	case defaultCountryCode != "":
+		digits = "+" + strings.TrimPrefix(defaultCountryCode, "+") + strings.TrimLeft(digits, "0")
+	default:
+		return "", fmt.Errorf("phone number %q has no country code", phone)
+	}
+
+	if !phoneNumberPattern.MatchString(digits) {
+		return "", fmt.Errorf("not a valid phone number: %q", phone)
+	}
+	return digits, nil
+}
+
+// detectCountry 为短信/语音通道识别接收者的国际区号，多个接收者区号不一致时返回空
+func (c *Client) detectCountry(channelID int, receivers ...string) (countryCode, region string) {
+	if messageType, ok := c.channelType(channelID); ok && messageType != MessageTypeSMS && messageType != MessageTypeVoice {
+		return "", ""
+	}
+
+	for i, receiver := range receivers {
+		code, r, ok := DetectCountry(receiver)
+		if !ok {
+			return "", ""
+		}
+		if i > 0 && code != countryCode {
+			// 混合区号的批次由网关逐个路由
+			return "", ""
+		}
+		countryCode, region = code, r
+	}
+	return countryCode, region
+}
@@ -0,0 +1,93 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestDetectCountry 测试根据号码前缀识别区号
+func TestDetectCountry(t *testing.T) {
+	tests := []struct {
+		phone  string
+		code   string
+		region string
+		ok     bool
+	}{
+		{"+8613800138000", "86", "CN", true},
+		{"+1 415-555-2671", "1", "US", true},
+		{"0085291234567", "852", "HK", true},
+		{"+44 20 7946 0958", "44", "GB", true},
+		{"13800138000", "", "", false},
+		{"+999123456", "", "", false},
+	}
+
+	for _, tt := range tests {
+		code, region, ok := DetectCountry(tt.phone)
+		if code != tt.code || region != tt.region || ok != tt.ok {
+			t.Errorf("DetectCountry(%q) = %q, %q, %v, want %q, %q, %v", tt.phone, code, region, ok, tt.code, tt.region, tt.ok)
+		}
+	}
+}
+
+// TestNormalizePhoneNumber 测试手机号规范化
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		phone   string
+		def     string
+		want    string
+		wantErr bool
+	}{
+		{"138 0013 8000", "86", "+8613800138000", false},
+		{"+1 (415) 555-2671", "86", "+14155552671", false},
+		{"0044 20 7946 0958", "", "+442079460958", false},
+		{"020 7946 0958", "+44", "+442079460958", false},
+		{"13800138000", "", "", true},
+		{"+86abc", "", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizePhoneNumber(tt.phone, tt.def)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizePhoneNumber(%q, %q) = %q, %v, want %q", tt.phone, tt.def, got, err, tt.want)
+		}
+	}
+}
+
+// TestSendCountryCodeDetection 测试发送时自动填充区号
+func TestSendCountryCodeDetection(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithChannelType(3, MessageTypeEmail))
+	ctx := context.Background()
+
+	client.SendMessage(ctx, &SendMessageRequest{ChannelID: 1, Receiver: "+8613800138000"})
+	client.SendBatch(ctx, &SendBatchRequest{ChannelID: 1, Receivers: []string{"+14155552671", "+8613800138000"}})
+	client.SendMessage(ctx, &SendMessageRequest{ChannelID: 1, Receiver: "+8613800138000", Region: "CN", CountryCode: "86"})
+	client.SendMessage(ctx, &SendMessageRequest{ChannelID: 3, Receiver: "user@example.com"})
+
+	if len(bodies) != 4 {
+		t.Fatalf("requests = %d, want 4", len(bodies))
+	}
+	if bodies[0]["country_code"] != "86" || bodies[0]["region"] != "CN" {
+		t.Errorf("single send: %v", bodies[0])
+	}
+	if _, ok := bodies[1]["country_code"]; ok {
+		t.Errorf("mixed batch should not set country_code: %v", bodies[1])
+	}
+	if bodies[2]["country_code"] != "86" {
+		t.Errorf("explicit country code: %v", bodies[2])
+	}
+	if _, ok := bodies[3]["country_code"]; ok {
+		t.Errorf("email channel should not set country_code: %v", bodies[3])
+	}
+}
@@ -0,0 +1,96 @@
+package mlievpush
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TenantConfig 租户凭证及限制，用于 ClientRegistry.Register
+type TenantConfig struct {
+	BaseURL   string           // 网关地址
+	AppID     string           // 应用ID
+	AppSecret string           // 应用密钥
+	RateLimit *RateLimitConfig // 租户独立的客户端限流（可选），各租户互不占用令牌
+	Quota     AccountQuota     // 租户发送配额（可选），零值表示不限
+	Options   []ClientOption   // 租户专属的客户端选项（可选），在注册表的公共选项之后应用
+}
+
+// ClientRegistry 多租户客户端注册表，适用于代表多个应用发送消息的 SaaS 平台
+// 按租户管理 (baseURL, appID, 密钥) 凭证，所有租户共享同一个传输层（连接池），限流和配额按租户独立计算；
+// 发送、批量发送和 FanOut 等方法继承自 ClientManager，账号名称即租户名称
+type ClientRegistry struct {
+	*ClientManager
+
+	transport http.RoundTripper // 所有租户共享的传输层
+	opts      []ClientOption    // 所有租户的公共客户端选项
+}
+
+// NewClientRegistry 创建多租户客户端注册表，transport 为nil时使用 http.DefaultTransport 的副本（已被替换为非 *http.Transport 时使用标准库默认配置），opts 应用于所有租户的客户端
+// 公共选项中的 WithTimeout 只影响各租户自己的 http.Client；WithProxy、WithTLSConfig 等修改传输层的选项会为该租户复制一份传输层，不再共享连接池
+func NewClientRegistry(transport http.RoundTripper, opts ...ClientOption) *ClientRegistry {
+	if transport == nil {
+		transport = newDefaultTransport()
+	}
+	return &ClientRegistry{
+		ClientManager: NewClientManager(),
+		transport:     transport,
+		opts:          opts,
+	}
+}
+
+// Register 注册或替换租户的客户端并返回；替换时新凭证立即生效，该租户的配额计数重新开始
+func (r *ClientRegistry) Register(tenant string, cfg TenantConfig) (*Client, error) {
+	if tenant == "" {
+		return nil, errors.New("tenant name is required")
+	}
+	if cfg.BaseURL == "" || cfg.AppID == "" || cfg.AppSecret == "" {
+		return nil, fmt.Errorf("tenant %q: base_url, app_id and app_secret are required", tenant)
+	}
+
+	opts := make([]ClientOption, 0, len(r.opts)+len(cfg.Options)+2)
+	opts = append(opts, WithHTTPClient(&http.Client{Timeout: 10 * time.Second, Transport: r.transport}))
+	opts = append(opts, r.opts...)
+	if rl := cfg.RateLimit; rl != nil {
+		opts = append(opts, WithRateLimit(rl.PerSecond, rl.Burst))
+	}
+	opts = append(opts, cfg.Options...)
+
+	client := NewClient(cfg.BaseURL, cfg.AppID, cfg.AppSecret, opts...)
+	r.Add(tenant, client, cfg.Quota)
+	return client, nil
+}
+
+// Transport 返回所有租户共享的传输层
+func (r *ClientRegistry) Transport() http.RoundTripper {
+	return r.transport
+}
+
+// CloseIdleConnections 关闭共享传输层中的空闲连接，如在批量移除租户后释放资源
+func (r *ClientRegistry) CloseIdleConnections() {
+	if t, ok := r.transport.(interface{ CloseIdleConnections() }); ok {
+		t.CloseIdleConnections()
+	}
+}
+
+// NewClientRegistry 为配置文件中的每个命名配置注册一个租户，租户名称即配置名；
+// 配置中的限流、配额等设置作为租户专属选项，opts 为所有租户的公共选项
+func (c *Config) NewClientRegistry(transport http.RoundTripper, opts ...ClientOption) (*ClientRegistry, error) {
+	r := NewClientRegistry(transport, opts...)
+	for name, p := range c.Profiles {
+		secret, err := p.secret()
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		var quota AccountQuota
+		if q := p.Quota; q != nil {
+			quota = AccountQuota{Limit: q.Limit, Period: time.Duration(q.Period)}
+		}
+		cfg := TenantConfig{BaseURL: p.BaseURL, AppID: p.AppID, AppSecret: secret, Quota: quota, Options: p.Options()}
+		if _, err := r.Register(name, cfg); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,91 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestClientRegistry 测试多租户注册、共享传输层和按租户限流
+func TestClientRegistry(t *testing.T) {
+	const baseURL = "https://push.example.com"
+	transport := &recordingTransport{}
+
+	r := NewClientRegistry(transport, WithTimeout(5*time.Second))
+	if _, err := r.Register("acme", TenantConfig{BaseURL: baseURL, AppID: "app_acme", AppSecret: "secret_acme",
+		RateLimit: &RateLimitConfig{PerSecond: 1, Burst: 1}}); err != nil {
+		t.Fatalf("Register(acme) error = %v", err)
+	}
+	if _, err := r.Register("globex", TenantConfig{BaseURL: baseURL, AppID: "app_globex", AppSecret: "secret_globex",
+		Quota: AccountQuota{Limit: 1, Period: time.Hour}}); err != nil {
+		t.Fatalf("Register(globex) error = %v", err)
+	}
+	if _, err := r.Register("broken", TenantConfig{BaseURL: baseURL, AppID: "app"}); err == nil {
+		t.Error("Register() without secret should fail")
+	}
+
+	ctx := context.Background()
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", SignatureName: "test"}
+	if _, err := r.SendMessage(ctx, "acme", req); err != nil {
+		t.Fatalf("SendMessage(acme) error = %v", err)
+	}
+	// acme 的令牌已用完，不影响 globex
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := r.SendMessage(shortCtx, "acme", req); err == nil {
+		t.Error("acme should be rate limited")
+	}
+	if _, err := r.SendMessage(ctx, "globex", req); err != nil {
+		t.Fatalf("SendMessage(globex) error = %v", err)
+	}
+	if _, err := r.SendMessage(ctx, "globex", req); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("SendMessage(globex) error = %v, want ErrQuotaExceeded", err)
+	}
+
+	apps := make(map[string]int)
+	for _, req := range transport.requests {
+		apps[req.Header.Get(HeaderAppID)]++
+	}
+	if apps["app_acme"] != 1 || apps["app_globex"] != 1 {
+		t.Errorf("requests by app = %v", apps)
+	}
+
+	acme, err := r.Client("acme")
+	if err != nil || acme.httpClient.Transport != transport || acme.httpClient.Timeout != 5*time.Second {
+		t.Errorf("acme client should use shared transport and common options")
+	}
+
+	r.Remove("acme")
+	if got := r.Accounts(); len(got) != 1 || got[0] != "globex" {
+		t.Errorf("Accounts() = %v", got)
+	}
+	if _, err := r.SendMessage(ctx, "acme", req); err == nil {
+		t.Error("removed tenant should fail")
+	}
+}
+
+// TestConfigNewClientRegistry 测试从配置文件创建多租户注册表
+func TestConfigNewClientRegistry(t *testing.T) {
+	cfg := &Config{Profiles: map[string]ProfileConfig{
+		"acme":   {BaseURL: "https://push.example.com", AppID: "app_acme", AppSecret: "secret_acme", Quota: &QuotaConfig{Limit: 1}},
+		"globex": {BaseURL: "https://push.example.com", AppID: "app_globex", AppSecret: "secret_globex"},
+	}}
+	transport := &recordingTransport{}
+	r, err := cfg.NewClientRegistry(transport)
+	if err != nil {
+		t.Fatalf("NewClientRegistry() error = %v", err)
+	}
+	if got := r.Accounts(); len(got) != 2 {
+		t.Fatalf("Accounts() = %v", got)
+	}
+	globex, _ := r.Client("globex")
+	if globex.httpClient.Transport != transport {
+		t.Error("tenant client should use shared transport")
+	}
+
+	cfg.Profiles["broken"] = ProfileConfig{BaseURL: "https://push.example.com", AppID: "app"}
+	if _, err := cfg.NewClientRegistry(nil); err == nil {
+		t.Error("profile without secret should fail")
+	}
+}
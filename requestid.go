@@ -0,0 +1,57 @@
+package mlievpush
+
+import (
+	"errors"
+	"strconv"
+)
+
+// HeaderRequestID 客户端请求ID请求头，每次尝试（含重试）各不相同
+const HeaderRequestID = "X-Request-Id"
+
+// newRequestID 生成单次尝试的请求ID: <operation_id>-<attempt>
+// 同一次逻辑调用的多次重试共享 operation_id，便于在网关日志中串联
+func newRequestID(operationID string, attempt int) string {
+	return operationID + "-" + strconv.Itoa(attempt)
+}
+
+// requestIDError 附带请求ID的错误
+type requestIDError struct {
+	requestID string
+	err       error
+}
+
+// Error 实现 error 接口
+func (e *requestIDError) Error() string {
+	return e.err.Error() + " (request_id=" + e.requestID + ")"
+}
+
+// Unwrap 返回原始错误
+func (e *requestIDError) Unwrap() error {
+	return e.err
+}
+
+// withRequestID 为请求错误附加请求ID
+// *APIError 直接填充 RequestID 字段，保持 IsAPIError 等类型判断可用
+func withRequestID(err error, requestID string) error {
+	if err == nil {
+		return nil
+	}
+	if apiErr, ok := err.(*APIError); ok {
+		apiErr.RequestID = requestID
+		return apiErr
+	}
+	return &requestIDError{requestID: requestID, err: err}
+}
+
+// RequestIDFromError 获取错误对应的请求ID，非请求错误返回空字符串
+func RequestIDFromError(err error) string {
+	var idErr *requestIDError
+	if errors.As(err, &idErr) {
+		return idErr.requestID
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RequestID
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestRequestID 测试请求ID贯穿请求头、钩子和错误
+func TestRequestID(t *testing.T) {
+	var headerID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headerID = r.Header.Get(HeaderRequestID)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeChannelNotFound, "message": "通道不存在"})
+	}))
+	defer server.Close()
+
+	var reqInfo *RequestInfo
+	var respInfo *ResponseInfo
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithHooks(Hooks{
+		OnRequest:  func(ctx context.Context, info *RequestInfo) { reqInfo = info },
+		OnResponse: func(ctx context.Context, info *ResponseInfo) { respInfo = info },
+	}))
+
+	_, err := client.QueryTask(context.Background(), "t1")
+	if !IsAPIError(err) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+
+	if headerID == "" || !strings.HasPrefix(headerID, reqInfo.OperationID+"-") {
+		t.Errorf("header request id = %q, operation id = %q", headerID, reqInfo.OperationID)
+	}
+	if reqInfo.RequestID != headerID || respInfo.RequestID != headerID || reqInfo.Attempt != 1 {
+		t.Errorf("hook request id = %q/%q attempt = %d, want %q", reqInfo.RequestID, respInfo.RequestID, reqInfo.Attempt, headerID)
+	}
+	if got := RequestIDFromError(err); got != headerID {
+		t.Errorf("RequestIDFromError() = %q, want %q", got, headerID)
+	}
+	if recent := client.RecentErrors(); len(recent) != 1 || recent[0].RequestID != headerID {
+		t.Errorf("RecentErrors() = %+v", recent)
+	}
+}
+
+// TestRequestIDTransportError 测试网络错误附带请求ID
+func TestRequestIDTransportError(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1", "test_app_id", "test_secret")
+
+	_, err := client.QueryTask(context.Background(), "t1")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if RequestIDFromError(err) == "" || !strings.Contains(err.Error(), "request_id=") {
+		t.Errorf("error should carry request id: %v", err)
+	}
+	if errors.Unwrap(err) == nil {
+		t.Error("original error should be unwrappable")
+	}
+
+	// 两次调用的请求ID不同
+	_, err2 := client.QueryTask(context.Background(), "t1")
+	if RequestIDFromError(err) == RequestIDFromError(err2) {
+		t.Error("request ids should be unique per call")
+	}
+}
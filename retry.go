@@ -0,0 +1,148 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Backoff 指数退避策略
+type Backoff struct {
+	Initial    time.Duration // 首次重试前的等待时间
+	Max        time.Duration // 单次等待时间上限，0表示不限制
+	Multiplier float64       // 每次重试等待时间的增长倍数，小于1时按1处理
+	Jitter     float64       // 随机抖动比例（0~1），实际等待时间在 [d*(1-Jitter), d*(1+Jitter)] 范围内
+}
+
+// DefaultBackoff 默认退避策略：200ms 起步，每次翻倍，最长 5s，±20% 抖动
+var DefaultBackoff = Backoff{
+	Initial:    200 * time.Millisecond,
+	Max:        5 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Delay 计算第 retry 次重试（从1开始）前的等待时间
+func (b Backoff) Delay(retry int) time.Duration {
+	return b.delay(retry, -1)
+}
+
+// delay 使用给定的 [0, 1) 随机数计算等待时间，random 小于0时使用全局随机源
+func (b Backoff) delay(retry int, random float64) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+	multiplier := math.Max(b.Multiplier, 1)
+	d := float64(b.Initial) * math.Pow(multiplier, float64(retry-1))
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		jitter := math.Min(b.Jitter, 1)
+		if random < 0 {
+			random = rand.Float64()
+		}
+		d *= 1 - jitter + 2*jitter*random
+	}
+	return time.Duration(d)
+}
+
+// WithRetry 开启自动重试，maxAttempts 为总尝试次数（含首次），小于等于1表示不重试
+// 网络错误、HTTP 5xx/429 以及 ErrorCodeAdvice 中 Retryable 的业务错误（30001 超出速率限制、30006 无可用通道、40006 网络超时、40007 熔断器打开）会按退避策略重试，其他业务错误不重试；
+// 服务端返回 Retry-After 时等待时间不小于该值。重试等待期间 ctx 结束会立即返回最后一次的错误。
+// 开启重试后，未设置 IdempotencyKey 的发送请求会自动生成幂等键，网关已处理但响应丢失时重试不会重复下发
+func WithRetry(maxAttempts int, backoff Backoff) ClientOption {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.backoff = backoff
+	}
+}
+
+// 重试决策原因，见 RetryInfo.Reason
+const (
+	RetryReasonNetwork      = "network"       // 连接失败、超时等传输层错误（重试）
+	RetryReasonHTTPStatus   = "http_status"   // HTTP 5xx/429（重试）
+	RetryReasonAPIError     = "api_error"     // 可重试的业务错误：30001 超出速率限制、30006 无可用通道、40006 网络超时、40007 熔断器打开（重试）
+	RetryReasonNotRetryable = "not_retryable" // 错误不可重试，如参数错误、鉴权失败（放弃）
+	RetryReasonCanceled     = "canceled"      // ctx 已取消或超时（放弃）
+	RetryReasonMaxAttempts  = "max_attempts"  // 已达到最大尝试次数或未开启重试（放弃）
+	RetryReasonDeadline     = "deadline"      // 退避等待结束前 ctx 就会超时（放弃）
+)
+
+// classifyRetry 判断单次尝试的错误是否可以重试，并返回决策原因
+func classifyRetry(err error, statusCode int) (bool, string) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false, RetryReasonCanceled
+	}
+	if isStreamInterrupted(err) {
+		// 已处理部分数据，重试会导致重复处理
+		return false, RetryReasonNotRetryable
+	}
+	var credErr *CredentialsError
+	if errors.As(err, &credErr) {
+		// 凭证提供者失败时请求未发送，重试前应由提供者自行处理（如 CachedCredentials 使用上一次的凭证）
+		return false, RetryReasonNotRetryable
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		// 是否重试由 ErrorCodeAdvice 中的 Retryable 决定，仅限网关明确的瞬时故障：
+		// 速率限制和无可用通道时消息未被受理；其他系统错误（如 40005 服务商错误）时消息可能已提交给服务商，重试可能导致重复下发
+		if apiErr.Retryable {
+			return true, RetryReasonAPIError
+		}
+		return false, RetryReasonNotRetryable
+	}
+	if statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests {
+		return true, RetryReasonHTTPStatus
+	}
+
+	// 连接失败、超时等传输层错误
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true, RetryReasonNetwork
+	}
+	return false, RetryReasonNotRetryable
+}
+
+// retryIdempotencyKey 开启重试且调用方未设置幂等键时为发送请求生成幂等键，同一逻辑调用的所有尝试共用该键
+func (c *Client) retryIdempotencyKey(ctx context.Context, key string) string {
+	if key != "" || c.maxAttempts <= 1 {
+		return key
+	}
+	if o := callOptionsFrom(ctx); o != nil && o.noRetry {
+		return key
+	}
+	return "retry:" + c.newNonce()
+}
+
+// retryDelay 计算下一次重试前的等待时间
+func (c *Client) retryDelay(retry int, err error) time.Duration {
+	delay := c.backoff.delay(retry, c.randFloat64())
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	return delay
+}
+
+// sleepContext 等待指定时间，ctx 结束时提前返回 false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		// 等待结束前 ctx 就会超时，无需再等
+		return false
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,239 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyServer 前 failures 次请求返回指定响应，之后返回成功
+func newFlakyServer(t *testing.T, failures int32, fail func(w http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			fail(w)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// TestRetry 测试可重试错误自动重试
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		fail      func(w http.ResponseWriter)
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"HTTP 503", func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) }, 3, false},
+		{"熔断器打开", func(w http.ResponseWriter) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeCircuitOpen, "message": "熔断器打开"})
+		}, 3, false},
+		{"超出速率限制", func(w http.ResponseWriter) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeRateLimitExceeded, "message": "超出速率限制"})
+		}, 3, false},
+		{"超出速率限制（HTTP 429）", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeRateLimitExceeded, "message": "超出速率限制"})
+		}, 3, false},
+		{"服务商错误不重试", func(w http.ResponseWriter) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeProviderError, "message": "服务商错误"})
+		}, 1, true},
+		{"参数错误不重试", func(w http.ResponseWriter) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeInvalidParams, "message": "请求参数错误"})
+		}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newFlakyServer(t, 2, tt.fail)
+
+			var attempts []int
+			var operationIDs []string
+			client := NewClient(server.URL, "test_app_id", "test_secret",
+				WithRetry(3, Backoff{Initial: time.Millisecond}),
+				WithHooks(Hooks{OnRequest: func(ctx context.Context, info *RequestInfo) {
+					attempts = append(attempts, info.Attempt)
+					operationIDs = append(operationIDs, info.OperationID)
+				}}),
+			)
+
+			_, err := client.QueryTask(context.Background(), "t1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls.Load(), tt.wantCalls)
+			}
+			for i := range attempts {
+				if attempts[i] != i+1 || operationIDs[i] != operationIDs[0] {
+					t.Errorf("attempt %d: got attempt=%d operation=%s", i, attempts[i], operationIDs[i])
+				}
+			}
+		})
+	}
+}
+
+// TestRetryIdempotencyKey 测试开启重试时发送请求自动生成幂等键，所有尝试共用同一个键
+func TestRetryIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		keys = append(keys, body.IdempotencyKey)
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithRetry(3, Backoff{Initial: time.Millisecond}))
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", SignatureName: "test"}
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("idempotency keys = %q, want the same generated key on every attempt", keys)
+	}
+	if req.IdempotencyKey != "" {
+		t.Errorf("caller's request was modified: %q", req.IdempotencyKey)
+	}
+
+	keys = nil
+	req.IdempotencyKey = "order-1"
+	if _, err := client.SendMessage(context.Background(), req, WithNoRetry()); err == nil {
+		t.Fatal("expected error without retry")
+	}
+	if len(keys) != 1 || keys[0] != "order-1" {
+		t.Errorf("idempotency keys = %q, want caller's key", keys)
+	}
+
+	keys = nil
+	req.IdempotencyKey = ""
+	client = NewClient(server.URL, "test_app_id", "test_secret")
+	client.SendMessage(context.Background(), req)
+	if len(keys) != 1 || keys[0] != "" {
+		t.Errorf("idempotency keys = %q, want none without retry", keys)
+	}
+}
+
+// TestRetryExhausted 测试重试次数用尽后返回最后一次错误
+func TestRetryExhausted(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, func(w http.ResponseWriter) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeNetworkTimeout, "message": "网络超时"})
+	})
+
+	var errorHooks int
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithRetry(2, Backoff{Initial: time.Millisecond}),
+		WithHooks(Hooks{OnError: func(ctx context.Context, err error) { errorHooks++ }}),
+	)
+
+	_, err := client.QueryTask(context.Background(), "t1")
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Code != ErrCodeNetworkTimeout {
+		t.Fatalf("expected network timeout APIError, got %v", err)
+	}
+	if calls.Load() != 2 || errorHooks != 1 {
+		t.Errorf("calls = %d, OnError = %d, want 2 and 1", calls.Load(), errorHooks)
+	}
+}
+
+// TestRetryContextCanceled 测试等待重试期间 ctx 结束立即返回
+func TestRetryContextCanceled(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) })
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithRetry(5, Backoff{Initial: time.Second}))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.QueryTask(ctx, "t1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("should not wait for backoff beyond ctx deadline, took %v", elapsed)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+// TestRetryHook 测试每次重试决策触发 OnRetry
+func TestRetryHook(t *testing.T) {
+	server, _ := newFlakyServer(t, 10, func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) })
+
+	var infos []RetryInfo
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithRetry(3, Backoff{Initial: time.Millisecond}),
+		WithCircuitBreaker(BreakerConfig{FailureThreshold: 10}),
+		WithHooks(Hooks{OnRetry: func(ctx context.Context, info *RetryInfo) { infos = append(infos, *info) }}),
+	)
+
+	if _, err := client.QueryTask(context.Background(), "t1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(infos) != 3 {
+		t.Fatalf("OnRetry calls = %d, want 3", len(infos))
+	}
+	for i, info := range infos[:2] {
+		if !info.Retry || info.Reason != RetryReasonHTTPStatus || info.Attempt != i+1 || info.MaxAttempts != 3 ||
+			info.Backoff <= 0 || info.BreakerState != BreakerClosed || info.Err == nil {
+			t.Errorf("retry %d: %+v", i, info)
+		}
+	}
+	if last := infos[2]; last.Retry || last.Reason != RetryReasonMaxAttempts || last.Backoff != 0 {
+		t.Errorf("last decision: %+v", last)
+	}
+
+	infos = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client = NewClient(server.URL, "test_app_id", "test_secret",
+		WithRetry(3, Backoff{Initial: time.Second}),
+		WithHooks(Hooks{OnRetry: func(ctx context.Context, info *RetryInfo) { infos = append(infos, *info) }}),
+	)
+	client.QueryTask(ctx, "t1")
+	if len(infos) != 1 || infos[0].Retry || infos[0].Reason != RetryReasonDeadline || infos[0].BreakerState != "" {
+		t.Errorf("deadline decision: %+v", infos)
+	}
+}
+
+// TestBackoffDelay 测试退避时间计算
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, w := range want {
+		if got := b.Delay(i + 1); got != w {
+			t.Errorf("Delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := b.Delay(1); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("Delay(1) with jitter = %v, out of range", d)
+		}
+	}
+}
+
+// TestClassifyRetryFollowsAdvice 测试业务错误是否重试与 ErrorCodeAdvice 中的 Retryable 一致
+func TestClassifyRetryFollowsAdvice(t *testing.T) {
+	for code, advice := range ErrorCodeAdvice {
+		if retry, _ := classifyRetry(NewAPIError(code, "error"), http.StatusOK); retry != advice.Retryable {
+			t.Errorf("classifyRetry(%d) = %v, want %v", code, retry, advice.Retryable)
+		}
+	}
+}
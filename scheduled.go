@@ -0,0 +1,157 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// FormatScheduledAt 将时间格式化为 ScheduledAt 使用的 ISO 8601 格式（转换为 UTC，避免时区歧义），零值返回空字符串
+func FormatScheduledAt(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return FormatDeadline(t)
+}
+
+// ParseScheduledAt 解析 ScheduledAt/DeadlineAt 格式的时间，必须带时区（Z 或 ±hh:mm），
+// 如 "2025-11-25 10:00:00" 这类不带时区的时间无法确定含义，返回错误
+func ParseScheduledAt(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse time %q: must be RFC 3339 with timezone offset: %w", s, err)
+	}
+	return t, nil
+}
+
+// Schedule 设置定时发送时间，零值表示立即发送
+func (r *SendMessageRequest) Schedule(t time.Time) {
+	r.ScheduledAt = FormatScheduledAt(t)
+}
+
+// Schedule 设置定时发送时间，零值表示立即发送
+func (r *SendBatchRequest) Schedule(t time.Time) {
+	r.ScheduledAt = FormatScheduledAt(t)
+}
+
+// ScheduledTime 解析定时发送时间，非定时消息或格式错误时返回零值
+func (d *QueryTaskData) ScheduledTime() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, d.ScheduledAt)
+	return t
+}
+
+// ListScheduledOptions 定时消息查询条件，零值字段不参与过滤
+type ListScheduledOptions struct {
+	ChannelID int       // 通道ID
+	Receiver  string    // 接收者
+	From      time.Time // 定时发送时间下限（含）
+	To        time.Time // 定时发送时间上限（不含）
+
+	Page     int    // 页码（从1开始），与 Cursor 二选一
+	PageSize int    // 每页数量，0 使用网关默认值
+	Cursor   string // 游标，传入上一页返回的 NextCursor
+}
+
+// query 编码为查询参数
+func (o *ListScheduledOptions) query() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.ChannelID > 0 {
+		query.Set("channel_id", strconv.Itoa(o.ChannelID))
+	}
+	if o.Receiver != "" {
+		query.Set("receiver", o.Receiver)
+	}
+	if !o.From.IsZero() {
+		query.Set("scheduled_from", FormatScheduledAt(o.From))
+	}
+	if !o.To.IsZero() {
+		query.Set("scheduled_to", FormatScheduledAt(o.To))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	} else if o.Page > 0 {
+		query.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	return query
+}
+
+// ListScheduled 分页查询尚未发送的定时消息，按定时发送时间升序排列，opts 为 nil 时返回第一页
+func (c *Client) ListScheduled(ctx context.Context, opts *ListScheduledOptions) (*ListTasksData, error) {
+	if err := c.requireFeature(ctx, FeatureScheduled); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/messages/scheduled"
+	if query := opts.query(); len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var data ListTasksData
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// RescheduleTaskData 修改定时发送时间响应数据
+type RescheduleTaskData struct {
+	TaskID      string `json:"task_id"`      // 任务ID
+	Status      string `json:"status"`       // 任务状态
+	ScheduledAt string `json:"scheduled_at"` // 新的定时发送时间
+
+	RawData // 原始JSON，见 RawData
+}
+
+// ScheduledTime 解析新的定时发送时间，格式错误时返回零值
+func (d *RescheduleTaskData) ScheduledTime() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, d.ScheduledAt)
+	return t
+}
+
+// RescheduleTask 修改定时消息的发送时间，newTime 必须晚于当前时间（本地校验，不会发送请求）
+// 任务已开始发送或已结束时返回错误码为 ErrCodeTaskNotCancelable 的 *APIError
+func (c *Client) RescheduleTask(ctx context.Context, taskID string, newTime time.Time) (*RescheduleTaskData, error) {
+	if err := c.requireFeature(ctx, FeatureScheduled); err != nil {
+		return nil, err
+	}
+	if newTime.IsZero() {
+		return nil, &FieldError{Field: "scheduled_at", Reason: "is required"}
+	}
+	if now := c.timeNow(); !newTime.After(now) {
+		return nil, &FieldError{Field: "scheduled_at", Reason: fmt.Sprintf("must be in the future, got %s", FormatScheduledAt(newTime))}
+	}
+
+	path := "/api/v1/messages/" + taskID + "/schedule"
+	reqData := map[string]interface{}{"scheduled_at": FormatScheduledAt(newTime)}
+
+	var data RescheduleTaskData
+	if err := c.doJSON(ctx, http.MethodPut, path, reqData, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// CancelScheduled 取消尚未发送的定时消息
+// 与 CancelTask 不同，只取消定时消息：任务不是定时消息、已开始发送或已结束时返回错误码为 ErrCodeTaskNotCancelable 的 *APIError
+func (c *Client) CancelScheduled(ctx context.Context, taskID string) (*CancelTaskData, error) {
+	if err := c.requireFeature(ctx, FeatureScheduled); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/messages/" + taskID + "/schedule"
+
+	var data CancelTaskData
+	if err := c.doJSON(ctx, http.MethodDelete, path, nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,106 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestScheduledTimeFormat 测试定时发送时间的格式化与校验
+func TestScheduledTimeFormat(t *testing.T) {
+	at := time.Date(2025, 11, 25, 18, 30, 0, 0, time.FixedZone("CST", 8*3600))
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}
+	req.Schedule(at)
+	if req.ScheduledAt != "2025-11-25T10:30:00Z" {
+		t.Errorf("ScheduledAt = %q", req.ScheduledAt)
+	}
+	if got, err := ParseScheduledAt(req.ScheduledAt); err != nil || !got.Equal(at) {
+		t.Errorf("ParseScheduledAt() = %v, %v", got, err)
+	}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	req.Schedule(time.Time{})
+	if req.ScheduledAt != "" {
+		t.Errorf("zero time: ScheduledAt = %q", req.ScheduledAt)
+	}
+
+	req.ScheduledAt = "2025-11-25 18:30:00"
+	var fieldErr *FieldError
+	if err := req.Validate(); !errors.As(err, &fieldErr) || fieldErr.Field != "scheduled_at" {
+		t.Errorf("expected scheduled_at error, got %v", err)
+	}
+	batch := &SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000"}, DeadlineAt: "2025-11-25T18:30:00"}
+	if err := batch.Validate(); !errors.As(err, &fieldErr) || fieldErr.Field != "deadline_at" {
+		t.Errorf("expected deadline_at error, got %v", err)
+	}
+}
+
+// TestScheduledManagement 测试定时消息查询、改期和取消
+func TestScheduledManagement(t *testing.T) {
+	var got struct {
+		method, path, query string
+		body                map[string]interface{}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.method, got.path, got.query, got.body = r.Method, r.URL.Path, r.URL.RawQuery, nil
+		json.NewDecoder(r.Body).Decode(&got.body)
+
+		var data interface{}
+		switch r.Method {
+		case http.MethodGet:
+			data = map[string]interface{}{"items": []map[string]interface{}{
+				{"task_id": "t1", "status": "pending", "scheduled_at": "2025-11-26T01:00:00Z"},
+			}}
+		case http.MethodPut:
+			data = map[string]interface{}{"task_id": "t1", "status": "pending", "scheduled_at": got.body["scheduled_at"]}
+		case http.MethodDelete:
+			data = map[string]interface{}{"task_id": "t1", "status": TaskStatusCanceled}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": data})
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 11, 25, 10, 0, 0, 0, time.UTC)
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	list, err := client.ListScheduled(ctx, &ListScheduledOptions{ChannelID: 3, To: now.Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("ListScheduled() error = %v", err)
+	}
+	if got.path != "/api/v1/messages/scheduled" || got.query != "channel_id=3&scheduled_to=2025-11-26T10%3A00%3A00Z" {
+		t.Errorf("request = %s?%s", got.path, got.query)
+	}
+	if len(list.Items) != 1 || !list.Items[0].ScheduledTime().Equal(time.Date(2025, 11, 26, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected list: %+v", list.Items)
+	}
+
+	newTime := time.Date(2025, 11, 26, 9, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	data, err := client.RescheduleTask(ctx, "t1", newTime)
+	if err != nil {
+		t.Fatalf("RescheduleTask() error = %v", err)
+	}
+	if got.method != http.MethodPut || got.path != "/api/v1/messages/t1/schedule" || got.body["scheduled_at"] != "2025-11-26T01:00:00Z" {
+		t.Errorf("request = %s %s %v", got.method, got.path, got.body)
+	}
+	if !data.ScheduledTime().Equal(newTime) {
+		t.Errorf("ScheduledTime() = %v", data.ScheduledTime())
+	}
+
+	got.path = ""
+	if _, err := client.RescheduleTask(ctx, "t1", now.Add(-time.Minute)); !errors.Is(err, ErrInvalidRequest) || got.path != "" {
+		t.Errorf("past time: err = %v, path = %q", err, got.path)
+	}
+
+	canceled, err := client.CancelScheduled(ctx, "t1")
+	if err != nil || canceled.Status != TaskStatusCanceled || got.method != http.MethodDelete || got.path != "/api/v1/messages/t1/schedule" {
+		t.Errorf("CancelScheduled() = %+v, %v (%s %s)", canceled, err, got.method, got.path)
+	}
+}
@@ -0,0 +1,102 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSkipped 快速失败模式下，因前面的消息发送失败而未发送的消息的错误
+var ErrSkipped = errors.New("mlievpush: skipped after earlier failure")
+
+// SendAllOption SendAll 配置选项
+type SendAllOption func(*sendAllOptions)
+
+// sendAllOptions SendAll 配置
+type sendAllOptions struct {
+	failFast bool
+}
+
+// WithFailFast 开启快速失败：任一消息发送失败后取消进行中的请求，未发送的消息结果为 ErrSkipped
+// 默认发送全部消息并收集每条结果
+func WithFailFast() SendAllOption {
+	return func(o *sendAllOptions) {
+		o.failFast = true
+	}
+}
+
+// SendAll 并发发送一组消息，返回与 reqs 一一对应的结果（Index 为在 reqs 中的下标）
+// concurrency 小于等于0时使用 DefaultStreamConcurrency；全部成功时 error 为nil，
+// 否则返回第一个失败（按发生顺序）的错误，可用 errors.Is/As 判断
+func (c *Client) SendAll(ctx context.Context, reqs []*SendMessageRequest, concurrency int, opts ...SendAllOption) ([]StreamResult, error) {
+	var o sendAllOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultStreamConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]StreamResult, len(reqs))
+	var (
+		mu       sync.Mutex
+		firstErr error
+		failed   int
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed++
+		if firstErr == nil {
+			firstErr = err
+			if o.failFast {
+				cancel()
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, req := range reqs {
+		results[i] = StreamResult{Index: i, Request: req}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			// 快速失败已取消，或调用方的 ctx 已结束
+			mu.Lock()
+			aborted := o.failFast && firstErr != nil
+			mu.Unlock()
+			if aborted {
+				results[i].Err = ErrSkipped
+			} else {
+				results[i].Err = ctx.Err()
+				fail(ctx.Err())
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, req *SendMessageRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			data, err := c.SendMessage(ctx, req)
+			results[i].Data, results[i].Err = data, err
+			if err != nil {
+				fail(err)
+			}
+		}(i, req)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, fmt.Errorf("send all: %d of %d messages failed: %w", failed, len(reqs), firstErr)
+	}
+	return results, nil
+}
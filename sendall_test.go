@@ -0,0 +1,89 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newReceiverServer 创建按接收者返回结果的mock服务器，接收者为 fail 时返回通道不存在
+func newReceiverServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t-" + req.Receiver}}
+		if req.Receiver == "fail" {
+			resp = map[string]interface{}{"code": ErrCodeChannelNotFound, "message": "通道不存在"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestSendAllCollect 测试收集全部结果
+func TestSendAllCollect(t *testing.T) {
+	var calls int32
+	server := newReceiverServer(t, &calls)
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	reqs := []*SendMessageRequest{
+		{ChannelID: 1, Receiver: "a"},
+		{ChannelID: 1, Receiver: "fail"},
+		{ChannelID: 1, Receiver: "c"},
+	}
+	results, err := client.SendAll(context.Background(), reqs, 2)
+	if !errors.Is(err, ErrChannelNotFound) {
+		t.Fatalf("expected ErrChannelNotFound, got %v", err)
+	}
+	if len(results) != 3 || calls != 3 {
+		t.Fatalf("expected 3 results and 3 calls, got %d and %d", len(results), calls)
+	}
+	if results[0].Data.TaskID != "t-a" || results[1].Err == nil || results[2].Data.TaskID != "t-c" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+// TestSendAllFailFast 测试快速失败
+func TestSendAllFailFast(t *testing.T) {
+	var calls int32
+	server := newReceiverServer(t, &calls)
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	reqs := []*SendMessageRequest{
+		{ChannelID: 1, Receiver: "fail"},
+		{ChannelID: 1, Receiver: "b"},
+		{ChannelID: 1, Receiver: "c"},
+	}
+	results, err := client.SendAll(context.Background(), reqs, 1, WithFailFast())
+	if !errors.Is(err, ErrChannelNotFound) {
+		t.Fatalf("expected ErrChannelNotFound, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+	for _, r := range results[1:] {
+		if !errors.Is(r.Err, ErrSkipped) {
+			t.Errorf("expected ErrSkipped for %d, got %v", r.Index, r.Err)
+		}
+	}
+}
+
+// TestSendAllSuccess 测试全部成功
+func TestSendAllSuccess(t *testing.T) {
+	var calls int32
+	server := newReceiverServer(t, &calls)
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	results, err := client.SendAll(context.Background(), []*SendMessageRequest{{ChannelID: 1, Receiver: "a"}}, 0)
+	if err != nil || len(results) != 1 || results[0].Data == nil {
+		t.Errorf("SendAll() = %+v, %v", results, err)
+	}
+}
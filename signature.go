@@ -1,50 +1,176 @@
 package mlievpush
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"sort"
+	"errors"
+	"fmt"
+	"strings"
 )
 
-// sortParams 按 key 排序参数并返回 JSON 字符串
+// sortParams 按 key 递归排序参数并返回 JSON 字符串（规则见 CanonicalJSON）
 // 如果 params 为空或 nil，返回空字符串
 func sortParams(params map[string]interface{}) string {
 	if len(params) == 0 {
 		return ""
 	}
 
-	// 提取所有 key 并排序
-	keys := make([]string, 0, len(params))
-	for k := range params {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	// 按排序后的 key 构建新的 map
-	sortedMap := make(map[string]interface{})
-	for _, k := range keys {
-		sortedMap[k] = params[k]
+	// encoding/json 对 map 的 key 逐层排序；结构体按字段顺序、json.RawMessage 原样输出，
+	// 含有这类值时先转换为通用类型，保证任意层级的 key 都有序
+	var v interface{} = params
+	if !isCanonicalValue(params) {
+		if normalized, err := normalizeValue(params); err == nil {
+			v = normalized
+		}
 	}
 
 	// 序列化为 JSON（不转义 Unicode，无空格）
-	result, _ := json.Marshal(sortedMap)
+	result, _ := json.Marshal(v)
 	return string(result)
 }
 
+// isCanonicalValue 判断值序列化后是否已按 key 逐层有序（仅由 map、切片和基本类型组成）
+func isCanonicalValue(v interface{}) bool {
+	switch x := v.(type) {
+	case nil, string, bool, json.Number, float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	case map[string]interface{}:
+		for _, item := range x {
+			if !isCanonicalValue(item) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		for _, item := range x {
+			if !isCanonicalValue(item) {
+				return false
+			}
+		}
+		return true
+	case map[string]string, []string:
+		return true
+	default:
+		return false
+	}
+}
+
+// normalizeValue 序列化后重新解析为通用类型（数字保持原样）
+func normalizeValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var normalized interface{}
+	if err := dec.Decode(&normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// CanonicalJSON 将 JSON 请求体规范化为参与签名的参数字符串（签名内容中的 sorted_params）
+//
+// 规范化规则：
+//   - 请求体必须是 JSON 对象，空对象或空请求体为空字符串
+//   - 各层对象的 key 按 UTF-8 字节序升序排列，数组保持原顺序
+//   - 数字保留请求体中的原始写法（不转换为浮点数，大整数不丢失精度）
+//   - 字符串按 encoding/json 的规则重新转义：非 ASCII 字符原样输出，<、>、& 及 U+2028、U+2029 转义为 \uXXXX
+//   - 不含空白字符
+//
+// 客户端与 Verifier 都对实际传输的请求体字节做规范化，双方结果一致
+func CanonicalJSON(body []byte) (string, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return "", nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var params map[string]interface{}
+	if err := dec.Decode(&params); err != nil {
+		return "", fmt.Errorf("canonicalize body: %w", err)
+	}
+	if dec.More() {
+		return "", errors.New("canonicalize body: unexpected data after JSON object")
+	}
+	return sortParams(params), nil
+}
+
 // generateSignature 生成请求签名
 // 签名算法: HMAC-SHA256(method + path + sorted_params + timestamp + nonce, app_secret)
 func generateSignature(method, path string, params map[string]interface{}, timestamp, nonce, appSecret string) string {
-	sortedParams := sortParams(params)
+	return signContent(canonicalString(method, path, sortParams(params), timestamp, nonce), appSecret)
+}
+
+// canonicalString 构造签名内容: method + path + sorted_params + timestamp + nonce
+func canonicalString(method, path, sortedParams, timestamp, nonce string) string {
+	return method + path + sortedParams + timestamp + nonce
+}
+
+// withContentDigest 追加请求体摘要: canonical + content_sha256（未启用摘要时不变）
+func withContentDigest(canonical, digest string) string {
+	return canonical + strings.ToLower(digest)
+}
 
-	// 构造签名内容: method + path + sorted_params + timestamp + nonce
-	signContent := method + path + sortedParams + timestamp + nonce
+// ContentSHA256 计算请求体的 SHA-256 摘要（小写十六进制），即 X-Content-SHA256 请求头的值
+func ContentSHA256(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
 
+// signContent 对签名内容计算 HMAC-SHA256
+func signContent(content, appSecret string) string {
 	// HMAC-SHA256 计算
 	mac := hmac.New(sha256.New, []byte(appSecret))
-	mac.Write([]byte(signContent))
+	mac.Write([]byte(content))
 
 	// 十六进制编码（小写）
 	return hex.EncodeToString(mac.Sum(nil))
 }
+
+// GenerateSignature 生成请求签名
+// 供模拟网关、回调发送方等需要自行签名的场景使用
+func GenerateSignature(method, path string, params map[string]interface{}, timestamp, nonce, appSecret string) string {
+	return generateSignature(method, path, params, timestamp, nonce, appSecret)
+}
+
+// VerifySignature 校验请求签名，使用常量时间比较防止时序攻击
+// 供回调接收方或兼容网关的服务端使用
+func VerifySignature(method, path string, params map[string]interface{}, timestamp, nonce, appSecret, signature string) bool {
+	expected := generateSignature(method, path, params, timestamp, nonce, appSecret)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// SignatureDebugInfo 签名调试信息（不包含密钥，参数中的接收者按 Redactor 脱敏，使用 NoopRedactor 可查看原始内容）
+type SignatureDebugInfo struct {
+	AppID           string // 应用ID
+	Method          string // 请求方法
+	Path            string // 请求路径
+	SortedParams    string // 排序后的参数JSON
+	Timestamp       string // 时间戳
+	Nonce           string // 随机数
+	ContentSHA256   string // 请求体摘要（未启用 WithContentDigest 时为空）
+	CanonicalString string // 完整的签名内容
+	Signature       string // 客户端计算的签名
+	Version         string // 签名算法版本，见 Signer
+}
+
+// WithContentDigest 设置是否发送 X-Content-SHA256 请求头
+// 启用后请求体的 SHA-256 摘要会追加到签名内容末尾: method + path + sorted_params + timestamp + nonce + content_sha256，
+// 服务端可以先廉价地校验摘要，再校验签名，同时保证请求体逐字节未被篡改
+func WithContentDigest(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.contentDigest = enabled
+	}
+}
+
+// WithSignatureDebug 设置签名调试回调
+// 服务端返回签名验证失败（20003）时，回调会收到客户端实际使用的签名内容，便于与服务端逐项对比
+func WithSignatureDebug(fn func(SignatureDebugInfo)) ClientOption {
+	return func(c *Client) {
+		c.signatureDebug = fn
+	}
+}
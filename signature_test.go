@@ -0,0 +1,267 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestVerifySignature 测试签名校验
+func TestVerifySignature(t *testing.T) {
+	params := map[string]interface{}{"channel_id": 1, "receiver": "13800138000"}
+	signature := GenerateSignature(http.MethodPost, "/api/v1/messages", params, "1700000000", "abc123", "secret")
+
+	if !VerifySignature(http.MethodPost, "/api/v1/messages", params, "1700000000", "abc123", "secret", signature) {
+		t.Error("valid signature should verify")
+	}
+	if VerifySignature(http.MethodPost, "/api/v1/messages", params, "1700000001", "abc123", "secret", signature) {
+		t.Error("signature with different timestamp should not verify")
+	}
+}
+
+// TestSignatureDebug 测试签名失败时输出调试信息
+func TestSignatureDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    ErrCodeInvalidSignature,
+			"message": "签名验证失败",
+		})
+	}))
+	defer server.Close()
+
+	var got *SignatureDebugInfo
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithRedactor(NoopRedactor), WithSignatureDebug(func(info SignatureDebugInfo) {
+		got = &info
+	}))
+
+	_, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if got == nil {
+		t.Fatal("signature debug callback not called")
+	}
+
+	if got.Method != http.MethodPost || got.Path != "/api/v1/messages" {
+		t.Errorf("unexpected method/path: %s %s", got.Method, got.Path)
+	}
+	wantCanonical := got.Method + got.Path + got.SortedParams + got.Timestamp + got.Nonce
+	if got.CanonicalString != wantCanonical {
+		t.Errorf("CanonicalString = %q, want %q", got.CanonicalString, wantCanonical)
+	}
+	if got.Signature != signContent(got.CanonicalString, "test_secret") {
+		t.Error("Signature does not match canonical string")
+	}
+	if strings.Contains(got.CanonicalString, "test_secret") {
+		t.Error("debug info must not contain the secret")
+	}
+}
+
+// TestSignatureDebugRedacted 测试签名调试信息默认脱敏接收者
+func TestSignatureDebugRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeInvalidSignature, "message": "签名验证失败"})
+	}))
+	defer server.Close()
+
+	var got SignatureDebugInfo
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithSignatureDebug(func(info SignatureDebugInfo) {
+		got = info
+	}))
+	client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"})
+
+	if strings.Contains(got.CanonicalString, "13800138000") || strings.Contains(got.SortedParams, "13800138000") {
+		t.Errorf("receiver should be redacted: %s", got.CanonicalString)
+	}
+	if !strings.HasSuffix(got.CanonicalString, got.Timestamp+got.Nonce) {
+		t.Errorf("timestamp and nonce should be kept: %s", got.CanonicalString)
+	}
+}
+
+// TestContentDigest 测试请求体摘要参与签名
+func TestContentDigest(t *testing.T) {
+	var gotDigest string
+	verifier := NewVerifier(StaticSecret("test_secret"), WithRequireContentDigest())
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotDigest = r.Header.Get(HeaderContentSHA256)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{}})
+	})))
+	defer server.Close()
+
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithContentDigest(true))
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if len(gotDigest) != 64 {
+		t.Errorf("X-Content-SHA256 = %q, want 64 hex chars", gotDigest)
+	}
+
+	// 未发送摘要时被要求摘要的服务端拒绝
+	plain := NewClient(server.URL, "test_app_id", "test_secret")
+	if _, err := plain.SendMessage(context.Background(), req); err == nil {
+		t.Error("expected error without content digest")
+	}
+}
+
+// TestContentDigestMismatch 测试请求体与摘要不符时拒绝
+func TestContentDigestMismatch(t *testing.T) {
+	body := []byte(`{"channel_id":1}`)
+	params := map[string]interface{}{"channel_id": 1}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	digest := ContentSHA256(body)
+	canonical := withContentDigest(canonicalString(http.MethodPost, "/api/v1/messages", sortParams(params), timestamp, "n1"), digest)
+
+	// 参数规范化后相同，但请求体字节不同
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/messages", strings.NewReader(`{"channel_id": 1}`))
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, "n1")
+	r.Header.Set(HeaderContentSHA256, digest)
+	r.Header.Set(HeaderSignature, signContent(canonical, "secret"))
+
+	_, err := NewVerifier(StaticSecret("secret")).Verify(r)
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+// signatureVector 签名测试向量（testdata/signature_vectors.json，签名由独立实现计算）
+type signatureVector struct {
+	Name            string `json:"name"`
+	Method          string `json:"method"`
+	Path            string `json:"path"`
+	Body            string `json:"body"`
+	CanonicalParams string `json:"canonical_params"`
+	Timestamp       string `json:"timestamp"`
+	Nonce           string `json:"nonce"`
+	AppSecret       string `json:"app_secret"`
+	Signature       string `json:"signature"`
+}
+
+// TestSignatureVectors 测试规范化结果和签名与已知向量一致，且 Verifier 接受
+func TestSignatureVectors(t *testing.T) {
+	raw, err := os.ReadFile("testdata/signature_vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []signatureVector
+	if err := json.Unmarshal(raw, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(StaticSecret("test_secret"), WithMaxClockSkew(100*365*24*time.Hour))
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			canonical, err := CanonicalJSON([]byte(v.Body))
+			if err != nil {
+				t.Fatalf("CanonicalJSON() error = %v", err)
+			}
+			if canonical != v.CanonicalParams {
+				t.Errorf("CanonicalJSON() = %s, want %s", canonical, v.CanonicalParams)
+			}
+
+			in := SignatureInput{Method: v.Method, Path: v.Path, Body: canonical, Timestamp: v.Timestamp, Nonce: v.Nonce}
+			if got, _ := SignerV1.Sign(in, v.AppSecret); got != v.Signature {
+				t.Errorf("signature = %s, want %s", got, v.Signature)
+			}
+
+			r := httptest.NewRequest(v.Method, v.Path, strings.NewReader(v.Body))
+			if v.Body != "" {
+				r.Header.Set("Content-Type", "application/json")
+			}
+			r.Header.Set(HeaderTimestamp, v.Timestamp)
+			r.Header.Set(HeaderNonce, v.Nonce)
+			r.Header.Set(HeaderSignature, v.Signature)
+			if _, err := verifier.Verify(r); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+		})
+	}
+}
+
+// TestCanonicalJSONRoundTrip 测试客户端签名的内容与线上传输的请求体一致
+func TestCanonicalJSONRoundTrip(t *testing.T) {
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	var verifyErr error
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, verifyErr = verifier.Verify(r)
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"success","data":{"task_id":"t1"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	req := &SendMessageRequest{
+		ChannelID: 1,
+		Receiver:  "13800138000",
+		TemplateParams: map[string]interface{}{
+			"order_id": int64(9007199254740993), // 超出 float64 精度
+			"amount":   json.Number("1.50"),
+			"link":     "https://example.com/?a=1&b=<2>",
+		},
+	}
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if verifyErr != nil {
+		t.Fatalf("Verify() error = %v", verifyErr)
+	}
+
+	canonical, _ := CanonicalJSON(body)
+	for _, want := range []string{"9007199254740993", `"amount":1.50`} {
+		if !strings.Contains(canonical, want) {
+			t.Errorf("canonical params %s missing %s", canonical, want)
+		}
+	}
+}
+
+// TestCanonicalJSONErrors 测试非对象请求体
+func TestCanonicalJSONErrors(t *testing.T) {
+	for _, body := range []string{`[1,2]`, `{"a":1} {"b":2}`, `{"a":`} {
+		if _, err := CanonicalJSON([]byte(body)); err == nil {
+			t.Errorf("CanonicalJSON(%s) expected error", body)
+		}
+	}
+	if got, err := CanonicalJSON([]byte("  \n")); got != "" || err != nil {
+		t.Errorf("CanonicalJSON(whitespace) = %q, %v", got, err)
+	}
+}
+
+// TestSortParamsDeep 测试结构体、json.RawMessage 等嵌套值的 key 同样逐层有序
+func TestSortParamsDeep(t *testing.T) {
+	type buyer struct {
+		Name string `json:"name"`
+		ID   int    `json:"id"`
+	}
+	params := map[string]interface{}{
+		"channel_id": 1,
+		"template_params": map[string]interface{}{
+			"order": map[string]interface{}{
+				"buyer": buyer{Name: "张三", ID: 7},
+				"items": json.RawMessage(`[{"sku":"A1","qty":2}]`),
+			},
+			"code": "123456",
+		},
+	}
+	want := `{"channel_id":1,"template_params":{"code":"123456","order":{"buyer":{"id":7,"name":"张三"},"items":[{"qty":2,"sku":"A1"}]}}}`
+	if got := sortParams(params); got != want {
+		t.Errorf("sortParams() = %s, want %s", got, want)
+	}
+
+	// 与对同一请求体规范化的结果一致
+	body, _ := json.Marshal(params)
+	if canonical, _ := CanonicalJSON(body); canonical != want {
+		t.Errorf("CanonicalJSON() = %s, want %s", canonical, want)
+	}
+}
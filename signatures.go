@@ -0,0 +1,131 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// 签名审核状态
+const (
+	SignatureStatusPending  = "pending"  // 审核中
+	SignatureStatusApproved = "approved" // 已通过
+	SignatureStatusRejected = "rejected" // 未通过
+)
+
+// Signature 通道下登记的签名（短信签名、发件人名称等 sender-ID）
+type Signature struct {
+	ID           int    `json:"id"`                      // 签名ID
+	ChannelID    int    `json:"channel_id"`              // 通道ID
+	Name         string `json:"name"`                    // 签名名称，即发送请求中的 SignatureName
+	Status       string `json:"status"`                  // 审核状态，见 SignatureStatus* 常量
+	RejectReason string `json:"reject_reason,omitempty"` // 审核未通过的原因
+	CreatedAt    string `json:"created_at"`              // 创建时间
+	UpdatedAt    string `json:"updated_at"`              // 更新时间
+
+	RawData // 原始JSON，见 RawData
+}
+
+// Approved 判断签名是否已审核通过，可用于发送
+func (s *Signature) Approved() bool {
+	return s.Status == SignatureStatusApproved
+}
+
+// ListSignaturesOptions 签名列表查询条件，零值字段不参与过滤
+type ListSignaturesOptions struct {
+	ChannelID int    // 通道ID
+	Status    string // 审核状态
+	Page      int    // 页码（从1开始）
+	PageSize  int    // 每页数量，0 使用网关默认值
+}
+
+// ListSignaturesData 签名列表分页数据
+type ListSignaturesData struct {
+	Items    []Signature `json:"items"`     // 签名列表
+	Total    int         `json:"total"`     // 签名总数
+	Page     int         `json:"page"`      // 当前页码
+	PageSize int         `json:"page_size"` // 每页数量
+
+	RawData // 原始JSON，见 RawData
+}
+
+// ByName 按名称查找签名，未找到返回 nil
+func (d *ListSignaturesData) ByName(name string) *Signature {
+	for i := range d.Items {
+		if d.Items[i].Name == name {
+			return &d.Items[i]
+		}
+	}
+	return nil
+}
+
+// CreateSignatureRequest 创建签名请求
+type CreateSignatureRequest struct {
+	ChannelID   int    `json:"channel_id"`            // 通道ID（必填）
+	Name        string `json:"name"`                  // 签名名称（必填）
+	Description string `json:"description,omitempty"` // 签名用途说明，供服务商审核参考（可选）
+}
+
+// ListSignatures 分页查询签名，opts 为 nil 时返回第一页
+func (c *Client) ListSignatures(ctx context.Context, opts *ListSignaturesOptions) (*ListSignaturesData, error) {
+	if err := c.requireFeature(ctx, FeatureSignatures); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/signatures"
+	if opts != nil {
+		query := url.Values{}
+		if opts.ChannelID > 0 {
+			query.Set("channel_id", strconv.Itoa(opts.ChannelID))
+		}
+		if opts.Status != "" {
+			query.Set("status", opts.Status)
+		}
+		if opts.Page > 0 {
+			query.Set("page", strconv.Itoa(opts.Page))
+		}
+		if opts.PageSize > 0 {
+			query.Set("page_size", strconv.Itoa(opts.PageSize))
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	}
+
+	var data ListSignaturesData
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// CreateSignature 创建签名，新签名需要服务商审核通过（Status 为 approved）后才能使用
+func (c *Client) CreateSignature(ctx context.Context, req *CreateSignatureRequest) (*Signature, error) {
+	if err := c.requireFeature(ctx, FeatureSignatures); err != nil {
+		return nil, err
+	}
+
+	var data Signature
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/signatures", req, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// CheckSignature 检查签名是否已在通道下登记并审核通过，用于在发送前发现签名配置错误
+func (c *Client) CheckSignature(ctx context.Context, channelID int, name string) (*Signature, error) {
+	data, err := c.ListSignatures(ctx, &ListSignaturesOptions{ChannelID: channelID})
+	if err != nil {
+		return nil, err
+	}
+	sig := data.ByName(name)
+	if sig == nil {
+		return nil, fmt.Errorf("signature %q is not registered for channel %d", name, channelID)
+	}
+	if !sig.Approved() {
+		return sig, fmt.Errorf("signature %q is %s for channel %d", name, sig.Status, channelID)
+	}
+	return sig, nil
+}
@@ -0,0 +1,60 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSignatureManagement 测试签名查询、创建与检查
+func TestSignatureManagement(t *testing.T) {
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, err := verifier.Verify(r)
+		if err != nil {
+			t.Errorf("Verify() error = %v", err)
+			return
+		}
+		var data interface{}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Path != "/api/v1/signatures" || r.URL.Query().Get("channel_id") != "1" {
+				t.Errorf("unexpected request %s", r.URL)
+			}
+			data = map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"id": 1, "channel_id": 1, "name": "木雷科技", "status": "approved"},
+				map[string]interface{}{"id": 2, "channel_id": 1, "name": "新签名", "status": "pending"},
+			}, "total": 2}
+		case http.MethodPost:
+			data = map[string]interface{}{"id": 3, "channel_id": 1, "name": verified.Params["name"], "status": "pending"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": data})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	ctx := context.Background()
+
+	list, err := client.ListSignatures(ctx, &ListSignaturesOptions{ChannelID: 1})
+	if err != nil || list.Total != 2 || list.ByName("木雷科技") == nil {
+		t.Fatalf("ListSignatures() = %+v, %v", list, err)
+	}
+
+	created, err := client.CreateSignature(ctx, &CreateSignatureRequest{ChannelID: 1, Name: "测试"})
+	if err != nil || created.Name != "测试" || created.Approved() {
+		t.Errorf("CreateSignature() = %+v, %v", created, err)
+	}
+
+	if sig, err := client.CheckSignature(ctx, 1, "木雷科技"); err != nil || sig.ID != 1 {
+		t.Errorf("CheckSignature(approved) = %+v, %v", sig, err)
+	}
+	if sig, err := client.CheckSignature(ctx, 1, "新签名"); err == nil || sig == nil {
+		t.Errorf("CheckSignature(pending) = %+v, %v, want error", sig, err)
+	}
+	if _, err := client.CheckSignature(ctx, 1, "不存在"); err == nil {
+		t.Error("CheckSignature(unknown) want error")
+	}
+}
@@ -0,0 +1,67 @@
+package mlievpush
+
+import "fmt"
+
+// HeaderSignatureVersion 签名算法版本请求头，使用 SignerV1 时不发送，兼容只支持 V1 的网关
+const HeaderSignatureVersion = "X-Signature-Version"
+
+// SignatureVersion1 HMAC-SHA256 签名（默认）
+const SignatureVersion1 = "1"
+
+// SignatureInput 参与签名的请求内容
+type SignatureInput struct {
+	Method        string // 请求方法
+	Path          string // 请求路径
+	Body          string // 按 key 排序后的参数JSON，无参数时为空
+	Timestamp     string // 时间戳（X-Timestamp）
+	Nonce         string // 随机数（X-Nonce）
+	ContentSHA256 string // 请求体摘要（X-Content-SHA256），未启用 WithContentDigest 时为空
+}
+
+// Canonical 返回签名内容: method + path + sorted_params + timestamp + nonce [+ content_sha256]
+func (in SignatureInput) Canonical() string {
+	return withContentDigest(canonicalString(in.Method, in.Path, in.Body, in.Timestamp, in.Nonce), in.ContentSHA256)
+}
+
+// Signer 请求签名算法
+// 新算法（如 HMAC-SHA512、Ed25519 请求签名）实现该接口后通过 WithSigner 选择，
+// 非 V1 算法的版本号通过 X-Signature-Version 请求头告知网关
+type Signer interface {
+	Version() string                                          // 算法版本，见 HeaderSignatureVersion
+	Sign(in SignatureInput, appSecret string) (string, error) // 计算 X-Signature 请求头的值
+}
+
+// SignerV1 HMAC-SHA256(canonical, app_secret)，十六进制小写
+var SignerV1 Signer = hmacSHA256Signer{}
+
+// hmacSHA256Signer V1 签名算法
+type hmacSHA256Signer struct{}
+
+// Version 实现 Signer 接口
+func (hmacSHA256Signer) Version() string { return SignatureVersion1 }
+
+// Sign 实现 Signer 接口
+func (hmacSHA256Signer) Sign(in SignatureInput, appSecret string) (string, error) {
+	return signContent(in.Canonical(), appSecret), nil
+}
+
+// WithSigner 设置请求签名算法，传入 nil 时使用 SignerV1
+// 需要网关支持对应的签名版本后再切换
+func WithSigner(signer Signer) ClientOption {
+	return func(c *Client) {
+		c.signer = signer
+	}
+}
+
+// sign 使用配置的签名算法计算签名
+func (c *Client) sign(in SignatureInput, appSecret string) (signature, version string, err error) {
+	signer := c.signer
+	if signer == nil {
+		signer = SignerV1
+	}
+	signature, err = signer.Sign(in, appSecret)
+	if err != nil {
+		return "", "", fmt.Errorf("sign request: %w", err)
+	}
+	return signature, signer.Version(), nil
+}
@@ -0,0 +1,86 @@
+package mlievpush
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// hmacSHA512Signer 测试用的 V2 签名算法
+type hmacSHA512Signer struct{}
+
+func (hmacSHA512Signer) Version() string { return "2" }
+
+func (hmacSHA512Signer) Sign(in SignatureInput, appSecret string) (string, error) {
+	mac := hmac.New(sha512.New, []byte(appSecret))
+	mac.Write([]byte(in.Canonical()))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// failingSigner 测试用的签名失败算法（如远程密钥服务不可用）
+type failingSigner struct{}
+
+func (failingSigner) Version() string { return "kms" }
+
+func (failingSigner) Sign(in SignatureInput, appSecret string) (string, error) {
+	return "", errors.New("kms unavailable")
+}
+
+// TestSignerV1 测试 V1 签名与 GenerateSignature 一致
+func TestSignerV1(t *testing.T) {
+	params := map[string]interface{}{"receiver": "13800138000", "channel_id": 1}
+	in := SignatureInput{Method: http.MethodPost, Path: "/api/v1/messages", Body: sortParams(params), Timestamp: "1700000000", Nonce: "n1"}
+	got, err := SignerV1.Sign(in, "test_secret")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if want := GenerateSignature(in.Method, in.Path, params, in.Timestamp, in.Nonce, "test_secret"); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+}
+
+// TestWithSigner 测试自定义签名算法和版本请求头
+func TestWithSigner(t *testing.T) {
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	var headers http.Header
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		_, verifyErr = verifier.Verify(r)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"success","data":{"task_id":"t1"}}`))
+	}))
+	defer server.Close()
+
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}
+
+	// 默认 V1 不发送版本请求头，兼容现有网关
+	if _, err := NewClient(server.URL, "test_app_id", "test_secret").SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if headers.Get(HeaderSignatureVersion) != "" || verifyErr != nil {
+		t.Errorf("version header = %q, verify error = %v", headers.Get(HeaderSignatureVersion), verifyErr)
+	}
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithSigner(hmacSHA512Signer{}))
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if headers.Get(HeaderSignatureVersion) != "2" || len(headers.Get(HeaderSignature)) != 128 {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+	if !errors.Is(verifyErr, ErrInvalidSignature) || !strings.Contains(verifyErr.Error(), "unsupported signature version") {
+		t.Errorf("expected unsupported version error, got %v", verifyErr)
+	}
+
+	client = NewClient(server.URL, "test_app_id", "test_secret", WithSigner(failingSigner{}))
+	if _, err := client.SendMessage(context.Background(), req); err == nil || !strings.Contains(err.Error(), "kms unavailable") {
+		t.Errorf("expected signer error, got %v", err)
+	}
+}
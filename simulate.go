@@ -0,0 +1,62 @@
+package mlievpush
+
+import (
+	"math/rand"
+	"net/http"
+	"strings"
+)
+
+// simulatedFailurePaths 模拟失败生效的发送接口
+var simulatedFailurePaths = map[string]bool{
+	"/api/v1/messages":       true,
+	"/api/v1/messages/batch": true,
+}
+
+// DefaultSimulatedFailureCodes 默认模拟的服务商侧错误码
+var DefaultSimulatedFailureCodes = []int{
+	ErrCodeProviderError,
+	ErrCodeNetworkTimeout,
+	ErrCodeRateLimitExceeded,
+	ErrCodeNoAvailableChannel,
+}
+
+// WithSimulatedFailures 开启故障注入（仅用于预发/测试环境）：发送请求以 rate（0~1）的概率直接返回模拟的服务商错误，不调用网关
+// 模拟错误是 Simulated 为 true 的 *APIError，错误码从 codes 中随机选取（未指定时使用 DefaultSimulatedFailureCodes），
+// 与真实错误一样经过钩子、运行统计和自动重试，便于验证补偿和降级逻辑。查询等非发送接口不受影响
+func WithSimulatedFailures(rate float64, codes ...int) ClientOption {
+	return func(c *Client) {
+		c.simulatedFailureRate = rate
+		c.simulatedFailureCodes = codes
+		if len(codes) == 0 {
+			c.simulatedFailureCodes = DefaultSimulatedFailureCodes
+		}
+	}
+}
+
+// simulateFailure 按配置的概率返回模拟错误，未命中时返回 nil
+func (c *Client) simulateFailure(method, path string) *APIError {
+	path, _, _ = strings.Cut(path, "?")
+	if c.simulatedFailureRate <= 0 || method != http.MethodPost || !simulatedFailurePaths[path] {
+		return nil
+	}
+	if c.random() >= c.simulatedFailureRate {
+		return nil
+	}
+
+	code := c.simulatedFailureCodes[int(c.random()*float64(len(c.simulatedFailureCodes)))]
+	message := ErrorCodeMessages[code]
+	if message == "" {
+		message = "simulated failure"
+	}
+	apiErr := NewAPIError(code, message+" (simulated)")
+	apiErr.Simulated = true
+	return apiErr
+}
+
+// random 返回 [0, 1) 范围内的随机数，优先使用 WithRandSource 设置的随机源
+func (c *Client) random() float64 {
+	if r := c.randFloat64(); r >= 0 {
+		return r
+	}
+	return rand.Float64()
+}
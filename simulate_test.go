@@ -0,0 +1,58 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestWithSimulatedFailures 测试故障注入不调用网关
+func TestWithSimulatedFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"success","data":{"task_id":"t1","status":"pending"}}`))
+	}))
+	defer server.Close()
+
+	var hookErrs int
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithSimulatedFailures(1, ErrCodeProviderError),
+		WithHooks(Hooks{OnError: func(ctx context.Context, err error) { hookErrs++ }}),
+	)
+
+	_, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.Simulated || apiErr.Code != ErrCodeProviderError {
+		t.Fatalf("expected simulated provider error, got %v", err)
+	}
+	if calls.Load() != 0 || hookErrs != 1 {
+		t.Errorf("gateway calls = %d, hook errors = %d", calls.Load(), hookErrs)
+	}
+
+	// 查询接口不受影响
+	if _, err := client.QueryTask(context.Background(), "t1"); err != nil || calls.Load() != 1 {
+		t.Errorf("QueryTask() error = %v, calls = %d", err, calls.Load())
+	}
+}
+
+// TestSimulatedFailureRate 测试故障注入概率
+func TestSimulatedFailureRate(t *testing.T) {
+	client := NewClient("http://example.com", "id", "secret",
+		WithSimulatedFailures(0.3), WithRandSource(rand.New(rand.NewSource(1))))
+
+	failures := 0
+	for i := 0; i < 1000; i++ {
+		if err := client.simulateFailure(http.MethodPost, "/api/v1/messages/batch"); err != nil {
+			failures++
+		}
+	}
+	if failures < 240 || failures > 360 {
+		t.Errorf("failures = %d, want about 300", failures)
+	}
+}
@@ -0,0 +1,413 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSpoolMessageNotFound 暂存中不存在指定ID的消息
+var ErrSpoolMessageNotFound = errors.New("mlievpush: spooled message not found")
+
+// SpooledMessage 暂存的待发送消息
+type SpooledMessage struct {
+	ID            string              `json:"id"`                        // 暂存ID
+	Request       *SendMessageRequest `json:"request"`                   // 发送请求（已设置幂等键，重发不会重复投递）
+	SpooledAt     time.Time           `json:"spooled_at"`                // 暂存时间
+	Reason        string              `json:"reason"`                    // 暂存原因
+	Category      string              `json:"category,omitempty"`        // 消息分类（DegradationPolicy.Categorize），决定重发规则
+	Attempts      int                 `json:"attempts,omitempty"`        // 重发失败次数（可重试的失败，消息仍保留在暂存中）
+	LastError     string              `json:"last_error,omitempty"`      // 最近一次重发失败的原因
+	LastAttemptAt time.Time           `json:"last_attempt_at,omitempty"` // 最近一次重发失败的时间
+	Header        http.Header         `json:"header,omitempty"`          // 发送时通过 WithHeader 附加的请求头，重发时同样附加
+	NoRetry       bool                `json:"no_retry,omitempty"`        // 发送时使用了 WithNoRetry，重发时同样不自动重试
+}
+
+// Spool 待发送消息的持久化暂存，网关不可用时保存消息，恢复后按暂存顺序重发
+// 实现需并发安全；内置 FileSpool，也可以基于数据库或消息队列实现
+type Spool interface {
+	Put(ctx context.Context, msg *SpooledMessage) error // 保存消息，ID 已存在时覆盖
+	// Peek 按 (SpooledAt, ID) 从早到晚返回排在 after 之后的最多 limit 条消息，不删除；after 为nil时从头开始
+	// 重发时以上一页的最后一条消息作为 after 继续读取，跳过熔断器仍打开的通道而不阻塞其他通道
+	Peek(ctx context.Context, after *SpooledMessage, limit int) ([]*SpooledMessage, error)
+	Remove(ctx context.Context, id string) error // 删除已处理的消息，消息不存在时不返回错误
+
+	Get(ctx context.Context, id string) (*SpooledMessage, error) // 获取指定消息，不存在时返回 ErrSpoolMessageNotFound
+	Len(ctx context.Context) (int, error)                        // 暂存的消息数
+	// Stats 返回积压统计（Pending、ByChannel、Failing、Oldest），供监控抓取频繁调用，不应逐条读取消息
+	Stats(ctx context.Context) (*SpoolStats, error)
+}
+
+// spoolCompressedMagic 压缩暂存内容的头部标识，用于区分未压缩的内容（开启加密时位于密文内部）
+var spoolCompressedMagic = []byte("MPSPZIP1")
+
+// spoolCorruptSuffix 无法解析的暂存文件被隔离后的后缀
+const spoolCorruptSuffix = ".corrupt"
+
+// FileSpool 基于本地目录的暂存，每条消息一个以暂存ID命名的 JSON 文件，写入采用临时文件加重命名，进程崩溃不会留下半条消息
+// 打开时加载索引，之后按ID直接读写文件；无法解析的文件重命名为 <ID>.json.corrupt 隔离，不影响其他消息重发
+// 同一目录只应由一个进程使用；文件包含接收者和模板参数，目录权限为 0700（已存在的目录会收紧权限），可通过 WithSpoolCipher 加密、WithSpoolCompression 压缩
+type FileSpool struct {
+	dir    string
+	cipher SpoolCipher // 文件加密（可选）
+	comp   Compression // 文件压缩（可选），在加密之前压缩
+	mu     sync.Mutex
+	index  []spoolEntry          // 按 (SpooledAt, ID) 排序
+	ids    map[string]spoolEntry // 暂存ID -> 索引项
+	// 积压统计，随索引增量维护，Stats 无需读取文件
+	byChannel map[int]int
+	failing   int
+}
+
+// spoolEntry FileSpool 的索引项
+type spoolEntry struct {
+	id        string
+	at        time.Time
+	channelID int  // 通道ID
+	failing   bool // 是否重发失败过
+}
+
+// newSpoolEntry 根据消息创建索引项
+func newSpoolEntry(msg *SpooledMessage) spoolEntry {
+	e := spoolEntry{id: msg.ID, at: msg.SpooledAt, failing: msg.Attempts > 0}
+	if msg.Request != nil {
+		e.channelID = msg.Request.ChannelID
+	}
+	return e
+}
+
+// before 判断索引项是否排在 (at, id) 之前
+func (e spoolEntry) before(at time.Time, id string) bool {
+	if !e.at.Equal(at) {
+		return e.at.Before(at)
+	}
+	return e.id < id
+}
+
+// NewFileSpool 创建基于目录的暂存，目录不存在时自动创建，已存在时将权限收紧为 0700
+func NewFileSpool(dir string, opts ...FileSpoolOption) (*FileSpool, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create spool dir: %w", err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("stat spool dir: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("spool path %s is not a directory", dir)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		if err := os.Chmod(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("restrict spool dir permissions: %w", err)
+		}
+	}
+
+	s := &FileSpool{dir: dir, ids: make(map[string]spoolEntry), byChannel: make(map[int]int)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.load(context.Background()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load 扫描目录建立索引，无法解析的文件被隔离；无法解密的文件说明加密配置有误，直接返回错误
+func (s *FileSpool) load(ctx context.Context) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("read spool dir: %w", err)
+	}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || !e.Type().IsRegular() || validSpoolID(id) != nil {
+			continue
+		}
+		msg, err := s.read(ctx, id)
+		if errors.Is(err, ErrSpoolDecrypt) {
+			return err
+		}
+		if err != nil {
+			if qErr := s.quarantine(id); qErr != nil {
+				return qErr
+			}
+			continue
+		}
+		s.insert(newSpoolEntry(msg))
+	}
+	return nil
+}
+
+// Put 实现 Spool 接口
+func (s *FileSpool) Put(ctx context.Context, msg *SpooledMessage) error {
+	if err := validSpoolID(msg.ID); err != nil {
+		return err
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal spooled message: %w", err)
+	}
+	if s.comp != nil {
+		if data, err = compressSpoolData(s.comp, data); err != nil {
+			return err
+		}
+	}
+	if s.cipher != nil {
+		sealed, err := s.cipher.Seal(ctx, data)
+		if err != nil {
+			return fmt.Errorf("encrypt spooled message: %w", err)
+		}
+		data = append(append([]byte{}, spoolSealedMagic...), sealed...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create spool file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write spool file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("sync spool file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("close spool file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(msg.ID)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("commit spool file: %w", err)
+	}
+	s.drop(msg.ID)
+	s.insert(newSpoolEntry(msg))
+	return nil
+}
+
+// Peek 实现 Spool 接口，limit 小于等于0时返回全部消息
+// 无法解析的文件被隔离并跳过；无法解密的文件（如 KMS 暂时不可用）保留在暂存中，本次跳过
+func (s *FileSpool) Peek(ctx context.Context, after *SpooledMessage, limit int) ([]*SpooledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := 0
+	if after != nil {
+		cursor := spoolEntry{id: after.ID, at: after.SpooledAt}
+		i = sort.Search(len(s.index), func(i int) bool { return cursor.before(s.index[i].at, s.index[i].id) })
+	}
+	var msgs []*SpooledMessage
+	for i < len(s.index) && (limit <= 0 || len(msgs) < limit) {
+		id := s.index[i].id
+		msg, err := s.read(ctx, id)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// 文件已被外部删除
+				s.drop(id)
+				continue
+			}
+			if errors.Is(err, ErrSpoolDecrypt) {
+				// 可能是 KMS 暂时不可用，保留文件，跳过该消息
+				i++
+				continue
+			}
+			if qErr := s.quarantine(id); qErr != nil {
+				return msgs, qErr
+			}
+			continue
+		}
+		msgs = append(msgs, msg)
+		i++
+	}
+	return msgs, nil
+}
+
+// Remove 实现 Spool 接口
+func (s *FileSpool) Remove(ctx context.Context, id string) error {
+	if validSpoolID(id) != nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove spool file: %w", err)
+	}
+	s.drop(id)
+	return nil
+}
+
+// Get 实现 Spool 接口
+func (s *FileSpool) Get(ctx context.Context, id string) (*SpooledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ids[id]; !ok {
+		return nil, fmt.Errorf("spool id %q: %w", id, ErrSpoolMessageNotFound)
+	}
+	return s.read(ctx, id)
+}
+
+// Len 实现 Spool 接口
+func (s *FileSpool) Len(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.index), nil
+}
+
+// Stats 实现 Spool 接口，统计随索引维护，不读取文件
+func (s *FileSpool) Stats(ctx context.Context) (*SpoolStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := &SpoolStats{Pending: len(s.index), ByChannel: make(map[int]int, len(s.byChannel)), Failing: s.failing}
+	for id, n := range s.byChannel {
+		stats.ByChannel[id] = n
+	}
+	if len(s.index) > 0 {
+		stats.Oldest = s.index[0].at
+	}
+	return stats, nil
+}
+
+// path 消息文件路径
+func (s *FileSpool) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// read 读取、解密、解压并解析消息文件，文件内容与文件名不一致时视为损坏
+func (s *FileSpool) read(ctx context.Context, id string) (*SpooledMessage, error) {
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return nil, fmt.Errorf("read spool file: %w", err)
+	}
+	if sealed, ok := bytes.CutPrefix(data, spoolSealedMagic); ok {
+		if s.cipher == nil {
+			return nil, fmt.Errorf("spool file %s: %w: file is encrypted but no cipher is configured", id, ErrSpoolDecrypt)
+		}
+		if data, err = s.cipher.Open(ctx, sealed); err != nil {
+			return nil, fmt.Errorf("spool file %s: %w: %v", id, ErrSpoolDecrypt, err)
+		}
+	}
+	if compressed, ok := bytes.CutPrefix(data, spoolCompressedMagic); ok {
+		if data, err = decompressSpoolData(s.comp, compressed); err != nil {
+			return nil, fmt.Errorf("spool file %s: %w", id, err)
+		}
+	}
+	var msg SpooledMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("decode spool file %s: %w", id, err)
+	}
+	if msg.ID != id || msg.Request == nil {
+		return nil, fmt.Errorf("decode spool file %s: malformed message", id)
+	}
+	return &msg, nil
+}
+
+// compressSpoolData 压缩消息内容，格式为 spoolCompressedMagic || len(格式名称)(1字节) || 格式名称 || 压缩数据
+func compressSpoolData(c Compression, data []byte) ([]byte, error) {
+	name := c.Name()
+	if name == "" || len(name) > 0xff {
+		return nil, fmt.Errorf("invalid compression name %q", name)
+	}
+	var buf bytes.Buffer
+	buf.Write(spoolCompressedMagic)
+	buf.WriteByte(byte(len(name)))
+	buf.WriteString(name)
+	w, err := c.NewWriter(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("create %s writer: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return nil, fmt.Errorf("compress spooled message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("compress spooled message: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressSpoolData 按头部记录的格式名称解压消息内容，优先使用配置的格式 c，其他格式需为内置或已通过 RegisterCompression 注册
+func decompressSpoolData(c Compression, data []byte) ([]byte, error) {
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return nil, errors.New("compressed data too short")
+	}
+	name := string(data[1 : 1+data[0]])
+	if c == nil || c.Name() != name {
+		c = compressionByName(name)
+	}
+	if c == nil {
+		return nil, fmt.Errorf("unknown compression %q", name)
+	}
+	r, err := c.NewReader(bytes.NewReader(data[1+data[0]:]))
+	if err != nil {
+		return nil, fmt.Errorf("create %s reader: %w", name, err)
+	}
+	defer r.Close()
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompress spooled message: %w", err)
+	}
+	return plain, nil
+}
+
+// quarantine 将损坏的消息文件重命名隔离并移出索引，调用方需持有锁（load 时除外）
+func (s *FileSpool) quarantine(id string) error {
+	if err := os.Rename(s.path(id), s.path(id)+spoolCorruptSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("quarantine spool file: %w", err)
+	}
+	s.drop(id)
+	return nil
+}
+
+// insert 按顺序插入索引项，调用方需持有锁
+func (s *FileSpool) insert(e spoolEntry) {
+	i := sort.Search(len(s.index), func(i int) bool { return !s.index[i].before(e.at, e.id) })
+	s.index = append(s.index, spoolEntry{})
+	copy(s.index[i+1:], s.index[i:])
+	s.index[i] = e
+	s.ids[e.id] = e
+	s.byChannel[e.channelID]++
+	if e.failing {
+		s.failing++
+	}
+}
+
+// drop 从索引中移除消息，调用方需持有锁
+func (s *FileSpool) drop(id string) {
+	e, ok := s.ids[id]
+	if !ok {
+		return
+	}
+	delete(s.ids, id)
+	if s.byChannel[e.channelID]--; s.byChannel[e.channelID] <= 0 {
+		delete(s.byChannel, e.channelID)
+	}
+	if e.failing {
+		s.failing--
+	}
+	i := sort.Search(len(s.index), func(i int) bool { return !s.index[i].before(e.at, id) })
+	if i < len(s.index) && s.index[i].id == id {
+		s.index = append(s.index[:i], s.index[i+1:]...)
+	}
+}
+
+// validSpoolID 校验暂存ID可以安全地用作文件名
+func validSpoolID(id string) error {
+	if id == "" || strings.HasPrefix(id, ".") || strings.ContainsAny(id, "/\\\x00") {
+		return fmt.Errorf("invalid spool id %q", id)
+	}
+	return nil
+}
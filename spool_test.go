@@ -0,0 +1,168 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestFileSpool 测试目录暂存的顺序、删除和持久化
+func TestFileSpool(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := NewFileSpool(dir)
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	ctx := context.Background()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"c", "a", "b"} {
+		msg := &SpooledMessage{ID: id, Request: &SendMessageRequest{ChannelID: 1, Receiver: id}, SpooledAt: base.Add(time.Duration(i) * time.Second)}
+		if err := spool.Put(ctx, msg); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	if err := spool.Put(ctx, &SpooledMessage{ID: "../x"}); err == nil {
+		t.Error("Put() with path separator should fail")
+	}
+	// 崩溃遗留的临时文件被忽略
+	os.WriteFile(filepath.Join(dir, ".tmp-123"), []byte("{"), 0o600)
+
+	// 重新打开目录，消息仍在
+	spool, err = NewFileSpool(dir)
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	msgs, err := spool.Peek(ctx, nil, 2)
+	if err != nil || len(msgs) != 2 || msgs[0].ID != "c" || msgs[1].ID != "a" || msgs[0].Request.Receiver != "c" {
+		t.Fatalf("Peek() = %+v, %v", msgs, err)
+	}
+
+	if err := spool.Remove(ctx, "a"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := spool.Remove(ctx, "missing"); err != nil {
+		t.Errorf("Remove(missing) error = %v", err)
+	}
+	msgs, _ = spool.Peek(ctx, nil, 0)
+	if len(msgs) != 2 || msgs[0].ID != "c" || msgs[1].ID != "b" {
+		t.Errorf("Peek() after Remove = %+v", msgs)
+	}
+	if n, err := spool.Len(ctx); n != 2 || err != nil {
+		t.Errorf("Len() = %d, %v", n, err)
+	}
+	if msg, err := spool.Get(ctx, "b"); err != nil || msg.Request.Receiver != "b" {
+		t.Errorf("Get(b) = %+v, %v", msg, err)
+	}
+	if _, err := spool.Get(ctx, "a"); !errors.Is(err, ErrSpoolMessageNotFound) {
+		t.Errorf("Get(a) error = %v, want ErrSpoolMessageNotFound", err)
+	}
+	// 从上一页的最后一条之后继续读取，之前的消息已被删除也不影响
+	msgs, _ = spool.Peek(ctx, &SpooledMessage{ID: "a", SpooledAt: base.Add(time.Second)}, 0)
+	if len(msgs) != 1 || msgs[0].ID != "b" {
+		t.Errorf("Peek(after a) = %+v", msgs)
+	}
+}
+
+// TestFileSpoolCorrupt 测试损坏的文件被隔离，不影响其他消息
+func TestFileSpoolCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := NewFileSpool(dir)
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	ctx := context.Background()
+	for i, id := range []string{"a", "b", "c"} {
+		spool.Put(ctx, &SpooledMessage{ID: id, Request: &SendMessageRequest{ChannelID: 1, Receiver: id}, SpooledAt: time.Unix(int64(i), 0)})
+	}
+	os.WriteFile(filepath.Join(dir, "a.json"), []byte("{"), 0o600)
+
+	msgs, err := spool.Peek(ctx, nil, 2)
+	if err != nil || len(msgs) != 2 || msgs[0].ID != "b" || msgs[1].ID != "c" {
+		t.Fatalf("Peek() = %+v, %v", msgs, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.json.corrupt")); err != nil {
+		t.Errorf("corrupt file should be quarantined: %v", err)
+	}
+	if n, _ := spool.Len(ctx); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+
+	// 打开目录时同样隔离损坏的文件
+	os.WriteFile(filepath.Join(dir, "b.json"), []byte("not json"), 0o600)
+	spool, err = NewFileSpool(dir)
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	if n, _ := spool.Len(ctx); n != 1 {
+		t.Errorf("Len() after reopen = %d, want 1", n)
+	}
+}
+
+// TestFileSpoolDirPermissions 测试已存在目录的权限被收紧
+func TestFileSpoolDirPermissions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "spool")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileSpool(dir); err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil || info.Mode().Perm() != 0o700 {
+		t.Errorf("dir mode = %v, %v, want 0700", info.Mode().Perm(), err)
+	}
+}
+
+// countingCipher 统计解密次数的加密
+type countingCipher struct {
+	SpoolCipher
+	opens atomic.Int64
+}
+
+// Open 实现 SpoolCipher 接口
+func (c *countingCipher) Open(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	c.opens.Add(1)
+	return c.SpoolCipher.Open(ctx, ciphertext)
+}
+
+// TestFileSpoolStats 测试积压统计随索引维护，不读取和解密文件
+func TestFileSpoolStats(t *testing.T) {
+	aesCipher, _ := NewAESGCMCipher(make([]byte, 32))
+	cipher := &countingCipher{SpoolCipher: aesCipher}
+	spool, err := NewFileSpool(t.TempDir(), WithSpoolCipher(cipher))
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	ctx := context.Background()
+	for i, ch := range []int{1, 1, 2} {
+		spool.Put(ctx, &SpooledMessage{ID: fmt.Sprintf("m%d", i), Request: &SendMessageRequest{ChannelID: ch}, SpooledAt: time.Unix(int64(10+i), 0)})
+	}
+	// 覆盖写入：记录重发失败
+	spool.Put(ctx, &SpooledMessage{ID: "m1", Request: &SendMessageRequest{ChannelID: 1}, SpooledAt: time.Unix(11, 0), Attempts: 1})
+	spool.Remove(ctx, "m0")
+
+	stats, err := spool.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Pending != 2 || stats.ByChannel[1] != 1 || stats.ByChannel[2] != 1 || stats.Failing != 1 || !stats.Oldest.Equal(time.Unix(11, 0)) {
+		t.Errorf("Stats() = %+v", stats)
+	}
+	if n := cipher.opens.Load(); n != 0 {
+		t.Errorf("Stats() decrypted %d messages, want 0", n)
+	}
+
+	spool.Remove(ctx, "m1")
+	spool.Remove(ctx, "m2")
+	if stats, _ := spool.Stats(ctx); stats.Pending != 0 || len(stats.ByChannel) != 0 || stats.Failing != 0 || !stats.Oldest.IsZero() {
+		t.Errorf("Stats() after remove = %+v", stats)
+	}
+}
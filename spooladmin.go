@@ -0,0 +1,103 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// errNoDegradation 未设置 WithDegradation
+var errNoDegradation = errors.New("degradation policy is not configured")
+
+// SpoolStats 暂存统计，用于运维查看本地积压情况；Spool.Stats 只填写积压字段，累计计数由 Client.SpoolStats 补充
+type SpoolStats struct {
+	Pending   int         `json:"pending"`    // 暂存中的消息数
+	ByChannel map[int]int `json:"by_channel"` // 各通道暂存中的消息数
+	Failing   int         `json:"failing"`    // 重发失败过（Attempts > 0）的消息数
+	Oldest    time.Time   `json:"oldest"`     // 最早的暂存时间，暂存为空时为零值
+	Spooled   int64       `json:"spooled"`    // 客户端创建以来累计暂存的消息数
+	Drained   int64       `json:"drained"`    // 累计重发成功的消息数
+	Dropped   int64       `json:"dropped"`    // 累计因不可重试的失败或过期删除的消息数
+}
+
+// spoolCounters 暂存累计计数，并发安全
+type spoolCounters struct {
+	spooled atomic.Int64
+	drained atomic.Int64
+	dropped atomic.Int64
+}
+
+// SpoolStats 获取暂存统计，积压部分来自 Spool.Stats（不读取消息内容），可供监控频繁抓取；未设置 WithDegradation 时积压为零值
+// 可注册到调试接口：debughttp.WithSection("spool", ...)，或通过 metrics.NewSpool 输出为 Prometheus 指标
+func (c *Client) SpoolStats(ctx context.Context) (*SpoolStats, error) {
+	stats := &SpoolStats{ByChannel: make(map[int]int)}
+	if p := c.degradation; p != nil {
+		s, err := p.Spool.Stats(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("spool stats: %w", err)
+		}
+		stats = s
+		if stats.ByChannel == nil {
+			stats.ByChannel = make(map[int]int)
+		}
+	}
+	stats.Spooled = c.spoolCounts.spooled.Load()
+	stats.Drained = c.spoolCounts.drained.Load()
+	stats.Dropped = c.spoolCounts.dropped.Load()
+	return stats, nil
+}
+
+// ListSpooled 按暂存顺序列出排在 after 之后的最多 limit 条暂存消息（after 为nil时从头开始），含重发失败次数和原因
+func (c *Client) ListSpooled(ctx context.Context, after *SpooledMessage, limit int) ([]*SpooledMessage, error) {
+	if c.degradation == nil {
+		return nil, errNoDegradation
+	}
+	return c.degradation.Spool.Peek(ctx, after, limit)
+}
+
+// GetSpooled 获取指定的暂存消息，不存在时返回满足 errors.Is(err, ErrSpoolMessageNotFound) 的错误
+func (c *Client) GetSpooled(ctx context.Context, id string) (*SpooledMessage, error) {
+	if c.degradation == nil {
+		return nil, errNoDegradation
+	}
+	return c.degradation.Spool.Get(ctx, id)
+}
+
+// DeleteSpooled 删除指定的暂存消息，不再重发，消息不存在时不返回错误
+func (c *Client) DeleteSpooled(ctx context.Context, id string) error {
+	if c.degradation == nil {
+		return errNoDegradation
+	}
+	if err := c.degradation.Spool.Remove(ctx, id); err != nil {
+		return fmt.Errorf("remove spooled message: %w", err)
+	}
+	return nil
+}
+
+// RetrySpooled 立即重发指定的暂存消息，不等待 RunSpoolDrainer 的下一轮，不检查 DrainRules 的 MaxAge
+// 成功或不可重试的失败时从暂存中删除并通过 OnDrain 回调；可重试的失败（如熔断器仍打开）保留在暂存中并记录失败原因
+func (c *Client) RetrySpooled(ctx context.Context, id string) (*SendMessageData, error) {
+	if c.degradation == nil {
+		return nil, errNoDegradation
+	}
+	msg, err := c.degradation.Spool.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.SendMessage(ctx, msg.Request, msg.callOptions()...)
+	if err != nil && spoolRetryable(err) {
+		if ctx.Err() == nil {
+			if recErr := c.recordSpoolFailure(ctx, msg, err); recErr != nil {
+				return nil, errors.Join(err, recErr)
+			}
+		}
+		return nil, err
+	}
+	if finErr := c.finishSpooled(ctx, msg, data, err); finErr != nil {
+		return data, errors.Join(err, finErr)
+	}
+	return data, err
+}
@@ -0,0 +1,75 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestSpoolAdmin 测试暂存的查看、强制重发、删除和统计
+func TestSpoolAdmin(t *testing.T) {
+	server := newSuccessServer(t)
+	spool, err := NewFileSpool(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	var drained []DrainResult
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithCircuitBreaker(BreakerConfig{FailureThreshold: 1, Cooldown: time.Minute}),
+		WithDegradation(DegradationPolicy{Spool: spool, OnDrain: func(r DrainResult) { drained = append(drained, r) }}))
+	client.breakers.record(1, NewAPIError(ErrCodeProviderError, "服务商错误"), http.StatusOK)
+	ctx := context.Background()
+
+	spool.Put(ctx, &SpooledMessage{ID: "blocked", Request: &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}, SpooledAt: time.Unix(1, 0)})
+	spool.Put(ctx, &SpooledMessage{ID: "other", Request: &SendMessageRequest{ChannelID: 2, Receiver: "13800138000"}, SpooledAt: time.Unix(2, 0)})
+	spool.Put(ctx, &SpooledMessage{ID: "stale", Request: &SendMessageRequest{ChannelID: 2, Receiver: "13800138001"}, SpooledAt: time.Unix(3, 0)})
+
+	// 熔断中的消息重发失败，保留在暂存中并记录原因
+	if _, err := client.RetrySpooled(ctx, "blocked"); err == nil {
+		t.Fatal("RetrySpooled() on open circuit should fail")
+	}
+	msg, err := client.GetSpooled(ctx, "blocked")
+	if err != nil || msg.Attempts != 1 || msg.LastError == "" || msg.LastAttemptAt.IsZero() {
+		t.Fatalf("GetSpooled() = %+v, %v", msg, err)
+	}
+
+	stats, err := client.SpoolStats(ctx)
+	if err != nil {
+		t.Fatalf("SpoolStats() error = %v", err)
+	}
+	if stats.Pending != 3 || stats.ByChannel[2] != 2 || stats.Failing != 1 || !stats.Oldest.Equal(time.Unix(1, 0)) {
+		t.Errorf("SpoolStats() = %+v", stats)
+	}
+
+	if data, err := client.RetrySpooled(ctx, "other"); err != nil || data.TaskID != "t1" {
+		t.Fatalf("RetrySpooled() = %+v, %v", data, err)
+	}
+	if len(drained) != 1 || drained[0].Message.ID != "other" {
+		t.Errorf("OnDrain results = %+v", drained)
+	}
+	if _, err := client.RetrySpooled(ctx, "other"); !errors.Is(err, ErrSpoolMessageNotFound) {
+		t.Errorf("RetrySpooled() after delivery error = %v, want ErrSpoolMessageNotFound", err)
+	}
+
+	if err := client.DeleteSpooled(ctx, "stale"); err != nil {
+		t.Fatalf("DeleteSpooled() error = %v", err)
+	}
+	msgs, err := client.ListSpooled(ctx, nil, 0)
+	if err != nil || len(msgs) != 1 || msgs[0].ID != "blocked" {
+		t.Errorf("ListSpooled() = %+v, %v", msgs, err)
+	}
+	if stats, _ := client.SpoolStats(ctx); stats.Pending != 1 || stats.Drained != 1 || stats.Dropped != 0 {
+		t.Errorf("SpoolStats() = %+v", stats)
+	}
+
+	// 未设置降级策略
+	plain := NewClient(server.URL, "test_app_id", "test_secret")
+	if _, err := plain.RetrySpooled(ctx, "blocked"); err == nil {
+		t.Error("RetrySpooled() without degradation should fail")
+	}
+	if stats, err := plain.SpoolStats(ctx); err != nil || stats.Pending != 0 {
+		t.Errorf("SpoolStats() without degradation = %+v, %v", stats, err)
+	}
+}
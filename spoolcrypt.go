@@ -0,0 +1,158 @@
+package mlievpush
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrSpoolDecrypt 暂存文件无法解密（未配置加密、密钥不匹配或内容被篡改）
+var ErrSpoolDecrypt = errors.New("mlievpush: cannot decrypt spool file")
+
+// SpoolCipher 暂存内容的静态加密，暂存文件包含接收者、验证码等敏感数据
+// 内置 NewAESGCMCipher（本地密钥）和 NewEnvelopeCipher（KMS 信封加密），实现需并发安全
+type SpoolCipher interface {
+	Seal(ctx context.Context, plaintext []byte) ([]byte, error)  // 加密
+	Open(ctx context.Context, ciphertext []byte) ([]byte, error) // 解密
+}
+
+// KeyWrapper 密钥加密服务（如云 KMS）的接口，用于信封加密：数据密钥由 KMS 加密后与密文一同保存
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)       // 加密数据密钥
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) // 解密数据密钥
+}
+
+// FileSpoolOption FileSpool 配置选项
+type FileSpoolOption func(*FileSpool)
+
+// WithSpoolCipher 设置暂存文件的加密方式，消息写入前加密、读取时解密
+// 开启前已存在的明文文件仍可读取；加密文件在未配置或密钥不匹配时 NewFileSpool 返回满足 errors.Is(err, ErrSpoolDecrypt) 的错误
+func WithSpoolCipher(c SpoolCipher) FileSpoolOption {
+	return func(s *FileSpool) {
+		s.cipher = c
+	}
+}
+
+// WithSpoolCompression 设置暂存文件的压缩格式，消息在加密之前压缩、解密之后解压
+// 开启前已存在的未压缩文件仍可读取；读取时按文件头记录的格式名称解压，切换格式后旧格式需为内置或已通过 RegisterCompression 注册
+func WithSpoolCompression(c Compression) FileSpoolOption {
+	return func(s *FileSpool) {
+		s.comp = c
+	}
+}
+
+// spoolSealedMagic 加密暂存文件的头部标识，用于区分明文文件
+var spoolSealedMagic = []byte("MPSPOOL1")
+
+// aesGCMCipher 基于 AES-GCM 的加密
+type aesGCMCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMCipher 使用本地密钥创建 AES-GCM 加密，key 长度为 16、24 或 32 字节（AES-128/192/256）
+// 每条消息使用随机 nonce，密文格式为 nonce || ciphertext
+func NewAESGCMCipher(key []byte) (SpoolCipher, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &aesGCMCipher{aead: aead}, nil
+}
+
+// Seal 实现 SpoolCipher 接口
+func (c *aesGCMCipher) Seal(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return gcmSeal(c.aead, nil, plaintext)
+}
+
+// Open 实现 SpoolCipher 接口
+func (c *aesGCMCipher) Open(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return gcmOpen(c.aead, ciphertext)
+}
+
+// envelopeCipher 信封加密
+type envelopeCipher struct {
+	kms KeyWrapper
+}
+
+// NewEnvelopeCipher 创建信封加密：每条消息生成随机的 AES-256 数据密钥，数据密钥经 kms 加密后与密文一同保存
+// 主密钥不离开 KMS，轮换主密钥后旧消息仍可由 KMS 解密；每次读写都会调用 kms
+func NewEnvelopeCipher(kms KeyWrapper) SpoolCipher {
+	return &envelopeCipher{kms: kms}
+}
+
+// Seal 实现 SpoolCipher 接口，密文格式为 len(wrapped)(2字节) || wrapped || nonce || ciphertext
+func (c *envelopeCipher) Seal(ctx context.Context, plaintext []byte) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate data key: %w", err)
+	}
+	wrapped, err := c.kms.WrapKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key: %w", err)
+	}
+	if len(wrapped) > 0xffff {
+		return nil, fmt.Errorf("wrapped data key too large: %d bytes", len(wrapped))
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, 2, 2+len(wrapped))
+	binary.BigEndian.PutUint16(prefix, uint16(len(wrapped)))
+	return gcmSeal(aead, append(prefix, wrapped...), plaintext)
+}
+
+// Open 实现 SpoolCipher 接口
+func (c *envelopeCipher) Open(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 2 {
+		return nil, errors.New("ciphertext too short")
+	}
+	n := int(binary.BigEndian.Uint16(ciphertext))
+	if len(ciphertext) < 2+n {
+		return nil, errors.New("ciphertext too short")
+	}
+	key, err := c.kms.UnwrapKey(ctx, ciphertext[2:2+n])
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return gcmOpen(aead, ciphertext[2+n:])
+}
+
+// newGCM 创建 AES-GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create aes cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+	return aead, nil
+}
+
+// gcmSeal 使用随机 nonce 加密，输出 dst || nonce || ciphertext
+func gcmSeal(aead cipher.AEAD, dst, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return aead.Seal(append(dst, nonce...), nonce, plaintext, nil), nil
+}
+
+// gcmOpen 解密 nonce || ciphertext
+func gcmOpen(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, nil)
+}
@@ -0,0 +1,154 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// xorKMS 测试用的密钥加密服务
+type xorKMS struct{}
+
+// WrapKey 实现 KeyWrapper 接口
+func (k *xorKMS) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	wrapped := make([]byte, len(key))
+	for i, b := range key {
+		wrapped[i] = b ^ 0x5a
+	}
+	return wrapped, nil
+}
+
+// UnwrapKey 实现 KeyWrapper 接口
+func (k *xorKMS) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	return k.WrapKey(ctx, wrapped)
+}
+
+// TestFileSpoolCipher 测试暂存文件加密落盘、解密读取
+func TestFileSpoolCipher(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	aesCipher, err := NewAESGCMCipher(key)
+	if err != nil {
+		t.Fatalf("NewAESGCMCipher() error = %v", err)
+	}
+	if _, err := NewAESGCMCipher([]byte("short")); err == nil {
+		t.Error("NewAESGCMCipher() with invalid key length should fail")
+	}
+
+	tests := []struct {
+		name   string
+		cipher SpoolCipher
+	}{
+		{"AES-GCM", aesCipher},
+		{"信封加密", NewEnvelopeCipher(&xorKMS{})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			spool, err := NewFileSpool(dir, WithSpoolCipher(tt.cipher))
+			if err != nil {
+				t.Fatalf("NewFileSpool() error = %v", err)
+			}
+			ctx := context.Background()
+			msg := &SpooledMessage{ID: "a", Request: &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}, SpooledAt: time.Unix(1, 0)}
+			if err := spool.Put(ctx, msg); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+
+			data, _ := os.ReadFile(filepath.Join(dir, "a.json"))
+			if bytes.Contains(data, []byte("13800138000")) {
+				t.Error("spool file should not contain the receiver in plaintext")
+			}
+
+			spool, err = NewFileSpool(dir, WithSpoolCipher(tt.cipher))
+			if err != nil {
+				t.Fatalf("NewFileSpool() reopen error = %v", err)
+			}
+			msgs, err := spool.Peek(ctx, nil, 0)
+			if err != nil || len(msgs) != 1 || msgs[0].Request.Receiver != "13800138000" {
+				t.Fatalf("Peek() = %+v, %v", msgs, err)
+			}
+
+			// 未配置加密时拒绝打开，而不是把加密文件当作损坏文件隔离
+			if _, err := NewFileSpool(dir); !errors.Is(err, ErrSpoolDecrypt) {
+				t.Errorf("NewFileSpool() without cipher error = %v, want ErrSpoolDecrypt", err)
+			}
+		})
+	}
+}
+
+// TestFileSpoolCipherMismatch 测试密钥不匹配时无法打开暂存，开启加密前的明文文件仍可读取
+func TestFileSpoolCipherMismatch(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	plain, err := NewFileSpool(dir)
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	plain.Put(ctx, &SpooledMessage{ID: "legacy", Request: &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}, SpooledAt: time.Unix(1, 0)})
+
+	c1, _ := NewAESGCMCipher(bytes.Repeat([]byte{1}, 16))
+	spool, err := NewFileSpool(dir, WithSpoolCipher(c1))
+	if err != nil {
+		t.Fatalf("NewFileSpool() error = %v", err)
+	}
+	spool.Put(ctx, &SpooledMessage{ID: "sealed", Request: &SendMessageRequest{ChannelID: 1, Receiver: "13800138001"}, SpooledAt: time.Unix(2, 0)})
+	if msgs, err := spool.Peek(ctx, nil, 0); err != nil || len(msgs) != 2 {
+		t.Fatalf("Peek() = %+v, %v", msgs, err)
+	}
+
+	c2, _ := NewAESGCMCipher(bytes.Repeat([]byte{2}, 16))
+	if _, err := NewFileSpool(dir, WithSpoolCipher(c2)); !errors.Is(err, ErrSpoolDecrypt) {
+		t.Errorf("NewFileSpool() with wrong key error = %v, want ErrSpoolDecrypt", err)
+	}
+}
+
+// TestFileSpoolCompression 测试暂存文件压缩后加密落盘，重新打开后解密、解压读取
+func TestFileSpoolCompression(t *testing.T) {
+	aesCipher, _ := NewAESGCMCipher(bytes.Repeat([]byte{1}, 32))
+	tests := []struct {
+		name string
+		opts []FileSpoolOption
+	}{
+		{"gzip", []FileSpoolOption{WithSpoolCompression(Gzip)}},
+		{"gzip+AES-GCM", []FileSpoolOption{WithSpoolCompression(Gzip), WithSpoolCipher(aesCipher)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			ctx := context.Background()
+			// 开启压缩前写入的未压缩文件
+			plain, _ := NewFileSpool(dir)
+			plain.Put(ctx, &SpooledMessage{ID: "a", Request: &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}, SpooledAt: time.Unix(1, 0)})
+
+			spool, err := NewFileSpool(dir, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFileSpool() error = %v", err)
+			}
+			params := map[string]interface{}{"content": string(bytes.Repeat([]byte("活动通知"), 200))}
+			msg := &SpooledMessage{ID: "b", Request: &SendMessageRequest{ChannelID: 1, Receiver: "13900139000", TemplateParams: params}, SpooledAt: time.Unix(2, 0)}
+			if err := spool.Put(ctx, msg); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			raw, _ := os.ReadFile(filepath.Join(dir, "b.json"))
+			if bytes.Contains(raw, []byte("13900139000")) || len(raw) > 1000 {
+				t.Errorf("spool file is not compressed: %d bytes", len(raw))
+			}
+
+			reopened, err := NewFileSpool(dir, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFileSpool() reopen error = %v", err)
+			}
+			msgs, err := reopened.Peek(ctx, nil, 0)
+			if err != nil || len(msgs) != 2 {
+				t.Fatalf("Peek() = %d messages, %v", len(msgs), err)
+			}
+			if msgs[0].Request.Receiver != "13800138000" || msgs[1].Request.TemplateParams["content"] != params["content"] {
+				t.Errorf("Peek() = %+v, %+v", msgs[0].Request, msgs[1].Request)
+			}
+		})
+	}
+}
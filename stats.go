@@ -0,0 +1,165 @@
+package mlievpush
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultRecentErrors 默认保留的最近错误数量
+const defaultRecentErrors = 20
+
+// Stats 客户端运行统计
+type Stats struct {
+	Requests   int64         `json:"requests"`    // 请求总数
+	Successes  int64         `json:"successes"`   // 成功数
+	Failures   int64         `json:"failures"`    // 失败数（含业务错误）
+	APIErrors  int64         `json:"api_errors"`  // 业务错误数
+	InFlight   int64         `json:"in_flight"`   // 进行中的请求数
+	AvgLatency time.Duration `json:"avg_latency"` // 平均耗时
+}
+
+// ErrorRecord 最近错误记录（已脱敏）
+type ErrorRecord struct {
+	Time      time.Time `json:"time"`                 // 发生时间
+	Method    string    `json:"method"`               // 请求方法
+	Path      string    `json:"path"`                 // 请求路径
+	RequestID string    `json:"request_id,omitempty"` // 请求ID
+	Code      int       `json:"code,omitempty"`       // 业务错误码
+	Message   string    `json:"message"`              // 错误信息（已脱敏）
+}
+
+// ConfigSnapshot 客户端配置快照（密钥已掩码）
+type ConfigSnapshot struct {
+	BaseURL                string                  `json:"base_url"`                 // 基础URL
+	AppID                  string                  `json:"app_id"`                   // 应用ID
+	AppSecret              string                  `json:"app_secret"`               // 应用密钥（掩码）
+	Timeout                time.Duration           `json:"timeout"`                  // 请求超时时间
+	MaxAttempts            int                     `json:"max_attempts"`             // 最大尝试次数（含首次）
+	PanicRecovery          bool                    `json:"panic_recovery"`           // 是否捕获回调panic
+	DefaultAttachmentLimit AttachmentLimit         `json:"default_attachment_limit"` // 默认附件限制
+	AttachmentLimits       map[int]AttachmentLimit `json:"attachment_limits"`        // 通道附件限制
+}
+
+// clientStats 客户端统计数据，并发安全
+type clientStats struct {
+	requests     atomic.Int64
+	successes    atomic.Int64
+	failures     atomic.Int64
+	apiErrors    atomic.Int64
+	inFlight     atomic.Int64
+	latencyNanos atomic.Int64
+
+	mu     sync.Mutex
+	recent []ErrorRecord // 环形缓冲区
+	next   int           // 下一个写入位置
+}
+
+// newClientStats 创建统计数据
+func newClientStats(recentSize int) *clientStats {
+	return &clientStats{
+		recent: make([]ErrorRecord, 0, recentSize),
+	}
+}
+
+// begin 记录请求开始
+func (s *clientStats) begin() {
+	s.requests.Add(1)
+	s.inFlight.Add(1)
+}
+
+// end 记录请求结束
+func (s *clientStats) end(method, path string, duration time.Duration, err error, redactor Redactor) {
+	s.inFlight.Add(-1)
+	s.latencyNanos.Add(int64(duration))
+
+	if err == nil {
+		s.successes.Add(1)
+		return
+	}
+
+	s.failures.Add(1)
+	record := ErrorRecord{
+		Time:      time.Now(),
+		Method:    method,
+		Path:      path,
+		RequestID: RequestIDFromError(err),
+		Message:   redactor.RedactText(err.Error()),
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		s.apiErrors.Add(1)
+		record.Code = apiErr.Code
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.recent) < cap(s.recent) {
+		s.recent = append(s.recent, record)
+	} else if cap(s.recent) > 0 {
+		s.recent[s.next] = record
+	}
+	if cap(s.recent) > 0 {
+		s.next = (s.next + 1) % cap(s.recent)
+	}
+}
+
+// Stats 获取客户端运行统计
+func (c *Client) Stats() Stats {
+	s := c.stats
+	stats := Stats{
+		Requests:  s.requests.Load(),
+		Successes: s.successes.Load(),
+		Failures:  s.failures.Load(),
+		APIErrors: s.apiErrors.Load(),
+		InFlight:  s.inFlight.Load(),
+	}
+	if done := stats.Successes + stats.Failures; done > 0 {
+		stats.AvgLatency = time.Duration(s.latencyNanos.Load() / done)
+	}
+	return stats
+}
+
+// RecentErrors 获取最近的错误记录（按时间从旧到新，已脱敏）
+func (c *Client) RecentErrors() []ErrorRecord {
+	s := c.stats
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]ErrorRecord, 0, len(s.recent))
+	if len(s.recent) < cap(s.recent) {
+		return append(records, s.recent...)
+	}
+	records = append(records, s.recent[s.next:]...)
+	return append(records, s.recent[:s.next]...)
+}
+
+// Snapshot 获取客户端配置快照，密钥已掩码，可安全输出到日志或调试接口
+func (c *Client) Snapshot() ConfigSnapshot {
+	limits := make(map[int]AttachmentLimit, len(c.attachmentLimits))
+	for id, limit := range c.attachmentLimits {
+		limits[id] = limit
+	}
+
+	appID, appSecret := c.staticCredentials()
+	return ConfigSnapshot{
+		BaseURL:                c.baseURL,
+		AppID:                  appID,
+		AppSecret:              maskSecret(appSecret),
+		Timeout:                c.httpClient.Timeout,
+		MaxAttempts:            c.maxAttempts,
+		PanicRecovery:          c.recoverPanics,
+		DefaultAttachmentLimit: c.defaultAttachmentLimit,
+		AttachmentLimits:       limits,
+	}
+}
+
+// maskSecret 掩码密钥，仅保留首尾各两个字符
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:2] + strings.Repeat("*", len(secret)-4) + secret[len(secret)-2:]
+}
@@ -0,0 +1,91 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestStats 测试请求统计与最近错误
+func TestStats(t *testing.T) {
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{}}
+		if fail {
+			resp = map[string]interface{}{"code": ErrCodeInvalidReceiver, "message": "invalid receiver 13800138000"}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	ctx := context.Background()
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}
+
+	client.SendMessage(ctx, req)
+	fail = true
+	client.SendMessage(ctx, req)
+
+	stats := client.Stats()
+	if stats.Requests != 2 || stats.Successes != 1 || stats.Failures != 1 || stats.APIErrors != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.InFlight != 0 {
+		t.Errorf("InFlight = %d, want 0", stats.InFlight)
+	}
+
+	errs := client.RecentErrors()
+	if len(errs) != 1 {
+		t.Fatalf("RecentErrors() = %d, want 1", len(errs))
+	}
+	if errs[0].Code != ErrCodeInvalidReceiver {
+		t.Errorf("Code = %d, want %d", errs[0].Code, ErrCodeInvalidReceiver)
+	}
+	if strings.Contains(errs[0].Message, "13800138000") {
+		t.Errorf("receiver should be redacted: %s", errs[0].Message)
+	}
+}
+
+// TestRecentErrorsRing 测试最近错误的环形缓冲区
+func TestRecentErrorsRing(t *testing.T) {
+	s := newClientStats(3)
+	c := &Client{stats: s}
+
+	for _, path := range []string{"/a", "/b", "/c", "/d", "/e"} {
+		s.begin()
+		s.end(http.MethodGet, path, 0, context.Canceled, DefaultRedactor)
+	}
+
+	errs := c.RecentErrors()
+	if len(errs) != 3 {
+		t.Fatalf("RecentErrors() = %d, want 3", len(errs))
+	}
+	if errs[0].Path != "/c" || errs[2].Path != "/e" {
+		t.Errorf("unexpected order: %s, %s, %s", errs[0].Path, errs[1].Path, errs[2].Path)
+	}
+}
+
+// TestSnapshot 测试配置快照掩码密钥
+func TestSnapshot(t *testing.T) {
+	client := NewClient("https://push.example.com", "test_app_id", "secret123456789")
+	snap := client.Snapshot()
+
+	if snap.AppSecret == "secret123456789" || !strings.HasPrefix(snap.AppSecret, "se") {
+		t.Errorf("AppSecret = %q, should be masked", snap.AppSecret)
+	}
+	if snap.BaseURL != "https://push.example.com" {
+		t.Errorf("BaseURL = %q", snap.BaseURL)
+	}
+}
+
+// TestRedactMessage 测试错误信息脱敏
+func TestRedactMessage(t *testing.T) {
+	got := redactMessage("invalid receiver 13800138000, user@example.com")
+	want := "invalid receiver 138****8000, ***@example.com"
+	if got != want {
+		t.Errorf("redactMessage() = %q, want %q", got, want)
+	}
+}
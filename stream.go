@@ -0,0 +1,99 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultStreamConcurrency SendStream 默认的最大并发数
+const DefaultStreamConcurrency = 8
+
+// StreamOptions 流式发送配置
+type StreamOptions struct {
+	Concurrency int                // 最大并发请求数，0 使用 DefaultStreamConcurrency
+	PerSecond   float64            // 本次流式发送的速率上限（每秒请求数），0 表示仅受客户端 WithRateLimit 限制
+	OnResult    func(StreamResult) // 每条消息发送完成后调用（串行调用，无需加锁），可为nil
+}
+
+// StreamResult 流式发送中单条消息的结果
+type StreamResult struct {
+	Index   int                 // 消息序号（从0开始，按迭代器返回顺序）
+	Request *SendMessageRequest // 发送请求
+	Data    *SendMessageData    // 发送成功时的响应数据
+	Err     error               // 发送失败的错误
+}
+
+// StreamSummary 流式发送汇总
+type StreamSummary struct {
+	Total     int // 已从迭代器取出的消息数
+	Succeeded int // 发送成功数
+	Failed    int // 发送失败数
+}
+
+// SendStream 从迭代器逐条取出消息并发送，并发数和速率受 opts 限制，每条结果通过 opts.OnResult 回调
+// next 返回 false 表示迭代结束；消息按需拉取，不会一次性加载到内存（适合数据库游标、channel 等数据源）
+// 单条消息发送失败不会中断流程；ctx 结束时停止拉取，等待已发出的请求完成后返回汇总和 ctx 错误
+func (c *Client) SendStream(ctx context.Context, next func() (*SendMessageRequest, bool), opts StreamOptions) (*StreamSummary, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultStreamConcurrency
+	}
+	var limiter *rateLimiter
+	if opts.PerSecond > 0 {
+		limiter = &rateLimiter{rate: opts.PerSecond, burst: 1, tokens: 1, last: time.Now()}
+	}
+
+	summary := &StreamSummary{}
+	var mu sync.Mutex
+	report := func(r StreamResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Err != nil {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+		if opts.OnResult != nil {
+			c.safeCall(ctx, "OnResult", func() { opts.OnResult(r) })
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for index := 0; ctx.Err() == nil; index++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		if limiter != nil {
+			if err := limiter.wait(ctx); err != nil {
+				<-sem
+				continue
+			}
+		}
+
+		req, ok := next()
+		if !ok {
+			<-sem
+			break
+		}
+		summary.Total++
+
+		wg.Add(1)
+		go func(index int, req *SendMessageRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			data, err := c.SendMessage(ctx, req)
+			report(StreamResult{Index: index, Request: req, Data: data, Err: err})
+		}(index, req)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return summary, fmt.Errorf("send stream: %w", err)
+	}
+	return summary, nil
+}
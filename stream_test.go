@@ -0,0 +1,98 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSendStream 测试流式发送的并发限制和结果回调
+func TestSendStream(t *testing.T) {
+	var inflight, maxInflight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInflight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInflight, m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		var req SendMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if req.Receiver == "bad" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeInvalidReceiver, "message": "invalid receiver"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": req.Receiver}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	receivers := []string{"a", "b", "bad", "c", "d", "e", "f", "g", "h", "i"}
+	i := 0
+	next := func() (*SendMessageRequest, bool) {
+		if i == len(receivers) {
+			return nil, false
+		}
+		req := &SendMessageRequest{ChannelID: 1, Receiver: receivers[i], SignatureName: "test"}
+		i++
+		return req, true
+	}
+
+	seen := make(map[int]bool)
+	summary, err := client.SendStream(context.Background(), next, StreamOptions{
+		Concurrency: 3,
+		OnResult: func(r StreamResult) {
+			seen[r.Index] = true
+			if (r.Err != nil) != (r.Request.Receiver == "bad") {
+				t.Errorf("result %d: err = %v", r.Index, r.Err)
+			}
+			if r.Err == nil && r.Data.TaskID != r.Request.Receiver {
+				t.Errorf("result %d: task id = %s", r.Index, r.Data.TaskID)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendStream() error = %v", err)
+	}
+	if summary.Total != 10 || summary.Succeeded != 9 || summary.Failed != 1 || len(seen) != 10 {
+		t.Errorf("summary = %+v, seen = %d", summary, len(seen))
+	}
+	if got := atomic.LoadInt32(&maxInflight); got > 3 {
+		t.Errorf("max concurrency = %d, want <= 3", got)
+	}
+}
+
+// TestSendStreamCanceled 测试 ctx 结束后停止拉取
+func TestSendStreamCanceled(t *testing.T) {
+	server := newSuccessServer(t)
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pulled := 0
+	next := func() (*SendMessageRequest, bool) {
+		pulled++
+		if pulled == 5 {
+			cancel()
+		}
+		return &SendMessageRequest{ChannelID: 1, Receiver: "13800138000", SignatureName: "test"}, true
+	}
+
+	summary, err := client.SendStream(ctx, next, StreamOptions{PerSecond: 1000})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("SendStream() error = %v, want context.Canceled", err)
+	}
+	if summary.Total != 5 || pulled != 5 {
+		t.Errorf("summary = %+v, pulled = %d", summary, pulled)
+	}
+}
@@ -0,0 +1,168 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListTasksOptions 任务列表查询条件，零值字段不参与过滤
+type ListTasksOptions struct {
+	Status    string    // 任务状态，见 TaskStatus* 常量
+	ChannelID int       // 通道ID
+	Receiver  string    // 接收者
+	Since     time.Time // 创建时间下限（含）
+	Until     time.Time // 创建时间上限（不含）
+
+	Page     int    // 页码（从1开始），与 Cursor 二选一
+	PageSize int    // 每页数量，0 使用网关默认值
+	Cursor   string // 游标，传入上一页返回的 NextCursor；大数据量翻页时比页码更稳定
+}
+
+// ListTasksData 任务列表分页数据
+type ListTasksData struct {
+	Items      []QueryTaskData `json:"items"`                 // 任务列表
+	Total      int             `json:"total"`                 // 符合条件的任务总数
+	Page       int             `json:"page"`                  // 当前页码（游标分页时为0）
+	PageSize   int             `json:"page_size"`             // 每页数量
+	NextCursor string          `json:"next_cursor,omitempty"` // 下一页游标，为空表示没有更多数据
+
+	RawData // 原始JSON，见 RawData
+}
+
+// query 编码为查询参数
+func (o *ListTasksOptions) query() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Status != "" {
+		query.Set("status", o.Status)
+	}
+	if o.ChannelID > 0 {
+		query.Set("channel_id", strconv.Itoa(o.ChannelID))
+	}
+	if o.Receiver != "" {
+		query.Set("receiver", o.Receiver)
+	}
+	if !o.Since.IsZero() {
+		query.Set("start_time", FormatDeadline(o.Since))
+	}
+	if !o.Until.IsZero() {
+		query.Set("end_time", FormatDeadline(o.Until))
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	} else if o.Page > 0 {
+		query.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	return query
+}
+
+// ListTasks 按条件分页查询任务，opts 为 nil 时返回第一页
+func (c *Client) ListTasks(ctx context.Context, opts *ListTasksOptions) (*ListTasksData, error) {
+	if err := c.requireFeature(ctx, FeatureTaskList); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/messages"
+	if query := opts.query(); len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ListTasksData
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// CancelTaskData 取消任务响应数据
+type CancelTaskData struct {
+	TaskID     string `json:"task_id"`     // 任务ID
+	Status     string `json:"status"`      // 任务状态（canceled）
+	CanceledAt string `json:"canceled_at"` // 取消时间
+
+	RawData // 原始JSON，见 RawData
+}
+
+// CancelTask 取消待发送的任务（如设置了 ScheduledAt 的定时消息）
+// 任务已开始发送或已结束时返回错误码为 ErrCodeTaskNotCancelable 的 *APIError
+func (c *Client) CancelTask(ctx context.Context, taskID string) (*CancelTaskData, error) {
+	if err := c.requireFeature(ctx, FeatureCancel); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/messages/" + taskID
+	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data CancelTaskData
+	if err := decodeData(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal response data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// ReplayTask 使用任务的原始参数重新发送一条新消息（新的任务ID和幂等键），适用于客服"重发一次"的场景
+// 定时发送时间和投递截止时间不会沿用（立即发送），附件不会重发；重发的消息同样经过本地校验和预发环境防护
+func (c *Client) ReplayTask(ctx context.Context, taskID string) (*SendMessageData, error) {
+	task, err := c.QueryTask(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("query task %s: %w", taskID, err)
+	}
+	if task.SignatureName == "" {
+		return nil, fmt.Errorf("replay task %s: original request parameters are not available", taskID)
+	}
+
+	return c.SendMessage(ctx, &SendMessageRequest{
+		ChannelID:      task.ChannelID,
+		SignatureName:  task.SignatureName,
+		Receiver:       task.Receiver,
+		CountryCode:    task.CountryCode,
+		Region:         task.Region,
+		TemplateParams: task.TemplateParams,
+		IdempotencyKey: "replay:" + taskID + ":" + c.newNonce(),
+		CallbackURL:    task.CallbackURL,
+	})
+}
+
+// LastAttemptTime 解析最近一次提交服务商的时间，未提交或格式错误时返回零值
+func (d *QueryTaskData) LastAttemptTime() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, d.LastAttemptAt)
+	return t
+}
+
+// NextRetryTime 解析下次重试时间，不再重试或格式错误时返回零值
+func (d *QueryTaskData) NextRetryTime() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, d.NextRetryAt)
+	return t
+}
+
+// WillRetry 判断任务是否已安排重试（如"失败，10:05 重试"），成功或已取消的任务返回false
+func (d *QueryTaskData) WillRetry() bool {
+	if d.Status == TaskStatusSuccess || d.Status == TaskStatusCanceled {
+		return false
+	}
+	return !d.NextRetryTime().IsZero()
+}
+
+// PermanentlyFailed 判断任务是否已失败且不会再重试
+func (d *QueryTaskData) PermanentlyFailed() bool {
+	return d.Status == TaskStatusFailed && !d.WillRetry()
+}
@@ -0,0 +1,171 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestListTasks 测试任务列表查询条件和分页
+func TestListTasks(t *testing.T) {
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.Verify(r); err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+		if r.URL.Path != "/api/v1/messages" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		query = make(map[string]string)
+		for k, v := range r.URL.Query() {
+			query[k] = v[0]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data": map[string]interface{}{
+				"items":       []map[string]interface{}{{"task_id": "t1", "status": "failed"}},
+				"total":       41,
+				"page_size":   20,
+				"next_cursor": "c2",
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	data, err := client.ListTasks(context.Background(), &ListTasksOptions{
+		Status:    TaskStatusFailed,
+		ChannelID: 3,
+		Since:     time.Date(2025, 11, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600)),
+		Cursor:    "c1",
+		Page:      5,
+		PageSize:  20,
+	})
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+
+	want := map[string]string{"status": "failed", "channel_id": "3", "start_time": "2025-11-01T00:00:00Z", "cursor": "c1", "page_size": "20"}
+	if len(query) != len(want) {
+		t.Errorf("query = %v, want %v", query, want)
+	}
+	for k, v := range want {
+		if query[k] != v {
+			t.Errorf("query[%s] = %q, want %q", k, query[k], v)
+		}
+	}
+	if len(data.Items) != 1 || data.Total != 41 || data.NextCursor != "c2" {
+		t.Errorf("unexpected data: %+v", data)
+	}
+
+	if _, err := client.ListTasks(context.Background(), nil); err != nil || len(query) != 0 {
+		t.Errorf("nil options: err = %v, query = %v", err, query)
+	}
+}
+
+// TestCancelTask 测试取消任务
+func TestCancelTask(t *testing.T) {
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.Verify(r); err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s, want DELETE", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/messages/sent" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": ErrCodeTaskNotCancelable, "message": "任务不可取消"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data":    map[string]interface{}{"task_id": "scheduled", "status": TaskStatusCanceled},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	data, err := client.CancelTask(context.Background(), "scheduled")
+	if err != nil || data.Status != TaskStatusCanceled {
+		t.Fatalf("CancelTask() = %+v, %v", data, err)
+	}
+
+	_, err = client.CancelTask(context.Background(), "sent")
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Code != ErrCodeTaskNotCancelable || apiErr.Retryable {
+		t.Errorf("expected not cancelable error, got %v", err)
+	}
+}
+
+// TestReplayTask 测试使用原始参数重发任务
+func TestReplayTask(t *testing.T) {
+	var replayed SendMessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data interface{}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/messages/old":
+			data = map[string]interface{}{
+				"task_id": "old", "channel_id": 1, "receiver": "13800138000", "status": "failed",
+				"signature_name": "公司", "template_params": map[string]interface{}{"code": "1234"},
+				"callback_url": "https://tenant.example.com/callback",
+			}
+		case r.Method == http.MethodGet:
+			data = map[string]interface{}{"task_id": "legacy", "channel_id": 1, "receiver": "13800138000"}
+		case r.Method == http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&replayed)
+			data = map[string]interface{}{"task_id": "new", "status": "pending"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": data})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	data, err := client.ReplayTask(context.Background(), "old")
+	if err != nil || data.TaskID != "new" {
+		t.Fatalf("ReplayTask() = %+v, %v", data, err)
+	}
+	if replayed.SignatureName != "公司" || replayed.Receiver != "13800138000" || replayed.TemplateParams["code"] != "1234" ||
+		!strings.HasPrefix(replayed.IdempotencyKey, "replay:old:") || replayed.CallbackURL != "https://tenant.example.com/callback" {
+		t.Errorf("unexpected replayed request: %+v", replayed)
+	}
+
+	if _, err := client.ReplayTask(context.Background(), "legacy"); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("expected missing parameters error, got %v", err)
+	}
+}
+
+// TestQueryTaskRetryTiming 测试重试时间解析
+func TestQueryTaskRetryTiming(t *testing.T) {
+	var data QueryTaskData
+	body := `{"task_id":"t1","status":"failed","retry_count":1,"max_retry":3,
+		"last_attempt_at":"2024-01-01T10:00:00+08:00","next_retry_at":"2024-01-01T10:05:00+08:00"}`
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got := data.NextRetryTime().Sub(data.LastAttemptTime()); got != 5*time.Minute {
+		t.Errorf("retry gap = %v", got)
+	}
+	if !data.WillRetry() || data.PermanentlyFailed() {
+		t.Error("expected task to be retrying")
+	}
+
+	data.NextRetryAt = ""
+	if data.WillRetry() || !data.PermanentlyFailed() || !data.NextRetryTime().IsZero() {
+		t.Error("expected task to be permanently failed")
+	}
+
+	data = QueryTaskData{Status: TaskStatusSuccess, NextRetryAt: "2024-01-01T10:05:00+08:00"}
+	if data.WillRetry() || data.PermanentlyFailed() {
+		t.Error("expected successful task not to retry")
+	}
+}
@@ -0,0 +1,230 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// 模板审核状态
+const (
+	TemplateStatusPending  = "pending"  // 审核中
+	TemplateStatusApproved = "approved" // 已通过
+	TemplateStatusRejected = "rejected" // 未通过
+)
+
+// Template 消息模板
+type Template struct {
+	ID           int      `json:"id"`                      // 模板ID
+	ChannelID    int      `json:"channel_id"`              // 通道ID
+	Name         string   `json:"name"`                    // 模板名称
+	MessageType  string   `json:"message_type"`            // 消息类型，见 MessageType* 常量
+	Content      string   `json:"content"`                 // 模板内容，占位符格式为 ${name}
+	Params       []string `json:"params"`                  // 模板参数名
+	Status       string   `json:"status"`                  // 审核状态，见 TemplateStatus* 常量
+	RejectReason string   `json:"reject_reason,omitempty"` // 审核未通过的原因
+	CreatedAt    string   `json:"created_at"`              // 创建时间
+	UpdatedAt    string   `json:"updated_at"`              // 更新时间
+
+	RawData // 原始JSON，见 RawData
+}
+
+// Render 使用模板参数渲染模板内容，缺失的参数保留原占位符
+func (t *Template) Render(params map[string]interface{}) string {
+	return RenderTemplate(t.Content, params)
+}
+
+// MissingParams 返回 params 中缺少的模板参数名
+func (t *Template) MissingParams(params map[string]interface{}) []string {
+	var missing []string
+	for _, name := range t.Params {
+		if _, ok := params[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// ListTemplatesOptions 模板列表查询条件，零值字段不参与过滤
+type ListTemplatesOptions struct {
+	ChannelID int    // 通道ID
+	Status    string // 审核状态
+	Page      int    // 页码（从1开始）
+	PageSize  int    // 每页数量，0 使用网关默认值
+}
+
+// ListTemplatesData 模板列表分页数据
+type ListTemplatesData struct {
+	Items    []Template `json:"items"`     // 模板列表
+	Total    int        `json:"total"`     // 模板总数
+	Page     int        `json:"page"`      // 当前页码
+	PageSize int        `json:"page_size"` // 每页数量
+
+	RawData // 原始JSON，见 RawData
+}
+
+// CreateTemplateRequest 创建模板请求
+type CreateTemplateRequest struct {
+	ChannelID   int    `json:"channel_id"`            // 通道ID（必填）
+	Name        string `json:"name"`                  // 模板名称（必填）
+	Content     string `json:"content"`               // 模板内容（必填），占位符格式为 ${name}
+	Description string `json:"description,omitempty"` // 模板说明，供服务商审核参考（可选）
+}
+
+// UpdateTemplateRequest 更新模板请求，空字段保持不变；修改内容后模板需要重新审核
+type UpdateTemplateRequest struct {
+	Name        string `json:"name,omitempty"`        // 模板名称
+	Content     string `json:"content,omitempty"`     // 模板内容
+	Description string `json:"description,omitempty"` // 模板说明
+}
+
+// ListTemplates 分页查询模板，opts 为 nil 时返回第一页
+func (c *Client) ListTemplates(ctx context.Context, opts *ListTemplatesOptions) (*ListTemplatesData, error) {
+	if err := c.requireFeature(ctx, FeatureTemplates); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/templates"
+	if opts != nil {
+		query := url.Values{}
+		if opts.ChannelID > 0 {
+			query.Set("channel_id", strconv.Itoa(opts.ChannelID))
+		}
+		if opts.Status != "" {
+			query.Set("status", opts.Status)
+		}
+		if opts.Page > 0 {
+			query.Set("page", strconv.Itoa(opts.Page))
+		}
+		if opts.PageSize > 0 {
+			query.Set("page_size", strconv.Itoa(opts.PageSize))
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	}
+
+	var data ListTemplatesData
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetTemplate 查询模板详情
+func (c *Client) GetTemplate(ctx context.Context, templateID int) (*Template, error) {
+	if err := c.requireFeature(ctx, FeatureTemplates); err != nil {
+		return nil, err
+	}
+
+	var data Template
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/templates/"+strconv.Itoa(templateID), nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// CreateTemplate 创建模板，新模板需要服务商审核通过（Status 为 approved）后才能使用
+func (c *Client) CreateTemplate(ctx context.Context, req *CreateTemplateRequest) (*Template, error) {
+	if err := c.requireFeature(ctx, FeatureTemplates); err != nil {
+		return nil, err
+	}
+
+	var data Template
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/templates", req, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// UpdateTemplate 更新模板
+func (c *Client) UpdateTemplate(ctx context.Context, templateID int, req *UpdateTemplateRequest) (*Template, error) {
+	if err := c.requireFeature(ctx, FeatureTemplates); err != nil {
+		return nil, err
+	}
+
+	var data Template
+	if err := c.doJSON(ctx, http.MethodPut, "/api/v1/templates/"+strconv.Itoa(templateID), req, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// TimeRange 统计时间范围，零值表示不限制
+type TimeRange struct {
+	Since time.Time // 开始时间（含）
+	Until time.Time // 结束时间（不含）
+}
+
+// query 编码为查询参数
+func (r TimeRange) query() url.Values {
+	query := url.Values{}
+	if !r.Since.IsZero() {
+		query.Set("start_time", FormatDeadline(r.Since))
+	}
+	if !r.Until.IsZero() {
+		query.Set("end_time", FormatDeadline(r.Until))
+	}
+	return query
+}
+
+// TemplateStats 模板使用统计
+type TemplateStats struct {
+	TemplateID     int                `json:"template_id"`     // 模板ID
+	StartTime      string             `json:"start_time"`      // 统计开始时间
+	EndTime        string             `json:"end_time"`        // 统计结束时间
+	SentCount      int                `json:"sent_count"`      // 发送数量
+	DeliveredCount int                `json:"delivered_count"` // 回调确认送达数量
+	FailedCount    int                `json:"failed_count"`    // 失败数量（发送失败或回调拒绝）
+	Failures       []FailureBreakdown `json:"failures"`        // 失败原因分布，按数量降序
+
+	RawData // 原始JSON，见 RawData
+}
+
+// FailureBreakdown 失败原因统计
+type FailureBreakdown struct {
+	ProviderCode string `json:"provider_code"` // 服务商错误码
+	Reason       string `json:"reason"`        // 失败原因
+	Count        int    `json:"count"`         // 数量
+}
+
+// DeliveryRate 送达率（送达数/发送数），未发送时为0
+func (s *TemplateStats) DeliveryRate() float64 {
+	if s.SentCount == 0 {
+		return 0
+	}
+	return float64(s.DeliveredCount) / float64(s.SentCount)
+}
+
+// GetTemplateStats 查询模板在时间范围内的发送量、送达率和失败原因分布，用于发现送达率差的模板
+func (c *Client) GetTemplateStats(ctx context.Context, templateID int, r TimeRange) (*TemplateStats, error) {
+	if err := c.requireFeature(ctx, FeatureStatistics); err != nil {
+		return nil, err
+	}
+
+	path := "/api/v1/templates/" + strconv.Itoa(templateID) + "/stats"
+	if query := r.query(); len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var data TemplateStats
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// doJSON 执行请求并将响应数据解析到 out
+func (c *Client) doJSON(ctx context.Context, method, path string, reqData, out interface{}) error {
+	resp, err := c.doRequest(ctx, method, path, reqData)
+	if err != nil {
+		return err
+	}
+	if err := decodeData(resp.Data, out); err != nil {
+		return fmt.Errorf("unmarshal response data: %w", err)
+	}
+	return nil
+}
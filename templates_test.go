@@ -0,0 +1,95 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestTemplateManagement 测试模板增改查
+func TestTemplateManagement(t *testing.T) {
+	verifier := NewVerifier(StaticSecret("test_secret"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, err := verifier.Verify(r)
+		if err != nil {
+			t.Errorf("Verify() error = %v", err)
+			return
+		}
+		tpl := map[string]interface{}{"id": 5, "channel_id": 1, "name": "otp", "content": "验证码${code}，${minutes}分钟内有效", "params": []string{"code", "minutes"}, "status": "approved"}
+		var data interface{} = tpl
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/templates":
+			if r.URL.Query().Get("channel_id") != "1" {
+				t.Errorf("query = %s", r.URL.RawQuery)
+			}
+			data = map[string]interface{}{"items": []interface{}{tpl}, "total": 1}
+		case r.Method == http.MethodPost:
+			tpl["status"] = TemplateStatusPending
+			tpl["name"] = verified.Params["name"]
+		case r.Method == http.MethodPut:
+			if r.URL.Path != "/api/v1/templates/5" || r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("unexpected update request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+			}
+			tpl["content"] = verified.Params["content"]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": data})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	ctx := context.Background()
+
+	list, err := client.ListTemplates(ctx, &ListTemplatesOptions{ChannelID: 1})
+	if err != nil || len(list.Items) != 1 {
+		t.Fatalf("ListTemplates() = %+v, %v", list, err)
+	}
+	tpl := list.Items[0]
+	if got := tpl.Render(map[string]interface{}{"code": "1234", "minutes": 5}); got != "验证码1234，5分钟内有效" {
+		t.Errorf("Render() = %q", got)
+	}
+	if missing := tpl.MissingParams(map[string]interface{}{"code": "1"}); len(missing) != 1 || missing[0] != "minutes" {
+		t.Errorf("MissingParams() = %v", missing)
+	}
+
+	created, err := client.CreateTemplate(ctx, &CreateTemplateRequest{ChannelID: 1, Name: "welcome", Content: "欢迎"})
+	if err != nil || created.Name != "welcome" || created.Status != TemplateStatusPending {
+		t.Errorf("CreateTemplate() = %+v, %v", created, err)
+	}
+
+	updated, err := client.UpdateTemplate(ctx, 5, &UpdateTemplateRequest{Content: "新内容"})
+	if err != nil || updated.Content != "新内容" {
+		t.Errorf("UpdateTemplate() = %+v, %v", updated, err)
+	}
+
+	if got, err := client.GetTemplate(ctx, 5); err != nil || got.ID != 5 {
+		t.Errorf("GetTemplate() = %+v, %v", got, err)
+	}
+}
+
+// TestGetTemplateStats 测试模板使用统计
+func TestGetTemplateStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/templates/5/stats" || r.URL.Query().Get("start_time") != "2025-11-01T00:00:00Z" || r.URL.Query().Has("end_time") {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{
+			"template_id": 5, "sent_count": 200, "delivered_count": 150, "failed_count": 50,
+			"failures": []map[string]interface{}{{"provider_code": "MK:0001", "reason": "空号", "count": 40}},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	stats, err := client.GetTemplateStats(context.Background(), 5, TimeRange{Since: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("GetTemplateStats() error = %v", err)
+	}
+	if stats.DeliveryRate() != 0.75 || len(stats.Failures) != 1 || stats.Failures[0].Count != 40 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
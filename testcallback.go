@@ -0,0 +1,35 @@
+package mlievpush
+
+import (
+	"context"
+	"net/http"
+)
+
+// TestCallbackData 测试回调的投递结果
+type TestCallbackData struct {
+	EventID      string `json:"event_id"`                // 测试事件ID，回调接口收到的事件 event_id 与之相同
+	TaskID       string `json:"task_id"`                 // 合成的任务ID，不对应真实任务
+	CallbackURL  string `json:"callback_url"`            // 投递的回调地址（应用注册的地址）
+	Delivered    bool   `json:"delivered"`               // 回调接口是否返回 2xx
+	StatusCode   int    `json:"status_code"`             // 回调接口返回的HTTP状态码，未建立连接时为0
+	ErrorMessage string `json:"error_message,omitempty"` // 投递失败原因（连接失败、超时、非 2xx 等）
+	DurationMs   int64  `json:"duration_ms"`             // 投递耗时（毫秒）
+	SentAt       string `json:"sent_at"`                 // 投递时间
+
+	RawData // 原始JSON，见 RawData
+}
+
+// TestCallback 请求网关向应用注册的回调地址同步投递一条已签名的测试事件（type 为 "test"），返回投递结果
+// 适合在每次发布后做端到端校验：回调地址、网络连通性、签名密钥是否配置正确
+// 回调处理器侧可以用 callback/callbacktest 包确认收到了该事件
+func (c *Client) TestCallback(ctx context.Context) (*TestCallbackData, error) {
+	if err := c.requireFeature(ctx, FeatureCallbackTest); err != nil {
+		return nil, err
+	}
+
+	var data TestCallbackData
+	if err := c.doJSON(ctx, http.MethodPost, "/api/v1/callbacks/test", nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
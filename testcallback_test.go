@@ -0,0 +1,40 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestTestCallback 测试触发测试回调
+func TestTestCallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/callbacks/test" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data": map[string]interface{}{
+				"event_id":      "evt-test",
+				"callback_url":  "https://example.com/callback",
+				"delivered":     false,
+				"status_code":   401,
+				"error_message": "invalid signature",
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	data, err := client.TestCallback(context.Background())
+	if err != nil {
+		t.Fatalf("TestCallback() error = %v", err)
+	}
+	if data.EventID != "evt-test" || data.Delivered || data.StatusCode != 401 || data.ErrorMessage != "invalid signature" {
+		t.Errorf("unexpected data: %+v", data)
+	}
+}
@@ -0,0 +1,83 @@
+package mlievpush
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// 任务生命周期事件类型
+const (
+	TimelineEventCreated   = "created"   // 网关接收请求、创建任务
+	TimelineEventQueued    = "queued"    // 进入发送队列（定时任务到期后）
+	TimelineEventAttempted = "attempted" // 提交服务商（每次重试各一条）
+	TimelineEventCallback  = "callback"  // 收到服务商回执/回调
+	TimelineEventCompleted = "completed" // 任务进入终态
+)
+
+// TimelineEvent 任务生命周期事件
+type TimelineEvent struct {
+	Type      string `json:"type"`                 // 事件类型，见 TimelineEvent* 常量
+	Time      string `json:"time"`                 // 发生时间（RFC 3339）
+	Status    string `json:"status,omitempty"`     // 事件发生后的任务状态
+	ChannelID int    `json:"channel_id,omitempty"` // 通道ID（attempted）
+	Provider  string `json:"provider,omitempty"`   // 服务商（attempted、callback）
+	Attempt   int    `json:"attempt,omitempty"`    // 第几次提交服务商（attempted）
+	Detail    string `json:"detail,omitempty"`     // 说明（失败原因、服务商状态码等）
+}
+
+// At 解析事件时间，格式错误时返回零值
+func (e *TimelineEvent) At() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, e.Time)
+	return t
+}
+
+// TaskTimeline 任务时间线，事件按时间升序排列
+type TaskTimeline struct {
+	TaskID string          `json:"task_id"` // 任务ID
+	Events []TimelineEvent `json:"events"`  // 生命周期事件
+
+	RawData // 原始JSON，见 RawData
+}
+
+// TimelineGap 相邻两个事件之间的耗时
+type TimelineGap struct {
+	From     TimelineEvent // 前一个事件
+	To       TimelineEvent // 后一个事件
+	Duration time.Duration // 耗时
+}
+
+// Gaps 返回相邻事件之间的耗时，用于定位慢投递的时间花在了哪个环节
+func (t *TaskTimeline) Gaps() []TimelineGap {
+	var gaps []TimelineGap
+	for i := 1; i < len(t.Events); i++ {
+		from, to := t.Events[i-1], t.Events[i]
+		gaps = append(gaps, TimelineGap{From: from, To: to, Duration: to.At().Sub(from.At())})
+	}
+	return gaps
+}
+
+// Duration 返回第一个事件到最后一个事件的总耗时
+func (t *TaskTimeline) Duration() time.Duration {
+	if len(t.Events) < 2 {
+		return 0
+	}
+	return t.Events[len(t.Events)-1].At().Sub(t.Events[0].At())
+}
+
+// GetTaskTimeline 查询任务的生命周期事件（创建、入队、提交服务商、收到回执等）及时间
+func (c *Client) GetTaskTimeline(ctx context.Context, taskID string) (*TaskTimeline, error) {
+	if err := c.requireFeature(ctx, FeatureTaskTimeline); err != nil {
+		return nil, err
+	}
+
+	var data TaskTimeline
+	if err := c.doJSON(ctx, http.MethodGet, "/api/v1/messages/"+taskID+"/timeline", nil, &data); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(data.Events, func(i, j int) bool {
+		return data.Events[i].At().Before(data.Events[j].At())
+	})
+	return &data, nil
+}
@@ -0,0 +1,45 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGetTaskTimeline 测试任务时间线
+func TestGetTaskTimeline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/messages/t1/timeline" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{
+			"task_id": "t1",
+			"events": []interface{}{
+				map[string]interface{}{"type": "callback", "time": "2025-11-01T10:00:42Z", "provider": "aliyun", "detail": "DELIVRD"},
+				map[string]interface{}{"type": "created", "time": "2025-11-01T10:00:00Z"},
+				map[string]interface{}{"type": "attempted", "time": "2025-11-01T10:00:02Z", "channel_id": 1, "provider": "aliyun", "attempt": 1},
+			},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	timeline, err := client.GetTaskTimeline(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("GetTaskTimeline() error = %v", err)
+	}
+	if len(timeline.Events) != 3 || timeline.Events[0].Type != TimelineEventCreated || timeline.Events[2].Type != TimelineEventCallback {
+		t.Fatalf("events not sorted: %+v", timeline.Events)
+	}
+	gaps := timeline.Gaps()
+	if len(gaps) != 2 || gaps[0].Duration != 2*time.Second || gaps[1].Duration != 40*time.Second {
+		t.Errorf("Gaps() = %+v", gaps)
+	}
+	if timeline.Duration() != 42*time.Second {
+		t.Errorf("Duration() = %v", timeline.Duration())
+	}
+}
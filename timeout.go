@@ -0,0 +1,147 @@
+package mlievpush
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+)
+
+// 请求阶段，用于定位超时或取消时时间花在了哪里
+const (
+	PhaseRateLimit        = "rate_limit"        // 等待客户端限流令牌
+	PhaseDNS              = "dns"               // 域名解析
+	PhaseConnect          = "connect"           // 建立TCP连接
+	PhaseTLSHandshake     = "tls_handshake"     // TLS握手
+	PhaseWriteRequest     = "write_request"     // 发送请求
+	PhaseAwaitingResponse = "awaiting_response" // 请求已发出，等待网关响应
+	PhaseReadBody         = "read_body"         // 读取响应体
+	PhaseBackoff          = "backoff"           // 重试前的退避等待
+)
+
+// TimeoutError 请求超时（ctx 截止时间到达或 HTTP 客户端超时）
+// errors.Is(err, context.DeadlineExceeded) 为true，Unwrap 返回原始错误
+type TimeoutError struct {
+	Method   string        // 请求方法
+	Path     string        // 请求路径
+	Elapsed  time.Duration // 从首次尝试开始的总耗时（含重试）
+	Attempts int           // 已尝试次数
+	Phase    string        // 超时发生的阶段，见 Phase* 常量，未知时为空
+	Err      error         // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s %s timed out after %s (attempts %d, phase %s): %v",
+		e.Method, e.Path, e.Elapsed.Round(time.Millisecond), e.Attempts, phaseOrUnknown(e.Phase), e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is 支持 errors.Is(err, context.DeadlineExceeded)，包括 HTTP 客户端超时
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// CanceledError 请求被取消（ctx 被取消）
+// errors.Is(err, context.Canceled) 为true，Unwrap 返回原始错误
+type CanceledError struct {
+	Method   string        // 请求方法
+	Path     string        // 请求路径
+	Elapsed  time.Duration // 从首次尝试开始的总耗时（含重试）
+	Attempts int           // 已尝试次数
+	Phase    string        // 取消发生的阶段，见 Phase* 常量，未知时为空
+	Err      error         // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("%s %s canceled after %s (attempts %d, phase %s): %v",
+		e.Method, e.Path, e.Elapsed.Round(time.Millisecond), e.Attempts, phaseOrUnknown(e.Phase), e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// Is 支持 errors.Is(err, context.Canceled)
+func (e *CanceledError) Is(target error) bool {
+	return target == context.Canceled
+}
+
+// phaseOrUnknown 阶段为空时显示 unknown
+func phaseOrUnknown(phase string) string {
+	if phase == "" {
+		return "unknown"
+	}
+	return phase
+}
+
+// phaseTracker 记录请求当前所处的阶段
+type phaseTracker struct {
+	phase atomic.Value // string
+}
+
+// set 更新当前阶段
+func (p *phaseTracker) set(phase string) {
+	p.phase.Store(phase)
+}
+
+// current 返回当前阶段
+func (p *phaseTracker) current() string {
+	phase, _ := p.phase.Load().(string)
+	return phase
+}
+
+// trace 返回更新阶段的 httptrace 钩子，与 ctx 中已有的钩子叠加
+func (p *phaseTracker) trace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { p.set(PhaseDNS) },
+		ConnectStart:         func(string, string) { p.set(PhaseConnect) },
+		TLSHandshakeStart:    func() { p.set(PhaseTLSHandshake) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { p.set(PhaseWriteRequest) },
+		GotConn:              func(httptrace.GotConnInfo) { p.set(PhaseWriteRequest) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { p.set(PhaseAwaitingResponse) },
+		GotFirstResponseByte: func() { p.set(PhaseReadBody) },
+	})
+}
+
+// phaseTrackerKey context 中 phaseTracker 的键
+type phaseTrackerKey struct{}
+
+// setPhase 更新 ctx 中记录的请求阶段（未记录时忽略）
+func setPhase(ctx context.Context, phase string) {
+	if p, ok := ctx.Value(phaseTrackerKey{}).(*phaseTracker); ok {
+		p.set(phase)
+	}
+}
+
+// contextError 将超时、取消包装为 TimeoutError、CanceledError，其他错误原样返回
+func contextError(ctx context.Context, err error, method, path string, elapsed time.Duration, attempts int, phase string) error {
+	if err == nil {
+		return nil
+	}
+	var timeoutErr *TimeoutError
+	var canceledErr *CanceledError
+	if errors.As(err, &timeoutErr) || errors.As(err, &canceledErr) {
+		return err
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(ctx.Err(), context.Canceled):
+		return &CanceledError{Method: method, Path: path, Elapsed: elapsed, Attempts: attempts, Phase: phase, Err: err}
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(ctx.Err(), context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return &TimeoutError{Method: method, Path: path, Elapsed: elapsed, Attempts: attempts, Phase: phase, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newSlowServer 创建延迟响应的服务端
+func newSlowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"message":"success","data":{"task_id":"t1"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestTimeoutError 测试超时错误记录阶段和耗时
+func TestTimeoutError(t *testing.T) {
+	server := newSlowServer(t, time.Second)
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	_, err := client.QueryTask(ctx, "t1")
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+	if timeoutErr.Phase != PhaseAwaitingResponse || timeoutErr.Attempts != 1 || timeoutErr.Elapsed < 30*time.Millisecond {
+		t.Errorf("unexpected error: %+v", timeoutErr)
+	}
+	if timeoutErr.Path != "/api/v1/messages/t1" {
+		t.Errorf("path = %s", timeoutErr.Path)
+	}
+}
+
+// TestTimeoutErrorHTTPClient 测试 HTTP 客户端超时同样返回 TimeoutError
+func TestTimeoutErrorHTTPClient(t *testing.T) {
+	server := newSlowServer(t, time.Second)
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithTimeout(20*time.Millisecond))
+
+	_, err := client.QueryTask(context.Background(), "t1")
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+}
+
+// TestCanceledErrorBackoff 测试重试退避期间取消
+func TestCanceledErrorBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithRetry(3, Backoff{Initial: time.Second}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err := client.QueryTask(ctx, "t1")
+
+	var canceledErr *CanceledError
+	if !errors.As(err, &canceledErr) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected CanceledError, got %v", err)
+	}
+	if canceledErr.Phase != PhaseBackoff || canceledErr.Attempts != 1 {
+		t.Errorf("unexpected error: %+v", canceledErr)
+	}
+	// 仍可以取到最后一次尝试的原始错误
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected HTTPError 503 to be wrapped, got %v", err)
+	}
+}
+
+// TestTimeoutErrorRateLimit 测试等待限流令牌时超时
+func TestTimeoutErrorRateLimit(t *testing.T) {
+	server := newSlowServer(t, 0)
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithRateLimit(0.5, 1))
+	if _, err := client.QueryTask(context.Background(), "t1"); err != nil {
+		t.Fatalf("QueryTask() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.QueryTask(ctx, "t1")
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Phase != PhaseRateLimit {
+		t.Errorf("expected rate limit timeout, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package mlievpush
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ConnPoolConfig 连接池配置，零值字段保留传输层原有设置
+type ConnPoolConfig struct {
+	MaxIdleConns        int           // 全部主机的最大空闲连接数
+	MaxIdleConnsPerHost int           // 每个主机的最大空闲连接数（http.Transport 默认为2，高并发推送时需调大）
+	MaxConnsPerHost     int           // 每个主机的最大连接数（含使用中的连接）
+	IdleConnTimeout     time.Duration // 空闲连接保留时间
+}
+
+// WithProxy 设置HTTP代理（如公司出口代理），proxy 为nil时直连，不读取 HTTP_PROXY 等环境变量
+// 默认传输层使用环境变量中的代理设置；通过 WithTransport 设置了非 *http.Transport 的传输层时不生效
+func WithProxy(proxy *url.URL) ClientOption {
+	return func(c *Client) {
+		c.updateTransport(func(t *http.Transport) {
+			if proxy == nil {
+				t.Proxy = nil
+				return
+			}
+			t.Proxy = http.ProxyURL(proxy)
+		})
+	}
+}
+
+// WithTLSConfig 设置TLS配置，如信任私有CA签发的网关证书、双向TLS客户端证书
+// 通过 WithTransport 设置了非 *http.Transport 的传输层时不生效
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.updateTransport(func(t *http.Transport) {
+			t.TLSClientConfig = config
+		})
+	}
+}
+
+// WithConnPool 设置连接池参数，适用于每秒推送大量消息的服务
+// 通过 WithTransport 设置了非 *http.Transport 的传输层时不生效
+func WithConnPool(config ConnPoolConfig) ClientOption {
+	return func(c *Client) {
+		c.updateTransport(func(t *http.Transport) {
+			if config.MaxIdleConns > 0 {
+				t.MaxIdleConns = config.MaxIdleConns
+			}
+			if config.MaxIdleConnsPerHost > 0 {
+				t.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+			}
+			if config.MaxConnsPerHost > 0 {
+				t.MaxConnsPerHost = config.MaxConnsPerHost
+			}
+			if config.IdleConnTimeout > 0 {
+				t.IdleConnTimeout = config.IdleConnTimeout
+			}
+		})
+	}
+}
+
+// WithForceHTTP2 设置是否尝试使用HTTP/2（仅HTTPS网关），多个请求复用同一连接
+// 默认传输层已开启；WithTransport 传入的传输层设置了 TLSClientConfig 等字段时标准库不再尝试HTTP/2，需通过此选项开启
+// 通过 WithTransport 设置了非 *http.Transport 的传输层时不生效
+func WithForceHTTP2(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.updateTransport(func(t *http.Transport) {
+			t.ForceAttemptHTTP2 = enabled
+		})
+	}
+}
+
+// updateTransport 复制当前传输层（未设置时复制 http.DefaultTransport）并修改，避免影响共享的传输层
+func (c *Client) updateTransport(update func(*http.Transport)) {
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = newDefaultTransport()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return
+	}
+	update(transport)
+	c.httpClient.Transport = transport
+}
+
+// newDefaultTransport 复制 http.DefaultTransport；应用将其替换为其他实现（如 otel、httpcache 包装）时，
+// 返回与标准库默认配置相同的新传输层
+func newDefaultTransport() *http.Transport {
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		return t.Clone()
+	}
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
@@ -0,0 +1,126 @@
+package mlievpush
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestWithProxy 测试通过代理发送请求
+func TestWithProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := NewClient("http://push.internal", "test_app_id", "test_secret", WithProxy(proxyURL))
+	if _, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if proxiedHost != "push.internal" {
+		t.Errorf("expected request via proxy for push.internal, got %q", proxiedHost)
+	}
+}
+
+// TestWithTLSConfig 测试信任私有CA
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	defer server.Close()
+
+	req := &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}
+	if _, err := NewClient(server.URL, "test_app_id", "test_secret").SendMessage(context.Background(), req); err == nil {
+		t.Fatal("expected certificate error without the private CA")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithTLSConfig(&tls.Config{RootCAs: pool}),
+		WithProxy(nil),
+	)
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	// 两个选项修改的是同一个传输层副本
+	transport := client.httpClient.Transport.(*http.Transport)
+	if transport.TLSClientConfig == nil || transport.Proxy != nil || transport == http.DefaultTransport {
+		t.Errorf("unexpected transport: %+v", transport)
+	}
+}
+
+// TestTransportOptionsDoNotMutateShared 测试传输层选项不修改调用方传入的 http.Client 和被替换的 DefaultTransport
+func TestTransportOptionsDoNotMutateShared(t *testing.T) {
+	shared := &http.Client{}
+	NewClient("http://push.internal", "test_app_id", "test_secret",
+		WithHTTPClient(shared), WithTimeout(time.Second), WithProxy(nil))
+	if shared.Transport != nil || shared.Timeout != 0 {
+		t.Errorf("shared client was modified: %+v", shared)
+	}
+
+	// 应用将 DefaultTransport 替换为包装实现时不会panic
+	orig := http.DefaultTransport
+	http.DefaultTransport = &recordingTransport{}
+	defer func() { http.DefaultTransport = orig }()
+	client := NewClient("http://push.internal", "test_app_id", "test_secret", WithConnPool(ConnPoolConfig{MaxIdleConnsPerHost: 10}))
+	if transport, ok := client.httpClient.Transport.(*http.Transport); !ok || transport.MaxIdleConnsPerHost != 10 || transport.Proxy == nil {
+		t.Errorf("unexpected transport: %+v", client.httpClient.Transport)
+	}
+	if r := NewClientRegistry(nil); r.Transport() == nil {
+		t.Error("registry transport should fall back to a default transport")
+	}
+}
+
+// TestWithConnPool 测试连接池参数
+func TestWithConnPool(t *testing.T) {
+	client := NewClient("http://push.internal", "test_app_id", "test_secret",
+		WithConnPool(ConnPoolConfig{MaxIdleConnsPerHost: 100, MaxConnsPerHost: 200, IdleConnTimeout: time.Minute}),
+	)
+	transport := client.httpClient.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != 100 || transport.MaxConnsPerHost != 200 || transport.IdleConnTimeout != time.Minute {
+		t.Errorf("unexpected transport: %+v", transport)
+	}
+	// 未设置的字段保留默认值
+	if transport.MaxIdleConns != http.DefaultTransport.(*http.Transport).MaxIdleConns {
+		t.Errorf("MaxIdleConns = %d", transport.MaxIdleConns)
+	}
+}
+
+// TestWithForceHTTP2 测试通过HTTP/2发送请求
+func TestWithForceHTTP2(t *testing.T) {
+	var proto int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto = r.ProtoMajor
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	client := NewClient(server.URL, "test_app_id", "test_secret",
+		WithTransport(&http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}),
+		WithForceHTTP2(true),
+	)
+	if _, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if proto != 2 {
+		t.Errorf("expected HTTP/2, got HTTP/%d", proto)
+	}
+}
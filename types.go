@@ -4,20 +4,70 @@ import "encoding/json"
 
 // SendMessageRequest 发送单条消息请求
 type SendMessageRequest struct {
-	ChannelID      int               `json:"channel_id"`                // 通道ID（必填）
-	SignatureName  string            `json:"signature_name"`            // 签名名称（必填）
-	Receiver       string            `json:"receiver"`                  // 接收者（必填）
-	TemplateParams map[string]string `json:"template_params,omitempty"` // 模板参数（可选）
-	ScheduledAt    string            `json:"scheduled_at,omitempty"`    // 定时发送时间（ISO 8601格式，可选）
+	ChannelID      int                    `json:"channel_id"`                // 通道ID（必填）
+	SignatureName  string                 `json:"signature_name"`            // 签名名称（必填）
+	Receiver       string                 `json:"receiver"`                  // 接收者（必填）
+	CountryCode    string                 `json:"country_code,omitempty"`    // 国际电话区号（如 "86"，短信/语音可选，为空时根据 E.164 前缀自动识别）
+	Region         string                 `json:"region,omitempty"`          // 地区代码（ISO 3166-1 alpha-2，如 "CN"，可选）
+	TemplateParams map[string]interface{} `json:"template_params,omitempty"` // 模板参数（可选）
+	ScheduledAt    string                 `json:"scheduled_at,omitempty"`    // 定时发送时间（ISO 8601格式，须带时区，可选），可用 Schedule 设置
+	DeadlineAt     string                 `json:"deadline_at,omitempty"`     // 投递截止时间，超过后网关将丢弃消息（ISO 8601格式，可选）
+	Attachments    []Attachment           `json:"attachments,omitempty"`     // 附件列表（可选，邮件等通道）
+	IdempotencyKey string                 `json:"idempotency_key,omitempty"` // 幂等键（可选），网关对相同键的请求只处理一次
+	CallbackURL    string                 `json:"callback_url,omitempty"`    // 投递回调地址（可选），覆盖应用注册的回调地址，如租户自己的接收端
 }
 
 // SendBatchRequest 批量发送消息请求
 type SendBatchRequest struct {
-	ChannelID      int               `json:"channel_id"`                // 通道ID（必填）
-	SignatureName  string            `json:"signature_name"`            // 签名名称（必填）
-	Receivers      []string          `json:"receivers"`                 // 接收者列表（必填）
-	TemplateParams map[string]string `json:"template_params,omitempty"` // 模板参数（可选）
-	ScheduledAt    string            `json:"scheduled_at,omitempty"`    // 定时发送时间（ISO 8601格式，可选）
+	ChannelID      int                    `json:"channel_id"`                // 通道ID（必填）
+	SignatureName  string                 `json:"signature_name"`            // 签名名称（必填）
+	Receivers      []string               `json:"receivers"`                 // 接收者列表（必填）
+	CountryCode    string                 `json:"country_code,omitempty"`    // 国际电话区号（如 "86"，短信/语音可选，为空时根据 E.164 前缀自动识别）
+	Region         string                 `json:"region,omitempty"`          // 地区代码（ISO 3166-1 alpha-2，如 "CN"，可选）
+	TemplateParams map[string]interface{} `json:"template_params,omitempty"` // 模板参数（可选）
+	ScheduledAt    string                 `json:"scheduled_at,omitempty"`    // 定时发送时间（ISO 8601格式，须带时区，可选），可用 Schedule 设置
+	DeadlineAt     string                 `json:"deadline_at,omitempty"`     // 投递截止时间，超过后网关将丢弃消息（ISO 8601格式，可选）
+	Attachments    []Attachment           `json:"attachments,omitempty"`     // 附件列表（可选，邮件等通道）
+	IdempotencyKey string                 `json:"idempotency_key,omitempty"` // 幂等键（可选），网关对相同键的请求只处理一次
+	CallbackURL    string                 `json:"callback_url,omitempty"`    // 投递回调地址（可选），覆盖应用注册的回调地址，如租户自己的接收端
+}
+
+// channelRequest 指定了通道的请求
+type channelRequest interface {
+	channelID() int
+}
+
+// channelID 实现 channelRequest 接口
+func (r *SendMessageRequest) channelID() int { return r.ChannelID }
+
+// channelID 实现 channelRequest 接口
+func (r *SendBatchRequest) channelID() int { return r.ChannelID }
+
+// receiverRequest 指定了接收者的请求
+type receiverRequest interface {
+	receivers() []string
+}
+
+// receivers 实现 receiverRequest 接口
+func (r *SendMessageRequest) receivers() []string { return []string{r.Receiver} }
+
+// receivers 实现 receiverRequest 接口
+func (r *SendBatchRequest) receivers() []string { return r.Receivers }
+
+// requestReceivers 获取请求的接收者，非发送类请求返回nil
+func requestReceivers(reqData interface{}) []string {
+	if r, ok := reqData.(receiverRequest); ok {
+		return r.receivers()
+	}
+	return nil
+}
+
+// requestChannelID 获取请求的通道ID，非发送类请求返回0
+func requestChannelID(reqData interface{}) int {
+	if r, ok := reqData.(channelRequest); ok {
+		return r.channelID()
+	}
+	return 0
 }
 
 // Response 通用API响应结构
@@ -32,6 +82,8 @@ type SendMessageData struct {
 	TaskID    string `json:"task_id"`    // 任务ID（UUID格式）
 	Status    string `json:"status"`     // 任务状态
 	CreatedAt string `json:"created_at"` // 创建时间
+
+	RawData // 原始JSON，见 RawData
 }
 
 // SendBatchData 批量发送消息响应数据
@@ -41,6 +93,13 @@ type SendBatchData struct {
 	SuccessCount int    `json:"success_count"` // 成功入队数量
 	FailedCount  int    `json:"failed_count"`  // 失败数量
 	CreatedAt    string `json:"created_at"`    // 创建时间
+
+	FailedReceivers []FailedReceiver `json:"failed_receivers,omitempty"` // 未被接受的接收者明细（网关支持时返回）
+	Results         []BatchResult    `json:"results,omitempty"`          // 每个接收者的结果，按请求顺序（仅 SendBatchDetailed）
+
+	Chunks []SendBatchData `json:"chunks,omitempty"` // 自动分片发送时各分片的结果（未分片时为空）
+
+	RawData // 原始JSON，见 RawData
 }
 
 // QueryTaskData 查询任务状态响应数据
@@ -54,10 +113,69 @@ type QueryTaskData struct {
 	Content        string `json:"content"`         // 消息内容
 	Status         string `json:"status"`          // 任务状态
 	CallbackStatus string `json:"callback_status"` // 回调状态
+	ErrorMessage   string `json:"error_message"`   // 失败原因（发送失败或回调拒绝时）
 	RetryCount     int    `json:"retry_count"`     // 已重试次数
 	MaxRetry       int    `json:"max_retry"`       // 最大重试次数
 	CreatedAt      string `json:"created_at"`      // 创建时间
 	UpdatedAt      string `json:"updated_at"`      // 更新时间
+
+	LastAttemptAt string `json:"last_attempt_at,omitempty"` // 最近一次提交服务商的时间（RFC 3339），见 LastAttemptTime
+	NextRetryAt   string `json:"next_retry_at,omitempty"`   // 下次重试时间（RFC 3339），不再重试时为空，见 NextRetryTime
+	ScheduledAt   string `json:"scheduled_at,omitempty"`    // 定时发送时间（RFC 3339），非定时消息为空，见 ScheduledTime
+
+	Annotations []TaskAnnotation `json:"annotations,omitempty"` // 任务备注
+
+	SignatureName  string                 `json:"signature_name,omitempty"`  // 原始请求的签名名称
+	TemplateParams map[string]interface{} `json:"template_params,omitempty"` // 原始请求的模板参数
+	CountryCode    string                 `json:"country_code,omitempty"`    // 原始请求的国际电话区号
+	Region         string                 `json:"region,omitempty"`          // 原始请求的地区代码
+	IdempotencyKey string                 `json:"idempotency_key,omitempty"` // 原始请求的幂等键
+	CallbackURL    string                 `json:"callback_url,omitempty"`    // 原始请求的回调地址
+
+	RawData // 原始JSON，见 RawData
+}
+
+// AnnotateTaskRequest 添加任务备注请求
+type AnnotateTaskRequest struct {
+	Note string `json:"note"` // 备注内容（必填）
+}
+
+// TaskAnnotation 任务备注
+type TaskAnnotation struct {
+	ID        int    `json:"id"`         // 备注ID
+	Note      string `json:"note"`       // 备注内容
+	Author    string `json:"author"`     // 添加者（应用ID或操作人）
+	CreatedAt string `json:"created_at"` // 创建时间
+
+	RawData // 原始JSON，见 RawData
+}
+
+// QueryBatchData 查询批量任务响应数据
+type QueryBatchData struct {
+	BatchID         string         `json:"batch_id"`         // 批次ID
+	AppID           string         `json:"app_id"`           // 应用ID
+	ChannelID       int            `json:"channel_id"`       // 通道ID
+	MessageType     string         `json:"message_type"`     // 消息类型
+	TotalCount      int            `json:"total_count"`      // 总数量
+	PendingCount    int            `json:"pending_count"`    // 待处理数量
+	ProcessingCount int            `json:"processing_count"` // 处理中数量
+	SuccessCount    int            `json:"success_count"`    // 成功数量
+	FailedCount     int            `json:"failed_count"`     // 失败数量
+	Tasks           []BatchSubTask `json:"tasks"`            // 各接收者的子任务
+	CreatedAt       string         `json:"created_at"`       // 创建时间
+	UpdatedAt       string         `json:"updated_at"`       // 更新时间
+
+	RawData // 原始JSON，见 RawData
+}
+
+// BatchSubTask 批量任务中单个接收者的子任务
+type BatchSubTask struct {
+	TaskID         string `json:"task_id"`         // 任务ID
+	Receiver       string `json:"receiver"`        // 接收者
+	Status         string `json:"status"`          // 任务状态
+	CallbackStatus string `json:"callback_status"` // 回调状态
+	ErrorMessage   string `json:"error_message"`   // 失败原因
+	UpdatedAt      string `json:"updated_at"`      // 更新时间
 }
 
 // TaskStatus 任务状态枚举
@@ -66,6 +184,7 @@ const (
 	TaskStatusProcessing = "processing" // 处理中
 	TaskStatusSuccess    = "success"    // 成功
 	TaskStatusFailed     = "failed"     // 失败
+	TaskStatusCanceled   = "canceled"   // 已取消
 )
 
 // CallbackStatus 回调状态枚举
@@ -78,9 +197,22 @@ const (
 // MessageType 消息类型枚举
 const (
 	MessageTypeSMS        = "sms"         // 短信
+	MessageTypeVoice      = "voice"       // 语音
 	MessageTypeEmail      = "email"       // 邮件
 	MessageTypeWechatWork = "wechat_work" // 企业微信
 	MessageTypeDingtalk   = "dingtalk"    // 钉钉
 	MessageTypeWebhook    = "webhook"     // Webhook
 	MessageTypePush       = "push"        // 推送通知
 )
+
+// UploadAttachmentData 上传附件响应数据
+type UploadAttachmentData struct {
+	AttachmentID string `json:"attachment_id"` // 附件ID，发送消息时通过 Attachment.ID 引用
+	Filename     string `json:"filename"`      // 文件名
+	ContentType  string `json:"content_type"`  // MIME类型
+	Size         int64  `json:"size"`          // 文件大小（字节）
+	SHA256       string `json:"sha256"`        // 文件内容SHA-256摘要
+	ExpiresAt    string `json:"expires_at"`    // 过期时间，过期后需重新上传
+
+	RawData // 原始JSON，见 RawData
+}
@@ -0,0 +1,157 @@
+package mlievpush
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MaxTemplateParamsBytes 模板参数序列化后的最大字节数
+const MaxTemplateParamsBytes = 32 * 1024
+
+// FieldError 请求字段校验错误（本地校验，不会发送请求），可用 errors.Is(err, ErrInvalidRequest) 判断
+type FieldError struct {
+	Field  string // 字段名（JSON 名称，如 "channel_id"、"receivers[2]"）
+	Reason string // 错误原因
+}
+
+// Error 实现 error 接口
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid field %s: %s", e.Field, e.Reason)
+}
+
+// Is 使 errors.Is(err, ErrInvalidRequest) 成立
+func (e *FieldError) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// WithRequestValidation 设置 SendMessage/SendBatch 是否在发送前调用请求的 Validate，默认开启
+func WithRequestValidation(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.skipValidation = !enabled
+	}
+}
+
+// fieldErrors 收集字段校验错误
+type fieldErrors []error
+
+// add 记录字段错误
+func (errs *fieldErrors) add(field, format string, args ...interface{}) {
+	*errs = append(*errs, &FieldError{Field: field, Reason: fmt.Sprintf(format, args...)})
+}
+
+// requireChannel 校验通道ID
+func (errs *fieldErrors) requireChannel(channelID int) {
+	if channelID <= 0 {
+		errs.add("channel_id", "must be positive, got %d", channelID)
+	}
+}
+
+// require 校验必填字符串字段
+func (errs *fieldErrors) require(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		errs.add(field, "is required")
+	}
+}
+
+// checkParams 校验模板参数可以序列化且不超过大小限制
+func (errs *fieldErrors) checkParams(params map[string]interface{}) {
+	if len(params) == 0 {
+		return
+	}
+	marshaled, err := MarshalParams(params)
+	if err != nil {
+		errs.add("template_params", "%v", err)
+		return
+	}
+	data, err := json.Marshal(marshaled)
+	if err != nil {
+		errs.add("template_params", "cannot be encoded as JSON: %v", err)
+		return
+	}
+	if len(data) > MaxTemplateParamsBytes {
+		errs.add("template_params", "encoded size %d bytes exceeds limit of %d", len(data), MaxTemplateParamsBytes)
+	}
+}
+
+// checkCallbackURL 校验回调地址为绝对 http/https 地址（为空时不校验）
+func (errs *fieldErrors) checkCallbackURL(callbackURL string) {
+	if callbackURL == "" {
+		return
+	}
+	u, err := url.Parse(callbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.add("callback_url", "must be an absolute http or https URL, got %q", callbackURL)
+	}
+}
+
+// checkTime 校验时间字段为带时区的 RFC 3339 格式（为空时不校验）
+func (errs *fieldErrors) checkTime(field, value string) {
+	if value == "" {
+		return
+	}
+	if _, err := ParseScheduledAt(value); err != nil {
+		errs.add(field, "must be RFC 3339 with timezone offset (see FormatScheduledAt), got %q", value)
+	}
+}
+
+// err 合并所有字段错误
+func (errs fieldErrors) err() error {
+	return errors.Join(errs...)
+}
+
+// Validate 校验通道ID、接收者、模板参数大小和时间格式，所有字段错误通过 errors.Join 合并返回
+// 签名名称由网关按通道配置校验（部分通道不需要签名），这里不做要求
+func (r *SendMessageRequest) Validate() error {
+	var errs fieldErrors
+	errs.requireChannel(r.ChannelID)
+	errs.require("receiver", r.Receiver)
+	errs.checkParams(r.TemplateParams)
+	errs.checkTime("scheduled_at", r.ScheduledAt)
+	errs.checkTime("deadline_at", r.DeadlineAt)
+	errs.checkCallbackURL(r.CallbackURL)
+	return errs.err()
+}
+
+// Validate 校验通道ID、接收者列表、模板参数大小和时间格式，所有字段错误通过 errors.Join 合并返回
+func (r *SendBatchRequest) Validate() error {
+	var errs fieldErrors
+	errs.requireChannel(r.ChannelID)
+	if len(r.Receivers) == 0 {
+		errs.add("receivers", "is required")
+	}
+	for i, receiver := range r.Receivers {
+		errs.require(fmt.Sprintf("receivers[%d]", i), receiver)
+	}
+	errs.checkParams(r.TemplateParams)
+	errs.checkTime("scheduled_at", r.ScheduledAt)
+	errs.checkTime("deadline_at", r.DeadlineAt)
+	errs.checkCallbackURL(r.CallbackURL)
+	return errs.err()
+}
+
+// Validate 校验备注内容
+func (r *AnnotateTaskRequest) Validate() error {
+	var errs fieldErrors
+	errs.require("note", r.Note)
+	return errs.err()
+}
+
+// Validate 校验必填字段
+func (r *CreateTemplateRequest) Validate() error {
+	var errs fieldErrors
+	errs.requireChannel(r.ChannelID)
+	errs.require("name", r.Name)
+	errs.require("content", r.Content)
+	return errs.err()
+}
+
+// Validate 校验必填字段
+func (r *CreateSignatureRequest) Validate() error {
+	var errs fieldErrors
+	errs.requireChannel(r.ChannelID)
+	errs.require("name", r.Name)
+	return errs.err()
+}
@@ -0,0 +1,66 @@
+package mlievpush
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRequestValidate 测试请求字段校验
+func TestRequestValidate(t *testing.T) {
+	err := (&SendMessageRequest{ChannelID: 0, Receiver: " "}).Validate()
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("Validate() error = %v, want ErrInvalidRequest", err)
+	}
+	for _, field := range []string{"channel_id", "receiver"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Validate() error %q does not mention %s", err, field)
+		}
+	}
+
+	big := map[string]interface{}{"content": strings.Repeat("x", MaxTemplateParamsBytes)}
+	err = (&SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000", ""}, TemplateParams: big}).Validate()
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || !strings.Contains(err.Error(), "receivers[1]") || !strings.Contains(err.Error(), "template_params") {
+		t.Errorf("SendBatchRequest.Validate() error = %v", err)
+	}
+
+	if err := (&SendMessageRequest{ChannelID: 1, Receiver: "13800138000"}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (&CreateTemplateRequest{ChannelID: 1, Name: "otp"}).Validate(); err == nil {
+		t.Error("CreateTemplateRequest.Validate() without content should fail")
+	}
+}
+
+// TestSendValidation 测试发送前自动校验及关闭校验
+func TestSendValidation(t *testing.T) {
+	server := newSuccessServer(t)
+	req := &SendMessageRequest{ChannelID: 0, Receiver: "13800138000"}
+
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+	if _, err := client.SendMessage(context.Background(), req); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("SendMessage() error = %v, want ErrInvalidRequest", err)
+	}
+
+	client = NewClient(server.URL, "test_app_id", "test_secret", WithRequestValidation(false))
+	if _, err := client.SendMessage(context.Background(), req); err != nil {
+		t.Errorf("SendMessage() with validation disabled error = %v", err)
+	}
+}
+
+// TestCallbackURLValidate 测试回调地址校验
+func TestCallbackURLValidate(t *testing.T) {
+	for _, callbackURL := range []string{"tenant.example.com/callback", "ftp://example.com/cb", "/callback", "https://"} {
+		err := (&SendMessageRequest{ChannelID: 1, Receiver: "13800138000", CallbackURL: callbackURL}).Validate()
+		if !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), "callback_url") {
+			t.Errorf("Validate(%q) error = %v", callbackURL, err)
+		}
+	}
+
+	req := &SendBatchRequest{ChannelID: 1, Receivers: []string{"13800138000"}, CallbackURL: "https://tenant.example.com/callback"}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
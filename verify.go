@@ -0,0 +1,273 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 签名相关请求头
+const (
+	HeaderAppID     = "X-App-Id"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+
+	HeaderContentSHA256 = "X-Content-SHA256" // 请求体 SHA-256 摘要（可选，存在时参与签名）
+)
+
+// RequestHeaders 返回客户端可能发送的全部自定义请求头，用于网关配置 CORS 的 Access-Control-Allow-Headers
+// 另需放行 Content-Type，以及通过 WithHeader 附加的请求头
+func RequestHeaders() []string {
+	return []string{
+		HeaderAppID,
+		HeaderTimestamp,
+		HeaderNonce,
+		HeaderSignature,
+		HeaderRequestID,
+		HeaderContentSHA256,
+		HeaderSignatureVersion,
+	}
+}
+
+// DefaultMaxClockSkew 默认允许的时钟偏差（前后各5分钟）
+const DefaultMaxClockSkew = 5 * time.Minute
+
+// DefaultMaxBodySize 默认允许的请求体大小（1 MiB），足够容纳回调和普通 JSON 请求
+const DefaultMaxBodySize = 1 << 20
+
+// 服务端校验错误
+var (
+	ErrInvalidSignature = errors.New("mlievpush: invalid signature")      // 签名校验失败
+	ErrUnknownApp       = errors.New("mlievpush: unknown app")            // 未知应用
+	ErrReplayedNonce    = errors.New("mlievpush: nonce already used")     // 随机数重复使用（重放请求）
+	ErrInvalidRequest   = errors.New("mlievpush: invalid request")        // 请求格式错误
+	ErrTimestampSkew    = errors.New("mlievpush: timestamp skew")         // 请求时间戳超出允许的时钟偏差
+	ErrBodyTooLarge     = errors.New("mlievpush: request body too large") // 请求体超出 WithMaxBodySize 限制
+)
+
+// SecretLookup 根据应用ID查找应用密钥，应用不存在时应返回 ErrUnknownApp
+type SecretLookup func(ctx context.Context, appID string) (string, error)
+
+// StaticSecret 返回固定密钥的 SecretLookup，适用于单应用场景
+func StaticSecret(appSecret string) SecretLookup {
+	return func(ctx context.Context, appID string) (string, error) {
+		return appSecret, nil
+	}
+}
+
+// VerifiedRequest 校验通过的请求
+type VerifiedRequest struct {
+	AppID     string                 // 应用ID
+	Timestamp time.Time              // 请求时间
+	Nonce     string                 // 随机数
+	Body      []byte                 // 原始请求体
+	Params    map[string]interface{} // 参与签名的参数（JSON请求体解析结果，multipart 请求为普通字段和 content_sha256）
+}
+
+// Verifier 服务端请求签名校验器，供回调接收方或兼容网关使用
+type Verifier struct {
+	lookup        SecretLookup
+	nonces        NonceCache
+	skew          time.Duration
+	requireDigest bool
+	maxBody       int64
+	now           func() time.Time
+}
+
+// VerifierOption 校验器配置选项
+type VerifierOption func(*Verifier)
+
+// WithNonceCache 设置随机数缓存，拒绝时间窗口内重复使用的随机数（防重放）
+func WithNonceCache(cache NonceCache) VerifierOption {
+	return func(v *Verifier) {
+		v.nonces = cache
+	}
+}
+
+// WithMaxClockSkew 设置允许的最大时钟偏差（默认 DefaultMaxClockSkew），小于等于0时忽略
+func WithMaxClockSkew(skew time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		if skew > 0 {
+			v.skew = skew
+		}
+	}
+}
+
+// WithRequireContentDigest 要求请求携带 X-Content-SHA256 请求头，缺失时拒绝请求
+func WithRequireContentDigest() VerifierOption {
+	return func(v *Verifier) {
+		v.requireDigest = true
+	}
+}
+
+// WithMaxBodySize 设置允许的请求体大小上限，默认 DefaultMaxBodySize；请求体在签名校验前读取，限制大小可防止公开的回调地址被大请求耗尽内存
+// 兼容网关接收附件上传时需要调大
+func WithMaxBodySize(n int64) VerifierOption {
+	return func(v *Verifier) {
+		if n > 0 {
+			v.maxBody = n
+		}
+	}
+}
+
+// NewVerifier 创建签名校验器
+func NewVerifier(lookup SecretLookup, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		lookup:  lookup,
+		skew:    DefaultMaxClockSkew,
+		maxBody: DefaultMaxBodySize,
+		now:     time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Verify 校验请求签名，请求体读取后会被重置，后续处理函数可以再次读取
+func (v *Verifier) Verify(r *http.Request) (*VerifiedRequest, error) {
+	ctx := r.Context()
+	appID := r.Header.Get(HeaderAppID)
+	appSecret, err := v.lookup(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("lookup secret for app %q: %w", appID, err)
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(http.MaxBytesReader(nil, r.Body, v.maxBody))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, tooLarge.Limit)
+			}
+			return nil, fmt.Errorf("%w: read body: %w", ErrInvalidRequest, err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	var params map[string]interface{}
+	var signParams string
+	if contentType := r.Header.Get("Content-Type"); isMultipart(contentType) {
+		// multipart 请求签名普通字段和请求体哈希
+		params, err = multipartSignParams(contentType, body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+		}
+		signParams = sortParams(params)
+	} else if len(body) > 0 {
+		if err := json.Unmarshal(body, &params); err != nil {
+			return nil, fmt.Errorf("%w: unmarshal body: %w", ErrInvalidRequest, err)
+		}
+		// 对实际收到的请求体规范化，数字保持原样
+		if signParams, err = CanonicalJSON(body); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+		}
+	}
+
+	timestamp := r.Header.Get(HeaderTimestamp)
+	nonce := r.Header.Get(HeaderNonce)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed timestamp %q", ErrInvalidSignature, timestamp)
+	}
+	requestTime := time.Unix(unix, 0)
+	if skew := v.now().Sub(requestTime); skew > v.skew || skew < -v.skew {
+		// 与签名错误区分，便于排查服务器时钟问题
+		return nil, fmt.Errorf("%w: request time %s differs from server time by %s (max ±%s)",
+			ErrTimestampSkew, requestTime.UTC().Format(time.RFC3339), skew.Round(time.Second), v.skew)
+	}
+
+	// 请求体摘要先于签名校验，摘要不符时无需再做参数规范化
+	digest := r.Header.Get(HeaderContentSHA256)
+	if digest == "" && v.requireDigest {
+		return nil, fmt.Errorf("%w: missing %s header", ErrInvalidSignature, HeaderContentSHA256)
+	}
+	if digest != "" && !strings.EqualFold(digest, ContentSHA256(body)) {
+		return nil, fmt.Errorf("%w: content digest mismatch", ErrInvalidSignature)
+	}
+
+	// 目前只支持 V1 签名，其他版本明确拒绝而不是报签名不符
+	if version := r.Header.Get(HeaderSignatureVersion); version != "" && version != SignatureVersion1 {
+		return nil, fmt.Errorf("%w: unsupported signature version %q", ErrInvalidSignature, version)
+	}
+	canonical := withContentDigest(canonicalString(r.Method, signPath(r), signParams, timestamp, nonce), digest)
+	if !hmac.Equal([]byte(signContent(canonical, appSecret)), []byte(r.Header.Get(HeaderSignature))) {
+		return nil, ErrInvalidSignature
+	}
+
+	// 签名通过后再记录随机数，避免伪造请求占满缓存
+	if v.nonces != nil {
+		fresh, err := v.nonces.Add(ctx, appID+":"+nonce, 2*v.skew)
+		if err != nil {
+			return nil, fmt.Errorf("check nonce: %w", err)
+		}
+		if !fresh {
+			return nil, ErrReplayedNonce
+		}
+	}
+
+	return &VerifiedRequest{
+		AppID:     appID,
+		Timestamp: requestTime,
+		Nonce:     nonce,
+		Body:      body,
+		Params:    params,
+	}, nil
+}
+
+// Middleware 返回校验签名的HTTP中间件
+// 校验失败时返回401（请求格式错误返回400，请求体过大返回413），通过时可用 VerifiedRequestFromContext 获取校验结果
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, err := v.Verify(r)
+		if err != nil {
+			http.Error(w, err.Error(), VerifyErrorStatus(err))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), verifiedRequestKey{}, verified)))
+	})
+}
+
+// VerifyErrorStatus 返回校验错误对应的HTTP状态码
+func VerifyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrTimestampSkew),
+		errors.Is(err, ErrUnknownApp), errors.Is(err, ErrReplayedNonce):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// verifiedRequestKey context中保存校验结果的键
+type verifiedRequestKey struct{}
+
+// VerifiedRequestFromContext 获取中间件保存的校验结果
+func VerifiedRequestFromContext(ctx context.Context) (*VerifiedRequest, bool) {
+	v, ok := ctx.Value(verifiedRequestKey{}).(*VerifiedRequest)
+	return v, ok
+}
+
+// signPath 参与签名的路径（包含查询字符串）
+func signPath(r *http.Request) string {
+	if r.URL.RawQuery == "" {
+		return r.URL.Path
+	}
+	return r.URL.Path + "?" + r.URL.RawQuery
+}
@@ -0,0 +1,211 @@
+package mlievpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSignedServerRequest 构造带签名的服务端请求
+func newSignedServerRequest(t *testing.T, secret, nonce string, ts time.Time, payload map[string]interface{}) *http.Request {
+	t.Helper()
+
+	body, _ := json.Marshal(payload)
+	var params map[string]interface{}
+	json.Unmarshal(body, &params)
+
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/messages", bytes.NewReader(body))
+	r.Header.Set(HeaderAppID, "test_app_id")
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderSignature, GenerateSignature(http.MethodPost, "/api/v1/messages", params, timestamp, nonce, secret))
+	return r
+}
+
+// TestVerifier 测试服务端签名校验
+func TestVerifier(t *testing.T) {
+	payload := map[string]interface{}{"channel_id": 1, "receiver": "13800138000"}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		req     *http.Request
+		wantErr error
+	}{
+		{"签名正确", newSignedServerRequest(t, "secret", "n1", now, payload), nil},
+		{"密钥错误", newSignedServerRequest(t, "wrong", "n2", now, payload), ErrInvalidSignature},
+		{"时间戳过期", newSignedServerRequest(t, "secret", "n3", now.Add(-10*time.Minute), payload), ErrTimestampSkew},
+		{"时间戳超前", newSignedServerRequest(t, "secret", "n4", now.Add(10*time.Minute), payload), ErrTimestampSkew},
+	}
+
+	v := NewVerifier(StaticSecret("secret"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verified, err := v.Verify(tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if verified.Params["receiver"] != "13800138000" {
+					t.Errorf("unexpected params: %v", verified.Params)
+				}
+				// 请求体可以再次读取
+				body, _ := io.ReadAll(tt.req.Body)
+				if !bytes.Equal(body, verified.Body) {
+					t.Error("request body should be restored")
+				}
+			}
+		})
+	}
+}
+
+// TestVerifierMaxClockSkew 测试自定义时钟偏差
+func TestVerifierMaxClockSkew(t *testing.T) {
+	payload := map[string]interface{}{"channel_id": 1}
+	now := time.Now()
+
+	strict := NewVerifier(StaticSecret("secret"), WithMaxClockSkew(30*time.Second))
+	_, err := strict.Verify(newSignedServerRequest(t, "secret", "n1", now.Add(-2*time.Minute), payload))
+	if !errors.Is(err, ErrTimestampSkew) {
+		t.Fatalf("Verify() error = %v, want %v", err, ErrTimestampSkew)
+	}
+	if errors.Is(err, ErrInvalidSignature) {
+		t.Error("skew error should not match ErrInvalidSignature")
+	}
+
+	loose := NewVerifier(StaticSecret("secret"), WithMaxClockSkew(15*time.Minute))
+	if _, err := loose.Verify(newSignedServerRequest(t, "secret", "n2", now.Add(-10*time.Minute), payload)); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+// TestVerifierMaxBodySize 测试请求体大小限制在签名校验前生效
+func TestVerifierMaxBodySize(t *testing.T) {
+	payload := map[string]interface{}{"channel_id": 1, "receiver": "13800138000", "content": strings.Repeat("x", 2048)}
+
+	v := NewVerifier(StaticSecret("secret"), WithMaxBodySize(1024))
+	_, err := v.Verify(newSignedServerRequest(t, "secret", "n1", time.Now(), payload))
+	if !errors.Is(err, ErrBodyTooLarge) || VerifyErrorStatus(err) != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Verify() error = %v, want ErrBodyTooLarge", err)
+	}
+
+	v = NewVerifier(StaticSecret("secret"))
+	if _, err := v.Verify(newSignedServerRequest(t, "secret", "n2", time.Now(), payload)); err != nil {
+		t.Errorf("Verify() with default limit error = %v", err)
+	}
+}
+
+// TestVerifierNonceReplay 测试随机数防重放
+func TestVerifierNonceReplay(t *testing.T) {
+	payload := map[string]interface{}{"channel_id": 1}
+	v := NewVerifier(StaticSecret("secret"), WithNonceCache(NewMemoryNonceCache()))
+
+	if _, err := v.Verify(newSignedServerRequest(t, "secret", "nonce-1", time.Now(), payload)); err != nil {
+		t.Fatalf("first Verify() error = %v", err)
+	}
+	_, err := v.Verify(newSignedServerRequest(t, "secret", "nonce-1", time.Now(), payload))
+	if !errors.Is(err, ErrReplayedNonce) {
+		t.Fatalf("replayed Verify() error = %v, want %v", err, ErrReplayedNonce)
+	}
+	if VerifyErrorStatus(err) != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", VerifyErrorStatus(err), http.StatusUnauthorized)
+	}
+}
+
+// TestVerifierMiddleware 测试校验中间件
+func TestVerifierMiddleware(t *testing.T) {
+	var appID string
+	handler := NewVerifier(StaticSecret("secret")).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified, _ := VerifiedRequestFromContext(r.Context())
+		appID = verified.AppID
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedServerRequest(t, "secret", "n1", time.Now(), map[string]interface{}{"a": 1}))
+	if w.Code != http.StatusOK || appID != "test_app_id" {
+		t.Errorf("status = %d, appID = %q", w.Code, appID)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newSignedServerRequest(t, "wrong", "n2", time.Now(), map[string]interface{}{"a": 1}))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+// TestRedisNonceCache 测试 Redis 随机数缓存
+func TestRedisNonceCache(t *testing.T) {
+	keys := map[string]bool{}
+	cache := NewRedisNonceCache(func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+		if keys[key] {
+			return false, nil
+		}
+		keys[key] = true
+		return true, nil
+	}, "push:nonce:")
+
+	ctx := context.Background()
+	if ok, _ := cache.Add(ctx, "abc", time.Minute); !ok {
+		t.Error("first Add should succeed")
+	}
+	if ok, _ := cache.Add(ctx, "abc", time.Minute); ok {
+		t.Error("second Add should fail")
+	}
+	if !keys["push:nonce:abc"] {
+		t.Error("key prefix not applied")
+	}
+}
+
+// TestMemoryNonceCacheExpiry 测试内存随机数缓存过期
+func TestMemoryNonceCacheExpiry(t *testing.T) {
+	cache := NewMemoryNonceCache()
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	cache.Add(ctx, "abc", time.Minute)
+	now = now.Add(2 * time.Minute)
+	if ok, _ := cache.Add(ctx, "abc", time.Minute); !ok {
+		t.Error("Add after expiry should succeed")
+	}
+}
+
+// TestRequestHeaders 测试客户端发送的自定义请求头都在 RequestHeaders 中，浏览器预检可以据此放行
+func TestRequestHeaders(t *testing.T) {
+	var sent http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "message": "success", "data": map[string]interface{}{"task_id": "t1"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithContentDigest(true), WithSigner(hmacSHA512Signer{}))
+	if _, err := client.SendMessage(context.Background(), &SendMessageRequest{ChannelID: 1, SignatureName: "test", Receiver: "13800138000"}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	allowed := map[string]bool{"Content-Type": true}
+	for _, h := range RequestHeaders() {
+		allowed[http.CanonicalHeaderKey(h)] = true
+	}
+	for key := range sent {
+		if strings.HasPrefix(key, "X-") && !allowed[key] {
+			t.Errorf("header %s is not listed in RequestHeaders()", key)
+		}
+	}
+	for _, h := range []string{HeaderRequestID, HeaderContentSHA256, HeaderSignatureVersion} {
+		if sent.Get(h) == "" {
+			t.Errorf("header %s was not sent", h)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package mlievpush
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultPollInterval 默认首次轮询间隔（兼容保留，默认轮询使用 DefaultPollBackoff）
+const DefaultPollInterval = time.Second
+
+// DefaultFallbackPollInterval 配置了 TaskNotifier 时的默认兜底轮询间隔
+const DefaultFallbackPollInterval = 30 * time.Second
+
+// DefaultPollBackoff WaitForTask 默认轮询间隔：1s、2s、4s，之后每 5s 一次，±10% 抖动，降低大量等待时的查询压力
+var DefaultPollBackoff = Backoff{
+	Initial:    DefaultPollInterval,
+	Max:        5 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.1,
+}
+
+// PollOptions 轮询配置
+type PollOptions struct {
+	Interval time.Duration        // 固定轮询间隔，0 且未设置 Backoff 时使用 DefaultPollBackoff
+	Backoff  *Backoff             // 退避策略（可选），设置后第 n 次等待时间为 Backoff.Delay(n)，忽略 Interval
+	OnPoll   func(*QueryTaskData) // 每次查询成功后的回调（可选），用于展示进度
+
+	FallbackInterval time.Duration // 配置了 TaskNotifier 时的兜底轮询间隔，0 使用 DefaultFallbackPollInterval
+}
+
+// delay 第 n 次（从1开始）轮询前的等待时间
+func (o PollOptions) delay(n int) time.Duration {
+	if o.Backoff != nil {
+		return o.Backoff.Delay(n)
+	}
+	if o.Interval > 0 {
+		return o.Interval
+	}
+	return DefaultPollBackoff.Delay(n)
+}
+
+// fallbackDelay 配置了 TaskNotifier 时的兜底轮询间隔
+func (o PollOptions) fallbackDelay() time.Duration {
+	if o.FallbackInterval > 0 {
+		return o.FallbackInterval
+	}
+	return DefaultFallbackPollInterval
+}
+
+// TaskNotifier 任务状态变更通知，通常由回调处理器实现（见 callback.TaskWatcher）
+// 配置后 WaitForTask 在收到通知时才查询任务，轮询只作为兜底，大幅减少 QueryTask 请求
+type TaskNotifier interface {
+	// Subscribe 订阅任务状态变更，任务收到回调时向 updates 发送信号（允许合并、丢弃重复信号）；cancel 取消订阅
+	Subscribe(taskID string) (updates <-chan struct{}, cancel func())
+}
+
+// WithTaskNotifier 设置任务状态变更通知，WaitForTask 自动切换为回调驱动
+// 多实例部署时回调可能由其他实例接收，此时依靠兜底轮询（PollOptions.FallbackInterval）发现终态
+func WithTaskNotifier(notifier TaskNotifier) ClientOption {
+	return func(c *Client) {
+		c.taskNotifier = notifier
+	}
+}
+
+// WaitForTask 轮询 QueryTask 直到任务进入终态（success、failed 或 canceled），返回终态的任务数据
+// 已安排重试的失败任务（NextRetryAt 非空）不视为终态，继续等待
+// 默认轮询间隔逐步拉长（见 DefaultPollBackoff）；配置了 WithTaskNotifier 时收到回调通知立即查询，轮询只作为兜底
+// 查询失败时立即返回错误（瞬时错误可通过 WithRetry 重试）；ctx 结束时返回最后一次查询到的任务数据和包装了 ctx.Err() 的错误
+func (c *Client) WaitForTask(ctx context.Context, taskID string, opts PollOptions) (*QueryTaskData, error) {
+	// 先订阅再查询，避免错过查询与订阅之间到达的回调
+	var updates <-chan struct{}
+	if c.taskNotifier != nil {
+		var unsubscribe func()
+		updates, unsubscribe = c.taskNotifier.Subscribe(taskID)
+		defer unsubscribe()
+	}
+
+	var last *QueryTaskData
+	for n := 1; ; n++ {
+		data, err := c.QueryTask(ctx, taskID)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return last, fmt.Errorf("wait for task %s: %w", taskID, ctxErr)
+			}
+			return last, err
+		}
+		last = data
+		if opts.OnPoll != nil {
+			c.safeCall(ctx, "OnPoll", func() { opts.OnPoll(data) })
+		}
+		if isTerminalTaskStatus(data.Status) && !data.WillRetry() {
+			return data, nil
+		}
+
+		if updates != nil {
+			if !waitNotified(ctx, updates, opts.fallbackDelay()) {
+				return last, fmt.Errorf("wait for task %s: %w", taskID, ctx.Err())
+			}
+			continue
+		}
+		if !sleepContext(ctx, opts.delay(n)) {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return last, fmt.Errorf("wait for task %s: %w", taskID, ctxErr)
+			}
+			// 下一次轮询前 ctx 就会超时
+			return last, fmt.Errorf("wait for task %s: %w", taskID, context.DeadlineExceeded)
+		}
+	}
+}
+
+// isTerminalTaskStatus 判断任务状态是否为终态
+func isTerminalTaskStatus(status string) bool {
+	return status == TaskStatusSuccess || status == TaskStatusFailed || status == TaskStatusCanceled
+}
+
+// waitNotified 等待任务通知或兜底轮询时间到达，ctx 结束时返回 false
+func waitNotified(ctx context.Context, updates <-chan struct{}, fallback time.Duration) bool {
+	timer := time.NewTimer(fallback)
+	defer timer.Stop()
+	select {
+	case <-updates:
+		return true
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,112 @@
+package mlievpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTaskStatusServer 创建依次返回给定状态的任务查询服务器（最后一个状态重复返回）
+func newTaskStatusServer(t *testing.T, statuses ...string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		if n >= len(statuses) {
+			n = len(statuses) - 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    0,
+			"message": "success",
+			"data":    map[string]interface{}{"task_id": "t1", "status": statuses[n]},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// TestWaitForTask 测试轮询直到终态
+func TestWaitForTask(t *testing.T) {
+	server, calls := newTaskStatusServer(t, TaskStatusPending, TaskStatusProcessing, TaskStatusSuccess)
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	var polled []string
+	data, err := client.WaitForTask(context.Background(), "t1", PollOptions{
+		Interval: time.Millisecond,
+		OnPoll:   func(d *QueryTaskData) { polled = append(polled, d.Status) },
+	})
+	if err != nil {
+		t.Fatalf("WaitForTask() error = %v", err)
+	}
+	if data.Status != TaskStatusSuccess || calls.Load() != 3 || len(polled) != 3 {
+		t.Errorf("status = %s, calls = %d, polled = %v", data.Status, calls.Load(), polled)
+	}
+}
+
+// TestWaitForTaskContext 测试 ctx 结束时返回最后一次的任务数据
+func TestWaitForTaskContext(t *testing.T) {
+	server, _ := newTaskStatusServer(t, TaskStatusProcessing)
+	client := NewClient(server.URL, "test_app_id", "test_secret")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	data, err := client.WaitForTask(ctx, "t1", PollOptions{Backoff: &Backoff{Initial: 5 * time.Millisecond, Multiplier: 2}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if data == nil || data.Status != TaskStatusProcessing {
+		t.Errorf("expected last task data, got %+v", data)
+	}
+}
+
+// testNotifier 测试用的任务通知器
+type testNotifier struct {
+	updates chan struct{}
+}
+
+// Subscribe 实现 TaskNotifier 接口
+func (n *testNotifier) Subscribe(taskID string) (<-chan struct{}, func()) {
+	return n.updates, func() {}
+}
+
+// TestWaitForTaskNotifier 测试收到通知后才查询
+func TestWaitForTaskNotifier(t *testing.T) {
+	server, calls := newTaskStatusServer(t, TaskStatusPending, TaskStatusSuccess)
+	notifier := &testNotifier{updates: make(chan struct{}, 1)}
+	client := NewClient(server.URL, "test_app_id", "test_secret", WithTaskNotifier(notifier))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		notifier.updates <- struct{}{}
+	}()
+
+	start := time.Now()
+	data, err := client.WaitForTask(context.Background(), "t1", PollOptions{Interval: time.Millisecond, FallbackInterval: time.Minute})
+	if err != nil {
+		t.Fatalf("WaitForTask() error = %v", err)
+	}
+	if data.Status != TaskStatusSuccess || calls.Load() != 2 {
+		t.Errorf("status = %s, calls = %d", data.Status, calls.Load())
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Error("expected notification to end the wait before the fallback poll")
+	}
+}
+
+// TestDefaultPollBackoff 测试默认轮询间隔逐步拉长
+func TestDefaultPollBackoff(t *testing.T) {
+	b := DefaultPollBackoff
+	b.Jitter = 0
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, d := range want {
+		if got := b.Delay(i + 1); got != d {
+			t.Errorf("Delay(%d) = %v, want %v", i+1, got, d)
+		}
+	}
+}